@@ -11,8 +11,8 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
-	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
@@ -64,7 +64,6 @@ func AbapAddonAssemblyKitCheckCommand() *cobra.Command {
 	var startTime time.Time
 	var commonPipelineEnvironment abapAddonAssemblyKitCheckCommonPipelineEnvironment
 	var logCollector *log.CollectorHook
-	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
 
 	var createAbapAddonAssemblyKitCheckCmd = &cobra.Command{
@@ -105,8 +104,7 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 				log.RegisterHook(&sentryHook)
 			}
 
-			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-				splunkClient = &splunk.Splunk{}
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 || len(GeneralConfig.HookConfig.TelemetryConfig.Sinks) > 0 {
 				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
 				log.RegisterHook(logCollector)
 			}
@@ -126,40 +124,41 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 
 			return nil
 		},
-		Run: func(_ *cobra.Command, _ []string) {
+		RunE: func(_ *cobra.Command, _ []string) error {
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
+			if GeneralConfig.DryRun {
+				RunStepDryRun(STEP_NAME, &metadata, stepConfig, nil)
+				return nil
+			}
 			handler := func() {
 				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				PushCommonPipelineEnvironment()
+				PublishCommandTranscript(STEP_NAME)
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultLeases()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
+				tracing.EndStep(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 				telemetryClient.SetData(&stepTelemetryData)
 				telemetryClient.Send()
-				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.Dsn,
-						GeneralConfig.HookConfig.SplunkConfig.Token,
-						GeneralConfig.HookConfig.SplunkConfig.Index,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
-				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
 			}
-			log.DeferExitHandler(handler)
+			removeExitHandler := log.DeferExitHandler(handler)
+			defer removeExitHandler()
 			defer handler()
+			StartStepTracing(STEP_NAME, startTime)
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME, GeneralConfig.HookConfig.PendoConfig.Token)
-			abapAddonAssemblyKitCheck(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			AddTelemetrySinks(telemetryClient, logCollector)
+			err := log.RunRecoverable(func() {
+				abapAddonAssemblyKitCheck(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			})
+			if err != nil {
+				return err
+			}
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
+			return nil
 		},
 	}
 
@@ -210,6 +209,7 @@ func abapAddonAssemblyKitCheckMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_abapAddonAssemblyKitCertificateFile"),
+						Secret:    true,
 					},
 					{
 						Name: "abapAddonAssemblyKitCertificatePass",
@@ -225,6 +225,7 @@ func abapAddonAssemblyKitCheckMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_abapAddonAssemblyKitCertificatePass"),
+						Secret:    true,
 					},
 					{
 						Name:        "abapAddonAssemblyKitEndpoint",
@@ -243,6 +244,7 @@ func abapAddonAssemblyKitCheckMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_username"),
+						Secret:      true,
 					},
 					{
 						Name:        "password",
@@ -252,6 +254,7 @@ func abapAddonAssemblyKitCheckMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_password"),
+						Secret:      true,
 					},
 					{
 						Name:        "addonDescriptorFileName",
@@ -9,8 +9,8 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
-	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
@@ -36,7 +36,6 @@ func AbapAddonAssemblyKitPublishTargetVectorCommand() *cobra.Command {
 	var stepConfig abapAddonAssemblyKitPublishTargetVectorOptions
 	var startTime time.Time
 	var logCollector *log.CollectorHook
-	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
 
 	var createAbapAddonAssemblyKitPublishTargetVectorCmd = &cobra.Command{
@@ -75,8 +74,7 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 				log.RegisterHook(&sentryHook)
 			}
 
-			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-				splunkClient = &splunk.Splunk{}
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 || len(GeneralConfig.HookConfig.TelemetryConfig.Sinks) > 0 {
 				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
 				log.RegisterHook(logCollector)
 			}
@@ -96,39 +94,40 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 
 			return nil
 		},
-		Run: func(_ *cobra.Command, _ []string) {
+		RunE: func(_ *cobra.Command, _ []string) error {
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
+			if GeneralConfig.DryRun {
+				RunStepDryRun(STEP_NAME, &metadata, stepConfig, nil)
+				return nil
+			}
 			handler := func() {
+				PushCommonPipelineEnvironment()
+				PublishCommandTranscript(STEP_NAME)
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultLeases()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
+				tracing.EndStep(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 				telemetryClient.SetData(&stepTelemetryData)
 				telemetryClient.Send()
-				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.Dsn,
-						GeneralConfig.HookConfig.SplunkConfig.Token,
-						GeneralConfig.HookConfig.SplunkConfig.Index,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
-				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
 			}
-			log.DeferExitHandler(handler)
+			removeExitHandler := log.DeferExitHandler(handler)
+			defer removeExitHandler()
 			defer handler()
+			StartStepTracing(STEP_NAME, startTime)
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME, GeneralConfig.HookConfig.PendoConfig.Token)
-			abapAddonAssemblyKitPublishTargetVector(stepConfig, &stepTelemetryData)
+			AddTelemetrySinks(telemetryClient, logCollector)
+			err := log.RunRecoverable(func() {
+				abapAddonAssemblyKitPublishTargetVector(stepConfig, &stepTelemetryData)
+			})
+			if err != nil {
+				return err
+			}
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
+			return nil
 		},
 	}
 
@@ -182,6 +181,7 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_abapAddonAssemblyKitCertificateFile"),
+						Secret:    true,
 					},
 					{
 						Name: "abapAddonAssemblyKitCertificatePass",
@@ -197,6 +197,7 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Mandatory: false,
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_abapAddonAssemblyKitCertificatePass"),
+						Secret:    true,
 					},
 					{
 						Name:        "abapAddonAssemblyKitEndpoint",
@@ -215,6 +216,7 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_username"),
+						Secret:      true,
 					},
 					{
 						Name:        "password",
@@ -224,6 +226,7 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_password"),
+						Secret:      true,
 					},
 					{
 						Name:        "targetVectorScope",
@@ -274,6 +277,7 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_abapAddonAssemblyKitOriginHash"),
+						Secret:      true,
 					},
 				},
 			},
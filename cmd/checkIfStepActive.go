@@ -69,7 +69,7 @@ func checkIfStepActive(utils piperutils.FileUtils) error {
 	var pConfig config.Config
 
 	// load project config and defaults
-	projectConfig, err := initializeConfig(&pConfig)
+	projectConfig, err := initializeConfig(&pConfig, checkStepActiveOptions.openFile, checkStepActiveOptions.fileExists)
 	if err != nil {
 		log.Entry().Errorf("Failed to load project config: %v", err)
 		return errors.Wrapf(err, "Failed to load project config failed")
@@ -142,14 +142,14 @@ func addCheckStepActiveFlags(cmd *cobra.Command) {
 	_ = cmd.MarkFlagRequired("step")
 }
 
-func initializeConfig(pConfig *config.Config) (*config.Config, error) {
+func initializeConfig(pConfig *config.Config, openFile func(s string, t map[string]string) (io.ReadCloser, error), fileExists func(filename string) (bool, error)) (*config.Config, error) {
 	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
 	var customConfig io.ReadCloser
 	var err error
 	//accept that config file cannot be loaded as its not mandatory here
-	if exists, err := checkStepActiveOptions.fileExists(projectConfigFile); exists {
+	if exists, err := fileExists(projectConfigFile); exists {
 		log.Entry().Infof("Project config: '%s'", projectConfigFile)
-		customConfig, err = checkStepActiveOptions.openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+		customConfig, err = openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
 		if err != nil {
 			return nil, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
 		}
@@ -160,7 +160,7 @@ func initializeConfig(pConfig *config.Config) (*config.Config, error) {
 
 	defaultConfig := []io.ReadCloser{}
 	for _, f := range GeneralConfig.DefaultConfig {
-		fc, err := checkStepActiveOptions.openFile(f, GeneralConfig.GitHubAccessTokens)
+		fc, err := openFile(f, GeneralConfig.GitHubAccessTokens)
 		// only create error for non-default values
 		if err != nil && f != ".pipeline/defaults.yaml" {
 			return nil, errors.Wrapf(err, "config: getting defaults failed: '%v'", f)
@@ -35,6 +35,7 @@ type checkmarxExecuteScanUtils interface {
 	Stat(name string) (os.FileInfo, error)
 	Open(name string) (*os.File, error)
 	WriteFile(filename string, data []byte, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
 	MkdirAll(path string, perm os.FileMode) error
 	PathMatch(pattern, name string) (bool, error)
 	GetWorkspace() string
@@ -60,6 +61,10 @@ func (c *checkmarxExecuteScanUtilsBundle) WriteFile(filename string, data []byte
 	return os.WriteFile(filename, data, perm)
 }
 
+func (c *checkmarxExecuteScanUtilsBundle) FileWrite(path string, content []byte, perm os.FileMode) error {
+	return os.WriteFile(path, content, perm)
+}
+
 func (c *checkmarxExecuteScanUtilsBundle) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
@@ -376,6 +381,14 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 	}
 	reports = append(reports, piperutils.Path{Target: xmlReportName})
 
+	findings, err := checkmarx.ConvertCxxmlToFindings(xmlReportName)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert results into findings")
+	}
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "checkmarxExecuteScan", Scanner: "checkmarx", Project: config.ProjectName, Findings: findings}, utils); err != nil {
+		log.Entry().WithError(err).Warning("failed to write findings")
+	}
+
 	// generate sarif report
 	if config.ConvertToSarif {
 		log.Entry().Info("Calling conversion to SARIF function.")
@@ -388,6 +401,12 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 			return fmt.Errorf("failed to write sarif")
 		}
 		reports = append(reports, paths...)
+
+		findingsSarif, err := reporting.WriteFindingsSarif(checkmarx.CreateFindingsSarif(findings, fmt.Sprint(results["CheckmarxVersion"])), checkmarx.ReportsDirectory, utils)
+		if err != nil {
+			return errors.Wrap(err, "failed to write sarif of the findings")
+		}
+		reports = append(reports, piperutils.Path{Name: "Checkmarx Findings SARIF Report", Target: findingsSarif})
 	}
 
 	// create toolrecord
@@ -416,7 +435,7 @@ func verifyCxProjectCompliance(ctx context.Context, config checkmarxExecuteScanO
 
 	if config.VulnerabilityThresholdEnabled {
 		insecure, insecureResults, neutralResults = enforceThresholds(config, results)
-		scanReport := checkmarx.CreateCustomReport(results, insecureResults, neutralResults)
+		scanReport := checkmarx.CreateCustomReport(results, findings, insecureResults, neutralResults)
 
 		if insecure && config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
 			log.Entry().Debug("Creating/updating GitHub issue with check results")
@@ -19,6 +19,7 @@ import (
 	"github.com/bmatcuk/doublestar"
 
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/google/go-github/v45/github"
@@ -299,10 +300,11 @@ type checkmarxExecuteScanUtilsMock struct {
 	errorOnWriteFile      bool
 	errorOnPathMatch      bool
 	workspace             string
+	reports               *mock.FilesMock
 }
 
 func newCheckmarxExecuteScanUtilsMock() *checkmarxExecuteScanUtilsMock {
-	return &checkmarxExecuteScanUtilsMock{}
+	return &checkmarxExecuteScanUtilsMock{reports: &mock.FilesMock{}}
 }
 
 func (c *checkmarxExecuteScanUtilsMock) GetWorkspace() string {
@@ -323,8 +325,15 @@ func (c *checkmarxExecuteScanUtilsMock) WriteFile(filename string, data []byte,
 	return os.WriteFile(filename, data, perm)
 }
 
+func (c *checkmarxExecuteScanUtilsMock) FileWrite(path string, content []byte, perm os.FileMode) error {
+	if c.errorOnWriteFile {
+		return fmt.Errorf("error on WriteFile")
+	}
+	return c.reports.FileWrite(path, content, perm)
+}
+
 func (c *checkmarxExecuteScanUtilsMock) MkdirAll(path string, perm os.FileMode) error {
-	return os.MkdirAll(path, perm)
+	return c.reports.MkdirAll(path, perm)
 }
 
 func (c *checkmarxExecuteScanUtilsMock) FileInfoHeader(fi os.FileInfo) (*zip.FileHeader, error) {
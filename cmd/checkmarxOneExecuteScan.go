@@ -34,6 +34,7 @@ type checkmarxOneExecuteScanUtils interface {
 	Stat(name string) (os.FileInfo, error)
 	Open(name string) (*os.File, error)
 	WriteFile(filename string, data []byte, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
 	MkdirAll(path string, perm os.FileMode) error
 	PathMatch(pattern, name string) (bool, error)
 	GetWorkspace() string
@@ -42,15 +43,16 @@ type checkmarxOneExecuteScanUtils interface {
 }
 
 type checkmarxOneExecuteScanHelper struct {
-	ctx     context.Context
-	config  checkmarxOneExecuteScanOptions
-	sys     checkmarxOne.System
-	influx  *checkmarxOneExecuteScanInflux
-	utils   checkmarxOneExecuteScanUtils
-	Project *checkmarxOne.Project
-	Group   *checkmarxOne.Group
-	App     *checkmarxOne.Application
-	reports []piperutils.Path
+	ctx      context.Context
+	config   checkmarxOneExecuteScanOptions
+	sys      checkmarxOne.System
+	influx   *checkmarxOneExecuteScanInflux
+	utils    checkmarxOneExecuteScanUtils
+	Project  *checkmarxOne.Project
+	Group    *checkmarxOne.Group
+	App      *checkmarxOne.Application
+	reports  []piperutils.Path
+	findings []reporting.Finding
 }
 
 type checkmarxOneExecuteScanUtilsBundle struct {
@@ -209,7 +211,7 @@ func Authenticate(config checkmarxOneExecuteScanOptions, influx *checkmarxOneExe
 
 	utils := newcheckmarxOneExecuteScanUtilsBundle("./", ghClient)
 
-	return checkmarxOneExecuteScanHelper{ctx, config, sys, influx, utils, nil, nil, nil, []piperutils.Path{}}, nil
+	return checkmarxOneExecuteScanHelper{ctx, config, sys, influx, utils, nil, nil, nil, []piperutils.Path{}, nil}, nil
 }
 
 func (c *checkmarxOneExecuteScanHelper) GetProjectByName() (*checkmarxOne.Project, error) {
@@ -540,7 +542,7 @@ func (c *checkmarxOneExecuteScanHelper) CheckCompliance(scan *checkmarxOne.Scan,
 
 	if c.config.VulnerabilityThresholdEnabled {
 		insecure, insecureResults, neutralResults = c.enforceThresholds(detailedResults)
-		scanReport := checkmarxOne.CreateCustomReport(detailedResults, insecureResults, neutralResults)
+		scanReport := checkmarxOne.CreateCustomReport(detailedResults, c.findings, insecureResults, neutralResults)
 
 		if insecure && c.config.CreateResultIssue && len(c.config.GithubToken) > 0 && len(c.config.GithubAPIURL) > 0 && len(c.config.Owner) > 0 && len(c.config.Repository) > 0 {
 			log.Entry().Debug("Creating/updating GitHub issue with check results")
@@ -597,7 +599,7 @@ func (c *checkmarxOneExecuteScanHelper) GetReportPDF(scan *checkmarxOne.Scan) er
 	return nil
 }
 
-func (c *checkmarxOneExecuteScanHelper) GetReportSARIF(scan *checkmarxOne.Scan, scanmeta *checkmarxOne.ScanMetadata, results *[]checkmarxOne.ScanResult) error {
+func (c *checkmarxOneExecuteScanHelper) GetReportSARIF(scan *checkmarxOne.Scan, scanmeta *checkmarxOne.ScanMetadata, results *[]checkmarxOne.ScanResult, detailedResults *map[string]interface{}) error {
 	if c.config.ConvertToSarif {
 		log.Entry().Info("Calling conversion to SARIF function.")
 		sarif, err := checkmarxOne.ConvertCxJSONToSarif(c.sys, c.config.ServerURL, results, scanmeta, scan)
@@ -609,6 +611,19 @@ func (c *checkmarxOneExecuteScanHelper) GetReportSARIF(scan *checkmarxOne.Scan,
 			return fmt.Errorf("Failed to write SARIF: %s", err)
 		}
 		c.reports = append(c.reports, paths...)
+
+		findingsSarif, err := reporting.WriteFindingsSarif(checkmarxOne.CreateFindingsSarif(c.findings, fmt.Sprint((*detailedResults)["ToolVersion"])), checkmarxOne.ReportsDirectory, c.utils)
+		if err != nil {
+			return fmt.Errorf("Failed to write SARIF of the findings: %s", err)
+		}
+		c.reports = append(c.reports, piperutils.Path{Name: "CheckmarxOne Findings SARIF Report", Target: findingsSarif})
+	}
+	return nil
+}
+
+func (c *checkmarxOneExecuteScanHelper) WriteFindings() error {
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "checkmarxOneExecuteScan", Scanner: "checkmarxOne", Project: c.config.ProjectName, Findings: c.findings}, c.utils); err != nil {
+		return fmt.Errorf("Failed to write findings: %s", err)
 	}
 	return nil
 }
@@ -673,10 +688,15 @@ func (c *checkmarxOneExecuteScanHelper) ParseResults(scan *checkmarxOne.Scan) (m
 	if err != nil {
 		log.Entry().WithError(err).Warnf("Failed to get PDF report")
 	}
-	err = c.GetReportSARIF(scan, &scanmeta, &results)
+	c.findings = checkmarxOne.Findings(results)
+	err = c.GetReportSARIF(scan, &scanmeta, &results, &detailedResults)
 	if err != nil {
 		log.Entry().WithError(err).Warnf("Failed to get SARIF report")
 	}
+	err = c.WriteFindings()
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to write findings")
+	}
 	err = c.GetHeaderReportJSON(&detailedResults)
 	if err != nil {
 		log.Entry().WithError(err).Warnf("Failed to generate JSON Header report")
@@ -1223,6 +1243,10 @@ func (c *checkmarxOneExecuteScanUtilsBundle) WriteFile(filename string, data []b
 	return os.WriteFile(filename, data, perm)
 }
 
+func (c *checkmarxOneExecuteScanUtilsBundle) FileWrite(path string, content []byte, perm os.FileMode) error {
+	return os.WriteFile(path, content, perm)
+}
+
 func (c *checkmarxOneExecuteScanUtilsBundle) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+type configLintCommandOptions struct {
+	openFile        func(s string, t map[string]string) (io.ReadCloser, error)
+	fileExists      func(filename string) (bool, error)
+	stepMetadata    func() map[string]config.StepData
+	stageConfigFile string
+	outputFile      string
+	failOnWarning   bool
+}
+
+var configLintOptions configLintCommandOptions
+
+// ConfigLintCommand is the entry command for validating the project configuration and defaults against the step metadata
+func ConfigLintCommand() *cobra.Command {
+	configLintOptions.openFile = config.OpenPiperFile
+	configLintOptions.fileExists = piperutils.FileExists
+	configLintOptions.stepMetadata = GetAllStepMetadata
+	var configLintCmd = &cobra.Command{
+		Use:   "configLint",
+		Short: "Validates the project configuration and defaults against the metadata of all steps.",
+		Long: `Validates the project configuration as well as the defaults against the metadata of all steps.
+It reports unknown keys, keys configured in a section where they are not allowed, wrong types, deprecated aliases,
+values which are not part of the possible values and stages which are not part of the stage conditions.
+The findings are written in SARIF format.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			err := configLint(utils)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("Linting the configuration failed")
+			}
+		},
+	}
+	addConfigLintFlags(configLintCmd)
+	return configLintCmd
+}
+
+func configLint(utils piperutils.FileUtils) error {
+	linter := config.NewConfigLinter(configLintOptions.stepMetadata(), lintStageNames())
+
+	findings := []config.LintFinding{}
+	files := append([]string{getProjectConfigFile(GeneralConfig.CustomConfig)}, GeneralConfig.DefaultConfig...)
+	for _, file := range files {
+		content, err := readLintFile(file)
+		if err != nil {
+			return err
+		}
+		if content == nil {
+			log.Entry().Infof("Skipping '%v', it does not exist", file)
+			continue
+		}
+		log.Entry().Infof("Linting '%v'", file)
+		findings = append(findings, linter.Lint(file, content)...)
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, finding := range findings {
+		entry := log.Entry().WithField("rule", finding.RuleID)
+		message := fmt.Sprintf("%v:%v:%v: %v", finding.File, finding.Line, finding.Column, finding.Message)
+		if finding.Level == config.LintLevelError {
+			errorCount++
+			entry.Error(message)
+		} else {
+			warningCount++
+			entry.Warn(message)
+		}
+	}
+
+	sarif, err := json.MarshalIndent(lintFindingsToSarif(findings), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF report")
+	}
+	log.Entry().Infof("Writing SARIF report %v", configLintOptions.outputFile)
+	if err := utils.FileWrite(configLintOptions.outputFile, sarif, 0666); err != nil {
+		return fmt.Errorf("error writing file '%v': %w", configLintOptions.outputFile, err)
+	}
+
+	if errorCount > 0 || (configLintOptions.failOnWarning && warningCount > 0) {
+		return errors.Errorf("the configuration contains %v error(s) and %v warning(s)", errorCount, warningCount)
+	}
+	log.Entry().Infof("The configuration contains %v error(s) and %v warning(s)", errorCount, warningCount)
+	return nil
+}
+
+// readLintFile returns the content of the file, nil if a local file does not exist
+func readLintFile(file string) ([]byte, error) {
+	if exists, _ := configLintOptions.fileExists(file); !exists && !isRemoteFile(file) {
+		return nil, nil
+	}
+	f, err := configLintOptions.openFile(file, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: open configuration file '%v' failed", file)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: reading configuration file '%v' failed", file)
+	}
+	return content, nil
+}
+
+func isRemoteFile(file string) bool {
+	u, err := url.Parse(file)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// lintStageNames returns the names of the stages of the stage conditions, nil if they are not available
+func lintStageNames() []string {
+	stageConfigFile, err := configLintOptions.openFile(configLintOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Stage configuration file '%v' not available, stage names are not checked", configLintOptions.stageConfigFile)
+		return nil
+	}
+	runConfigV1 := &config.RunConfigV1{RunConfig: config.RunConfig{StageConfigFile: stageConfigFile}}
+	if err := runConfigV1.LoadConditionsV1(); err != nil {
+		log.Entry().WithError(err).Warnf("Stage configuration file '%v' is invalid, stage names are not checked", configLintOptions.stageConfigFile)
+		return nil
+	}
+	stageNames := []string{}
+	for _, stage := range runConfigV1.PipelineConfig.Spec.Stages {
+		stageNames = append(stageNames, stage.DisplayName, stage.Name)
+	}
+	return stageNames
+}
+
+func lintFindingsToSarif(findings []config.LintFinding) format.SARIF {
+	ruleIDs := []string{}
+	for ruleID := range config.LintRules {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+
+	rules := []format.SarifRule{}
+	ruleIndex := map[string]int{}
+	for i, ruleID := range ruleIDs {
+		ruleIndex[ruleID] = i
+		rules = append(rules, format.SarifRule{
+			ID:               ruleID,
+			Name:             ruleID,
+			ShortDescription: &format.Message{Text: config.LintRules[ruleID]},
+		})
+	}
+
+	results := []format.Results{}
+	for _, finding := range findings {
+		results = append(results, format.Results{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndex[finding.RuleID],
+			Level:     finding.Level,
+			Message:   &format.Message{Text: finding.Message},
+			Locations: []format.Location{{
+				PhysicalLocation: format.PhysicalLocation{
+					ArtifactLocation: format.ArtifactLocation{URI: finding.File},
+					Region:           format.Region{StartLine: finding.Line, StartColumn: finding.Column},
+				},
+			}},
+		})
+	}
+
+	return format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []format.Runs{{
+			Results: results,
+			Tool: format.Tool{
+				Driver: format.Driver{
+					Name:           "piper configLint",
+					Version:        GitTag,
+					InformationUri: "https://github.com/SAP/jenkins-library",
+					Rules:          rules,
+				},
+			},
+		}},
+	}
+}
+
+func addConfigLintFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&configLintOptions.stageConfigFile, "stageConfig", ".resources/piper-stage-config.yml",
+		"Default config of piper pipeline stages")
+	cmd.Flags().StringVar(&configLintOptions.outputFile, "outputFile", "piper-config-lint.sarif", "Defines the file path of the SARIF report")
+	cmd.Flags().BoolVar(&configLintOptions.failOnWarning, "failOnWarning", false, "Fail also if the configuration contains warnings")
+}
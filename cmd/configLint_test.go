@@ -0,0 +1,127 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func configLintOpenFileMock(files map[string]string) func(name string, tokens map[string]string) (io.ReadCloser, error) {
+	return func(name string, tokens map[string]string) (io.ReadCloser, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("file '%v' not found", name)
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+func TestConfigLintCommand(t *testing.T) {
+	cmd := ConfigLintCommand()
+
+	t.Run("Flags", func(t *testing.T) {
+		assert.NotNil(t, cmd.Flags().Lookup("stageConfig"))
+		assert.NotNil(t, cmd.Flags().Lookup("outputFile"))
+		assert.NotNil(t, cmd.Flags().Lookup("failOnWarning"))
+	})
+}
+
+func TestConfigLint(t *testing.T) {
+	stepMetadata := func() map[string]config.StepData {
+		return map[string]config.StepData{
+			"step1": {Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+				{Name: "p1", Type: "bool", Scope: []string{"GENERAL", "STEPS", "STAGES"}},
+			}}}},
+		}
+	}
+	setup := func(files map[string]string) {
+		configLintOptions.openFile = configLintOpenFileMock(files)
+		configLintOptions.fileExists = func(filename string) (bool, error) {
+			_, ok := files[filename]
+			return ok, nil
+		}
+		configLintOptions.stepMetadata = stepMetadata
+		configLintOptions.stageConfigFile = "stage-config.yml"
+		configLintOptions.outputFile = "lint.sarif"
+		configLintOptions.failOnWarning = false
+		GeneralConfig.CustomConfig = ".pipeline/config.yml"
+		GeneralConfig.DefaultConfig = []string{".pipeline/defaults.yaml"}
+	}
+	defer func() { configLintOptions = configLintCommandOptions{} }()
+
+	stageConfig := `
+spec:
+  stages:
+    - name: build
+      displayName: Build
+`
+
+	t.Run("success", func(t *testing.T) {
+		setup(map[string]string{
+			"stage-config.yml":     stageConfig,
+			".pipeline/config.yml": "general:\n  p1: true\nstages:\n  Build:\n    p1: true\n",
+		})
+		utils := &mock.FilesMock{}
+
+		assert.NoError(t, configLint(utils))
+
+		content, err := utils.FileRead("lint.sarif")
+		if assert.NoError(t, err) {
+			var sarif format.SARIF
+			assert.NoError(t, json.Unmarshal(content, &sarif))
+			assert.Equal(t, "2.1.0", sarif.Version)
+			assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, len(config.LintRules))
+			assert.Empty(t, sarif.Runs[0].Results)
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		setup(map[string]string{
+			"stage-config.yml":        stageConfig,
+			".pipeline/config.yml":    "general:\n  p2: true\n",
+			".pipeline/defaults.yaml": "stages:\n  Test:\n    p1: true\n",
+		})
+		utils := &mock.FilesMock{}
+
+		assert.NoError(t, configLint(utils))
+
+		content, err := utils.FileRead("lint.sarif")
+		if assert.NoError(t, err) {
+			var sarif format.SARIF
+			assert.NoError(t, json.Unmarshal(content, &sarif))
+			if assert.Len(t, sarif.Runs[0].Results, 2) {
+				result := sarif.Runs[0].Results[0]
+				assert.Equal(t, config.LintRuleUnknownKey, result.RuleID)
+				assert.Equal(t, "warning", result.Level)
+				assert.Equal(t, ".pipeline/config.yml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+				assert.Equal(t, 2, result.Locations[0].PhysicalLocation.Region.StartLine)
+				assert.Equal(t, config.LintRuleUnknownStage, sarif.Runs[0].Results[1].RuleID)
+				assert.Equal(t, ".pipeline/defaults.yaml", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+			}
+		}
+
+		configLintOptions.failOnWarning = true
+		assert.EqualError(t, configLint(utils), "the configuration contains 0 error(s) and 2 warning(s)")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		setup(map[string]string{
+			".pipeline/config.yml": "steps:\n  step1:\n    p1: 'yes'\n",
+		})
+		utils := &mock.FilesMock{}
+
+		assert.EqualError(t, configLint(utils), "the configuration contains 1 error(s) and 0 warning(s)")
+		assert.True(t, utils.HasWrittenFile("lint.sarif"))
+	})
+}
@@ -1,41 +1,65 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/contrast"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-github/v45/github"
 )
 
 type contrastExecuteScanUtils interface {
 	command.ExecRunner
 	piperutils.FileUtils
+	GetIssueService() *github.IssuesService
+	GetSearchService() *github.SearchService
 }
 
 type contrastExecuteScanUtilsBundle struct {
 	*command.Command
 	*piperutils.Files
+	issues *github.IssuesService
+	search *github.SearchService
 }
 
-func newContrastExecuteScanUtils() contrastExecuteScanUtils {
+func (c *contrastExecuteScanUtilsBundle) GetIssueService() *github.IssuesService {
+	return c.issues
+}
+
+func (c *contrastExecuteScanUtilsBundle) GetSearchService() *github.SearchService {
+	return c.search
+}
+
+func newContrastExecuteScanUtils(client *github.Client) contrastExecuteScanUtils {
 	utils := contrastExecuteScanUtilsBundle{
 		Command: &command.Command{},
 		Files:   &piperutils.Files{},
 	}
+	if client != nil {
+		utils.issues = client.Issues
+		utils.search = client.Search
+	}
 	utils.Stdout(log.Writer())
 	utils.Stderr(log.Writer())
 	return &utils
 }
 
 func contrastExecuteScan(config contrastExecuteScanOptions, telemetryData *telemetry.CustomData) {
-	utils := newContrastExecuteScanUtils()
+	ctx, ghClient, err := piperGithub.NewClientBuilder(config.GithubToken, config.GithubAPIURL).Build()
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to get GitHub client")
+	}
+	utils := newContrastExecuteScanUtils(ghClient)
 
-	reports, err := runContrastExecuteScan(&config, telemetryData, utils)
+	reports, err := runContrastExecuteScan(ctx, &config, telemetryData, utils)
 	piperutils.PersistReportsAndLinks("contrastExecuteScan", "./", utils, reports, nil)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
@@ -65,7 +89,7 @@ func validateConfigs(config *contrastExecuteScanOptions) error {
 	return nil
 }
 
-func runContrastExecuteScan(config *contrastExecuteScanOptions, telemetryData *telemetry.CustomData, utils contrastExecuteScanUtils) (reports []piperutils.Path, err error) {
+func runContrastExecuteScan(ctx context.Context, config *contrastExecuteScanOptions, telemetryData *telemetry.CustomData, utils contrastExecuteScanUtils) (reports []piperutils.Path, err error) {
 	err = validateConfigs(config)
 	if err != nil {
 		log.Entry().Errorf("config is invalid: %v", err)
@@ -82,7 +106,7 @@ func runContrastExecuteScan(config *contrastExecuteScanOptions, telemetryData *t
 		return nil, err
 	}
 
-	findings, err := contrastInstance.GetVulnerabilities()
+	findings, vulnerabilities, err := contrastInstance.GetVulnerabilities()
 	if err != nil {
 		log.Entry().Errorf("error while getting vulns")
 		return nil, err
@@ -100,11 +124,28 @@ func runContrastExecuteScan(config *contrastExecuteScanOptions, telemetryData *t
 	}
 	reports = append(reports, paths...)
 
+	scanFindings := make([]reporting.Finding, 0, len(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		scanFindings = append(scanFindings, vulnerability.ToFinding())
+	}
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "contrastExecuteScan", Scanner: "contrast", Project: appInfo.Name, Findings: scanFindings}, utils); err != nil {
+		log.Entry().WithError(err).Warning("failed to write findings")
+	}
+
+	paths, err = contrast.WriteCustomReports(contrast.CreateCustomReport(appInfo, scanFindings), contrast.CreateSarif(scanFindings), "./", utils)
+	if err != nil {
+		log.Entry().WithError(err).Warning("failed to write reports")
+	}
+	reports = append(reports, paths...)
+
 	if config.CheckForCompliance {
 		for _, results := range findings {
 			if results.ClassificationName == "Audit All" {
 				unaudited := results.Total - results.Audited
 				if unaudited > config.VulnerabilityThresholdTotal {
+					if err := createContrastResultIssues(ctx, config, utils, scanFindings); err != nil {
+						log.Entry().WithError(err).Warning("failed to upload scan results into GitHub")
+					}
 					msg := fmt.Sprintf("Your application %v in organization %v is not compliant. Total unaudited issues are %v which is greater than the VulnerabilityThresholdTotal count %v",
 						config.ApplicationID, config.OrganizationID, unaudited, config.VulnerabilityThresholdTotal)
 					return reports, fmt.Errorf(msg)
@@ -123,6 +164,23 @@ func runContrastExecuteScan(config *contrastExecuteScanOptions, telemetryData *t
 	return reports, nil
 }
 
+// createContrastResultIssues creates or updates a GitHub issue per open finding if the creation of result issues is activated
+func createContrastResultIssues(ctx context.Context, config *contrastExecuteScanOptions, utils contrastExecuteScanUtils, findings []reporting.Finding) error {
+	if !config.CreateResultIssue || len(config.GithubToken) == 0 || len(config.GithubAPIURL) == 0 || len(config.Owner) == 0 || len(config.Repository) == 0 {
+		return nil
+	}
+	log.Entry().Debug("Creating/updating GitHub issues with scan results")
+	issueDetails := reporting.FindingIssues(reporting.OpenFindings(findings))
+	gh := reporting.GitHub{
+		Owner:         &config.Owner,
+		Repository:    &config.Repository,
+		Assignees:     &config.Assignees,
+		IssueService:  utils.GetIssueService(),
+		SearchService: utils.GetSearchService(),
+	}
+	return gh.UploadMultipleReports(ctx, &issueDetails)
+}
+
 func getApplicationUrls(config *contrastExecuteScanOptions) (string, string) {
 	appURL := fmt.Sprintf("%s/api/v4/organizations/%s/applications/%s", config.Server, config.OrganizationID, config.ApplicationID)
 	guiURL := fmt.Sprintf("%s/Contrast/static/ng/index.html#/%s/applications/%s", config.Server, config.OrganizationID, config.ApplicationID)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-github/v45/github"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,6 +22,14 @@ func newContrastExecuteScanTestsUtils() contrastExecuteScanMockUtils {
 	return utils
 }
 
+func (c contrastExecuteScanMockUtils) GetIssueService() *github.IssuesService {
+	return nil
+}
+
+func (c contrastExecuteScanMockUtils) GetSearchService() *github.SearchService {
+	return nil
+}
+
 func TestGetAuth(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		config := &contrastExecuteScanOptions{
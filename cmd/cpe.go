@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+)
+
+type cpeCommandOptions struct {
+	output       io.Writer
+	stepMetadata func() map[string]config.StepData
+}
+
+var cpeOptions cpeCommandOptions
+
+// CpeCommand is the entry command for inspecting the commonPipelineEnvironment and the journal of its changes
+func CpeCommand() *cobra.Command {
+	cpeOptions.output = os.Stdout
+	cpeOptions.stepMetadata = GetAllStepMetadata
+	var cpeCmd = &cobra.Command{
+		Use:   "cpe",
+		Short: "Inspects the commonPipelineEnvironment and the history of its changes.",
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+		},
+	}
+
+	cpeCmd.AddCommand(&cobra.Command{
+		Use:   "history [key]",
+		Short: "Lists the recorded changes of all keys or of a single key including the stage and step which wrote them.",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			key := ""
+			if len(args) > 0 {
+				key = args[0]
+			}
+			if err := cpeHistory(key); err != nil {
+				log.Entry().WithError(err).Fatal("Reading the history of the commonPipelineEnvironment failed")
+			}
+		},
+	})
+
+	cpeCmd.AddCommand(&cobra.Command{
+		Use:   "diff <fromStep> [toStep]",
+		Short: "Shows the keys which changed between the last writes of two steps.",
+		Long: `Replays the journal of the commonPipelineEnvironment up to the last change written by each step and compares the resulting values.
+If the second step is omitted, the current state is used.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			toStep := ""
+			if len(args) > 1 {
+				toStep = args[1]
+			}
+			if err := cpeDiff(args[0], toStep); err != nil {
+				log.Entry().WithError(err).Fatal("Comparing the commonPipelineEnvironment failed")
+			}
+		},
+	})
+
+	cpeCmd.AddCommand(&cobra.Command{
+		Use:   "schema",
+		Short: "Prints the keys of the commonPipelineEnvironment declared as outputs in the step metadata together with their types.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := cpeSchema(); err != nil {
+				log.Entry().WithError(err).Fatal("Printing the schema of the commonPipelineEnvironment failed")
+			}
+		},
+	})
+	return cpeCmd
+}
+
+func readCPEJournal() (piperenv.Journal, error) {
+	return piperenv.ReadJournal(filepath.Join(GeneralConfig.EnvRootPath, piperenv.CommonPipelineEnvironment))
+}
+
+func cpeHistory(key string) error {
+	journal, err := readCPEJournal()
+	if err != nil {
+		return err
+	}
+	history := journal.History(key)
+	if len(history) == 0 {
+		fmt.Fprintln(cpeOptions.output, "no changes recorded")
+		return nil
+	}
+	for _, entry := range history {
+		origin := entry.Step
+		if len(entry.Stage) > 0 {
+			origin = entry.Stage + "/" + entry.Step
+		}
+		if len(entry.Error) > 0 {
+			fmt.Fprintf(cpeOptions.output, "%v %v %v: %v rejected: %v\n", entry.Time.Format(time.RFC3339), origin, entry.Key, entry.Value, entry.Error)
+		} else if entry.Overwritten {
+			fmt.Fprintf(cpeOptions.output, "%v %v %v: %v -> %v\n", entry.Time.Format(time.RFC3339), origin, entry.Key, entry.Previous, entry.Value)
+		} else {
+			fmt.Fprintf(cpeOptions.output, "%v %v %v: %v\n", entry.Time.Format(time.RFC3339), origin, entry.Key, entry.Value)
+		}
+	}
+	return nil
+}
+
+func cpeDiff(fromStep, toStep string) error {
+	journal, err := readCPEJournal()
+	if err != nil {
+		return err
+	}
+	from, err := journal.StateAfter(fromStep)
+	if err != nil {
+		return err
+	}
+	to := journal.State()
+	if len(toStep) > 0 {
+		if to, err = journal.StateAfter(toStep); err != nil {
+			return err
+		}
+	}
+
+	changes := piperenv.DiffStates(from, to)
+	if len(changes) == 0 {
+		fmt.Fprintln(cpeOptions.output, "no differences")
+		return nil
+	}
+	for _, change := range changes {
+		switch {
+		case change.Added:
+			fmt.Fprintf(cpeOptions.output, "+ %v: %v\n", change.Key, change.To)
+		case change.Removed:
+			fmt.Fprintf(cpeOptions.output, "- %v: %v\n", change.Key, change.From)
+		default:
+			fmt.Fprintf(cpeOptions.output, "~ %v: %v -> %v\n", change.Key, change.From, change.To)
+		}
+	}
+	return nil
+}
+
+func cpeSchema() error {
+	schema, err := json.MarshalIndent(config.CPESchema(cpeOptions.stepMetadata()), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	_, err = fmt.Fprintln(cpeOptions.output, string(schema))
+	return err
+}
@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareCPEJournal(t *testing.T) {
+	GeneralConfig.EnvRootPath = t.TempDir()
+	defer piperenv.SetJournalContext("", "")
+
+	piperenv.SetJournalContext("Build", "artifactPrepareVersion")
+	require.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "artifactVersion", "1.0.0"))
+	piperenv.SetJournalContext("Build", "mavenBuild")
+	require.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "custom/buildSettingsInfo", "{}"))
+	piperenv.SetJournalContext("Release", "helmExecute")
+	require.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "artifactVersion", "1.0.1"))
+}
+
+func TestCpeHistory(t *testing.T) {
+	defer func() { GeneralConfig.EnvRootPath = "" }()
+	output := &bytes.Buffer{}
+	cpeOptions.output = output
+
+	t.Run("without journal", func(t *testing.T) {
+		GeneralConfig.EnvRootPath = t.TempDir()
+		output.Reset()
+		assert.NoError(t, cpeHistory(""))
+		assert.Equal(t, "no changes recorded\n", output.String())
+	})
+
+	t.Run("history of a key", func(t *testing.T) {
+		prepareCPEJournal(t)
+		output.Reset()
+		assert.NoError(t, cpeHistory("artifactVersion"))
+		lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		assert.Contains(t, string(lines[0]), " Build/artifactPrepareVersion artifactVersion: 1.0.0")
+		assert.Contains(t, string(lines[1]), " Release/helmExecute artifactVersion: 1.0.0 -> 1.0.1")
+	})
+
+	t.Run("rejected value", func(t *testing.T) {
+		prepareCPEJournal(t)
+		schema := piperenv.CPESchema{}
+		schema.AddKey("custom/isChangeInDevelopment", "bool", "isChangeInDevelopment", false)
+		piperenv.SetCPESchema(schema)
+		defer piperenv.SetCPESchema(nil)
+		assert.Error(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "custom/isChangeInDevelopment", "yes"))
+		output.Reset()
+
+		assert.NoError(t, cpeHistory("custom/isChangeInDevelopment"))
+		assert.Contains(t, output.String(), " custom/isChangeInDevelopment: yes rejected: value of type string does not match type bool")
+	})
+}
+
+func TestCpeDiff(t *testing.T) {
+	defer func() { GeneralConfig.EnvRootPath = "" }()
+	output := &bytes.Buffer{}
+	cpeOptions.output = output
+	prepareCPEJournal(t)
+
+	t.Run("between two steps", func(t *testing.T) {
+		output.Reset()
+		assert.NoError(t, cpeDiff("artifactPrepareVersion", "mavenBuild"))
+		assert.Equal(t, "+ custom/buildSettingsInfo: {}\n", output.String())
+	})
+
+	t.Run("against current state", func(t *testing.T) {
+		output.Reset()
+		assert.NoError(t, cpeDiff("mavenBuild", ""))
+		assert.Equal(t, "~ artifactVersion: 1.0.0 -> 1.0.1\n", output.String())
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		output.Reset()
+		assert.NoError(t, cpeDiff("helmExecute", ""))
+		assert.Equal(t, "no differences\n", output.String())
+	})
+
+	t.Run("unknown step", func(t *testing.T) {
+		assert.EqualError(t, cpeDiff("kanikoExecute", ""), "no changes of step 'kanikoExecute' recorded in the journal")
+	})
+}
+
+func TestCpeSchema(t *testing.T) {
+	output := &bytes.Buffer{}
+	cpeOptions.output = output
+	cpeOptions.stepMetadata = func() map[string]config.StepData {
+		return map[string]config.StepData{"artifactPrepareVersion": {Spec: config.StepSpec{Outputs: config.StepOutputs{Resources: []config.StepResources{
+			{Name: "commonPipelineEnvironment", Type: "piperEnvironment", Parameters: []map[string]interface{}{{"name": "artifactVersion"}}},
+		}}}}}
+	}
+
+	assert.NoError(t, cpeSchema())
+	assert.JSONEq(t, `{"artifactVersion": {"type": "string", "steps": ["artifactPrepareVersion"]}}`, output.String())
+}
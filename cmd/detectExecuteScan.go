@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +14,7 @@ import (
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/SAP/jenkins-library/pkg/golang"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
@@ -645,76 +645,20 @@ func checkIfArgumentIsInScanProperties(config detectExecuteScanOptions, argument
 	return false
 }
 
-func createVulnerabilityReport(config detectExecuteScanOptions, vulns *bd.Vulnerabilities, influx *detectExecuteScanInflux, sys *blackduckSystem) reporting.ScanReport {
+func createVulnerabilityReport(config detectExecuteScanOptions, findings []reporting.Finding, sys *blackduckSystem) reporting.ScanReport {
 	versionName := getVersionName(config)
 	versionUrl, _ := sys.Client.GetProjectVersionLink(config.ProjectName, versionName)
-	scanReport := reporting.ScanReport{
-		ReportTitle: "BlackDuck Security Vulnerability Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "BlackDuck Project Name ", Details: config.ProjectName},
-			{Description: "BlackDuck Project Version ", Details: fmt.Sprintf("<a href='%v'>%v</a>", versionUrl, versionName)},
-		},
-		Overview: []reporting.OverviewRow{
-			{Description: "Total number of vulnerabilities ", Details: fmt.Sprint(influx.detect_data.fields.vulnerabilities)},
-			{Description: "Total number of Critical/High vulnerabilties ", Details: fmt.Sprint(influx.detect_data.fields.major_vulnerabilities)},
-		},
-		SuccessfulScan: influx.detect_data.fields.major_vulnerabilities == 0,
-		ReportTime:     time.Now(),
-	}
-
-	detailTable := reporting.ScanDetailTable{
-		NoRowsMessage: "No publicly known vulnerabilities detected",
-		Headers: []string{
-			"Vulnerability Name",
-			"Severity",
-			"Overall Score",
-			"Base Score",
-			"Component Name",
-			"Component Version",
-			"Description",
-			"Status",
-		},
-		WithCounter:   true,
-		CounterHeader: "Entry#",
-	}
-
-	var vulnItems []bd.Vulnerability
-	if vulns != nil {
-		vulnItems = vulns.Items
+	subheaders := []reporting.Subheader{
+		{Description: "BlackDuck Project Name ", Details: config.ProjectName},
+		{Description: "BlackDuck Project Version ", Details: fmt.Sprintf("<a href='%v'>%v</a>", versionUrl, versionName)},
 	}
-
-	sort.Slice(vulnItems, func(i, j int) bool {
-		return vulnItems[i].OverallScore > vulnItems[j].OverallScore
-	})
-
-	for _, vuln := range vulnItems {
-		row := reporting.ScanRow{}
-		row.AddColumn(vuln.VulnerabilityWithRemediation.VulnerabilityName, 0)
-		row.AddColumn(vuln.VulnerabilityWithRemediation.Severity, 0)
-
-		var scoreStyle reporting.ColumnStyle = reporting.Yellow
-		if isMajorVulnerability(vuln) {
-			scoreStyle = reporting.Red
-		}
-		if !isActiveVulnerability(vuln) {
-			scoreStyle = reporting.Grey
-		}
-		row.AddColumn(vuln.VulnerabilityWithRemediation.OverallScore, scoreStyle)
-		row.AddColumn(vuln.VulnerabilityWithRemediation.BaseScore, 0)
-		row.AddColumn(vuln.Name, 0)
-		row.AddColumn(vuln.Version, 0)
-		row.AddColumn(vuln.VulnerabilityWithRemediation.Description, 0)
-		row.AddColumn(vuln.VulnerabilityWithRemediation.RemediationStatus, 0)
-
-		detailTable.Rows = append(detailTable.Rows, row)
-	}
-
-	scanReport.DetailTable = detailTable
+	scanReport := reporting.CreateFindingsScanReport("BlackDuck Security Vulnerability Report", subheaders, findings, reporting.SeverityHigh)
+	scanReport.DetailTable.NoRowsMessage = "No publicly known vulnerabilities detected"
 	return scanReport
 }
 
 func isActiveVulnerability(v bd.Vulnerability) bool {
-	if v.Ignored {
+	if v.Ignored || v.Assessment != nil {
 		return false
 	}
 	switch v.VulnerabilityWithRemediation.RemediationStatus {
@@ -772,7 +716,8 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 
 	errorsOccured := []string{}
-	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys)
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys, *assessments)
 	if err != nil {
 		if config.GenerateReportsForEmptyProjects &&
 			strings.Contains(err.Error(), "No Components found for project version") {
@@ -782,10 +727,27 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 		}
 	}
 
+	projectVersion, _ := sys.Client.GetProjectVersion(config.ProjectName, config.Version)
+
+	var projectLink string
+	if projectVersion != nil {
+		projectLink = projectVersion.Href
+	}
+
+	findings := []reporting.Finding{}
+	if vulns != nil {
+		for _, vuln := range vulns.Items {
+			finding := vuln.ToFinding()
+			finding.ProjectName = config.ProjectName
+			finding.ProjectVersion = config.Version
+			finding.ProjectLink = projectLink
+			findings = append(findings, finding)
+		}
+	}
+
 	if config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
-		log.Entry().Debugf("Creating result issues for %v alert(s)", len(vulns.Items))
-		issueDetails := make([]reporting.IssueDetail, len(vulns.Items))
-		piperutils.CopyAtoB(vulns.Items, issueDetails)
+		log.Entry().Debugf("Creating result issues for %v alert(s)", len(findings))
+		issueDetails := reporting.FindingIssues(findings)
 		gh := reporting.GitHub{
 			Owner:         &config.Owner,
 			Repository:    &config.Repository,
@@ -798,20 +760,17 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 		}
 	}
 
-	projectVersion, err := sys.Client.GetProjectVersion(config.ProjectName, config.Version)
-
-	var projectLink string
-	if projectVersion != nil {
-		projectLink = projectVersion.Href
-	}
-
-	sarif := bd.CreateSarifResultFile(vulns, config.ProjectName, config.Version, projectLink)
+	sarif := bd.CreateSarifResultFile(findings)
 	paths, err := bd.WriteSarifFile(sarif, utils)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
 	}
 
-	scanReport := createVulnerabilityReport(config, vulns, influx, sys)
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "detectExecuteScan", Scanner: "blackduck", Project: config.ProjectName, Findings: findings}, utils); err != nil {
+		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+	}
+
+	scanReport := createVulnerabilityReport(config, findings, sys)
 	vulnerabilityReportPaths, err := bd.WriteVulnerabilityReports(scanReport, utils)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -851,7 +810,7 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	return nil
 }
 
-func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem) (*bd.Vulnerabilities, error) {
+func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem, assessments []format.Assessment) (*bd.Vulnerabilities, error) {
 	detectVersionName := getVersionName(config)
 	components, err := sys.Client.GetComponents(config.ProjectName, detectVersionName)
 	if err != nil {
@@ -872,18 +831,22 @@ func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *d
 	majorVulns := 0
 	activeVulns := 0
 	for index, vuln := range vulns.Items {
-		if isActiveVulnerability(vuln) {
-			activeVulns++
-			if isMajorVulnerability(vuln) {
-				majorVulns++
-			}
-		}
 		component := componentLookup[fmt.Sprintf(keyFormat, vuln.Name, vuln.Version)]
 		if component != nil && len(component.Name) > 0 {
 			vulns.Items[index].Component = component
 		} else {
 			vulns.Items[index].Component = &bd.Component{Name: vuln.Name, Version: vuln.Version}
 		}
+		// assessed vulnerabilities are not counted, like the ones ignored in Black Duck
+		if vulns.Items[index].Assess(assessments) {
+			log.Entry().Debugf("Matched assessment with status %v and analysis %v to vulnerability %v of component %v", vulns.Items[index].Assessment.Status, vulns.Items[index].Assessment.Analysis, vuln.VulnerabilityName, vuln.Name)
+		}
+		if isActiveVulnerability(vulns.Items[index]) {
+			activeVulns++
+			if isMajorVulnerability(vulns.Items[index]) {
+				majorVulns++
+			}
+		}
 	}
 	influx.detect_data.fields.vulnerabilities = activeVulns
 	influx.detect_data.fields.major_vulnerabilities = majorVulns
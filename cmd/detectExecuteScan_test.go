@@ -16,6 +16,7 @@ import (
 
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -949,6 +950,14 @@ func TestIsActiveVulnerability(t *testing.T) {
 		}
 		assert.False(t, isActiveVulnerability(v))
 	})
+	t.Run("assessed", func(t *testing.T) {
+		// any matching assessment suppresses the vulnerability, also if it is still in process
+		v := bd.Vulnerability{
+			VulnerabilityWithRemediation: bd.VulnerabilityWithRemediation{RemediationStatus: "NEW"},
+			Assessment:                   &format.Assessment{Vulnerability: "CVE-2021-1", Status: format.InProcess},
+		}
+		assert.False(t, isActiveVulnerability(v))
+	})
 }
 
 func TestIsActivePolicyViolation(t *testing.T) {
@@ -979,7 +988,7 @@ func TestGetVulnerabilitiesWithComponents(t *testing.T) {
 		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
 		sys := newBlackduckMockSystem(config)
 
-		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys)
+		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys, nil)
 		assert.NoError(t, err)
 		vulnerabilitySpring := bd.Vulnerability{}
 		vulnerabilityLog4j1 := bd.Vulnerability{}
@@ -1009,4 +1018,20 @@ func TestGetVulnerabilitiesWithComponents(t *testing.T) {
 		assert.Equal(t, vulnerableComponentLog4j, vulnerabilityLog4j1.Component)
 		assert.Equal(t, vulnerableComponentLog4j, vulnerabilityLog4j2.Component)
 	})
+	t.Run("with assessments", func(t *testing.T) {
+		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
+		sys := newBlackduckMockSystem(config)
+		assessments := []format.Assessment{{Vulnerability: "BDSA-2020-4711", Status: format.NotRelevant, Analysis: format.NotUsed, Purls: []format.Purl{{Purl: "pkg:generic/Apache%20Log4j@4.5.16"}}}}
+
+		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys, assessments)
+		assert.NoError(t, err)
+		for _, v := range vulns.Items {
+			if v.VulnerabilityWithRemediation.VulnerabilityName == "BDSA-2020-4711" {
+				assert.Equal(t, &assessments[0], v.Assessment)
+				assert.False(t, isActiveVulnerability(v))
+			} else {
+				assert.Nil(t, v.Assessment)
+			}
+		}
+	})
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const maskedValue = "****"
+
+// RunStepDryRun prints the dry-run plan of the step without executing it.
+// Only steps which opt in via the metadata flag dryRunSimulation provide runStep, their execution is simulated while calls to executables and http requests are only recorded.
+// The recorded calls return empty results, thus the simulated step might fail early, in this case the plan only lists the calls up to the failure.
+func RunStepDryRun(stepName string, metadata *config.StepData, stepConfig interface{}, runStep func()) {
+	// the step handler is not executed, thus the secrets read while preparing the configuration are cleaned up here
+	defer config.RemoveVaultSecretFiles()
+	defer config.RevokeVaultLeases()
+
+	if runStep == nil {
+		log.Entry().Infof("Dry-run mode: step %v does not support simulating its execution, the planned commands are not available", stepName)
+		PrintDryRunPlan(stepName, metadata, stepConfig)
+		return
+	}
+
+	log.Entry().Info("Dry-run mode: external commands and http requests are only recorded")
+	command.SetDryRun(true)
+	piperhttp.SetDryRun(true)
+	defer command.SetDryRun(false)
+	defer piperhttp.SetDryRun(false)
+
+	// a fatal error of the step must not exit the process before the plan is printed
+	if !log.FatalRecoverable() {
+		log.SetFatalRecoverable(true)
+		defer log.SetFatalRecoverable(false)
+	}
+	err := log.RunRecoverable(runStep)
+
+	PrintDryRunPlan(stepName, metadata, stepConfig)
+	if err != nil {
+		log.Entry().WithError(err).Warn("Step failed in dry-run mode due to the empty results of the recorded calls, the plan ends at the failure")
+	}
+}
+
+// PrintDryRunPlan logs the resolved configuration, the names of the resolved secrets, the containers of a step and the commands and http requests recorded while simulating it.
+// Secrets are masked, in addition the log output is subject to the secret masking of log.RegisterSecret.
+func PrintDryRunPlan(stepName string, metadata *config.StepData, stepConfig interface{}) {
+	log.Entry().Infof("Dry-run plan for step %v (stage '%v')", stepName, GeneralConfig.StageName)
+
+	options, secrets, err := maskedStepOptions(metadata, stepConfig)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to resolve step options for dry-run plan")
+	} else {
+		log.Entry().Infof("Resolved options: %v", options)
+	}
+	if len(secrets) > 0 {
+		log.Entry().Infof("Resolved secrets: %v (values masked)", strings.Join(secrets, ", "))
+	}
+
+	for _, container := range metadata.Spec.Containers {
+		log.Entry().Infof("Container: %v (image: %v)", container.Name, container.Image)
+	}
+	for _, sidecar := range metadata.Spec.Sidecars {
+		log.Entry().Infof("Sidecar: %v (image: %v)", sidecar.Name, sidecar.Image)
+	}
+
+	for _, record := range command.DryRunRecords() {
+		if len(record.Dir) > 0 {
+			log.Entry().Infof("Command: %v (dir: %v)", record, record.Dir)
+			continue
+		}
+		log.Entry().Infof("Command: %v", record)
+	}
+	for _, record := range piperhttp.DryRunRecords() {
+		log.Entry().Infof("Request: %v", record)
+	}
+}
+
+// maskedStepOptions returns the step options as JSON with masked secret values as well as the names of the secrets which have a value
+func maskedStepOptions(metadata *config.StepData, stepConfig interface{}) (string, []string, error) {
+	optionsJSON, err := json.Marshal(stepConfig)
+	if err != nil {
+		return "", nil, err
+	}
+	options := map[string]interface{}{}
+	if err := json.Unmarshal(optionsJSON, &options); err != nil {
+		return "", nil, err
+	}
+	secrets := []string{}
+	for _, param := range metadata.Spec.Inputs.Parameters {
+		if _, ok := options[param.Name]; ok && param.Secret {
+			options[param.Name] = maskedValue
+			secrets = append(secrets, param.Name)
+		}
+	}
+	result, err := json.Marshal(options)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(result), secrets, nil
+}
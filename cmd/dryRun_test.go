@@ -0,0 +1,157 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+func TestMaskedStepOptions(t *testing.T) {
+	metadata := config.StepData{
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{Name: "username"},
+					{Name: "password", Secret: true},
+					{Name: "token", Secret: true},
+				},
+			},
+		},
+	}
+	options := struct {
+		Username string `json:"username,omitempty"`
+		Password string `json:"password,omitempty"`
+		Token    string `json:"token,omitempty"`
+	}{Username: "user", Password: "secret"}
+
+	result, secrets, err := maskedStepOptions(&metadata, options)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"****","username":"user"}`, result)
+	assert.Equal(t, []string{"password"}, secrets)
+}
+
+func TestPrintDryRunPlan(t *testing.T) {
+	command.SetDryRun(true)
+	piperhttp.SetDryRun(true)
+	defer command.SetDryRun(false)
+	defer piperhttp.SetDryRun(false)
+	stageName := GeneralConfig.StageName
+	GeneralConfig.StageName = "Build"
+	defer func() { GeneralConfig.StageName = stageName }()
+	_, hook := test.NewNullLogger()
+	log.RegisterHook(hook)
+	defer hook.Reset()
+
+	metadata := shellExecuteMetadata()
+	c := command.Command{}
+	assert.NoError(t, c.RunExecutable("echo", "hello"))
+
+	PrintDryRunPlan("shellExecute", &metadata, shellExecuteOptions{Sources: []string{"script.sh"}, GithubToken: "secret"})
+
+	messages := []string{}
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Contains(t, messages, "Dry-run plan for step shellExecute (stage 'Build')")
+	assert.Contains(t, messages, `Resolved options: {"githubToken":"****","sources":["script.sh"]}`)
+	assert.Contains(t, messages, "Resolved secrets: githubToken (values masked)")
+	assert.Contains(t, messages, "Command: echo hello")
+}
+
+func TestRunStepDryRun(t *testing.T) {
+	t.Run("without simulation", func(t *testing.T) {
+		_, hook := test.NewNullLogger()
+		log.RegisterHook(hook)
+		defer hook.Reset()
+
+		metadata := shellExecuteMetadata()
+		RunStepDryRun("shellExecute", &metadata, shellExecuteOptions{}, nil)
+
+		messages := []string{}
+		for _, entry := range hook.AllEntries() {
+			messages = append(messages, entry.Message)
+		}
+		assert.Contains(t, messages, "Dry-run mode: step shellExecute does not support simulating its execution, the planned commands are not available")
+		assert.Contains(t, messages, "Dry-run plan for step shellExecute (stage '"+GeneralConfig.StageName+"')")
+	})
+
+	t.Run("with simulation", func(t *testing.T) {
+		_, hook := test.NewNullLogger()
+		log.RegisterHook(hook)
+		defer hook.Reset()
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			t.Error("request must not be sent in dry-run mode")
+		}))
+		defer server.Close()
+
+		metadata := shellExecuteMetadata()
+		RunStepDryRun("shellExecute", &metadata, shellExecuteOptions{}, func() {
+			c := command.Command{}
+			assert.NoError(t, c.RunExecutable("mvn", "deploy"))
+			client := piperhttp.Client{}
+			_, err := client.SendRequest(http.MethodPut, server.URL+"/artifact", nil, nil, nil)
+			assert.NoError(t, err)
+			log.Entry().Fatal("unexpected empty response")
+		})
+
+		messages := []string{}
+		for _, entry := range hook.AllEntries() {
+			messages = append(messages, entry.Message)
+		}
+		assert.Contains(t, messages, "Command: mvn deploy")
+		assert.Contains(t, messages, "Request: PUT "+server.URL+"/artifact")
+		assert.Contains(t, messages, "Step failed in dry-run mode due to the empty results of the recorded calls, the plan ends at the failure")
+		assert.False(t, log.FatalRecoverable())
+		assert.Empty(t, command.DryRunRecords())
+	})
+}
+
+func TestDryRunStepCommand(t *testing.T) {
+	GeneralConfig.DryRun = true
+	defer func() { GeneralConfig.DryRun = false }()
+	stageName := GeneralConfig.StageName
+	GeneralConfig.StageName = "Build"
+	defer func() { GeneralConfig.StageName = stageName }()
+	_, hook := test.NewNullLogger()
+	log.RegisterHook(hook)
+	defer hook.Reset()
+	// the downloaded script is stored in the workspace
+	dir := t.TempDir()
+	oldCWD, _ := os.Getwd()
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(oldCWD) }()
+	script := filepath.Join(dir, "script.sh")
+	require.NoError(t, os.WriteFile(script, []byte("exit 1"), 0700))
+
+	cmd := ShellExecuteCommand()
+	require.NoError(t, cmd.Flags().Set("sources", "https://example.org/scripts/download.sh,"+script))
+	err := cmd.RunE(cmd, []string{})
+
+	assert.NoError(t, err)
+	messages := []string{}
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+		assert.NotEqual(t, "SUCCESS", entry.Message, "step must not be reported as executed")
+	}
+	assert.Contains(t, messages, "Dry-run plan for step shellExecute (stage 'Build')")
+	assert.Contains(t, messages, "Request: GET https://example.org/scripts/download.sh")
+	assert.Contains(t, messages, "Command: "+filepath.Join(".pipeline", "download.sh"))
+	assert.Contains(t, messages, "Command: "+script)
+	assert.NotContains(t, messages, "Step failed in dry-run mode due to the empty results of the recorded calls, the plan ends at the failure")
+	assert.Empty(t, command.DryRunRecords())
+	assert.Empty(t, piperhttp.DryRunRecords())
+}
@@ -242,7 +242,11 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 
 	if config.VerifyOnly {
 		log.Entry().Infof("Starting audit status check on project %v with version %v and project version ID %v", fortifyProjectName, fortifyProjectVersion, projectVersion.ID)
-		paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus)
+		findings, err := fetchFindings(sys, projectVersion, fortifyProjectName, utils)
+		if err != nil {
+			return reports, err
+		}
+		paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, findings, influx, auditStatus)
 		reports = append(reports, paths...)
 		return reports, err
 	}
@@ -310,6 +314,11 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 		return reports, err
 	}
 
+	findings, err := fetchFindings(sys, projectVersion, fortifyProjectName, utils)
+	if err != nil {
+		return reports, err
+	}
+
 	// SARIF conversion done after latest FPR is processed, but before the compliance is checked
 	if config.ConvertToSarif {
 		resultFilePath := fmt.Sprintf("%vtarget/result.fpr", config.ModulePath)
@@ -331,21 +340,40 @@ func runFortifyScan(ctx context.Context, config fortifyExecuteScanOptions, sys f
 			return reports, fmt.Errorf("failed to write gzip sarif")
 		}
 		reports = append(reports, paths...)
+
+		findingsSarif, err := reporting.WriteFindingsSarif(fortify.CreateFindingsSarif(findings), fortify.ReportsDirectory, utils)
+		if err != nil {
+			return reports, errors.Wrap(err, "failed to write sarif of the findings")
+		}
+		reports = append(reports, piperutils.Path{Name: "Fortify Findings SARIF Report", Target: findingsSarif})
 	}
 
 	log.Entry().Infof("Starting audit status check on project %v with version %v and project version ID %v", fortifyProjectName, fortifyProjectVersion, projectVersion.ID)
-	paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, influx, auditStatus)
+	paths, err := verifyFFProjectCompliance(ctx, config, utils, sys, project, projectVersion, filterSet, findings, influx, auditStatus)
 	reports = append(reports, paths...)
 	return reports, err
 }
 
+// fetchFindings converts the issues of the project version into findings which are the base of the reports, the SARIF file and the GitHub issues
+func fetchFindings(sys fortify.System, projectVersion *models.ProjectVersion, projectName string, utils fortifyUtils) ([]reporting.Finding, error) {
+	issues, err := sys.GetAllIssueDetails(projectVersion.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the issues of project version ID %v", projectVersion.ID)
+	}
+	findings := fortify.Findings(issues)
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "fortifyExecuteScan", Scanner: "fortify", Project: projectName, Findings: findings}, utils); err != nil {
+		log.Entry().WithError(err).Warning("failed to write findings")
+	}
+	return findings, nil
+}
+
 func classifyErrorOnLookup(err error) {
 	if strings.Contains(err.Error(), "connect: connection refused") || strings.Contains(err.Error(), "net/http: TLS handshake timeout") {
 		log.SetErrorCategory(log.ErrorService)
 	}
 }
 
-func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, sys fortify.System, project *models.Project, projectVersion *models.ProjectVersion, filterSet *models.FilterSet, influx *fortifyExecuteScanInflux, auditStatus map[string]string) ([]piperutils.Path, error) {
+func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, sys fortify.System, project *models.Project, projectVersion *models.ProjectVersion, filterSet *models.FilterSet, findings []reporting.Finding, influx *fortifyExecuteScanInflux, auditStatus map[string]string) ([]piperutils.Path, error) {
 	reports := []piperutils.Path{}
 	// Generate report
 	if config.Reporting {
@@ -371,13 +399,12 @@ func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOpt
 	log.Entry().Debugf("initial filter selector set: %v", issueFilterSelectorSet)
 
 	spotChecksCountByCategory := []fortify.SpotChecksAuditCount{}
-	numberOfViolations, issueGroups, err := analyseUnauditedIssues(config, sys, projectVersion, filterSet, issueFilterSelectorSet, influx, auditStatus, &spotChecksCountByCategory)
+	numberOfViolations, _, err := analyseUnauditedIssues(config, sys, projectVersion, filterSet, issueFilterSelectorSet, influx, auditStatus, &spotChecksCountByCategory)
 	if err != nil {
 		return reports, errors.Wrap(err, "failed to analyze unaudited issues")
 	}
-	numberOfSuspiciousExploitable, issueGroupsSuspiciousExploitable := analyseSuspiciousExploitable(config, sys, projectVersion, filterSet, issueFilterSelectorSet, influx, auditStatus)
+	numberOfSuspiciousExploitable, _ := analyseSuspiciousExploitable(config, sys, projectVersion, filterSet, issueFilterSelectorSet, influx, auditStatus)
 	numberOfViolations += numberOfSuspiciousExploitable
-	issueGroups = append(issueGroups, issueGroupsSuspiciousExploitable...)
 
 	log.Entry().Infof("Counted %v violations, details: %v", numberOfViolations, auditStatus)
 
@@ -388,7 +415,7 @@ func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOpt
 	influx.fortify_data.fields.violations = numberOfViolations
 
 	fortifyReportingData := prepareReportData(influx)
-	scanReport := fortify.CreateCustomReport(fortifyReportingData, issueGroups)
+	scanReport := fortify.CreateCustomReport(fortifyReportingData, findings)
 	paths, err := fortify.WriteCustomReports(scanReport)
 	if err != nil {
 		return reports, errors.Wrap(err, "failed to write custom reports")
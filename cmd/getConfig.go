@@ -26,6 +26,8 @@ type ConfigCommandOptions struct {
 	StepMetadata                  string // metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	StepName                      string
 	ContextConfig                 bool
+	Explain                       bool // if set: each value is annotated with the source it has been taken from
+	ResolveSecrets                bool // if set: secrets are read from the configured secret backend and are part of the output
 	OpenFile                      func(s string, t map[string]string) (io.ReadCloser, error)
 }
 
@@ -33,6 +35,8 @@ var configOptions ConfigCommandOptions
 
 func SetConfigOptions(c ConfigCommandOptions) {
 	configOptions.ContextConfig = c.ContextConfig
+	configOptions.Explain = c.Explain
+	configOptions.ResolveSecrets = c.ResolveSecrets
 	configOptions.OpenFile = c.OpenFile
 	configOptions.Output = c.Output
 	configOptions.OutputFile = c.OutputFile
@@ -137,6 +141,7 @@ func GetStageConfig() (config.StepConfig, error) {
 	}
 
 	defaultConfig := []io.ReadCloser{}
+	defaultNames := []string{}
 	for _, f := range GeneralConfig.DefaultConfig {
 		fc, err := configOptions.OpenFile(f, GeneralConfig.GitHubAccessTokens)
 		// only create error for non-default values
@@ -145,9 +150,14 @@ func GetStageConfig() (config.StepConfig, error) {
 		}
 		if err == nil {
 			defaultConfig = append(defaultConfig, fc)
+			defaultNames = append(defaultNames, f)
 		}
 	}
 
+	if configOptions.Explain {
+		myConfig.EnableSourceTracking(defaultNames)
+	}
+
 	return myConfig.GetStageConfig(GeneralConfig.ParametersJSON, customConfig, defaultConfig, GeneralConfig.IgnoreCustomDefaults, configOptions.StageConfigAcceptedParameters, GeneralConfig.StageName)
 }
 
@@ -196,6 +206,10 @@ func getConfig() (config.StepConfig, error) {
 		if err != nil {
 			return stepConfig, errors.Wrap(err, "defaults: retrieving step defaults failed")
 		}
+		defaultNames := make([]string, len(defaultConfig))
+		for i := range defaultConfig {
+			defaultNames[i] = "context defaults"
+		}
 
 		for _, f := range GeneralConfig.DefaultConfig {
 			fc, err := configOptions.OpenFile(f, GeneralConfig.GitHubAccessTokens)
@@ -205,9 +219,20 @@ func getConfig() (config.StepConfig, error) {
 			}
 			if err == nil {
 				defaultConfig = append(defaultConfig, fc)
+				defaultNames = append(defaultNames, f)
 			}
 		}
 
+		if configOptions.Explain {
+			myConfig.EnableSourceTracking(defaultNames)
+		}
+
+		myConfig.SetEnvRootPath(GeneralConfig.EnvRootPath)
+		// the configuration is printed, thus secrets are only resolved on request
+		if !configOptions.ResolveSecrets {
+			myConfig.DisableSecretResolution()
+		}
+
 		var flags map[string]interface{}
 
 		if configOptions.ContextConfig {
@@ -248,7 +273,12 @@ func GenerateConfig(formatter func(interface{}) (string, error)) error {
 		return err
 	}
 
-	myConfig, err := formatter(stepConfig.Config)
+	var output interface{} = stepConfig.Config
+	if configOptions.Explain {
+		output = explainConfig(stepConfig)
+	}
+
+	myConfig, err := formatter(output)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -276,7 +306,22 @@ func addConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&configOptions.StepMetadata, "stepMetadata", "", "Step metadata, passed as path to yaml")
 	cmd.Flags().StringVar(&configOptions.StepName, "stepName", "", "Step name, used to get step metadata if yaml path is not set")
 	cmd.Flags().BoolVar(&configOptions.ContextConfig, "contextConfig", false, "Defines if step context configuration should be loaded instead of step config")
+	cmd.Flags().BoolVar(&configOptions.Explain, "explain", false, "Annotates each configuration value with the source it has been taken from")
+	cmd.Flags().BoolVar(&configOptions.ResolveSecrets, "resolveSecrets", false, "Reads the secrets from the configured secret backend, they are contained in the output in plaintext")
+}
 
+type explainedValue struct {
+	Value  interface{}        `json:"value"`
+	Source config.ValueSource `json:"source"`
+}
+
+// explainConfig annotates each configuration value with its source
+func explainConfig(stepConfig config.StepConfig) map[string]explainedValue {
+	result := make(map[string]explainedValue, len(stepConfig.Config))
+	for key, value := range stepConfig.Config {
+		result[key] = explainedValue{Value: value, Source: stepConfig.Sources[key]}
+	}
+	return result
 }
 
 func defaultsAndFilters(metadata *config.StepData, stepName string) ([]io.ReadCloser, config.StepFilters, error) {
@@ -50,7 +50,7 @@ func TestConfigCommand(t *testing.T) {
 	})
 
 	t.Run("Optional flags", func(t *testing.T) {
-		exp := []string{"contextConfig", "output", "outputFile", "parametersJSON", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
+		exp := []string{"contextConfig", "explain", "output", "outputFile", "parametersJSON", "resolveSecrets", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
 		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
 	})
 
@@ -63,6 +63,23 @@ func TestConfigCommand(t *testing.T) {
 	})
 }
 
+func TestExplainConfig(t *testing.T) {
+	stepConfig := config.StepConfig{
+		Config: map[string]interface{}{"p1": "v1", "p2": true},
+		Sources: map[string]config.ValueSource{
+			"p1": {Layer: config.SourceProjectConfig, Section: "steps", Reference: "alias p1Alias"},
+			"p2": {Layer: config.SourceFlags},
+		},
+	}
+
+	result, err := config.GetJSON(explainConfig(stepConfig))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"p1": {"value": "v1", "source": {"layer": "projectConfig", "section": "steps", "reference": "alias p1Alias"}},
+		"p2": {"value": true, "source": {"layer": "flags"}}
+	}`, result)
+}
+
 func TestDefaultsAndFilters(t *testing.T) {
 	metadata := config.StepData{
 		Spec: config.StepSpec{
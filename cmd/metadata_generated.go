@@ -2,7 +2,10 @@
 
 package cmd
 
-import "github.com/SAP/jenkins-library/pkg/config"
+import (
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/spf13/cobra"
+)
 
 // GetStepMetadata return a map with all the step metadata mapped to their stepName
 func GetAllStepMetadata() map[string]config.StepData {
@@ -106,6 +109,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
 		"pythonBuild":                               pythonBuildMetadata(),
+		"securityGate":                              securityGateMetadata(),
 		"shellExecute":                              shellExecuteMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
 		"terraformExecute":                          terraformExecuteMetadata(),
@@ -122,3 +126,123 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"xsDeploy":                                  xsDeployMetadata(),
 	}
 }
+
+// GetAllStepCommands return a map with the command constructors of all steps mapped to their stepName
+func GetAllStepCommands() map[string]func() *cobra.Command {
+	return map[string]func() *cobra.Command{
+		"abapAddonAssemblyKitCheck":                 AbapAddonAssemblyKitCheckCommand,
+		"abapAddonAssemblyKitCheckCVs":              AbapAddonAssemblyKitCheckCVsCommand,
+		"abapAddonAssemblyKitCheckPV":               AbapAddonAssemblyKitCheckPVCommand,
+		"abapAddonAssemblyKitCreateTargetVector":    AbapAddonAssemblyKitCreateTargetVectorCommand,
+		"abapAddonAssemblyKitPublishTargetVector":   AbapAddonAssemblyKitPublishTargetVectorCommand,
+		"abapAddonAssemblyKitRegisterPackages":      AbapAddonAssemblyKitRegisterPackagesCommand,
+		"abapAddonAssemblyKitReleasePackages":       AbapAddonAssemblyKitReleasePackagesCommand,
+		"abapAddonAssemblyKitReserveNextPackages":   AbapAddonAssemblyKitReserveNextPackagesCommand,
+		"abapEnvironmentAssembleConfirm":            AbapEnvironmentAssembleConfirmCommand,
+		"abapEnvironmentAssemblePackages":           AbapEnvironmentAssemblePackagesCommand,
+		"abapEnvironmentBuild":                      AbapEnvironmentBuildCommand,
+		"abapEnvironmentCheckoutBranch":             AbapEnvironmentCheckoutBranchCommand,
+		"abapEnvironmentCloneGitRepo":               AbapEnvironmentCloneGitRepoCommand,
+		"abapEnvironmentCreateSystem":               AbapEnvironmentCreateSystemCommand,
+		"abapEnvironmentCreateTag":                  AbapEnvironmentCreateTagCommand,
+		"abapEnvironmentPullGitRepo":                AbapEnvironmentPullGitRepoCommand,
+		"abapEnvironmentPushATCSystemConfig":        AbapEnvironmentPushATCSystemConfigCommand,
+		"abapEnvironmentRunATCCheck":                AbapEnvironmentRunATCCheckCommand,
+		"abapEnvironmentRunAUnitTest":               AbapEnvironmentRunAUnitTestCommand,
+		"abapLandscapePortalUpdateAddOnProduct":     AbapLandscapePortalUpdateAddOnProductCommand,
+		"ansSendEvent":                              AnsSendEventCommand,
+		"apiKeyValueMapDownload":                    ApiKeyValueMapDownloadCommand,
+		"apiKeyValueMapUpload":                      ApiKeyValueMapUploadCommand,
+		"apiProviderDownload":                       ApiProviderDownloadCommand,
+		"apiProviderList":                           ApiProviderListCommand,
+		"apiProviderUpload":                         ApiProviderUploadCommand,
+		"apiProxyDownload":                          ApiProxyDownloadCommand,
+		"apiProxyList":                              ApiProxyListCommand,
+		"apiProxyUpload":                            ApiProxyUploadCommand,
+		"artifactPrepareVersion":                    ArtifactPrepareVersionCommand,
+		"ascAppUpload":                              AscAppUploadCommand,
+		"awsS3Upload":                               AwsS3UploadCommand,
+		"azureBlobUpload":                           AzureBlobUploadCommand,
+		"batsExecuteTests":                          BatsExecuteTestsCommand,
+		"checkmarxExecuteScan":                      CheckmarxExecuteScanCommand,
+		"checkmarxOneExecuteScan":                   CheckmarxOneExecuteScanCommand,
+		"cloudFoundryCreateService":                 CloudFoundryCreateServiceCommand,
+		"cloudFoundryCreateServiceKey":              CloudFoundryCreateServiceKeyCommand,
+		"cloudFoundryCreateSpace":                   CloudFoundryCreateSpaceCommand,
+		"cloudFoundryDeleteService":                 CloudFoundryDeleteServiceCommand,
+		"cloudFoundryDeleteSpace":                   CloudFoundryDeleteSpaceCommand,
+		"cloudFoundryDeploy":                        CloudFoundryDeployCommand,
+		"cnbBuild":                                  CnbBuildCommand,
+		"codeqlExecuteScan":                         CodeqlExecuteScanCommand,
+		"containerExecuteStructureTests":            ContainerExecuteStructureTestsCommand,
+		"containerSaveImage":                        ContainerSaveImageCommand,
+		"contrastExecuteScan":                       ContrastExecuteScanCommand,
+		"credentialdiggerScan":                      CredentialdiggerScanCommand,
+		"detectExecuteScan":                         DetectExecuteScanCommand,
+		"fortifyExecuteScan":                        FortifyExecuteScanCommand,
+		"gaugeExecuteTests":                         GaugeExecuteTestsCommand,
+		"gcpPublishEvent":                           GcpPublishEventCommand,
+		"gctsCloneRepository":                       GctsCloneRepositoryCommand,
+		"gctsCreateRepository":                      GctsCreateRepositoryCommand,
+		"gctsDeploy":                                GctsDeployCommand,
+		"gctsExecuteABAPQualityChecks":              GctsExecuteABAPQualityChecksCommand,
+		"gctsExecuteABAPUnitTests":                  GctsExecuteABAPUnitTestsCommand,
+		"gctsRollback":                              GctsRollbackCommand,
+		"githubCheckBranchProtection":               GithubCheckBranchProtectionCommand,
+		"githubCommentIssue":                        GithubCommentIssueCommand,
+		"githubCreateIssue":                         GithubCreateIssueCommand,
+		"githubCreatePullRequest":                   GithubCreatePullRequestCommand,
+		"githubPublishRelease":                      GithubPublishReleaseCommand,
+		"githubSetCommitStatus":                     GithubSetCommitStatusCommand,
+		"gitopsUpdateDeployment":                    GitopsUpdateDeploymentCommand,
+		"golangBuild":                               GolangBuildCommand,
+		"gradleExecuteBuild":                        GradleExecuteBuildCommand,
+		"hadolintExecute":                           HadolintExecuteCommand,
+		"helmExecute":                               HelmExecuteCommand,
+		"imagePushToRegistry":                       ImagePushToRegistryCommand,
+		"influxWriteData":                           InfluxWriteDataCommand,
+		"integrationArtifactDeploy":                 IntegrationArtifactDeployCommand,
+		"integrationArtifactDownload":               IntegrationArtifactDownloadCommand,
+		"integrationArtifactGetMplStatus":           IntegrationArtifactGetMplStatusCommand,
+		"integrationArtifactGetServiceEndpoint":     IntegrationArtifactGetServiceEndpointCommand,
+		"integrationArtifactResource":               IntegrationArtifactResourceCommand,
+		"integrationArtifactTransport":              IntegrationArtifactTransportCommand,
+		"integrationArtifactTriggerIntegrationTest": IntegrationArtifactTriggerIntegrationTestCommand,
+		"integrationArtifactUnDeploy":               IntegrationArtifactUnDeployCommand,
+		"integrationArtifactUpdateConfiguration":    IntegrationArtifactUpdateConfigurationCommand,
+		"integrationArtifactUpload":                 IntegrationArtifactUploadCommand,
+		"isChangeInDevelopment":                     IsChangeInDevelopmentCommand,
+		"jsonApplyPatch":                            JsonApplyPatchCommand,
+		"kanikoExecute":                             KanikoExecuteCommand,
+		"karmaExecuteTests":                         KarmaExecuteTestsCommand,
+		"kubernetesDeploy":                          KubernetesDeployCommand,
+		"malwareExecuteScan":                        MalwareExecuteScanCommand,
+		"mavenBuild":                                MavenBuildCommand,
+		"mavenExecute":                              MavenExecuteCommand,
+		"mavenExecuteIntegration":                   MavenExecuteIntegrationCommand,
+		"mavenExecuteStaticCodeChecks":              MavenExecuteStaticCodeChecksCommand,
+		"mtaBuild":                                  MtaBuildCommand,
+		"newmanExecute":                             NewmanExecuteCommand,
+		"nexusUpload":                               NexusUploadCommand,
+		"npmExecuteLint":                            NpmExecuteLintCommand,
+		"npmExecuteScripts":                         NpmExecuteScriptsCommand,
+		"pipelineCreateScanSummary":                 PipelineCreateScanSummaryCommand,
+		"protecodeExecuteScan":                      ProtecodeExecuteScanCommand,
+		"pythonBuild":                               PythonBuildCommand,
+		"securityGate":                              SecurityGateCommand,
+		"shellExecute":                              ShellExecuteCommand,
+		"sonarExecuteScan":                          SonarExecuteScanCommand,
+		"terraformExecute":                          TerraformExecuteCommand,
+		"tmsExport":                                 TmsExportCommand,
+		"tmsUpload":                                 TmsUploadCommand,
+		"transportRequestDocIDFromGit":              TransportRequestDocIDFromGitCommand,
+		"transportRequestReqIDFromGit":              TransportRequestReqIDFromGitCommand,
+		"transportRequestUploadCTS":                 TransportRequestUploadCTSCommand,
+		"transportRequestUploadRFC":                 TransportRequestUploadRFCCommand,
+		"transportRequestUploadSOLMAN":              TransportRequestUploadSOLMANCommand,
+		"uiVeri5ExecuteTests":                       UiVeri5ExecuteTestsCommand,
+		"vaultRotateSecretId":                       VaultRotateSecretIdCommand,
+		"whitesourceExecuteScan":                    WhitesourceExecuteScanCommand,
+		"xsDeploy":                                  XsDeployCommand,
+	}
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
@@ -13,8 +16,10 @@ import (
 )
 
 type pipelineCreateScanSummaryUtils interface {
+	FileExists(filename string) (bool, error)
 	FileRead(path string) ([]byte, error)
 	FileWrite(path string, content []byte, perm os.FileMode) error
+	WriteFile(path string, content []byte, perm os.FileMode) error
 	Glob(pattern string) (matches []string, err error)
 }
 
@@ -62,6 +67,16 @@ func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, tele
 	if len(config.PipelineLink) > 0 {
 		output = []byte(fmt.Sprintf("## Pipeline Source for Details\n\nAs listed results might be incomplete, it is crucial that you check the detailed [pipeline](%v) status.\n\n", config.PipelineLink))
 	}
+
+	findingsReport, err := createFindingsSummary(config, utils)
+	if err != nil {
+		return err
+	}
+	if findingsReport != nil && ((config.FailedOnly && !findingsReport.SuccessfulScan) || !config.FailedOnly) {
+		mdReport, _ := findingsReport.ToMarkdown()
+		output = append(output, mdReport...)
+	}
+
 	for _, scanReport := range scanReports {
 		if (config.FailedOnly && !scanReport.SuccessfulScan) || !config.FailedOnly {
 			mdReport, _ := scanReport.ToMarkdown()
@@ -76,3 +91,93 @@ func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, tele
 
 	return nil
 }
+
+// createFindingsSummary consolidates the findings of all scan steps and writes them as HTML and JSON report
+func createFindingsSummary(config *pipelineCreateScanSummaryOptions, utils pipelineCreateScanSummaryUtils) (*reporting.ScanReport, error) {
+	findingsReports, err := reporting.ReadFindingsReports(utils)
+	if err != nil {
+		return nil, err
+	}
+	if len(findingsReports) == 0 {
+		log.Entry().Debugf("no findings available in %v", reporting.FindingsDirectory)
+		return nil, nil
+	}
+
+	var previous *reporting.FindingsSummary
+	if len(config.PreviousSummaryFilePath) > 0 {
+		if exists, _ := utils.FileExists(config.PreviousSummaryFilePath); exists {
+			content, err := utils.FileRead(config.PreviousSummaryFilePath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read previous summary %v", config.PreviousSummaryFilePath)
+			}
+			previous = &reporting.FindingsSummary{}
+			if err := json.Unmarshal(content, previous); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, errors.Wrapf(err, "failed to parse previous summary %v", config.PreviousSummaryFilePath)
+			}
+		} else {
+			log.Entry().Warnf("previous summary %v does not exist, no trend is calculated", config.PreviousSummaryFilePath)
+		}
+	}
+
+	summary := reporting.CreateFindingsSummary(findingsReports, previous)
+	jsonSummary, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal summary")
+	}
+	if err := utils.FileWrite(config.JSONOutputFilePath, jsonSummary, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to write %v", config.JSONOutputFilePath)
+	}
+
+	scanReport := reporting.CreateFindingsSummaryReport(summary, reporting.SeverityHigh)
+	htmlReport, _ := scanReport.ToHTML()
+	if err := utils.FileWrite(config.HtmlOutputFilePath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to write %v", config.HtmlOutputFilePath)
+	}
+	reports := []piperutils.Path{
+		{Target: config.HtmlOutputFilePath},
+		{Target: config.JSONOutputFilePath},
+	}
+
+	if len(config.VexOutputFilePath) > 0 {
+		if err := writeVEX(config, summary, utils); err != nil {
+			return nil, err
+		}
+		reports = append(reports, piperutils.Path{Target: config.VexOutputFilePath})
+	}
+	if err := piperutils.PersistReportsAndLinks("pipelineCreateScanSummary", "", utils, reports, nil); err != nil {
+		log.Entry().WithError(err).Warn("failed to persist reports")
+	}
+	return &scanReport, nil
+}
+
+// writeVEX exports the consolidated findings together with the assessments as OpenVEX document
+func writeVEX(config *pipelineCreateScanSummaryOptions, summary reporting.FindingsSummary, utils pipelineCreateScanSummaryUtils) error {
+	assessments := []format.Assessment{}
+	if exists, _ := utils.FileExists(config.AssessmentFile); exists {
+		content, err := utils.FileRead(config.AssessmentFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read assessment file %v", config.AssessmentFile)
+		}
+		read, err := format.ReadAssessments(io.NopCloser(bytes.NewReader(content)))
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to parse assessment file %v", config.AssessmentFile)
+		}
+		assessments = *read
+	}
+
+	document := reporting.CreateVEX(summary, assessments, config.VexAuthor, config.VexProductID)
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal VEX document")
+	}
+	if err := utils.FileWrite(config.VexOutputFilePath, content, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to write %v", config.VexOutputFilePath)
+	}
+	log.Entry().Infof("VEX document with %v statements written to %v", len(document.Statements), config.VexOutputFilePath)
+	return nil
+}
@@ -9,16 +9,23 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
-	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
 
 type pipelineCreateScanSummaryOptions struct {
-	FailedOnly     bool   `json:"failedOnly,omitempty"`
-	OutputFilePath string `json:"outputFilePath,omitempty"`
-	PipelineLink   string `json:"pipelineLink,omitempty"`
+	FailedOnly              bool   `json:"failedOnly,omitempty"`
+	OutputFilePath          string `json:"outputFilePath,omitempty"`
+	PipelineLink            string `json:"pipelineLink,omitempty"`
+	HtmlOutputFilePath      string `json:"htmlOutputFilePath,omitempty"`
+	JSONOutputFilePath      string `json:"jsonOutputFilePath,omitempty"`
+	PreviousSummaryFilePath string `json:"previousSummaryFilePath,omitempty"`
+	AssessmentFile          string `json:"assessmentFile,omitempty"`
+	VexOutputFilePath       string `json:"vexOutputFilePath,omitempty"`
+	VexAuthor               string `json:"vexAuthor,omitempty"`
+	VexProductID            string `json:"vexProductId,omitempty"`
 }
 
 // PipelineCreateScanSummaryCommand Collect scan result information anc create a summary report
@@ -29,7 +36,6 @@ func PipelineCreateScanSummaryCommand() *cobra.Command {
 	var stepConfig pipelineCreateScanSummaryOptions
 	var startTime time.Time
 	var logCollector *log.CollectorHook
-	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
 
 	var createPipelineCreateScanSummaryCmd = &cobra.Command{
@@ -37,7 +43,14 @@ func PipelineCreateScanSummaryCommand() *cobra.Command {
 		Short: "Collect scan result information anc create a summary report",
 		Long: `This step allows you to create a summary report of your scan results.
 
-It is for example used to create a markdown file which can be used to create a GitHub issue.`,
+It is for example used to create a markdown file which can be used to create a GitHub issue.
+
+The findings of the security scans are consolidated: the same vulnerability of a package or weakness in the code reported by several scanners is listed only once together with all scanners which reported it and the highest reported severity.
+The consolidated findings are written as HTML and JSON report and precede the reports of the single steps in the markdown file.
+If the JSON report of a previous run is provided, new and resolved findings as well as the change of the number of findings per severity are listed.
+
+Together with the assessments the consolidated findings are exported as [OpenVEX](https://github.com/openvex/spec) document, which states for each vulnerability whether the product is affected.
+The reports and the VEX document are archived as artifacts of the pipeline.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -60,8 +73,7 @@ It is for example used to create a markdown file which can be used to create a G
 				log.RegisterHook(&sentryHook)
 			}
 
-			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-				splunkClient = &splunk.Splunk{}
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 || len(GeneralConfig.HookConfig.TelemetryConfig.Sinks) > 0 {
 				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
 				log.RegisterHook(logCollector)
 			}
@@ -81,39 +93,40 @@ It is for example used to create a markdown file which can be used to create a G
 
 			return nil
 		},
-		Run: func(_ *cobra.Command, _ []string) {
+		RunE: func(_ *cobra.Command, _ []string) error {
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
+			if GeneralConfig.DryRun {
+				RunStepDryRun(STEP_NAME, &metadata, stepConfig, nil)
+				return nil
+			}
 			handler := func() {
+				PushCommonPipelineEnvironment()
+				PublishCommandTranscript(STEP_NAME)
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultLeases()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = GitCommit
+				tracing.EndStep(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 				telemetryClient.SetData(&stepTelemetryData)
 				telemetryClient.Send()
-				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.Dsn,
-						GeneralConfig.HookConfig.SplunkConfig.Token,
-						GeneralConfig.HookConfig.SplunkConfig.Index,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
-				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-					splunkClient.Initialize(GeneralConfig.CorrelationID,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
-						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
-						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
 			}
-			log.DeferExitHandler(handler)
+			removeExitHandler := log.DeferExitHandler(handler)
+			defer removeExitHandler()
 			defer handler()
+			StartStepTracing(STEP_NAME, startTime)
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME, GeneralConfig.HookConfig.PendoConfig.Token)
-			pipelineCreateScanSummary(stepConfig, &stepTelemetryData)
+			AddTelemetrySinks(telemetryClient, logCollector)
+			err := log.RunRecoverable(func() {
+				pipelineCreateScanSummary(stepConfig, &stepTelemetryData)
+			})
+			if err != nil {
+				return err
+			}
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
+			return nil
 		},
 	}
 
@@ -125,6 +138,13 @@ func addPipelineCreateScanSummaryFlags(cmd *cobra.Command, stepConfig *pipelineC
 	cmd.Flags().BoolVar(&stepConfig.FailedOnly, "failedOnly", false, "Defines if only failed scans should be included into the summary.")
 	cmd.Flags().StringVar(&stepConfig.OutputFilePath, "outputFilePath", `scanSummary.md`, "Defines the filepath to the target file which will be created by the step.")
 	cmd.Flags().StringVar(&stepConfig.PipelineLink, "pipelineLink", os.Getenv("PIPER_pipelineLink"), "Link to the pipeline (e.g. Jenkins job url) for reference in the scan summary.")
+	cmd.Flags().StringVar(&stepConfig.HtmlOutputFilePath, "htmlOutputFilePath", `scanSummary.html`, "Defines the filepath to the HTML report of the consolidated findings which will be created by the step.")
+	cmd.Flags().StringVar(&stepConfig.JSONOutputFilePath, "jsonOutputFilePath", `scanSummary.json`, "Defines the filepath to the JSON report of the consolidated findings which will be created by the step.")
+	cmd.Flags().StringVar(&stepConfig.PreviousSummaryFilePath, "previousSummaryFilePath", os.Getenv("PIPER_previousSummaryFilePath"), "Defines the filepath to the JSON report of the consolidated findings of a previous run, e.g. of the last release, in order to list new and resolved findings.")
+	cmd.Flags().StringVar(&stepConfig.AssessmentFile, "assessmentFile", `hs-assessments.yaml`, "Defines the filepath to the assessments, either in the format of the assessment YAML file or an OpenVEX or CycloneDX VEX document. They are included in the VEX document.")
+	cmd.Flags().StringVar(&stepConfig.VexOutputFilePath, "vexOutputFilePath", `vex.openvex.json`, "Defines the filepath to the OpenVEX document which will be created by the step. No document is created if the value is empty.")
+	cmd.Flags().StringVar(&stepConfig.VexAuthor, "vexAuthor", `unknown`, "Author of the VEX statements, usually the organization which releases the product.")
+	cmd.Flags().StringVar(&stepConfig.VexProductID, "vexProductId", os.Getenv("PIPER_vexProductId"), "Identifier of the released product, e.g. its package URL. If set, the vulnerable packages are listed as its subcomponents in the VEX statements.")
 
 }
 
@@ -166,6 +186,69 @@ func pipelineCreateScanSummaryMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_pipelineLink"),
 					},
+					{
+						Name:        "htmlOutputFilePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `scanSummary.html`,
+					},
+					{
+						Name:        "jsonOutputFilePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `scanSummary.json`,
+					},
+					{
+						Name:        "previousSummaryFilePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_previousSummaryFilePath"),
+					},
+					{
+						Name:        "assessmentFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `hs-assessments.yaml`,
+					},
+					{
+						Name:        "vexOutputFilePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `vex.openvex.json`,
+					},
+					{
+						Name:        "vexAuthor",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `unknown`,
+					},
+					{
+						Name:        "vexProductId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_vexProductId"),
+					},
 				},
 			},
 		},
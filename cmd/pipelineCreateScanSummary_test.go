@@ -4,10 +4,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,6 +97,110 @@ func TestRunPipelineCreateScanSummary(t *testing.T) {
 		assert.Contains(t, fileContentString, "https://test.com/link")
 	})
 
+	t.Run("success - consolidated findings", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:          "scanSummary.md",
+			HtmlOutputFilePath:      "scanSummary.html",
+			JSONOutputFilePath:      "scanSummary.json",
+			PreviousSummaryFilePath: "previous/scanSummary.json",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step1.json", []byte(`{"title":"Title Scan 1"}`))
+		utils.AddFile(".pipeline/stepReports/findings/whitesourceExecuteScan_1.json", []byte(`{"scanner":"whitesource","findings":[{"scanner":"whitesource","type":"vulnerability","id":"CVE-2021-44228","cve":"CVE-2021-44228","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1","severity":"high","status":"open"}]}`))
+		utils.AddFile(".pipeline/stepReports/findings/detectExecuteScan_1.json", []byte(`{"scanner":"blackduck","findings":[{"scanner":"blackduck","type":"vulnerability","id":"BDSA-2021-3665","cve":"CVE-2021-44228","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1","severity":"critical","status":"open"}]}`))
+		utils.AddFile("previous/scanSummary.json", []byte(`{"findings":[{"fingerprint":"CVE-2020-1","id":"CVE-2020-1","severity":"high","status":"open"}],"counts":{"high":1}}`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		assert.Contains(t, string(fileContent), "Consolidated Security Findings")
+		assert.Contains(t, string(fileContent), "blackduck, whitesource")
+		assert.Contains(t, string(fileContent), "Title Scan 1")
+		assert.True(t, utils.HasWrittenFile("scanSummary.html"))
+		jsonContent, _ := utils.FileRead("scanSummary.json")
+		var summary reporting.FindingsSummary
+		if assert.NoError(t, json.Unmarshal(jsonContent, &summary)) {
+			assert.Len(t, summary.Findings, 1)
+			assert.True(t, summary.Findings[0].New)
+			assert.Equal(t, reporting.SeverityCritical, summary.Findings[0].Severity)
+			assert.Equal(t, 1, summary.Trend["critical"])
+			assert.Equal(t, -1, summary.Trend["high"])
+			assert.Len(t, summary.Resolved, 1)
+		}
+	})
+
+	t.Run("success - VEX document", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:     "scanSummary.md",
+			HtmlOutputFilePath: "scanSummary.html",
+			JSONOutputFilePath: "scanSummary.json",
+			AssessmentFile:     "hs-assessments.yaml",
+			VexOutputFilePath:  "vex.openvex.json",
+			VexAuthor:          "ACME",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/findings/whitesourceExecuteScan_1.json", []byte(`{"scanner":"whitesource","findings":[{"scanner":"whitesource","type":"vulnerability","id":"CVE-2021-44228","cve":"CVE-2021-44228","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1","severity":"high","status":"open"},{"scanner":"whitesource","type":"vulnerability","id":"CVE-2022-1","cve":"CVE-2022-1","purl":"pkg:npm/lodash@4.17.20","severity":"low","status":"notAffected"}]}`))
+		utils.AddFile("hs-assessments.yaml", []byte("ignore:\n  - vulnerability: CVE-2022-1\n    status: notRelevant\n    analysis: notUsed\n    purls:\n      - purl: pkg:npm/lodash@4.17.20\n"))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		vexContent, _ := utils.FileRead("vex.openvex.json")
+		var document format.OpenVEX
+		if assert.NoError(t, json.Unmarshal(vexContent, &document)) {
+			assert.Equal(t, "ACME", document.Author)
+			if assert.Len(t, document.Statements, 2) {
+				assert.Equal(t, format.VEXAffected, document.Statements[0].Status)
+				assert.Equal(t, format.VEXNotAffected, document.Statements[1].Status)
+				assert.Equal(t, format.VEXVulnerableCodeNotInExecutePath, document.Statements[1].Justification)
+			}
+		}
+		reportsContent, _ := utils.FileRead("pipelineCreateScanSummary_reports.json")
+		assert.Contains(t, string(reportsContent), "vex.openvex.json")
+	})
+
+	t.Run("error - invalid assessment file", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:    "scanSummary.md",
+			AssessmentFile:    "vex.json",
+			VexOutputFilePath: "vex.openvex.json",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/findings/whitesourceExecuteScan_1.json", []byte(`{"scanner":"whitesource","findings":[]}`))
+		utils.AddFile("vex.json", []byte(`{"@context":"https://openvex.dev/ns/v0.2.0","statements":{}}`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse assessment file vex.json")
+	})
+
+	t.Run("error - invalid previous summary", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath:          "scanSummary.md",
+			PreviousSummaryFilePath: "previous/scanSummary.json",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/findings/whitesourceExecuteScan_1.json", []byte(`{"scanner":"whitesource","findings":[]}`))
+		utils.AddFile("previous/scanSummary.json", []byte(`{`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse previous summary previous/scanSummary.json")
+	})
+
 	t.Run("error - read file", func(t *testing.T) {
 		t.Skip()
 		//ToDo
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,11 +11,21 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperenv/remote"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -24,11 +36,17 @@ type GeneralConfigOptions struct {
 	CorrelationID        string
 	CustomConfig         string
 	GitHubTokens         []string // list of entries in form of <server>:<token> to allow token authentication for downloading config / defaults
+	GitLabToken          string   // token for GitLab API requests which are not possible with the job token, e.g. reading job logs
 	DefaultConfig        []string //ordered list of Piper default configurations. Can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	IgnoreCustomDefaults bool
 	ParametersJSON       string
 	EnvRootPath          string
+	CPEBackend           string
+	CPEEncryptionKey     string // secret for the sensitive values of the commonPipelineEnvironment, the same for all steps of a pipeline run
 	NoTelemetry          bool
+	DryRun               bool
+	RecordHTTP           string
+	ReplayHTTP           string
 	StageName            string
 	StepConfigJSON       string
 	StepMetadata         string //metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
@@ -51,10 +69,12 @@ type GeneralConfigOptions struct {
 
 // HookConfiguration contains the configuration for supported hooks, so far Sentry and Splunk are supported.
 type HookConfiguration struct {
-	SentryConfig SentryConfiguration `json:"sentry,omitempty"`
-	SplunkConfig SplunkConfiguration `json:"splunk,omitempty"`
-	PendoConfig  PendoConfiguration  `json:"pendo,omitempty"`
-	OIDCConfig   OIDCConfiguration   `json:"oidc,omitempty"`
+	SentryConfig        SentryConfiguration        `json:"sentry,omitempty"`
+	SplunkConfig        SplunkConfiguration        `json:"splunk,omitempty"`
+	PendoConfig         PendoConfiguration         `json:"pendo,omitempty"`
+	OIDCConfig          OIDCConfiguration          `json:"oidc,omitempty"`
+	OpenTelemetryConfig OpenTelemetryConfiguration `json:"openTelemetry,omitempty"`
+	TelemetryConfig     TelemetryConfiguration     `json:"telemetry,omitempty"`
 }
 
 // SentryConfiguration defines the configuration options for the Sentry logging system
@@ -73,6 +93,40 @@ type SplunkConfiguration struct {
 	ProdCriblIndex    string `json:"prodCriblIndex,omitempty"`
 }
 
+// OpenTelemetryConfiguration defines the exporter for the traces of the step runs
+type OpenTelemetryConfiguration struct {
+	Exporter string `json:"exporter,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+	File     string `json:"file,omitempty"`
+}
+
+// TelemetryConfiguration defines additional destinations for the telemetry data of the step runs
+type TelemetryConfiguration struct {
+	Sinks []telemetry.SinkConfig `json:"sinks,omitempty"`
+}
+
+// CommandExecutionConfiguration defines timeouts, retries and resource limits for the executables called by the steps
+type CommandExecutionConfiguration struct {
+	Timeout         string   `json:"timeout,omitempty"`
+	Retries         int      `json:"retries,omitempty"`
+	RetryBackoff    string   `json:"retryBackoff,omitempty"`
+	RetryCategories []string `json:"retryCategories,omitempty"`
+	RetryPatterns   []string `json:"retryPatterns,omitempty"`
+	MaxOutputSize   string   `json:"maxOutputSize,omitempty"`
+	MemoryLimit     string   `json:"memoryLimit,omitempty"`
+	CPULimit        float64  `json:"cpuLimit,omitempty"`
+}
+
+// HTTPPolicyConfiguration defines rate limits and circuit breaking for the HTTP requests of the steps
+type HTTPPolicyConfiguration struct {
+	RateLimit               float64 `json:"rateLimit,omitempty"`
+	RateBurst               int     `json:"rateBurst,omitempty"`
+	MaxRetryAfter           string  `json:"maxRetryAfter,omitempty"`
+	CircuitBreakerThreshold int     `json:"circuitBreakerThreshold,omitempty"`
+	CircuitBreakerTimeout   string  `json:"circuitBreakerTimeout,omitempty"`
+}
+
 type PendoConfiguration struct {
 	Token string `json:"token,omitempty"`
 }
@@ -190,6 +244,7 @@ func Execute() {
 	rootCmd.AddCommand(GaugeExecuteTestsCommand())
 	rootCmd.AddCommand(BatsExecuteTestsCommand())
 	rootCmd.AddCommand(PipelineCreateScanSummaryCommand())
+	rootCmd.AddCommand(SecurityGateCommand())
 	rootCmd.AddCommand(TransportRequestDocIDFromGitCommand())
 	rootCmd.AddCommand(TransportRequestReqIDFromGitCommand())
 	rootCmd.AddCommand(WritePipelineEnv())
@@ -197,6 +252,9 @@ func Execute() {
 	rootCmd.AddCommand(InfluxWriteDataCommand())
 	rootCmd.AddCommand(AbapEnvironmentRunAUnitTestCommand())
 	rootCmd.AddCommand(CheckStepActiveCommand())
+	rootCmd.AddCommand(RunCommand())
+	rootCmd.AddCommand(ConfigLintCommand())
+	rootCmd.AddCommand(CpeCommand())
 	rootCmd.AddCommand(GolangBuildCommand())
 	rootCmd.AddCommand(ShellExecuteCommand())
 	rootCmd.AddCommand(ApiProxyDownloadCommand())
@@ -236,15 +294,21 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CorrelationID, "correlationID", provider.BuildURL(), "ID for unique identification of a pipeline run")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CustomConfig, "customConfig", ".pipeline/config.yml", "Path to the pipeline configuration file")
 	rootCmd.PersistentFlags().StringSliceVar(&GeneralConfig.GitHubTokens, "gitHubTokens", AccessTokensFromEnvJSON(os.Getenv("PIPER_gitHubTokens")), "List of entries in form of <hostname>:<token> to allow GitHub token authentication for downloading config / defaults")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GitLabToken, "gitLabToken", os.Getenv("PIPER_gitLabToken"), "GitLab token for API requests of the orchestrator integration which are not possible with the job token, e.g. reading job logs")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CPEEncryptionKey, "cpeEncryptionKey", os.Getenv("PIPER_cpeEncryptionKey"), "Secret for the encryption of sensitive values in the commonPipelineEnvironment of a pipeline run, without secret the values are stored in plaintext")
 	rootCmd.PersistentFlags().StringSliceVar(&GeneralConfig.DefaultConfig, "defaultConfig", []string{".pipeline/defaults.yaml"}, "Default configurations, passed as path to yaml file")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.IgnoreCustomDefaults, "ignoreCustomDefaults", false, "Disables evaluation of the parameter 'customDefaults' in the pipeline configuration file")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.ParametersJSON, "parametersJSON", os.Getenv("PIPER_parametersJSON"), "Parameters to be considered in JSON format")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.EnvRootPath, "envRootPath", ".pipeline", "Root path to Piper pipeline shared environments")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CPEBackend, "cpeBackend", os.Getenv("PIPER_cpeBackend"), "Backend sharing the commonPipelineEnvironment across agents, e.g. s3://bucket/path, gs://bucket/path, oci://registry/repository:tag or file:///shared/path")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StageName, "stageName", "", "Name of the stage for which configuration should be included")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StepConfigJSON, "stepConfigJSON", os.Getenv("PIPER_stepConfigJSON"), "Step configuration in JSON format")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoTelemetry, "noTelemetry", false, "Disables telemetry reporting")
+	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.DryRun, "dryRun", false, "Resolves the step configuration and prints it as a plan without executing the step, steps supporting a simulation list the commands and http requests they would issue")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.RecordHTTP, "recordHttp", os.Getenv("PIPER_recordHttp"), "Records all http requests and their responses in the given cassette file, secrets are masked")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.ReplayHTTP, "replayHttp", os.Getenv("PIPER_replayHttp"), "Answers all http requests with the responses recorded in the given cassette file instead of sending them")
 	rootCmd.PersistentFlags().BoolVarP(&GeneralConfig.Verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full.")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full, json.")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultServerURL, "vaultServerUrl", "", "The Vault server which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultNamespace, "vaultNamespace", "", "The Vault namespace which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultPath, "vaultPath", "", "The path which should be used to fetch credentials")
@@ -335,12 +399,47 @@ func initStageName(outputToLog bool) {
 }
 
 // PrepareConfig reads step configuration from various sources and merges it (defaults, config file, flags, ...)
-func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName string, options interface{}, openFile func(s string, t map[string]string) (io.ReadCloser, error)) error {
+func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName string, options interface{}, openFile func(s string, t map[string]string) (io.ReadCloser, error)) (err error) {
+	defer func() {
+		// the step is not executed, thus its exit handler does not clean up the secrets read from vault
+		if err != nil {
+			config.RemoveVaultSecretFiles()
+			config.RevokeVaultLeases()
+		}
+	}()
 
 	log.SetFormatter(GeneralConfig.LogFormat)
 
 	initStageName(true)
 
+	if GeneralConfig.LogFormat == "json" {
+		// error is ignored since the provider falls back to an unknown orchestrator
+		provider, _ := orchestrator.GetOrchestratorConfigProvider(nil)
+		log.SetPipelineContext(GeneralConfig.StageName, provider.BuildID(), GeneralConfig.CorrelationID)
+	}
+
+	if len(GeneralConfig.GitLabToken) > 0 && orchestrator.DetectOrchestrator() == orchestrator.GitLab {
+		log.RegisterSecret(GeneralConfig.GitLabToken)
+		if _, err := orchestrator.GetOrchestratorConfigProvider(&orchestrator.Options{GitLabToken: GeneralConfig.GitLabToken}); err != nil {
+			log.Entry().WithError(err).Warn("failed to configure GitLab token of the orchestrator integration")
+		}
+	}
+
+	// values written to the commonPipelineEnvironment are checked against the outputs declared by all steps
+	// and changes are recorded in its journal together with the stage and step
+	piperenv.SetCPESchemaProvider(allStepsCPESchema)
+	piperenv.SetJournalContext(GeneralConfig.StageName, stepName)
+	piperenv.SetEncryptionKeyProvider(cpeEncryptionKey)
+
+	if err := PullCommonPipelineEnvironment(); err != nil {
+		return err
+	}
+
+	if err := setupHTTPCassette(GeneralConfig.RecordHTTP, GeneralConfig.ReplayHTTP); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
 	filters := metadata.GetParameterFilters()
 
 	// add telemetry parameter "collectTelemetryData" to ALL, GENERAL and PARAMETER filters
@@ -348,6 +447,18 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	filters.General = append(filters.General, "collectTelemetryData")
 	filters.Parameters = append(filters.Parameters, "collectTelemetryData")
 
+	// add "commandExecution" which applies to all executables called by the step
+	filters.All = append(filters.All, "commandExecution")
+	filters.General = append(filters.General, "commandExecution")
+	filters.Stages = append(filters.Stages, "commandExecution")
+	filters.Steps = append(filters.Steps, "commandExecution")
+
+	// add "httpPolicy" which applies to all HTTP requests of the step
+	filters.All = append(filters.All, "httpPolicy")
+	filters.General = append(filters.General, "httpPolicy")
+	filters.Stages = append(filters.Stages, "httpPolicy")
+	filters.Steps = append(filters.Steps, "httpPolicy")
+
 	envParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	reportingEnvParams := config.ReportingParameters.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	resourceParams := mergeResourceParameters(envParams, reportingEnvParams)
@@ -368,6 +479,8 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 		GeneralConfig.VaultToken = os.Getenv("PIPER_vaultToken")
 	}
 	myConfig.SetVaultCredentials(GeneralConfig.VaultRoleID, GeneralConfig.VaultRoleSecretID, GeneralConfig.VaultToken)
+	myConfig.EnableTemporaryVaultCredentials()
+	myConfig.SetEnvRootPath(GeneralConfig.EnvRootPath)
 
 	if len(GeneralConfig.StepConfigJSON) != 0 {
 		// ignore config & defaults in favor of passed stepConfigJSON
@@ -433,6 +546,21 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 
 	retrieveHookConfig(stepConfig.HookConfig, &GeneralConfig.HookConfig)
 
+	executionOptions, err := retrieveCommandExecutionConfig(stepConfig.Config["commandExecution"])
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	command.SetExecutionOptions(executionOptions)
+	enableCommandTranscript(stepName, stepConfig.Config["commandExecution"])
+
+	requestPolicy, err := retrieveHTTPPolicyConfig(stepConfig.Config["httpPolicy"])
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	piperhttp.SetDefaultRequestPolicy(requestPolicy)
+
 	if GeneralConfig.GCPJsonKeyFilePath == "" {
 		GeneralConfig.GCPJsonKeyFilePath, _ = stepConfig.Config["gcpJsonKeyFilePath"].(string)
 	}
@@ -448,6 +576,107 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	return nil
 }
 
+// StartStepTracing initializes the configured trace exporter and starts the span of the step run.
+// The span is ended by tracing.EndStep.
+func StartStepTracing(stepName string, startTime time.Time) {
+	otelConfig := GeneralConfig.HookConfig.OpenTelemetryConfig
+	err := tracing.Initialize(GitCommit, tracing.Configuration{
+		Exporter: otelConfig.Exporter,
+		Endpoint: otelConfig.Endpoint,
+		Insecure: otelConfig.Insecure,
+		File:     otelConfig.File,
+	})
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to initialize tracing")
+	}
+	tracing.StartStep(stepName, startTime)
+}
+
+// AddTelemetrySinks adds the Splunk hooks as well as the configured telemetry sinks to the telemetry client
+func AddTelemetrySinks(telemetryClient *telemetry.Telemetry, logCollector *log.CollectorHook) {
+	splunkConfig := GeneralConfig.HookConfig.SplunkConfig
+	if len(splunkConfig.Dsn) > 0 {
+		telemetryClient.AddSink(splunk.NewSink(GeneralConfig.CorrelationID, splunkConfig.Dsn, splunkConfig.Token, splunkConfig.Index, splunkConfig.SendLogs, logCollector))
+	}
+	if len(splunkConfig.ProdCriblEndpoint) > 0 {
+		telemetryClient.AddSink(splunk.NewSink(GeneralConfig.CorrelationID, splunkConfig.ProdCriblEndpoint, splunkConfig.ProdCriblToken, splunkConfig.ProdCriblIndex, splunkConfig.SendLogs, logCollector))
+	}
+
+	telemetry.RegisterSink("splunk", splunk.NewSinkFactory(GeneralConfig.CorrelationID, logCollector))
+	for _, sinkConfig := range GeneralConfig.HookConfig.TelemetryConfig.Sinks {
+		sink, err := telemetry.NewSink(sinkConfig)
+		if err != nil {
+			log.Entry().WithError(err).Warn("failed to add telemetry sink")
+			continue
+		}
+		telemetryClient.AddSink(sink)
+	}
+}
+
+// cpeEncryptionKey provides the secret for the sensitive values of the commonPipelineEnvironment.
+// Since the steps of a pipeline run may be executed in different containers or on different agents, the key of the run is passed
+// via PIPER_cpeEncryptionKey to all steps, it is generated by the Jenkins library respectively by piper run.
+// Without key the sensitive values are stored in plaintext.
+func cpeEncryptionKey() (string, error) {
+	if len(GeneralConfig.CPEEncryptionKey) > 0 {
+		log.RegisterSecret(GeneralConfig.CPEEncryptionKey)
+	}
+	return GeneralConfig.CPEEncryptionKey, nil
+}
+
+// generateCPEEncryptionKey creates a random secret for the sensitive values of the commonPipelineEnvironment of a pipeline run
+func generateCPEEncryptionKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.Wrap(err, "failed to generate encryption key for the commonPipelineEnvironment")
+	}
+	return hex.EncodeToString(key), nil
+}
+
+var (
+	stepsCPESchema     piperenv.CPESchema
+	stepsCPESchemaOnce sync.Once
+)
+
+// allStepsCPESchema returns the schema of the commonPipelineEnvironment declared by the outputs of all steps.
+// It is built only once per process, e.g. when several steps are executed via piper run.
+func allStepsCPESchema() piperenv.CPESchema {
+	stepsCPESchemaOnce.Do(func() { stepsCPESchema = config.CPESchema(GetAllStepMetadata()) })
+	return stepsCPESchema
+}
+
+var remoteCPE *piperenv.RemoteEnvironment
+
+// PullCommonPipelineEnvironment loads the commonPipelineEnvironment from the configured backend into the envRootPath
+func PullCommonPipelineEnvironment() error {
+	remoteCPE = nil
+	if len(GeneralConfig.CPEBackend) == 0 {
+		return nil
+	}
+	backend, err := remote.NewBackend(GeneralConfig.CPEBackend)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to create commonPipelineEnvironment backend")
+	}
+	remoteEnvironment := piperenv.NewRemoteEnvironment(backend, filepath.Join(GeneralConfig.EnvRootPath, piperenv.CommonPipelineEnvironment))
+	if err := remoteEnvironment.Pull(); err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		return err
+	}
+	remoteCPE = remoteEnvironment
+	return nil
+}
+
+// PushCommonPipelineEnvironment stores the values of the commonPipelineEnvironment changed by the step in the configured backend
+func PushCommonPipelineEnvironment() {
+	if remoteCPE == nil {
+		return
+	}
+	if err := remoteCPE.Push(); err != nil {
+		log.Entry().WithError(err).Error("failed to push commonPipelineEnvironment")
+	}
+}
+
 func retrieveHookConfig(source map[string]interface{}, target *HookConfiguration) {
 	if source != nil {
 		log.Entry().Debug("Retrieving hook configuration")
@@ -462,6 +691,121 @@ func retrieveHookConfig(source map[string]interface{}, target *HookConfiguration
 	}
 }
 
+// retrieveCommandExecutionConfig converts the commandExecution configuration, e.g. {timeout: 30m, retries: 2, memoryLimit: 4g}
+func retrieveCommandExecutionConfig(source interface{}) (command.ExecutionOptions, error) {
+	options := command.ExecutionOptions{}
+	if source == nil {
+		return options, nil
+	}
+	var target CommandExecutionConfiguration
+	b, err := json.Marshal(source)
+	if err != nil {
+		return options, errors.Wrap(err, "failed to marshal commandExecution configuration")
+	}
+	if err := json.Unmarshal(b, &target); err != nil {
+		return options, errors.Wrap(err, "invalid commandExecution configuration")
+	}
+
+	options.Retries = target.Retries
+	options.RetryCategories = target.RetryCategories
+	options.RetryPatterns = target.RetryPatterns
+	options.CPULimit = target.CPULimit
+	if len(target.Timeout) > 0 {
+		if options.Timeout, err = time.ParseDuration(target.Timeout); err != nil {
+			return options, errors.Wrapf(err, "invalid commandExecution timeout '%v'", target.Timeout)
+		}
+	}
+	if len(target.RetryBackoff) > 0 {
+		if options.RetryBackoff, err = time.ParseDuration(target.RetryBackoff); err != nil {
+			return options, errors.Wrapf(err, "invalid commandExecution retryBackoff '%v'", target.RetryBackoff)
+		}
+	}
+	if len(target.MaxOutputSize) > 0 {
+		if options.MaxOutputSize, err = units.RAMInBytes(target.MaxOutputSize); err != nil {
+			return options, errors.Wrapf(err, "invalid commandExecution maxOutputSize '%v'", target.MaxOutputSize)
+		}
+	}
+	if len(target.MemoryLimit) > 0 {
+		if options.MemoryLimit, err = units.RAMInBytes(target.MemoryLimit); err != nil {
+			return options, errors.Wrapf(err, "invalid commandExecution memoryLimit '%v'", target.MemoryLimit)
+		}
+	}
+	return options, nil
+}
+
+// setupHTTPCassette enables recording the http requests of the step into the cassette file or replaying them from it
+func setupHTTPCassette(recordFile, replayFile string) error {
+	switch {
+	case len(recordFile) > 0 && len(replayFile) > 0:
+		return fmt.Errorf("http requests can either be recorded or replayed, not both")
+	case len(recordFile) > 0:
+		log.Entry().Infof("Recording http requests in %v", recordFile)
+		return errors.Wrap(piperhttp.RecordCassette(recordFile), "failed to record http requests")
+	case len(replayFile) > 0:
+		log.Entry().Infof("Replaying http requests from %v", replayFile)
+		return errors.Wrap(piperhttp.ReplayCassette(replayFile), "failed to replay http requests")
+	default:
+		piperhttp.StopCassette()
+		return nil
+	}
+}
+
+// retrieveHTTPPolicyConfig converts the httpPolicy configuration, e.g. {rateLimit: 5, circuitBreakerThreshold: 10, circuitBreakerTimeout: 1m}
+func retrieveHTTPPolicyConfig(source interface{}) (piperhttp.RequestPolicy, error) {
+	policy := piperhttp.RequestPolicy{}
+	if source == nil {
+		return policy, nil
+	}
+	var target HTTPPolicyConfiguration
+	b, err := json.Marshal(source)
+	if err != nil {
+		return policy, errors.Wrap(err, "failed to marshal httpPolicy configuration")
+	}
+	if err := json.Unmarshal(b, &target); err != nil {
+		return policy, errors.Wrap(err, "invalid httpPolicy configuration")
+	}
+
+	policy.RateLimit = target.RateLimit
+	policy.RateBurst = target.RateBurst
+	policy.CircuitBreakerThreshold = target.CircuitBreakerThreshold
+	if len(target.MaxRetryAfter) > 0 {
+		if policy.MaxRetryAfter, err = time.ParseDuration(target.MaxRetryAfter); err != nil {
+			return policy, errors.Wrapf(err, "invalid httpPolicy maxRetryAfter '%v'", target.MaxRetryAfter)
+		}
+	}
+	if len(target.CircuitBreakerTimeout) > 0 {
+		if policy.CircuitBreakerTimeout, err = time.ParseDuration(target.CircuitBreakerTimeout); err != nil {
+			return policy, errors.Wrapf(err, "invalid httpPolicy circuitBreakerTimeout '%v'", target.CircuitBreakerTimeout)
+		}
+	}
+	return policy, nil
+}
+
+// enableCommandTranscript records the calls to executables in <stepName>_transcript.json if commandExecution contains transcript: true.
+// The transcript is published via PublishCommandTranscript when the step ends.
+func enableCommandTranscript(stepName string, source interface{}) {
+	commandExecution, _ := source.(map[string]interface{})
+	if enabled, _ := commandExecution["transcript"].(bool); !enabled {
+		command.DisableTranscript()
+		return
+	}
+	if err := command.EnableTranscript(stepName, fmt.Sprintf("%v_transcript.json", stepName)); err != nil {
+		log.Entry().WithError(err).Warn("failed to enable command transcript")
+	}
+}
+
+// PublishCommandTranscript adds the command transcript to the reports of the step if it is recorded.
+// It is called when the step ends, thus the transcript is published also for steps which do not publish reports themselves.
+func PublishCommandTranscript(stepName string) {
+	file := command.TranscriptFile()
+	if len(file) == 0 {
+		return
+	}
+	if err := piperutils.AddReport(stepName, "", &piperutils.Files{}, piperutils.Path{Name: "Command transcript", Target: file}); err != nil {
+		log.Entry().WithError(err).Warn("failed to publish command transcript")
+	}
+}
+
 var errIncompatibleTypes = fmt.Errorf("incompatible types")
 
 func checkTypes(config map[string]interface{}, options interface{}) map[string]interface{} {
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
@@ -19,9 +20,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 )
 
 func resetEnv(e []string) {
@@ -162,6 +166,66 @@ func TestPrepareConfig(t *testing.T) {
 	})
 }
 
+func TestCommonPipelineEnvironmentBackend(t *testing.T) {
+	envRootPath := GeneralConfig.EnvRootPath
+	defer func() {
+		GeneralConfig.CPEBackend = ""
+		GeneralConfig.EnvRootPath = envRootPath
+	}()
+	sharedPath := filepath.Join(t.TempDir(), "shared")
+	GeneralConfig.CPEBackend = "file://" + sharedPath
+
+	t.Run("pull and push", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(sharedPath, 0777))
+		require.NoError(t, os.WriteFile(filepath.Join(sharedPath, "artifactVersion"), []byte("1.0.0"), 0666))
+		GeneralConfig.EnvRootPath = t.TempDir()
+
+		require.NoError(t, PullCommonPipelineEnvironment())
+		assert.Equal(t, "1.0.0", piperenv.GetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "artifactVersion"))
+
+		require.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "git/commitId", "abc"))
+		PushCommonPipelineEnvironment()
+		content, err := os.ReadFile(filepath.Join(sharedPath, "git", "commitId"))
+		require.NoError(t, err)
+		assert.Equal(t, "abc", string(content))
+	})
+
+	t.Run("invalid backend", func(t *testing.T) {
+		GeneralConfig.CPEBackend = "ftp://example.com"
+		assert.EqualError(t, PullCommonPipelineEnvironment(), "failed to create commonPipelineEnvironment backend: backend 'ftp' not supported, use one of file, s3, gs, oci")
+		// nothing is pushed without a successful pull
+		PushCommonPipelineEnvironment()
+	})
+}
+
+func TestCpeEncryptionKey(t *testing.T) {
+	encryptionKey := GeneralConfig.CPEEncryptionKey
+	defer func() { GeneralConfig.CPEEncryptionKey = encryptionKey }()
+
+	t.Run("passed via flag or environment", func(t *testing.T) {
+		GeneralConfig.CPEEncryptionKey = "runKey"
+		key, err := cpeEncryptionKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "runKey", key)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		GeneralConfig.CPEEncryptionKey = ""
+		key, err := cpeEncryptionKey()
+		assert.NoError(t, err)
+		assert.Empty(t, key)
+	})
+
+	t.Run("generated key", func(t *testing.T) {
+		key, err := generateCPEEncryptionKey()
+		assert.NoError(t, err)
+		assert.Len(t, key, 64)
+		other, err := generateCPEEncryptionKey()
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, other)
+	})
+}
+
 func TestRetrieveHookConfig(t *testing.T) {
 	tt := []struct {
 		hookJSON           []byte
@@ -193,6 +257,135 @@ func TestRetrieveHookConfig(t *testing.T) {
 	}
 }
 
+func TestRetrieveCommandExecutionConfig(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		options, err := retrieveCommandExecutionConfig(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, command.ExecutionOptions{}, options)
+	})
+
+	t.Run("all options", func(t *testing.T) {
+		source := map[string]interface{}{
+			"timeout":         "30m",
+			"retries":         2,
+			"retryBackoff":    "10s",
+			"retryCategories": []interface{}{"infrastructure", "service"},
+			"retryPatterns":   []interface{}{"Connection reset"},
+			"maxOutputSize":   "10m",
+			"memoryLimit":     "4g",
+			"cpuLimit":        1.5,
+		}
+		options, err := retrieveCommandExecutionConfig(source)
+		assert.NoError(t, err)
+		assert.Equal(t, command.ExecutionOptions{
+			Timeout:         30 * time.Minute,
+			Retries:         2,
+			RetryBackoff:    10 * time.Second,
+			RetryCategories: []string{"infrastructure", "service"},
+			RetryPatterns:   []string{"Connection reset"},
+			MaxOutputSize:   10 * 1024 * 1024,
+			MemoryLimit:     4 * 1024 * 1024 * 1024,
+			CPULimit:        1.5,
+		}, options)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := retrieveCommandExecutionConfig(map[string]interface{}{"timeout": "30"})
+		assert.EqualError(t, err, "invalid commandExecution timeout '30': time: missing unit in duration \"30\"")
+	})
+
+	t.Run("invalid memory limit", func(t *testing.T) {
+		_, err := retrieveCommandExecutionConfig(map[string]interface{}{"memoryLimit": "much"})
+		assert.ErrorContains(t, err, "invalid commandExecution memoryLimit 'much'")
+	})
+}
+
+func TestSetupHTTPCassette(t *testing.T) {
+	defer piperhttp.StopCassette()
+	file := filepath.Join(t.TempDir(), "cassette.json")
+
+	t.Run("record", func(t *testing.T) {
+		assert.NoError(t, setupHTTPCassette(file, ""))
+		assert.FileExists(t, file)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		assert.NoError(t, setupHTTPCassette("", file))
+	})
+
+	t.Run("replay missing cassette", func(t *testing.T) {
+		err := setupHTTPCassette("", filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorContains(t, err, "failed to replay http requests: failed to read cassette")
+	})
+
+	t.Run("record and replay", func(t *testing.T) {
+		assert.EqualError(t, setupHTTPCassette(file, file), "http requests can either be recorded or replayed, not both")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, setupHTTPCassette("", ""))
+	})
+}
+
+func TestRetrieveHTTPPolicyConfig(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		policy, err := retrieveHTTPPolicyConfig(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, piperhttp.RequestPolicy{}, policy)
+	})
+
+	t.Run("all options", func(t *testing.T) {
+		source := map[string]interface{}{
+			"rateLimit":               2.5,
+			"rateBurst":               5,
+			"maxRetryAfter":           "2m",
+			"circuitBreakerThreshold": 10,
+			"circuitBreakerTimeout":   "1m",
+		}
+		policy, err := retrieveHTTPPolicyConfig(source)
+		assert.NoError(t, err)
+		assert.Equal(t, piperhttp.RequestPolicy{
+			RateLimit:               2.5,
+			RateBurst:               5,
+			MaxRetryAfter:           2 * time.Minute,
+			CircuitBreakerThreshold: 10,
+			CircuitBreakerTimeout:   time.Minute,
+		}, policy)
+	})
+
+	t.Run("invalid circuit breaker timeout", func(t *testing.T) {
+		_, err := retrieveHTTPPolicyConfig(map[string]interface{}{"circuitBreakerTimeout": "30"})
+		assert.EqualError(t, err, "invalid httpPolicy circuitBreakerTimeout '30': time: missing unit in duration \"30\"")
+	})
+}
+
+func TestPublishCommandTranscript(t *testing.T) {
+	dir := t.TempDir()
+	oldWD, _ := os.Getwd()
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(oldWD)
+	defer command.DisableTranscript()
+
+	t.Run("transcript enabled", func(t *testing.T) {
+		enableCommandTranscript("mavenBuild", map[string]interface{}{"transcript": true})
+		assert.FileExists(t, filepath.Join(dir, "mavenBuild_transcript.json"))
+
+		PublishCommandTranscript("mavenBuild")
+
+		reports, err := os.ReadFile(filepath.Join(dir, "mavenBuild_reports.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"Command transcript","target":"mavenBuild_transcript.json","mandatory":false,"scope":""}]`, string(reports))
+	})
+
+	t.Run("transcript disabled", func(t *testing.T) {
+		enableCommandTranscript("npmExecuteScripts", map[string]interface{}{"timeout": "10m"})
+
+		PublishCommandTranscript("npmExecuteScripts")
+
+		assert.NoFileExists(t, filepath.Join(dir, "npmExecuteScripts_reports.json"))
+	})
+}
+
 func TestGetProjectConfigFile(t *testing.T) {
 
 	tt := []struct {
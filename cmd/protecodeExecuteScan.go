@@ -20,6 +20,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -222,6 +223,12 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 	log.Entry().Debugf("Delete scan %v for %v", config.CleanupMode, productID)
 	client.DeleteScan(config.CleanupMode, productID)
 
+	// assessed vulnerabilities are treated like the ones triaged in Protecode
+	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	if assessed := protecode.ApplyAssessments(&result.Result, *assessments); assessed > 0 {
+		log.Entry().Infof("%v vulnerabilities are assessed in %v", assessed, config.AssessmentFile)
+	}
+
 	//count vulnerabilities
 	log.Entry().Debug("Parse scan result")
 	parsedResult, vulns := client.ParseResultForInflux(result.Result, config.ExcludeCVEs)
@@ -255,8 +262,10 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 		{Name: "Protecode Report", Target: path.Join("artifact", config.ReportFileName), Scope: "job"},
 	}
 
+	findings := protecode.Findings(result.Result, config.ExcludeCVEs)
+
 	// write custom report
-	scanReport := protecode.CreateCustomReport(fileName, productID, parsedResult, vulns)
+	scanReport := protecode.CreateCustomReport(fileName, productID, findings)
 	paths, err := protecode.WriteCustomReports(scanReport, fileName, fmt.Sprint(productID), utils)
 	if err != nil {
 		// do not fail - consider failing later on
@@ -265,6 +274,11 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 		reports = append(reports, paths...)
 	}
 
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "protecodeExecuteScan", Scanner: "protecode", Project: fileName, Findings: findings}, utils); err != nil {
+		// do not fail - consider failing later on
+		log.Entry().Warning("failed to write findings ...", err)
+	}
+
 	// create toolrecord file
 	toolRecordFileName, err := createToolRecordProtecode(utils, "./", config, productID, webuiURL)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 func ReadPipelineEnv() *cobra.Command {
 	var stepConfig artifactPrepareVersionOptions
 	var encryptedCPE bool
+	var decryptSensitive bool
 	metadata := artifactPrepareVersionMetadata()
 
 	readPipelineEnvCmd := &cobra.Command{
@@ -41,7 +42,7 @@ func ReadPipelineEnv() *cobra.Command {
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
-			err := runReadPipelineEnv(stepConfig.Password, encryptedCPE)
+			err := runReadPipelineEnv(stepConfig.Password, encryptedCPE, decryptSensitive)
 			if err != nil {
 				log.Entry().Fatalf("error when writing reading Pipeline environment: %v", err)
 			}
@@ -49,10 +50,11 @@ func ReadPipelineEnv() *cobra.Command {
 	}
 
 	readPipelineEnvCmd.Flags().BoolVar(&encryptedCPE, "encryptedCPE", false, "Bool to use encryption in CPE")
+	readPipelineEnvCmd.Flags().BoolVar(&decryptSensitive, "decryptSensitive", false, "Decrypts the sensitive values, by default they are part of the output in encrypted form")
 	return readPipelineEnvCmd
 }
 
-func runReadPipelineEnv(stepConfigPassword string, encryptedCPE bool) error {
+func runReadPipelineEnv(stepConfigPassword string, encryptedCPE, decryptSensitive bool) error {
 	cpe := piperenv.CPEMap{}
 
 	err := cpe.LoadFromDisk(path.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment"))
@@ -60,6 +62,13 @@ func runReadPipelineEnv(stepConfigPassword string, encryptedCPE bool) error {
 		return err
 	}
 
+	// sensitive values are stored encrypted and only decrypted if requested, otherwise writePipelineEnv stores the encrypted values again as they are
+	if decryptSensitive {
+		if cpe, err = cpe.Decrypt(); err != nil {
+			return err
+		}
+	}
+
 	// try to encrypt
 	if encryptedCPE {
 		log.Entry().Debug("trying to encrypt CPE")
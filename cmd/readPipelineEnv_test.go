@@ -1,9 +1,13 @@
 package cmd
 
 import (
-	"github.com/stretchr/testify/assert"
+	"encoding/json"
+	"os"
 	"strings"
 	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCpeEncryption(t *testing.T) {
@@ -18,3 +22,38 @@ func TestCpeEncryption(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, decrypted, payload)
 }
+
+func TestRunReadPipelineEnvSensitive(t *testing.T) {
+	envRootPath := GeneralConfig.EnvRootPath
+	defer func() { GeneralConfig.EnvRootPath = envRootPath }()
+	GeneralConfig.EnvRootPath = t.TempDir()
+
+	schema := piperenv.CPESchema{}
+	schema.AddKey("custom/token", "string", "stepA", true)
+	piperenv.SetCPESchema(schema)
+	piperenv.SetEncryptionKeyProvider(func() (string, error) { return "runKey", nil })
+	defer piperenv.SetCPESchema(nil)
+	defer piperenv.SetEncryptionKeyProvider(nil)
+
+	assert.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "custom/token", "secretToken"))
+	assert.NoError(t, piperenv.SetResourceParameter(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "artifactVersion", "1.0.0"))
+
+	readOutput := func(decryptSensitive bool) map[string]interface{} {
+		stdout := os.Stdout
+		defer func() { os.Stdout = stdout }()
+		reader, writer, _ := os.Pipe()
+		os.Stdout = writer
+		assert.NoError(t, runReadPipelineEnv("", false, decryptSensitive))
+		writer.Close()
+		output := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(reader).Decode(&output))
+		return output
+	}
+
+	encrypted := readOutput(false)
+	assert.Len(t, encrypted, 2)
+	assert.Equal(t, "1.0.0", encrypted["artifactVersion"])
+	assert.NotEmpty(t, encrypted["custom/token.enc"])
+	assert.NotEqual(t, "secretToken", encrypted["custom/token.enc"])
+	assert.Equal(t, map[string]interface{}{"artifactVersion": "1.0.0", "custom/token": "secretToken"}, readOutput(true))
+}
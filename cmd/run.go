@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+type runCommandOptions struct {
+	openFile        func(s string, t map[string]string) (io.ReadCloser, error)
+	fileExists      func(filename string) (bool, error)
+	stepCommands    func() map[string]func() *cobra.Command
+	stageConfigFile string
+	stageName       string
+	all             bool
+}
+
+var runOptions runCommandOptions
+
+// RunCommand is the entry command for running the steps of one or all stages of a pipeline locally
+func RunCommand() *cobra.Command {
+	runOptions.openFile = config.OpenPiperFile
+	runOptions.fileExists = piperutils.FileExists
+	runOptions.stepCommands = GetAllStepCommands
+	var runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Runs the active steps of one or all stages of a pipeline.",
+		Long: `Evaluates the stage conditions and executes all active steps of the selected stage(s) in the defined order.
+The steps are executed within the same process and share the commonPipelineEnvironment via the envRootPath.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			initStageName(false)
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+			// all steps are executed within this process, thus a key of the run protects the sensitive values of the commonPipelineEnvironment
+			if len(GeneralConfig.CPEEncryptionKey) == 0 {
+				key, err := generateCPEEncryptionKey()
+				if err != nil {
+					log.Entry().WithError(err).Warn("sensitive values of the commonPipelineEnvironment are stored in plaintext")
+				}
+				GeneralConfig.CPEEncryptionKey = key
+			}
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			err := runPipeline(utils, executeStepCommand)
+			if err != nil {
+				log.Entry().WithError(err).Fatal("Running the pipeline failed")
+			}
+		},
+	}
+	addRunFlags(runCmd)
+	return runCmd
+}
+
+func runPipeline(utils piperutils.FileUtils, executeStep func(stepName string) error) error {
+	// the errors up to the execution of the steps are caused by the configuration, the steps set the category of their errors themselves
+	if !runOptions.all && len(runOptions.stageName) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("either a stage name or the flag --all needs to be provided")
+	}
+	if runOptions.all && len(runOptions.stageName) > 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("a stage name must not be provided together with the flag --all")
+	}
+
+	var pConfig config.Config
+
+	// load project config and defaults
+	projectConfig, err := initializeConfig(&pConfig, runOptions.openFile, runOptions.fileExists)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to load project config")
+	}
+
+	stageConfigFile, err := runOptions.openFile(runOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "config: open stage configuration file '%v' failed", runOptions.stageConfigFile)
+	}
+	defer stageConfigFile.Close()
+
+	// load and evaluate step conditions
+	runConfig := config.RunConfig{StageConfigFile: stageConfigFile}
+	runConfigV1 := &config.RunConfigV1{RunConfig: runConfig}
+	err = runConfigV1.InitRunConfigV1(projectConfig, utils, GeneralConfig.EnvRootPath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	stages, err := stagesToRun(runConfigV1.PipelineConfig.Spec.Stages, runOptions.stageName)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	for _, stage := range stages {
+		stageName := stage.DisplayName
+		if !runConfigV1.RunStages[stageName] {
+			log.Entry().Infof("Stage %s is not active, skipping", stageName)
+			continue
+		}
+		log.Entry().Infof("Running stage %s", stageName)
+		GeneralConfig.StageName = stageName
+		for _, step := range stage.Steps {
+			if !runConfigV1.RunSteps[stageName][step.Name] {
+				log.Entry().Debugf("Step %s in stage %s is not active, skipping", step.Name, stageName)
+				continue
+			}
+			log.Entry().Infof("Running step %s in stage %s", step.Name, stageName)
+			if err := executeStep(step.Name); err != nil {
+				return errors.Wrapf(err, "step %s in stage %s failed", step.Name, stageName)
+			}
+		}
+	}
+	return nil
+}
+
+// stagesToRun returns the stages in the order of the stage configuration, limited to stageName if provided.
+// A stage can be addressed via its display name as well as via its technical name.
+func stagesToRun(stages []config.Stage, stageName string) ([]config.Stage, error) {
+	if len(stageName) == 0 {
+		return stages, nil
+	}
+	for _, stage := range stages {
+		if stage.DisplayName == stageName || stage.Name == stageName {
+			return []config.Stage{stage}, nil
+		}
+	}
+	return nil, fmt.Errorf("stage %s is not part of the stage configuration", stageName)
+}
+
+// executeStepCommand runs a step in-process using a fresh instance of its command.
+// A fresh instance is required since the step command keeps its configuration and flag state.
+func executeStepCommand(stepName string) error {
+	newStepCommand, ok := runOptions.stepCommands()[stepName]
+	if !ok {
+		return fmt.Errorf("step %s is not available in this piper binary", stepName)
+	}
+	stepCmd := newStepCommand()
+	// every step registers its own hooks, thus the hooks of previous steps must not pile up
+	restoreHooks := log.IsolateHooks()
+	defer restoreHooks()
+	// a failing step must not exit the process but stop the pipeline with an error, this includes fatal errors during the config resolution
+	log.SetFatalRecoverable(true)
+	defer log.SetFatalRecoverable(false)
+	var stepErr error
+	if err := log.RunRecoverable(func() {
+		if stepCmd.PreRunE != nil {
+			if stepErr = stepCmd.PreRunE(stepCmd, []string{}); stepErr != nil {
+				return
+			}
+		}
+		stepErr = stepCmd.RunE(stepCmd, []string{})
+	}); err != nil {
+		return err
+	}
+	return stepErr
+}
+
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&runOptions.stageConfigFile, "stageConfig", ".resources/piper-stage-config.yml",
+		"Default config of piper pipeline stages")
+	cmd.Flags().StringVar(&runOptions.stageName, "stage", "", "Name of the stage to run")
+	cmd.Flags().BoolVar(&runOptions.all, "all", false, "Run all stages defined in the stage configuration")
+}
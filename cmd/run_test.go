@@ -0,0 +1,226 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func runOpenFileMock(name string, tokens map[string]string) (io.ReadCloser, error) {
+	var fileContent string
+	switch name {
+	case "stage-config.yml":
+		fileContent = `
+spec:
+  stages:
+    - name: build
+      displayName: Build
+      steps:
+        - name: firstStep
+        - name: secondStep
+          conditions:
+            - configKey: testConfig
+    - name: release
+      displayName: Release
+      steps:
+        - name: thirdStep
+          conditions:
+            - configKey: unknownConfig`
+	case ".pipeline/config.yml":
+		fileContent = `
+steps:
+  secondStep:
+    testConfig: 'testValue'`
+	default:
+		fileContent = ""
+	}
+	return io.NopCloser(strings.NewReader(fileContent)), nil
+}
+
+func runFileExistsMock(filename string) (bool, error) {
+	return filename == ".pipeline/config.yml", nil
+}
+
+func TestRunCommand(t *testing.T) {
+	cmd := RunCommand()
+
+	t.Run("Flags", func(t *testing.T) {
+		assert.NotNil(t, cmd.Flags().Lookup("stage"))
+		assert.NotNil(t, cmd.Flags().Lookup("all"))
+		assert.NotNil(t, cmd.Flags().Lookup("stageConfig"))
+	})
+}
+
+func TestRunPipeline(t *testing.T) {
+	setup := func(stageName string, all bool) {
+		runOptions.openFile = runOpenFileMock
+		runOptions.fileExists = runFileExistsMock
+		runOptions.stageConfigFile = "stage-config.yml"
+		runOptions.stageName = stageName
+		runOptions.all = all
+		GeneralConfig.CustomConfig = ".pipeline/config.yml"
+		GeneralConfig.DefaultConfig = []string{".pipeline/defaults.yaml"}
+	}
+	defer func() { runOptions = runCommandOptions{} }()
+
+	t.Run("success - all stages", func(t *testing.T) {
+		setup("", true)
+		executed := []string{}
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error {
+			executed = append(executed, GeneralConfig.StageName+"/"+stepName)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Build/firstStep", "Build/secondStep"}, executed)
+	})
+
+	t.Run("success - single stage by technical name", func(t *testing.T) {
+		setup("build", false)
+		executed := []string{}
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error {
+			executed = append(executed, stepName)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"firstStep", "secondStep"}, executed)
+	})
+
+	t.Run("success - inactive stage", func(t *testing.T) {
+		setup("Release", false)
+		executed := []string{}
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error {
+			executed = append(executed, stepName)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, executed)
+	})
+
+	t.Run("error - step fails", func(t *testing.T) {
+		defer log.SetErrorCategory(log.ErrorUndefined)
+		setup("Build", false)
+		executed := []string{}
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error {
+			executed = append(executed, stepName)
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("step error")
+		})
+		assert.EqualError(t, err, "step firstStep in stage Build failed: step error")
+		assert.Equal(t, []string{"firstStep"}, executed)
+		assert.Equal(t, log.ErrorBuild, log.GetErrorCategory())
+	})
+
+	t.Run("error - unknown stage", func(t *testing.T) {
+		defer log.SetErrorCategory(log.ErrorUndefined)
+		setup("Unknown", false)
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error { return nil })
+		assert.EqualError(t, err, "stage Unknown is not part of the stage configuration")
+		assert.Equal(t, log.ErrorConfiguration, log.GetErrorCategory())
+	})
+
+	t.Run("error - neither stage nor all", func(t *testing.T) {
+		setup("", false)
+		err := runPipeline(&mock.FilesMock{}, func(stepName string) error { return nil })
+		assert.EqualError(t, err, "either a stage name or the flag --all needs to be provided")
+	})
+}
+
+func TestExecuteStepCommand(t *testing.T) {
+	defer func() { runOptions = runCommandOptions{} }()
+	runOptions.stepCommands = GetAllStepCommands
+
+	t.Run("error - unknown step", func(t *testing.T) {
+		err := executeStepCommand("notExisting")
+		assert.EqualError(t, err, "step notExisting is not available in this piper binary")
+	})
+
+	t.Run("error - failing step stops the pipeline", func(t *testing.T) {
+		defer func() { runOptions = runCommandOptions{} }()
+		runOptions.openFile = runOpenFileMock
+		runOptions.fileExists = runFileExistsMock
+		runOptions.stageConfigFile = "stage-config.yml"
+		runOptions.stageName = "Build"
+		GeneralConfig.CustomConfig = ".pipeline/config.yml"
+		GeneralConfig.DefaultConfig = []string{".pipeline/defaults.yaml"}
+		executed := []string{}
+		runOptions.stepCommands = func() map[string]func() *cobra.Command {
+			return map[string]func() *cobra.Command{
+				"firstStep": func() *cobra.Command {
+					return &cobra.Command{RunE: func(_ *cobra.Command, _ []string) error {
+						executed = append(executed, "firstStep")
+						return log.RunRecoverable(func() {
+							log.Entry().Fatal("step execution failed")
+						})
+					}}
+				},
+				"secondStep": func() *cobra.Command {
+					return &cobra.Command{RunE: func(_ *cobra.Command, _ []string) error {
+						executed = append(executed, "secondStep")
+						return nil
+					}}
+				},
+			}
+		}
+
+		err := runPipeline(&mock.FilesMock{}, executeStepCommand)
+
+		assert.EqualError(t, err, "step firstStep in stage Build failed: step execution failed")
+		assert.Equal(t, []string{"firstStep"}, executed)
+	})
+
+	t.Run("error - fatal error during config resolution", func(t *testing.T) {
+		defer func() { runOptions = runCommandOptions{} }()
+		runOptions.stepCommands = func() map[string]func() *cobra.Command {
+			return map[string]func() *cobra.Command{
+				"firstStep": func() *cobra.Command {
+					return &cobra.Command{
+						PreRunE: func(_ *cobra.Command, _ []string) error {
+							log.Entry().Fatal("failed to resolve config")
+							return nil
+						},
+						RunE: func(_ *cobra.Command, _ []string) error {
+							t.Fatal("step must not run")
+							return nil
+						},
+					}
+				},
+			}
+		}
+
+		err := executeStepCommand("firstStep")
+
+		assert.EqualError(t, err, "failed to resolve config")
+	})
+
+	t.Run("success - hooks of a step are removed after its execution", func(t *testing.T) {
+		defer func() { runOptions = runCommandOptions{} }()
+		hook := &log.CollectorHook{}
+		runOptions.stepCommands = func() map[string]func() *cobra.Command {
+			return map[string]func() *cobra.Command{
+				"firstStep": func() *cobra.Command {
+					return &cobra.Command{
+						PreRunE: func(_ *cobra.Command, _ []string) error {
+							log.RegisterHook(hook)
+							return nil
+						},
+						RunE: func(_ *cobra.Command, _ []string) error { return nil },
+					}
+				},
+			}
+		}
+
+		assert.NoError(t, executeStepCommand("firstStep"))
+		assert.NotContains(t, logrus.StandardLogger().Hooks[logrus.InfoLevel], hook)
+	})
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/securitypolicy"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type securityGateUtils interface {
+	piperutils.FileUtils
+}
+
+type securityGateUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newSecurityGateUtils() securityGateUtils {
+	utils := securityGateUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func securityGate(config securityGateOptions, telemetryData *telemetry.CustomData) {
+	utils := newSecurityGateUtils()
+
+	err := runSecurityGate(&config, telemetryData, utils, time.Now())
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSecurityGate(config *securityGateOptions, telemetryData *telemetry.CustomData, utils securityGateUtils, now time.Time) error {
+	policy, err := securitypolicy.ReadPolicy(config.PolicyFile, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	reports, err := reporting.ReadFindingsReports(utils)
+	if err != nil {
+		return errors.Wrap(err, "failed to read findings of the security scans")
+	}
+	if len(reports) == 0 {
+		if config.FailOnMissingFindings {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("no findings of security scans available in %v, please make sure that the scan steps run before the security gate", reporting.FindingsDirectory)
+		}
+		log.Entry().Warnf("No findings of security scans available in %v, please make sure that the scan steps run before the security gate", reporting.FindingsDirectory)
+	}
+	for _, report := range reports {
+		log.Entry().Infof("Evaluating %v findings of %v for project %v", len(report.Findings), report.StepName, report.Project)
+	}
+	findings := reporting.AggregateFindings(reports)
+
+	result := securitypolicy.Evaluate(*policy, findings, now)
+	for _, waiver := range result.ExpiredWaivers {
+		log.Entry().Warnf("Waiver for %v expired on %v and is ignored: %v", waiver.ID, waiver.Expires, waiver.Reason)
+	}
+	for _, violation := range result.Violations {
+		switch {
+		case violation.Waiver != nil:
+			log.Entry().Info(violation.Explanation())
+		case violation.Failing():
+			log.Entry().Error(violation.Explanation())
+		default:
+			log.Entry().Warn(violation.Explanation())
+		}
+	}
+
+	paths, err := securitypolicy.WriteReports(result, config.PolicyFile, utils)
+	if err != nil {
+		return errors.Wrap(err, "failed to write security gate reports")
+	}
+	piperutils.PersistReportsAndLinks("securityGate", "", utils, paths, nil)
+
+	failing, warning, waived := result.Count()
+	log.Entry().Infof("%v violations fail the security gate, %v violations are warnings and %v violations are waived", failing, warning, waived)
+	if !result.Passed() {
+		if config.FailOnViolation {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("%v violations of the security policy %v", failing, config.PolicyFile)
+		}
+		log.Entry().Info("Step will only report the violations due to setting failOnViolation: false")
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type securityGateOptions struct {
+	PolicyFile            string `json:"policyFile,omitempty"`
+	FailOnViolation       bool   `json:"failOnViolation,omitempty"`
+	FailOnMissingFindings bool   `json:"failOnMissingFindings,omitempty"`
+}
+
+// SecurityGateCommand Evaluates the findings of all security scans against a central security policy
+func SecurityGateCommand() *cobra.Command {
+	const STEP_NAME = "securityGate"
+
+	metadata := securityGateMetadata()
+	var stepConfig securityGateOptions
+	var startTime time.Time
+	var logCollector *log.CollectorHook
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSecurityGateCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Evaluates the findings of all security scans against a central security policy",
+		Long: `This step evaluates the findings which the security scan steps collected in ` + "`" + `.pipeline/stepReports/findings` + "`" + ` against a declarative security policy.
+The step fails if at least one finding violates a rule with action ` + "`" + `fail` + "`" + ` and is not covered by a valid waiver.
+
+The result is explained in a markdown and HTML report as well as in a SARIF file, which list each violation together with the violated rule and a possible waiver.
+The report is also included into the summary of ` + "`" + `pipelineCreateScanSummary` + "`" + `.
+
+Findings which several scanners report for the same package and vulnerability are evaluated only once.
+
+The scan steps ` + "`" + `whitesourceExecuteScan` + "`" + `, ` + "`" + `detectExecuteScan` + "`" + `, ` + "`" + `protecodeExecuteScan` + "`" + `, ` + "`" + `checkmarxExecuteScan` + "`" + `, ` + "`" + `checkmarxOneExecuteScan` + "`" + `, ` + "`" + `fortifyExecuteScan` + "`" + ` and ` + "`" + `contrastExecuteScan` + "`" + ` write their findings there, independent of their SARIF or report settings, thus the step needs to run after them.
+If no findings are available the step fails unless ` + "`" + `failOnMissingFindings` + "`" + ` is set to ` + "`" + `false` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 || len(GeneralConfig.HookConfig.TelemetryConfig.Sinks) > 0 {
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			if GeneralConfig.DryRun {
+				RunStepDryRun(STEP_NAME, &metadata, stepConfig, nil)
+				return nil
+			}
+			handler := func() {
+				PushCommonPipelineEnvironment()
+				PublishCommandTranscript(STEP_NAME)
+				config.RemoveVaultSecretFiles()
+				config.RevokeVaultLeases()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				tracing.EndStep(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+			}
+			removeExitHandler := log.DeferExitHandler(handler)
+			defer removeExitHandler()
+			defer handler()
+			StartStepTracing(STEP_NAME, startTime)
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME, GeneralConfig.HookConfig.PendoConfig.Token)
+			AddTelemetrySinks(telemetryClient, logCollector)
+			err := log.RunRecoverable(func() {
+				securityGate(stepConfig, &stepTelemetryData)
+			})
+			if err != nil {
+				return err
+			}
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+			return nil
+		},
+	}
+
+	addSecurityGateFlags(createSecurityGateCmd, &stepConfig)
+	return createSecurityGateCmd
+}
+
+func addSecurityGateFlags(cmd *cobra.Command, stepConfig *securityGateOptions) {
+	cmd.Flags().StringVar(&stepConfig.PolicyFile, "policyFile", `.pipeline/security-policy.yml`, "Path to the YAML file containing the security policy.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnViolation, "failOnViolation", true, "Defines if the step fails in case the policy is violated. If set to `false` the violations are only reported.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnMissingFindings, "failOnMissingFindings", true, "Defines if the step fails in case no findings of security scans are available, e.g. since the scan steps did not run before the security gate. If set to `false` the step only warns and evaluates the policy against no findings.")
+
+}
+
+// retrieve step metadata
+func securityGateMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "securityGate",
+			Aliases:     []config.Alias{},
+			Description: "Evaluates the findings of all security scans against a central security policy",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "policyFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/security-policy.yml`,
+					},
+					{
+						Name:        "failOnViolation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnMissingFindings",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
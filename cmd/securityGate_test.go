@@ -0,0 +1,146 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type securityGateMockUtils struct {
+	*mock.FilesMock
+}
+
+func newSecurityGateTestsUtils() securityGateMockUtils {
+	utils := securityGateMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	return utils
+}
+
+const securityGateTestPolicy = `rules:
+  - name: no-critical
+    description: critical vulnerabilities need to be fixed
+    when:
+      minSeverity: critical
+  - name: high-with-fix
+    action: warn
+    when:
+      minSeverity: high
+      fixAvailable: true
+waivers:
+  - id: CVE-2021-44228
+    package: pkg:maven/org.apache.logging.log4j/log4j-core
+    reason: not used at runtime
+    expires: 2026-12-31
+`
+
+func addSecurityGateTestFindings(t *testing.T, utils securityGateMockUtils, findings ...reporting.Finding) {
+	content, err := json.Marshal(reporting.FindingsReport{StepName: "whitesourceExecuteScan", Scanner: "whitesource", Findings: findings})
+	require.NoError(t, err)
+	utils.AddFile(filepath.Join(reporting.FindingsDirectory, "whitesourceExecuteScan_1.json"), content)
+}
+
+func TestRunSecurityGate(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	log4j := reporting.Finding{Scanner: "whitesource", ID: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen}
+	lodash := reporting.Finding{Scanner: "whitesource", ID: "CVE-2021-23337", PackageURL: "pkg:npm/lodash@4.17.20", Severity: reporting.SeverityHigh, FixVersion: "4.17.21", Status: reporting.FindingStatusOpen}
+	minimist := reporting.Finding{Scanner: "whitesource", ID: "CVE-2021-44906", PackageURL: "pkg:npm/minimist@1.2.5", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen}
+
+	t.Run("passed with waived violation and warning", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml", FailOnViolation: true}
+		utils := newSecurityGateTestsUtils()
+		utils.AddFile(config.PolicyFile, []byte(securityGateTestPolicy))
+		addSecurityGateTestFindings(t, utils, log4j, lodash)
+
+		err := runSecurityGate(&config, nil, utils, now)
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile(filepath.Join("securityGate", "piper_security_gate_report.html")))
+		assert.True(t, utils.HasWrittenFile(filepath.Join("securityGate", "result.sarif")))
+		content, err := utils.FileRead(filepath.Join(reporting.StepReportDirectory, "securityGate.json"))
+		require.NoError(t, err)
+		var scanReport reporting.ScanReport
+		require.NoError(t, json.Unmarshal(content, &scanReport))
+		assert.True(t, scanReport.SuccessfulScan)
+		assert.Len(t, scanReport.DetailTable.Rows, 2)
+	})
+
+	t.Run("violation", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml", FailOnViolation: true}
+		utils := newSecurityGateTestsUtils()
+		utils.AddFile(config.PolicyFile, []byte(securityGateTestPolicy))
+		addSecurityGateTestFindings(t, utils, log4j, minimist)
+
+		err := runSecurityGate(&config, nil, utils, now)
+
+		assert.EqualError(t, err, "1 violations of the security policy .pipeline/security-policy.yml")
+
+		config.FailOnViolation = false
+		assert.NoError(t, runSecurityGate(&config, nil, utils, now))
+	})
+
+	t.Run("expired waiver", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml", FailOnViolation: true}
+		utils := newSecurityGateTestsUtils()
+		utils.AddFile(config.PolicyFile, []byte(securityGateTestPolicy))
+		addSecurityGateTestFindings(t, utils, log4j)
+
+		err := runSecurityGate(&config, nil, utils, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		assert.EqualError(t, err, "1 violations of the security policy .pipeline/security-policy.yml")
+	})
+
+	t.Run("same finding of several scanners", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml", FailOnViolation: true}
+		utils := newSecurityGateTestsUtils()
+		utils.AddFile(config.PolicyFile, []byte(securityGateTestPolicy))
+		addSecurityGateTestFindings(t, utils, minimist)
+		content, err := json.Marshal(reporting.FindingsReport{StepName: "detectExecuteScan", Scanner: "blackduck", Findings: []reporting.Finding{
+			{Scanner: "blackduck", ID: "CVE-2021-44906", CVE: "CVE-2021-44906", PackageURL: "pkg:npm/minimist@1.2.5", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen},
+		}})
+		require.NoError(t, err)
+		utils.AddFile(filepath.Join(reporting.FindingsDirectory, "detectExecuteScan_1.json"), content)
+
+		err = runSecurityGate(&config, nil, utils, now)
+
+		assert.EqualError(t, err, "1 violations of the security policy .pipeline/security-policy.yml")
+	})
+
+	t.Run("missing findings", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml", FailOnViolation: true, FailOnMissingFindings: true}
+		utils := newSecurityGateTestsUtils()
+		utils.AddFile(config.PolicyFile, []byte(securityGateTestPolicy))
+
+		err := runSecurityGate(&config, nil, utils, now)
+
+		assert.EqualError(t, err, "no findings of security scans available in .pipeline/stepReports/findings, please make sure that the scan steps run before the security gate")
+
+		config.FailOnMissingFindings = false
+		assert.NoError(t, runSecurityGate(&config, nil, utils, now))
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		t.Parallel()
+		config := securityGateOptions{PolicyFile: ".pipeline/security-policy.yml"}
+		utils := newSecurityGateTestsUtils()
+
+		err := runSecurityGate(&config, nil, utils, now)
+
+		assert.ErrorContains(t, err, "failed to read policy .pipeline/security-policy.yml")
+	})
+}
@@ -742,10 +742,23 @@ func reportGitHubIssuesAndCreateReports(
 	errorsOccured := make([]string, 0)
 	reportPaths := make([]piperutils.Path, 0)
 
+	findings := make([]reporting.Finding, 0, len(allAlerts)+len(allAssessedAlerts))
+	for _, alert := range allAlerts {
+		findings = append(findings, alert.ToFinding())
+	}
+	openFindings := findings[:len(allAlerts)]
+	for _, alert := range allAssessedAlerts {
+		finding := alert.ToFinding()
+		if alert.Assessment == nil {
+			// alerts ignored in WhiteSource have been assessed there
+			finding.Status = reporting.FindingStatusNotAffected
+		}
+		findings = append(findings, finding)
+	}
+
 	if config.CreateResultIssue && vulnerabilitiesCount > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
 		log.Entry().Debugf("Creating result issues for %v alert(s)", vulnerabilitiesCount)
-		issueDetails := make([]reporting.IssueDetail, len(allAlerts))
-		piperutils.CopyAtoB(allAlerts, issueDetails)
+		issueDetails := reporting.FindingIssues(openFindings)
 		gh := reporting.GitHub{
 			Owner:         &config.Owner,
 			Repository:    &config.Repository,
@@ -759,7 +772,7 @@ func reportGitHubIssuesAndCreateReports(
 		}
 	}
 
-	scanReport := ws.CreateCustomVulnerabilityReport(config.ProductName, scan, &allAlerts, cvssSeverityLimit)
+	scanReport := ws.CreateCustomVulnerabilityReport(config.ProductName, scan, findings, cvssSeverityLimit)
 	paths, err := ws.WriteCustomVulnerabilityReports(config.ProductName, scan, scanReport, utils)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -767,11 +780,7 @@ func reportGitHubIssuesAndCreateReports(
 
 	reportPaths = append(reportPaths, paths...)
 
-	combinedAlerts := make([]ws.Alert, 0, len(allAlerts)+len(allAssessedAlerts))
-	combinedAlerts = append(combinedAlerts, allAlerts...)
-	combinedAlerts = append(combinedAlerts, allAssessedAlerts...)
-
-	sarif := ws.CreateSarifResultFile(scan, &combinedAlerts)
+	sarif := ws.CreateSarifResultFile(scan, findings)
 	paths, err = ws.WriteSarifFile(sarif, utils)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -779,6 +788,10 @@ func reportGitHubIssuesAndCreateReports(
 
 	reportPaths = append(reportPaths, paths...)
 
+	if _, err := reporting.WriteFindingsReport(reporting.FindingsReport{StepName: "whitesourceExecuteScan", Scanner: "whitesource", Project: config.ProductName, Findings: findings}, utils); err != nil {
+		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+	}
+
 	sbom, err := ws.CreateCycloneSBOM(scan, &allLibraries, &allAlerts, &allAssessedAlerts)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -795,7 +808,7 @@ func reportGitHubIssuesAndCreateReports(
 }
 
 // read assessments from file and expose them to match alerts and filter them before processing
-func readAssessmentsFromFile(assessmentFilePath string, utils whitesourceUtils) *[]format.Assessment {
+func readAssessmentsFromFile(assessmentFilePath string, utils piperutils.FileUtils) *[]format.Assessment {
 	exists, err := utils.FileExists(assessmentFilePath)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -835,9 +848,9 @@ func checkProjectSecurityViolations(config *ScanOptions, cvssSeverityLimit float
 	filteredAlerts := []ws.Alert{}
 	if assessments != nil && len(*assessments) > 0 {
 		for _, alert := range alerts {
-			if result, err := alert.ContainedIn(assessments); err == nil && !result {
+			if !alert.ContainedIn(assessments) {
 				filteredAlerts = append(filteredAlerts, alert)
-			} else if alert.Assessment != nil {
+			} else {
 				log.Entry().Debugf("Matched assessment with status %v and analysis %v to vulnerability %v affecting packages %v", alert.Assessment.Status, alert.Assessment.Analysis, alert.Assessment.Vulnerability, alert.Assessment.Purls)
 				assessedAlerts = append(assessedAlerts, alert)
 			}
@@ -89,6 +89,18 @@ func runWritePipelineEnv(stepConfigPassword string, encryptedCPE bool) error {
 		return err
 	}
 
+	// the values are restored from the orchestrator, type mismatches are reported but must not break the pipeline
+	schema := allStepsCPESchema()
+	for _, typeErr := range schema.CheckTypes(commonPipelineEnv) {
+		log.Entry().Warnf("commonPipelineEnvironment: %v", typeErr)
+	}
+
+	// sensitive values passed in plaintext, e.g. read with decryptSensitive, are stored encrypted again
+	commonPipelineEnv, err = commonPipelineEnv.EncryptSensitive(schema)
+	if err != nil {
+		return err
+	}
+
 	rootPath := filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	err = commonPipelineEnv.WriteToDisk(rootPath)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/package-url/packageurl-go"
@@ -126,9 +127,8 @@ type Vulnerability struct {
 	Ignored                      bool   `json:"ignored,omitempty"`
 	VulnerabilityWithRemediation `json:"vulnerabilityWithRemediation,omitempty"`
 	Component                    *Component
-	projectName                  string
-	projectVersion               string
-	projectVersionLink           string
+	// Assessment is set if the vulnerability is assessed in the assessment file
+	Assessment *format.Assessment `json:"-"`
 }
 
 type VulnerabilityWithRemediation struct {
@@ -153,34 +153,83 @@ func (v Vulnerability) Title() string {
 
 // ToMarkdown returns the markdown representation of the contents
 func (v Vulnerability) ToMarkdown() ([]byte, error) {
-	vul := reporting.VulnerabilityReport{
-		ProjectName:          v.projectName,
-		ProjectVersion:       v.projectVersion,
-		BlackDuckProjectLink: v.projectVersionLink,
-		ArtifactID:           v.Component.Name,
-		Description:          v.Description,
-		DependencyType:       v.Component.MatchedType(),
-		Origin:               v.ComponentVersionOriginID,
-
-		// no information available about footer, yet
-		Footer: "",
+	vul := reporting.NewVulnerabilityReport(v.ToFinding())
+	vul.Origin = v.ComponentVersionOriginID
+	// no information available about footer, group, publish date and resolution yet
+	vul.Severity = v.VulnerabilityWithRemediation.Severity
+	if v.Component != nil {
+		vul.DependencyType = v.Component.MatchedType()
+	}
 
-		// no information available about group, yet
-		Group: "",
+	return vul.ToMarkdown()
+}
 
-		// no information available about publish date and resolution yet
-		PublishDate: "",
-		Resolution:  "",
+// ToFinding converts the vulnerability into the scanner independent finding
+func (v Vulnerability) ToFinding() reporting.Finding {
+	finding := reporting.Finding{
+		Scanner:          "blackduck",
+		Type:             reporting.FindingTypeVulnerability,
+		ID:               v.VulnerabilityName,
+		Name:             v.VulnerabilityName,
+		Description:      v.Description,
+		ComponentName:    v.Name,
+		ComponentVersion: v.Version,
+		Severity:         reporting.ParseSeverity(v.VulnerabilityWithRemediation.Severity),
+		CVSSScore:        float64(v.VulnerabilityWithRemediation.BaseScore),
+		Link:             v.RelatedVulnerability,
+		Status:           remediationStatus(v),
+		StatusComment:    v.RemediationComment,
+		// the GitHub issues of Black Duck are identified by the name of the vulnerability
+		IssueTitle: v.Title(),
+	}
+	if v.Component != nil {
+		finding.ComponentName = v.Component.Name
+		finding.PackageURL = v.Component.ToPackageUrl().ToString()
+	}
+	if v.Assessment != nil {
+		finding.Status = reporting.FindingStatusFromAssessment(v.Assessment)
+		finding.StatusComment = string(v.Assessment.Analysis)
+	}
+	if strings.HasPrefix(v.VulnerabilityName, "CVE-") {
+		finding.CVE = v.VulnerabilityName
+	} else if strings.HasPrefix(v.RelatedVulnerability, "CVE-") {
+		finding.CVE = v.RelatedVulnerability
+	}
+	if len(v.CweID) > 0 {
+		finding.CWE = v.CweID
+		if !strings.HasPrefix(finding.CWE, "CWE-") {
+			finding.CWE = "CWE-" + finding.CWE
+		}
+	}
+	return finding
+}
 
-		Score:             float64(v.VulnerabilityWithRemediation.BaseScore),
-		Severity:          v.VulnerabilityWithRemediation.Severity,
-		Version:           v.Version,
-		PackageURL:        v.Component.ToPackageUrl().ToString(),
-		VulnerabilityLink: v.RelatedVulnerability,
-		VulnerabilityName: v.VulnerabilityName,
+// Assess attaches the first assessment which applies to the vulnerability of the component and returns whether there is one
+func (v *Vulnerability) Assess(assessments []format.Assessment) bool {
+	purl := packageurl.PackageURL{Name: v.Name, Version: v.Version}
+	if v.Component != nil {
+		if componentPurl := v.Component.ToPackageUrl(); len(componentPurl.Name) > 0 {
+			purl = *componentPurl
+		}
 	}
+	v.Assessment = format.FindAssessment(assessments, purl, v.VulnerabilityName, v.RelatedVulnerability)
+	return v.Assessment != nil
+}
 
-	return vul.ToMarkdown()
+func remediationStatus(v Vulnerability) reporting.FindingStatus {
+	if v.Ignored {
+		return reporting.FindingStatusNotAffected
+	}
+	switch v.RemediationStatus {
+	case "", "NEW":
+		return reporting.FindingStatusOpen
+	case "NEEDS_REVIEW":
+		return reporting.FindingStatusInTriage
+	case "REMEDIATION_REQUIRED":
+		return reporting.FindingStatusConfirmed
+	}
+	// e.g. IGNORED, NOT_AFFECTED, MITIGATED, PATCHED or REMEDIATION_COMPLETE
+	return reporting.FindingStatusNotAffected
 }
 
 // ToTxt returns the textual representation of the contents
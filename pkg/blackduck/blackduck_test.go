@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/stretchr/testify/assert"
 )
@@ -750,3 +751,70 @@ func TestComponentToPackageUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestVulnerabilityToFinding(t *testing.T) {
+	t.Parallel()
+	vuln := Vulnerability{
+		Name:    "Minimist",
+		Version: "0.0.8",
+		VulnerabilityWithRemediation: VulnerabilityWithRemediation{
+			VulnerabilityName:    "BDSA-2020-0001",
+			Severity:             "CRITICAL",
+			BaseScore:            9.8,
+			CweID:                "1321",
+			RelatedVulnerability: "CVE-2020-7598",
+			RemediationStatus:    "NEW",
+		},
+		Component: &Component{
+			Name:    "Minimist",
+			Version: "0.0.8",
+			Origins: []ComponentOrigin{{ExternalNamespace: "npmjs", ExternalID: "minimist/0.0.8"}},
+		},
+	}
+	finding := vuln.ToFinding()
+	assert.Equal(t, "blackduck", finding.Scanner)
+	assert.Equal(t, "BDSA-2020-0001", finding.ID)
+	assert.Equal(t, "CVE-2020-7598", finding.CVE)
+	assert.Equal(t, "CWE-1321", finding.CWE)
+	assert.Equal(t, "pkg:npm/minimist@0.0.8", finding.PackageURL)
+	assert.Equal(t, "critical", finding.Severity.String())
+	assert.InDelta(t, 9.8, finding.CVSSScore, 0.01)
+	assert.Equal(t, "open", string(finding.Status))
+	// the title must not change, otherwise existing GitHub issues are not found anymore
+	assert.Equal(t, "BDSA-2020-0001", finding.Title())
+	assert.Equal(t, vuln.Title(), finding.Title())
+
+	vuln.RemediationStatus = "NEEDS_REVIEW"
+	assert.Equal(t, "inTriage", string(vuln.ToFinding().Status))
+	vuln.RemediationStatus = "MITIGATED"
+	assert.Equal(t, "notAffected", string(vuln.ToFinding().Status))
+	vuln.RemediationStatus = "NEW"
+	vuln.Ignored = true
+	assert.Equal(t, "notAffected", string(vuln.ToFinding().Status))
+}
+
+func TestVulnerabilityAssess(t *testing.T) {
+	t.Parallel()
+	vuln := Vulnerability{
+		Name:    "Minimist",
+		Version: "0.0.8",
+		VulnerabilityWithRemediation: VulnerabilityWithRemediation{
+			VulnerabilityName:    "BDSA-2020-0001",
+			RelatedVulnerability: "CVE-2020-7598",
+			RemediationStatus:    "NEW",
+		},
+		Component: &Component{
+			Name:    "Minimist",
+			Version: "0.0.8",
+			Origins: []ComponentOrigin{{ExternalNamespace: "npmjs", ExternalID: "minimist/0.0.8"}},
+		},
+	}
+
+	assert.False(t, vuln.Assess([]format.Assessment{{Vulnerability: "CVE-2020-7598", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "pkg:npm/minimist@1.2.6"}}}}))
+	assert.Nil(t, vuln.Assessment)
+
+	assert.True(t, vuln.Assess([]format.Assessment{{Vulnerability: "CVE-2020-7598", Status: format.NotRelevant, Analysis: format.NotUsed, Purls: []format.Purl{{Purl: "pkg:npm/minimist@0.0.8"}}}}))
+	finding := vuln.ToFinding()
+	assert.Equal(t, "notAffected", string(finding.Status))
+	assert.Equal(t, "notUsed", finding.StatusComment)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateSarifResultFile(t *testing.T) {
@@ -91,14 +92,16 @@ func TestCreateSarifResultFile(t *testing.T) {
 			},
 		},
 	}
-	vulns := Vulnerabilities{
-		Items: alerts,
+	findings := []reporting.Finding{}
+	for _, alert := range alerts {
+		finding := alert.ToFinding()
+		finding.ProjectName = "theProject"
+		finding.ProjectVersion = "1.0"
+		finding.ProjectLink = "https://blackduck.example.com/api/projects/1/versions/2"
+		findings = append(findings, finding)
 	}
-	projectName := "theProjectName"
-	projectVersion := "theProjectVersion"
-	projectLink := "theProjectLink"
 
-	sarif := CreateSarifResultFile(&vulns, projectName, projectVersion, projectLink)
+	sarif := CreateSarifResultFile(findings)
 
 	assert.Equal(t, "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json", sarif.Schema)
 	assert.Equal(t, "2.1.0", sarif.Version)
@@ -107,29 +110,30 @@ func TestCreateSarifResultFile(t *testing.T) {
 	assert.Equal(t, "unknown", sarif.Runs[0].Tool.Driver.Version)
 	assert.Equal(t, 4, len(sarif.Runs[0].Tool.Driver.Rules))
 	assert.Equal(t, 5, len(sarif.Runs[0].Results))
-	assert.Equal(t, "CRITICAL", sarif.Runs[0].Results[0].Properties.ToolSeverity)
+	assert.Equal(t, "critical", sarif.Runs[0].Results[0].Properties.ToolSeverity)
+	assert.Equal(t, "pkg:generic/test1@1.2.3", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Contains(t, sarif.Runs[0].Tool.Driver.Rules[0].Help.Markdown, "**Project Version:** [theProject 1.0](https://blackduck.example.com/api/projects/1/versions/2)")
 
 	// Test correctness of audit information
 	assert.Equal(t, true, sarif.Runs[0].Results[0].Properties.Audited)
-	assert.Equal(t, "IGNORED", sarif.Runs[0].Results[0].Properties.ToolState)
 	assert.Equal(t, alerts[0].BaseScore, sarif.Runs[0].Results[0].Properties.UnifiedCriticality)
 	assert.Equal(t, "critical", sarif.Runs[0].Results[0].Properties.UnifiedSeverity)
 	assert.Equal(t, "new", sarif.Runs[0].Results[1].Properties.UnifiedAuditState)
 	assert.Equal(t, "notRelevant", sarif.Runs[0].Results[0].Properties.UnifiedAuditState)
-	assert.Equal(t, "technical_user", sarif.Runs[0].Results[0].Properties.UnifiedAuditUser)
 	assert.Equal(t, format.AUDIT_REQUIREMENT_GROUP_1_DESC, sarif.Runs[0].Results[0].Properties.AuditRequirement)
 	assert.Equal(t, format.AUDIT_REQUIREMENT_GROUP_1_INDEX, sarif.Runs[0].Results[0].Properties.AuditRequirementIndex)
 	assert.Equal(t,
 		"CWE-45456543 Auto-remediated: CWE-45456543 is related to CVE-1, but the CWE team has determined that this component version is not affected.",
 		sarif.Runs[0].Results[0].Properties.ToolAuditMessage,
 	)
-	assert.Equal(t, 4, sarif.Runs[0].Results[0].Properties.ToolSeverityIndex)
-	assert.Equal(t, 3, sarif.Runs[0].Results[2].Properties.ToolSeverityIndex)
 
 	assert.Equal(t, false, sarif.Runs[0].Results[1].Properties.Audited)
-	assert.Equal(t, "NEW", sarif.Runs[0].Results[1].Properties.ToolState)
 	assert.Equal(t, "", sarif.Runs[0].Results[1].Properties.ToolAuditMessage)
 
+	// Test the CWE taxonomy
+	require.Len(t, sarif.Runs[0].Taxonomies, 1)
+	assert.Equal(t, 4, len(sarif.Runs[0].Taxonomies[0].Taxa))
+
 	collectedRules := []string{}
 	for _, rule := range sarif.Runs[0].Tool.Driver.Rules {
 		piperutils.ContainsString(vulnerabilities, rule.ID)
@@ -4,12 +4,13 @@ import (
 	"bytes"
 	"crypto/sha1"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"math"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -48,46 +49,38 @@ type LowPerQuery struct {
 	Total     int    `json:"total"`
 }
 
-func CreateCustomReport(data map[string]interface{}, insecure, neutral []string) reporting.ScanReport {
+// CreateCustomReport creates the scan report with the KPIs of the scan, the results of the thresholds and the summary of the findings are listed in the overview
+func CreateCustomReport(data map[string]interface{}, findings []reporting.Finding, insecure, neutral []string) reporting.ScanReport {
 	deepLink := fmt.Sprintf(`<a href="%v" target="_blank">Link to scan in CX UI</a>`, data["DeepLink"])
-
-	scanReport := reporting.ScanReport{
-		ReportTitle: "Checkmarx SAST Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "Project name", Details: fmt.Sprint(data["ProjectName"])},
-			{Description: "Project ID", Details: fmt.Sprint(data["ProjectId"])},
-			{Description: "Owner", Details: fmt.Sprint(data["Owner"])},
-			{Description: "Scan ID", Details: fmt.Sprint(data["ScanId"])},
-			{Description: "Team", Details: fmt.Sprint(data["Team"])},
-			{Description: "Team full path", Details: fmt.Sprint(data["TeamFullPathOnReportDate"])},
-			{Description: "Scan start", Details: fmt.Sprint(data["ScanStart"])},
-			{Description: "Scan duration", Details: fmt.Sprint(data["ScanTime"])},
-			{Description: "Scan type", Details: fmt.Sprint(data["ScanType"])},
-			{Description: "Preset", Details: fmt.Sprint(data["Preset"])},
-			{Description: "Report creation time", Details: fmt.Sprint(data["ReportCreationTime"])},
-			{Description: "Lines of code scanned", Details: fmt.Sprint(data["LinesOfCodeScanned"])},
-			{Description: "Files scanned", Details: fmt.Sprint(data["FilesScanned"])},
-			{Description: "Checkmarx version", Details: fmt.Sprint(data["CheckmarxVersion"])},
-			{Description: "Deep link", Details: deepLink},
-		},
-		Overview:   []reporting.OverviewRow{},
-		ReportTime: time.Now(),
+	subheaders := []reporting.Subheader{
+		{Description: "Project name", Details: fmt.Sprint(data["ProjectName"])},
+		{Description: "Project ID", Details: fmt.Sprint(data["ProjectId"])},
+		{Description: "Owner", Details: fmt.Sprint(data["Owner"])},
+		{Description: "Scan ID", Details: fmt.Sprint(data["ScanId"])},
+		{Description: "Team", Details: fmt.Sprint(data["Team"])},
+		{Description: "Team full path", Details: fmt.Sprint(data["TeamFullPathOnReportDate"])},
+		{Description: "Scan start", Details: fmt.Sprint(data["ScanStart"])},
+		{Description: "Scan duration", Details: fmt.Sprint(data["ScanTime"])},
+		{Description: "Scan type", Details: fmt.Sprint(data["ScanType"])},
+		{Description: "Preset", Details: fmt.Sprint(data["Preset"])},
+		{Description: "Report creation time", Details: fmt.Sprint(data["ReportCreationTime"])},
+		{Description: "Lines of code scanned", Details: fmt.Sprint(data["LinesOfCodeScanned"])},
+		{Description: "Files scanned", Details: fmt.Sprint(data["FilesScanned"])},
+		{Description: "Checkmarx version", Details: fmt.Sprint(data["CheckmarxVersion"])},
+		{Description: "Deep link", Details: deepLink},
 	}
+	scanReport := reporting.CreateFindingsScanReport("Checkmarx SAST Report", subheaders, findings, reporting.SeverityHigh)
 
+	overview := []reporting.OverviewRow{}
 	for _, issue := range insecure {
-		row := reporting.OverviewRow{}
-		row.Description = fmt.Sprint(issue)
-		row.Style = reporting.Red
-
-		scanReport.Overview = append(scanReport.Overview, row)
+		overview = append(overview, reporting.OverviewRow{Description: fmt.Sprint(issue), Style: reporting.Red})
 	}
 	for _, issue := range neutral {
-		row := reporting.OverviewRow{}
-		row.Description = fmt.Sprint(issue)
-
-		scanReport.Overview = append(scanReport.Overview, row)
+		overview = append(overview, reporting.OverviewRow{Description: fmt.Sprint(issue)})
 	}
+	scanReport.Overview = append(overview, scanReport.Overview...)
 
+	// the findings are listed in the findings report, the detail table shows the KPIs of the scan
 	detailTable := reporting.ScanDetailTable{
 		Headers: []string{
 			"KPI",
@@ -134,9 +127,21 @@ func CreateCustomReport(data map[string]interface{}, insecure, neutral []string)
 	}
 	scanReport.DetailTable = detailTable
 
+	// compliance is defined by the thresholds of the step
+	scanReport.SuccessfulScan = len(insecure) == 0
 	return scanReport
 }
 
+// CreateFindingsSarif creates the SARIF report of the findings in the scanner independent format
+func CreateFindingsSarif(findings []reporting.Finding, checkmarxVersion string) format.SARIF {
+	tool := format.Driver{
+		Name:           "Checkmarx SAST",
+		Version:        strings.TrimPrefix(checkmarxVersion, "V "),
+		InformationUri: "https://checkmarx.atlassian.net/wiki/spaces/KC/pages/1170245301/Navigating+Scan+Results+v9.0.0+to+v9.2.0",
+	}
+	return reporting.CreateFindingsSarif(tool, findings)
+}
+
 func CreateJSONReport(data map[string]interface{}) CheckmarxReportData {
 	checkmarxReportData := CheckmarxReportData{
 		ToolName:         `checkmarx`,
@@ -279,6 +284,59 @@ func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID
 	return reportPaths, nil
 }
 
+// ConvertCxxmlToFindings reads the XML report and converts its results into scanner independent findings
+func ConvertCxxmlToFindings(xmlReportName string) ([]reporting.Finding, error) {
+	data, err := os.ReadFile(xmlReportName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read XML report %v", xmlReportName)
+	}
+	return parseFindings(data)
+}
+
+func parseFindings(data []byte) ([]reporting.Finding, error) {
+	var cxxml CxXMLResults
+	if err := xml.Unmarshal(data, &cxxml); err != nil {
+		return nil, errors.Wrap(err, "failed to parse XML report")
+	}
+	findings := []reporting.Finding{}
+	for _, query := range cxxml.Query {
+		for _, result := range query.Result {
+			finding := reporting.Finding{
+				Scanner:     "checkmarx",
+				Type:        reporting.FindingTypeCode,
+				ID:          query.Name,
+				Name:        query.Name,
+				Description: query.Categories,
+				Location:    &reporting.Location{File: result.FileName, Line: result.Line, Column: result.Column},
+				Severity:    reporting.ParseSeverity(result.Severity),
+				Link:        result.DeepLink,
+				Status:      findingStatus(result),
+			}
+			if len(query.CweID) > 0 && query.CweID != "0" {
+				finding.CWE = "CWE-" + query.CweID
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// findingStatus maps the audit state of the result, e.g. 4 for ProposedNotExploitable
+func findingStatus(result CxxmlResult) reporting.FindingStatus {
+	if result.FalsePositive {
+		return reporting.FindingStatusNotAffected
+	}
+	switch result.State {
+	case 1:
+		return reporting.FindingStatusNotAffected
+	case 2, 3:
+		return reporting.FindingStatusConfirmed
+	case 4:
+		return reporting.FindingStatusInTriage
+	}
+	return reporting.FindingStatusOpen
+}
+
 func reportShaCheckmarx(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
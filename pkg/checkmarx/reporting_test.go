@@ -7,7 +7,9 @@ import (
 	"encoding/xml"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateJSONReport(t *testing.T) {
@@ -290,10 +292,21 @@ func TestCreateCustomReport(t *testing.T) {
 	insecure := []string{"insecure"}
 	neutral := []string{"neutral"}
 
-	reportingData := CreateCustomReport(resultMap, insecure, neutral)
+	findings := []reporting.Finding{
+		{Scanner: "checkmarx", Type: reporting.FindingTypeCode, ID: "SQL_Injection", Location: &reporting.Location{File: "src/db.cs", Line: 12}, Severity: reporting.SeverityHigh, Status: reporting.FindingStatusOpen},
+		{Scanner: "checkmarx", Type: reporting.FindingTypeCode, ID: "Hardcoded_Password", Location: &reporting.Location{File: "src/config.cs", Line: 3}, Severity: reporting.SeverityLow, Status: reporting.FindingStatusNotAffected},
+	}
+
+	reportingData := CreateCustomReport(resultMap, findings, insecure, neutral)
 	assert.Equal(t, "Checkmarx SAST Report", reportingData.ReportTitle)
 	assert.Equal(t, 15, len(reportingData.Subheaders))
-	assert.Equal(t, 2, len(reportingData.Overview))
+	assert.False(t, reportingData.SuccessfulScan)
+	if assert.Equal(t, 5, len(reportingData.Overview)) {
+		assert.Equal(t, "insecure", reportingData.Overview[0].Description)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), reportingData.Overview[0].Style)
+		assert.Equal(t, "neutral", reportingData.Overview[1].Description)
+		assert.Equal(t, "2", reportingData.Overview[2].Details)
+	}
 
 	subheaders := make(map[string]string)
 	for _, subheader := range reportingData.Subheaders {
@@ -347,4 +360,56 @@ func TestCreateCustomReport(t *testing.T) {
 	assert.Equal(t, "0", detailRows["Informational urgent issues"])
 	assert.Equal(t, "0", detailRows["Informational proposed not exploitable issues"])
 	assert.Equal(t, "0", detailRows["Informational to verify issues"])
+
+	reportingData = CreateCustomReport(resultMap, findings, nil, neutral)
+	assert.True(t, reportingData.SuccessfulScan)
+}
+
+func TestCreateFindingsSarif(t *testing.T) {
+	findings := []reporting.Finding{
+		{Scanner: "checkmarx", Type: reporting.FindingTypeCode, ID: "SQL_Injection", CWE: "CWE-89", Location: &reporting.Location{File: "src/db.cs", Line: 12, Column: 3}, Severity: reporting.SeverityHigh, Status: reporting.FindingStatusConfirmed},
+	}
+
+	sarif := CreateFindingsSarif(findings, "V 9.4.5")
+
+	require.Len(t, sarif.Runs, 1)
+	assert.Equal(t, "Checkmarx SAST", sarif.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, "9.4.5", sarif.Runs[0].Tool.Driver.Version)
+	require.Len(t, sarif.Runs[0].Results, 1)
+	assert.Equal(t, "SQL_Injection", sarif.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "src/db.cs", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 12, sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestParseFindings(t *testing.T) {
+	data := `<?xml version="1.0" encoding="utf-8"?>
+	<CxXMLResults ProjectName="Project 1" DeepLink="https://cx.test/CxWebClient/ViewerMain.aspx?scanid=1000005&amp;projectid=2">
+	<Query id="430" categories="OWASP Top 10 2017;A1-Injection" cweId="89" name="SQL_Injection" Severity="High" Language="CSharp">
+		<Result FileName="src/db.cs" Line="12" Column="3" FalsePositive="False" Severity="High" state="2" DeepLink="https://cx.test/CxWebClient/ViewerMain.aspx?pathid=1"></Result>
+		<Result FileName="src/user.cs" Line="7" Column="1" FalsePositive="False" Severity="High" state="4"></Result>
+		<Result FileName="src/test.cs" Line="1" Column="1" FalsePositive="True" Severity="Medium" state="0"></Result>
+	</Query>
+	<Query id="431" cweId="0" name="Hardcoded_Password" Severity="Low" Language="CSharp">
+		<Result FileName="src/config.cs" Line="3" Column="9" FalsePositive="False" Severity="Low" state="0"></Result>
+	</Query>
+	</CxXMLResults>`
+
+	findings, err := parseFindings([]byte(data))
+	assert.NoError(t, err)
+	if assert.Len(t, findings, 4) {
+		assert.Equal(t, "SQL_Injection", findings[0].ID)
+		assert.Equal(t, "CWE-89", findings[0].CWE)
+		assert.Equal(t, "src/db.cs", findings[0].Location.File)
+		assert.Equal(t, 12, findings[0].Location.Line)
+		assert.Equal(t, "high", findings[0].Severity.String())
+		assert.Equal(t, "confirmed", string(findings[0].Status))
+		assert.Equal(t, "https://cx.test/CxWebClient/ViewerMain.aspx?pathid=1", findings[0].Link)
+		assert.Equal(t, "inTriage", string(findings[1].Status))
+		assert.Equal(t, "notAffected", string(findings[2].Status))
+		assert.Equal(t, "open", string(findings[3].Status))
+		assert.Empty(t, findings[3].CWE)
+	}
+
+	_, err = parseFindings([]byte("<CxXMLResults"))
+	assert.Error(t, err)
 }
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -46,46 +45,38 @@ type LowPerQuery struct {
 	Total     int    `json:"total"`
 }
 
-func CreateCustomReport(data *map[string]interface{}, insecure, neutral []string) reporting.ScanReport {
+// CreateCustomReport creates the scan report with the KPIs of the scan, the results of the thresholds and the summary of the findings are listed in the overview
+func CreateCustomReport(data *map[string]interface{}, findings []reporting.Finding, insecure, neutral []string) reporting.ScanReport {
 	deepLink := fmt.Sprintf(`<a href="%v" target="_blank">Link to scan in CX1 UI</a>`, (*data)["DeepLink"])
-
-	scanReport := reporting.ScanReport{
-		ReportTitle: "CheckmarxOne SAST Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "Project name", Details: fmt.Sprint((*data)["ProjectName"])},
-			{Description: "Project ID", Details: fmt.Sprint((*data)["ProjectId"])},
-			{Description: "Owner", Details: fmt.Sprint((*data)["Owner"])},
-			{Description: "Scan ID", Details: fmt.Sprint((*data)["ScanId"])},
-			{Description: "Group", Details: fmt.Sprint((*data)["Group"])},
-			{Description: "Group full path", Details: fmt.Sprint((*data)["GroupFullPathOnReportDate"])},
-			{Description: "Scan start", Details: fmt.Sprint((*data)["ScanStart"])},
-			{Description: "Scan duration", Details: fmt.Sprint((*data)["ScanTime"])},
-			{Description: "Scan type", Details: fmt.Sprint((*data)["ScanType"])},
-			{Description: "Preset", Details: fmt.Sprint((*data)["Preset"])},
-			{Description: "Report creation time", Details: fmt.Sprint((*data)["ReportCreationTime"])},
-			{Description: "Lines of code scanned", Details: fmt.Sprint((*data)["LinesOfCodeScanned)"])},
-			{Description: "Files scanned", Details: fmt.Sprint((*data)["FilesScanned)"])},
-			{Description: "Tool version", Details: fmt.Sprint((*data)["ToolVersion"])},
-			{Description: "Deep link", Details: deepLink},
-		},
-		Overview:   []reporting.OverviewRow{},
-		ReportTime: time.Now(),
+	subheaders := []reporting.Subheader{
+		{Description: "Project name", Details: fmt.Sprint((*data)["ProjectName"])},
+		{Description: "Project ID", Details: fmt.Sprint((*data)["ProjectId"])},
+		{Description: "Owner", Details: fmt.Sprint((*data)["Owner"])},
+		{Description: "Scan ID", Details: fmt.Sprint((*data)["ScanId"])},
+		{Description: "Group", Details: fmt.Sprint((*data)["Group"])},
+		{Description: "Group full path", Details: fmt.Sprint((*data)["GroupFullPathOnReportDate"])},
+		{Description: "Scan start", Details: fmt.Sprint((*data)["ScanStart"])},
+		{Description: "Scan duration", Details: fmt.Sprint((*data)["ScanTime"])},
+		{Description: "Scan type", Details: fmt.Sprint((*data)["ScanType"])},
+		{Description: "Preset", Details: fmt.Sprint((*data)["Preset"])},
+		{Description: "Report creation time", Details: fmt.Sprint((*data)["ReportCreationTime"])},
+		{Description: "Lines of code scanned", Details: fmt.Sprint((*data)["LinesOfCodeScanned)"])},
+		{Description: "Files scanned", Details: fmt.Sprint((*data)["FilesScanned)"])},
+		{Description: "Tool version", Details: fmt.Sprint((*data)["ToolVersion"])},
+		{Description: "Deep link", Details: deepLink},
 	}
+	scanReport := reporting.CreateFindingsScanReport("CheckmarxOne SAST Report", subheaders, findings, reporting.SeverityHigh)
 
+	overview := []reporting.OverviewRow{}
 	for _, issue := range insecure {
-		row := reporting.OverviewRow{}
-		row.Description = fmt.Sprint(issue)
-		row.Style = reporting.Red
-
-		scanReport.Overview = append(scanReport.Overview, row)
+		overview = append(overview, reporting.OverviewRow{Description: fmt.Sprint(issue), Style: reporting.Red})
 	}
 	for _, issue := range neutral {
-		row := reporting.OverviewRow{}
-		row.Description = fmt.Sprint(issue)
-
-		scanReport.Overview = append(scanReport.Overview, row)
+		overview = append(overview, reporting.OverviewRow{Description: fmt.Sprint(issue)})
 	}
+	scanReport.Overview = append(overview, scanReport.Overview...)
 
+	// the findings are listed in the findings report, the detail table shows the KPIs of the scan
 	detailTable := reporting.ScanDetailTable{
 		Headers: []string{
 			"KPI",
@@ -132,9 +123,21 @@ func CreateCustomReport(data *map[string]interface{}, insecure, neutral []string
 	}
 	scanReport.DetailTable = detailTable
 
+	// compliance is defined by the thresholds of the step
+	scanReport.SuccessfulScan = len(insecure) == 0
 	return scanReport
 }
 
+// CreateFindingsSarif creates the SARIF report of the findings in the scanner independent format
+func CreateFindingsSarif(findings []reporting.Finding, toolVersion string) format.SARIF {
+	tool := format.Driver{
+		Name:           "CheckmarxOne SAST",
+		Version:        toolVersion,
+		InformationUri: "https://checkmarx.com/resource/documents/en/34965-165898-results-details-per-scanner.html",
+	}
+	return reporting.CreateFindingsSarif(tool, findings)
+}
+
 func CreateJSONHeaderReport(data *map[string]interface{}) CheckmarxOneReportData {
 	checkmarxReportData := CheckmarxOneReportData{
 		ToolName:        `CheckmarxOne`,
@@ -282,6 +285,43 @@ func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID
 	return reportPaths, nil
 }
 
+// Findings converts the results of the scan into scanner independent findings
+func Findings(results []ScanResult) []reporting.Finding {
+	findings := []reporting.Finding{}
+	for _, r := range results {
+		finding := reporting.Finding{
+			Scanner:     "checkmarxOne",
+			Type:        reporting.FindingTypeCode,
+			ID:          r.Data.QueryName,
+			Name:        r.Data.QueryName,
+			Description: r.Description,
+			Severity:    reporting.ParseSeverity(r.Severity),
+			Status:      findingStatus(r.State),
+		}
+		if len(r.Data.Nodes) > 0 {
+			finding.Location = &reporting.Location{File: r.Data.Nodes[0].FileName, Line: r.Data.Nodes[0].Line, Column: r.Data.Nodes[0].Column}
+		}
+		if r.VulnerabilityDetails.CweId > 0 {
+			finding.CWE = fmt.Sprintf("CWE-%d", r.VulnerabilityDetails.CweId)
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// findingStatus maps the audit state of the result, e.g. PROPOSED_NOT_EXPLOITABLE
+func findingStatus(state string) reporting.FindingStatus {
+	switch strings.TrimSpace(state) {
+	case "NOT_EXPLOITABLE":
+		return reporting.FindingStatusNotAffected
+	case "CONFIRMED", "URGENT":
+		return reporting.FindingStatusConfirmed
+	case "PROPOSED_NOT_EXPLOITABLE":
+		return reporting.FindingStatusInTriage
+	}
+	return reporting.FindingStatusOpen
+}
+
 func reportShaCheckmarxOne(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
@@ -105,3 +105,58 @@ func TestCreateJSONReport(t *testing.T) {
 
 	resultMap["LowPerQuery"] = lowPerQuery
 }
+
+func TestFindings(t *testing.T) {
+	results := []ScanResult{
+		{State: "CONFIRMED", Severity: "HIGH", Description: "user input is used in a query", Data: ScanResultData{QueryName: "SQL_Injection", Nodes: []ScanResultNodes{{FileName: "/src/db.go", Line: 12, Column: 3}, {FileName: "/src/query.go", Line: 4}}}, VulnerabilityDetails: ScanResultDetails{CweId: 89}},
+		{State: "PROPOSED_NOT_EXPLOITABLE", Severity: "MEDIUM", Data: ScanResultData{QueryName: "Reflected_XSS"}},
+		{State: "NOT_EXPLOITABLE", Severity: "LOW", Data: ScanResultData{QueryName: "Log_Forging"}},
+		{State: "TO_VERIFY", Severity: "INFO", Data: ScanResultData{QueryName: "Unused_Variable"}},
+	}
+
+	findings := Findings(results)
+	if assert.Len(t, findings, 4) {
+		assert.Equal(t, "SQL_Injection", findings[0].ID)
+		assert.Equal(t, "CWE-89", findings[0].CWE)
+		assert.Equal(t, "/src/db.go", findings[0].Location.File)
+		assert.Equal(t, 12, findings[0].Location.Line)
+		assert.Equal(t, "high", findings[0].Severity.String())
+		assert.Equal(t, "confirmed", string(findings[0].Status))
+		assert.Nil(t, findings[1].Location)
+		assert.Equal(t, "inTriage", string(findings[1].Status))
+		assert.Equal(t, "notAffected", string(findings[2].Status))
+		assert.Equal(t, "open", string(findings[3].Status))
+		assert.Equal(t, "info", findings[3].Severity.String())
+	}
+}
+
+func TestCreateCustomReport(t *testing.T) {
+	data := map[string]interface{}{"ProjectName": "ssba", "DeepLink": "https://cx1.test/projects/1"}
+	for _, severity := range []string{"High", "Medium", "Low", "Information"} {
+		data[severity] = map[string]int{"Issues": 1, "NotFalsePositive": 1}
+	}
+	data["High"].(map[string]int)["Issues"] = 3
+	findings := Findings([]ScanResult{
+		{State: "TO_VERIFY", Severity: "HIGH", Data: ScanResultData{QueryName: "SQL_Injection", Nodes: []ScanResultNodes{{FileName: "/src/db.go", Line: 12}}}},
+		{State: "NOT_EXPLOITABLE", Severity: "LOW", Data: ScanResultData{QueryName: "Log_Forging"}},
+	})
+
+	scanReport := CreateCustomReport(&data, findings, []string{"insecure"}, []string{"neutral"})
+
+	assert.Equal(t, "CheckmarxOne SAST Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	if assert.Len(t, scanReport.Overview, 5) {
+		assert.Equal(t, "insecure", scanReport.Overview[0].Description)
+		assert.Equal(t, "neutral", scanReport.Overview[1].Description)
+	}
+	if assert.Len(t, scanReport.DetailTable.Rows, 28) {
+		assert.Equal(t, "High issues", scanReport.DetailTable.Rows[0].Columns[0].Content)
+		assert.Equal(t, "3", scanReport.DetailTable.Rows[0].Columns[1].Content)
+	}
+
+	sarif := CreateFindingsSarif(findings, "3.0")
+	if assert.Len(t, sarif.Runs, 1) {
+		assert.Equal(t, "CheckmarxOne SAST", sarif.Runs[0].Tool.Driver.Name)
+		assert.Len(t, sarif.Runs[0].Results, 2)
+	}
+}
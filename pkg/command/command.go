@@ -3,16 +3,23 @@ package command
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Command defines the information required for executing a call to any executable
@@ -101,23 +108,34 @@ var ExecCommand = exec.Command
 
 // RunShell runs the specified command on the shell
 func (c *Command) RunShell(shell, script string) error {
+	return c.RunShellWithContext(context.Background(), shell, script)
+}
+
+// RunShellWithContext runs the specified command on the shell, the shell is killed once the context is done
+func (c *Command) RunShellWithContext(ctx context.Context, shell, script string) error {
+	if c.recordDryRun(shell, script) {
+		return nil
+	}
 	c.prepareOut()
 
-	cmd := ExecCommand(shell)
+	log.Entry().Infof("running shell script: %v %v", shell, script)
 
-	if len(c.dir) > 0 {
-		cmd.Dir = c.dir
-	}
+	newCmd := func() *exec.Cmd {
+		cmd := ExecCommand(shell)
 
-	appendEnvironment(cmd, c.env)
+		if len(c.dir) > 0 {
+			cmd.Dir = c.dir
+		}
 
-	in := bytes.Buffer{}
-	in.Write([]byte(script))
-	cmd.Stdin = &in
+		appendEnvironment(cmd, c.env)
 
-	log.Entry().Infof("running shell script: %v %v", shell, script)
+		in := bytes.Buffer{}
+		in.Write([]byte(script))
+		cmd.Stdin = &in
+		return cmd
+	}
 
-	if err := c.runCmd(cmd); err != nil {
+	if err := c.runWithRetries(ctx, newCmd, true, script); err != nil {
 		return errors.Wrapf(err, "running shell script failed with %v", shell)
 	}
 	return nil
@@ -136,34 +154,57 @@ func (c *Command) RunExecutable(executable string, params ...string) error {
 //
 //	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
 func (c *Command) RunExecutableWithAttrs(executable string, sysProcAttr *syscall.SysProcAttr, params ...string) error {
-	c.prepareOut()
+	return c.runExecutable(context.Background(), executable, sysProcAttr, params...)
+}
 
-	cmd := ExecCommand(executable, params...)
-	cmd.SysProcAttr = sysProcAttr
+// RunExecutableWithContext runs the specified executable with parameters, the executable is killed once the context is done
+// !! While the cmd.Env is applied during command execution, it is NOT involved when the actual executable is resolved.
+//
+//	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
+func (c *Command) RunExecutableWithContext(ctx context.Context, executable string, params ...string) error {
+	return c.runExecutable(ctx, executable, nil, params...)
+}
 
-	if len(c.dir) > 0 {
-		cmd.Dir = c.dir
+func (c *Command) runExecutable(ctx context.Context, executable string, sysProcAttr *syscall.SysProcAttr, params ...string) error {
+	if c.recordDryRun(executable, params...) {
+		return nil
 	}
+	c.prepareOut()
 
 	log.Entry().Infof("running command: %v %v", executable, strings.Join(params, (" ")))
 
-	appendEnvironment(cmd, c.env)
+	newCmd := func() *exec.Cmd {
+		cmd := ExecCommand(executable, params...)
+		cmd.SysProcAttr = sysProcAttr
 
-	if c.stdin != nil {
-		cmd.Stdin = c.stdin
+		if len(c.dir) > 0 {
+			cmd.Dir = c.dir
+		}
+
+		appendEnvironment(cmd, c.env)
+
+		if c.stdin != nil {
+			cmd.Stdin = c.stdin
+		}
+		return cmd
 	}
 
-	if err := c.runCmd(cmd); err != nil {
+	// the input can only be consumed once, thus calls with stdin are not retried
+	if err := c.runWithRetries(ctx, newCmd, c.stdin == nil, ""); err != nil {
 		return errors.Wrapf(err, "running command '%v' failed", executable)
 	}
 	return nil
 }
 
-// RunExecutableInBackground runs the specified executable with parameters in the background non blocking
+// RunExecutableInBackground runs the specified executable with parameters in the background non blocking.
+// Timeouts and retries of the ExecutionOptions do not apply, the execution is controlled by the caller.
 // !! While the cmd.Env is applied during command execution, it is NOT involved when the actual executable is resolved.
 //
 //	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
 func (c *Command) RunExecutableInBackground(executable string, params ...string) (Execution, error) {
+	if c.recordDryRun(executable, params...) {
+		return dryRunExecution{}, nil
+	}
 	c.prepareOut()
 
 	cmd := ExecCommand(executable, params...)
@@ -233,10 +274,19 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 	execution := execution{cmd: cmd, ul: log.NewURLLogger(c.StepName)}
 	execution.wg.Add(2)
 
+	options := currentExecutionOptions()
 	srcOut := stdout
 	srcErr := stderr
+	dstOut := newLimitedWriter(c.stdout, options.MaxOutputSize)
+	dstErr := newLimitedWriter(c.stderr, options.MaxOutputSize)
+	if transcriptEnabled() {
+		execution.stdoutTail = newTailBuffer(transcriptOutputLimit)
+		execution.stderrTail = newTailBuffer(transcriptOutputLimit)
+		dstOut = io.MultiWriter(dstOut, execution.stdoutTail)
+		dstErr = io.MultiWriter(dstErr, execution.stderrTail)
+	}
 
-	if c.ErrorCategoryMapping != nil {
+	if c.ErrorCategoryMapping != nil || len(options.RetryPatterns) > 0 {
 		prOut, pwOut := io.Pipe()
 		trOut := io.TeeReader(stdout, pwOut)
 		srcOut = prOut
@@ -250,13 +300,13 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		go func() {
 			defer execution.wg.Done()
 			defer pwOut.Close()
-			c.scanLog(trOut)
+			c.scanLog(trOut, &execution, options)
 		}()
 
 		go func() {
 			defer execution.wg.Done()
 			defer pwErr.Close()
-			c.scanLog(trErr)
+			c.scanLog(trErr, &execution, options)
 		}()
 	}
 
@@ -264,11 +314,11 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		if c.StepName != "" {
 			var buf bytes.Buffer
 			br := bufio.NewWriter(&buf)
-			_, execution.errCopyStdout = piperutils.CopyData(io.MultiWriter(c.stdout, br), srcOut)
+			_, execution.errCopyStdout = piperutils.CopyData(io.MultiWriter(dstOut, br), srcOut)
 			br.Flush()
 			execution.ul.Parse(buf)
 		} else {
-			_, execution.errCopyStdout = piperutils.CopyData(c.stdout, srcOut)
+			_, execution.errCopyStdout = piperutils.CopyData(dstOut, srcOut)
 		}
 		execution.wg.Done()
 	}()
@@ -277,11 +327,11 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		if c.StepName != "" {
 			var buf bytes.Buffer
 			bw := bufio.NewWriter(&buf)
-			_, execution.errCopyStderr = piperutils.CopyData(io.MultiWriter(c.stderr, bw), srcErr)
+			_, execution.errCopyStderr = piperutils.CopyData(io.MultiWriter(dstErr, bw), srcErr)
 			bw.Flush()
 			execution.ul.Parse(buf)
 		} else {
-			_, execution.errCopyStderr = piperutils.CopyData(c.stderr, srcErr)
+			_, execution.errCopyStderr = piperutils.CopyData(dstErr, srcErr)
 		}
 		execution.wg.Done()
 	}()
@@ -289,12 +339,15 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 	return &execution, nil
 }
 
-func (c *Command) scanLog(in io.Reader) {
+func (c *Command) scanLog(in io.Reader, execution *execution, options ExecutionOptions) {
 	scanner := bufio.NewScanner(in)
 	scanner.Split(scanShortLines)
 	for scanner.Scan() {
 		line := scanner.Text()
-		c.parseConsoleErrors(line)
+		category := c.parseConsoleErrors(line)
+		if options.isTransient(line, category) {
+			execution.transient.Store(true)
+		}
 	}
 	if err := scanner.Err(); err != nil {
 		log.Entry().WithError(err).Info("failed to scan log file")
@@ -326,15 +379,17 @@ func scanShortLines(data []byte, atEOF bool) (advance int, token []byte, err err
 	return 0, nil, nil
 }
 
-func (c *Command) parseConsoleErrors(logLine string) {
+// parseConsoleErrors sets the error category matching the log line and returns the category
+func (c *Command) parseConsoleErrors(logLine string) string {
 	for category, categoryErrors := range c.ErrorCategoryMapping {
 		for _, errorPart := range categoryErrors {
 			if matchPattern(logLine, errorPart) {
 				log.SetErrorCategory(log.ErrorCategoryByString(category))
-				return
+				return category
 			}
 		}
 	}
+	return ""
 }
 
 func matchPattern(text, pattern string) bool {
@@ -350,16 +405,95 @@ func matchPattern(text, pattern string) bool {
 	return true
 }
 
-func (c *Command) runCmd(cmd *exec.Cmd) error {
+// runWithRetries runs the command created by newCmd and repeats it in case of transient failures as configured via the ExecutionOptions.
+// The output of all attempts is streamed to the configured writers. If stdout is a buffer like bytes.Buffer, it is reset to contain
+// only the output of the final attempt. Buffers wrapped in another writer, e.g. an io.MultiWriter, are not reset and contain the output of all attempts. Each attempt is recorded in the transcript if enabled, script is the input passed to a shell.
+func (c *Command) runWithRetries(ctx context.Context, newCmd func() *exec.Cmd, retryable bool, script string) error {
+	options := currentExecutionOptions()
+	attempts := 1
+	if retryable {
+		attempts += options.Retries
+	}
+	backoff := options.RetryBackoff
+	captured, _ := c.stdout.(capturedOutput)
+	for attempt := 1; ; attempt++ {
+		capturedLen := 0
+		if captured != nil {
+			capturedLen = captured.Len()
+		}
+		cmd := newCmd()
+		start := time.Now()
+		execution, err := c.runCmd(ctx, cmd, options)
+		if transcriptEnabled() {
+			recordTranscript(cmd, script, attempt, start, c.exitCode, err, execution)
+		}
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || attempt >= attempts {
+			return err
+		}
+		log.Entry().WithError(err).Warnf("command failed with a transient error, retrying in %v (%v/%v)", backoff, attempt, attempts-1)
+		if captured != nil {
+			captured.Truncate(capturedLen)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// capturedOutput is implemented by writers which capture the output in memory, e.g. bytes.Buffer
+type capturedOutput interface {
+	Len() int
+	Truncate(n int)
+}
+
+func (c *Command) runCmd(ctx context.Context, cmd *exec.Cmd, options ExecutionOptions) (*execution, error) {
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	// the span is started in the context of the step if ctx has no span, thus spanCtx does not carry the deadline of ctx
+	spanCtx, span := tracing.StartSpan(ctx, "command "+filepath.Base(cmd.Path),
+		trace.WithAttributes(attribute.String("process.executable.name", filepath.Base(cmd.Path)), attribute.String("process.working_directory", cmd.Dir)))
+	defer span.End()
+	if tracing.Enabled() {
+		// nested piper calls continue the trace of the step
+		if len(cmd.Env) == 0 {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, tracing.Environment(spanCtx)...)
+	}
+
+	defer prepareProcess(cmd, options, ctx.Done() != nil)()
+
 	execution, err := c.startCmd(cmd)
 	if err != nil {
-		return err
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
+	defer watchProcess(cmd)()
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Entry().Warnf("terminating %v: %v", filepath.Base(cmd.Path), ctx.Err())
+			if err := terminate(cmd); err != nil {
+				log.Entry().WithError(err).Warn("failed to terminate command")
+			}
+		case <-finished:
+		}
+	}()
 
 	err = execution.Wait()
 
 	if execution.errCopyStdout != nil || execution.errCopyStderr != nil {
-		return fmt.Errorf("failed to capture stdout/stderr: '%v'/'%v'", execution.errCopyStdout, execution.errCopyStderr)
+		return execution, fmt.Errorf("failed to capture stdout/stderr: '%v'/'%v'", execution.errCopyStdout, execution.errCopyStderr)
 	}
 
 	if err != nil {
@@ -371,10 +505,22 @@ func (c *Command) runCmd(cmd *exec.Cmd) error {
 				c.exitCode = status.ExitStatus()
 			}
 		}
-		return errors.Wrap(err, "cmd.Run() failed")
+		span.SetAttributes(attribute.Int("process.exit.code", c.exitCode))
+		span.SetStatus(codes.Error, err.Error())
+		if ctx.Err() == context.DeadlineExceeded && options.Timeout > 0 {
+			return execution, errors.Wrapf(ctx.Err(), "command timed out after %v", options.Timeout)
+		}
+		if ctx.Err() != nil {
+			return execution, errors.Wrap(ctx.Err(), "command terminated")
+		}
+		if execution.transient.Load() {
+			return execution, &transientError{errors.Wrap(err, "cmd.Run() failed")}
+		}
+		return execution, errors.Wrap(err, "cmd.Run() failed")
 	}
+	span.SetAttributes(attribute.Int("process.exit.code", 0))
 	c.exitCode = 0
-	return nil
+	return execution, nil
 }
 
 func (c *Command) prepareOut() {
@@ -5,12 +5,15 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/stretchr/testify/assert"
@@ -235,6 +238,113 @@ func TestCmdPipes(t *testing.T) {
 	})
 }
 
+func TestExecutionOptions(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	defer SetExecutionOptions(ExecutionOptions{})
+
+	t.Run("timeout", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Timeout: 100 * time.Millisecond})
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		start := time.Now()
+		err := ex.RunExecutable("sleep")
+
+		assert.EqualError(t, err, "running command 'sleep' failed: command timed out after 100ms: context deadline exceeded")
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.NotEqual(t, 0, ex.GetExitCode())
+	})
+
+	t.Run("context", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{})
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutableWithContext(ctx, "sleep")
+
+		assert.EqualError(t, err, "running command 'sleep' failed: command terminated: context canceled")
+	})
+
+	t.Run("retry on transient pattern", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Retries: 2, RetryBackoff: time.Millisecond, RetryPatterns: []string{"Connection reset"}})
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("flaky", filepath.Join(t.TempDir(), "marker"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok\n", stdout.String(), "output of failed attempts must not be forwarded")
+	})
+
+	t.Run("retry streams output of all attempts", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Retries: 2, RetryBackoff: time.Millisecond, RetryPatterns: []string{"Connection reset"}})
+		stdout := new(strings.Builder)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("flaky", filepath.Join(t.TempDir(), "marker"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "partial\nok\n", stdout.String())
+	})
+
+	t.Run("retry on transient error category", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Retries: 1, RetryBackoff: time.Millisecond})
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), ErrorCategoryMapping: map[string][]string{"infrastructure": {"Connection reset"}}}
+
+		err := ex.RunExecutable("flaky", filepath.Join(t.TempDir(), "marker"))
+
+		assert.NoError(t, err)
+		log.SetErrorCategory(log.ErrorUndefined)
+	})
+
+	t.Run("no retry for other failures", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Retries: 2, RetryBackoff: time.Millisecond, RetryPatterns: []string{"Service Unavailable"}})
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("flaky", filepath.Join(t.TempDir(), "marker"))
+
+		assert.EqualError(t, err, "running command 'flaky' failed: cmd.Run() failed: exit status 1")
+	})
+
+	t.Run("retry does not reset wrapped buffers", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{Retries: 2, RetryBackoff: time.Millisecond, RetryPatterns: []string{"Connection reset"}})
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: io.MultiWriter(stdout), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("flaky", filepath.Join(t.TempDir(), "marker"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "partial\nok\n", stdout.String())
+	})
+
+	t.Run("output limit", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{MaxOutputSize: 5})
+		var logOutput bytes.Buffer
+		outWriter := log.Entry().Logger.Out
+		log.Entry().Logger.SetOutput(&logOutput)
+		defer func() { log.Entry().Logger.SetOutput(outWriter) }()
+		ex := Command{stdout: log.Writer(), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "foo bar")
+
+		assert.NoError(t, err)
+		assert.Contains(t, logOutput.String(), `msg="foo b"`)
+		assert.Contains(t, logOutput.String(), "[output truncated after 5 bytes]")
+	})
+
+	t.Run("no output limit for buffers", func(t *testing.T) {
+		SetExecutionOptions(ExecutionOptions{MaxOutputSize: 5})
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "foo bar")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo bar\n", stdout.String())
+	})
+}
+
 // based on https://golang.org/src/os/exec/exec_test.go
 // this is not directly executed
 func TestHelperProcess(*testing.T) {
@@ -274,6 +384,17 @@ func TestHelperProcess(*testing.T) {
 		for _, e := range os.Environ() {
 			fmt.Println(e)
 		}
+	case "sleep":
+		time.Sleep(10 * time.Second)
+	case "flaky":
+		// fails with a transient error on the first call, the marker file records the call
+		if _, err := os.Stat(args[0]); err != nil {
+			os.WriteFile(args[0], []byte{}, 0644)
+			fmt.Println("partial")
+			fmt.Fprintln(os.Stderr, "Connection reset by peer")
+			os.Exit(1)
+		}
+		fmt.Println("ok")
 	case "long":
 		b := []byte("a")
 		size := 64000
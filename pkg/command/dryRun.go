@@ -0,0 +1,67 @@
+package command
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// DryRunRecord contains the details of a call to an executable which has been recorded instead of being executed
+type DryRunRecord struct {
+	Executable string
+	Params     []string
+	Dir        string
+	Env        []string
+}
+
+// String returns the command line of the recorded call
+func (r DryRunRecord) String() string {
+	return strings.TrimSpace(r.Executable + " " + strings.Join(r.Params, " "))
+}
+
+var (
+	dryRun        bool
+	dryRunMutex   sync.Mutex
+	dryRunRecords []DryRunRecord
+)
+
+// SetDryRun enables or disables the dry-run mode.
+// In dry-run mode calls to executables are only recorded and not executed.
+func SetDryRun(enabled bool) {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	dryRun = enabled
+	dryRunRecords = nil
+}
+
+// DryRunRecords returns the calls to executables which have been recorded in dry-run mode
+func DryRunRecords() []DryRunRecord {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	return append([]DryRunRecord{}, dryRunRecords...)
+}
+
+// recordDryRun records the call in case dry-run mode is enabled and returns whether the call has been recorded
+func (c *Command) recordDryRun(executable string, params ...string) bool {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	if !dryRun {
+		return false
+	}
+	record := DryRunRecord{Executable: executable, Params: params, Dir: c.dir, Env: c.env}
+	dryRunRecords = append(dryRunRecords, record)
+	c.exitCode = 0
+	log.Entry().Infof("dry-run: skipping command: %v", record)
+	return true
+}
+
+type dryRunExecution struct{}
+
+func (dryRunExecution) Kill() error {
+	return nil
+}
+
+func (dryRunExecution) Wait() error {
+	return nil
+}
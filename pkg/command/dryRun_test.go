@@ -0,0 +1,46 @@
+//go:build unit
+// +build unit
+
+package command
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	ExecCommand = func(name string, arg ...string) *exec.Cmd {
+		t.Fatalf("command '%v' must not be executed in dry-run mode", name)
+		return nil
+	}
+	defer func() { ExecCommand = exec.Command }()
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	c := Command{}
+	c.SetDir("/tmp/dir")
+	c.AppendEnv([]string{"KEY=value"})
+
+	assert.NoError(t, c.RunExecutable("mvn", "clean", "install"))
+	assert.NoError(t, c.RunShell("/bin/bash", "echo hello"))
+	execution, err := c.RunExecutableInBackground("npm", "start")
+	assert.NoError(t, err)
+	assert.NoError(t, execution.Wait())
+	assert.NoError(t, execution.Kill())
+
+	records := DryRunRecords()
+	assert.Equal(t, []DryRunRecord{
+		{Executable: "mvn", Params: []string{"clean", "install"}, Dir: "/tmp/dir", Env: []string{"KEY=value"}},
+		{Executable: "/bin/bash", Params: []string{"echo hello"}, Dir: "/tmp/dir", Env: []string{"KEY=value"}},
+		{Executable: "npm", Params: []string{"start"}, Dir: "/tmp/dir", Env: []string{"KEY=value"}},
+	}, records)
+	assert.Equal(t, "mvn clean install", records[0].String())
+	assert.Equal(t, 0, c.GetExitCode())
+
+	t.Run("records are reset", func(t *testing.T) {
+		SetDryRun(false)
+		assert.Empty(t, DryRunRecords())
+	})
+}
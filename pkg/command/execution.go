@@ -1,9 +1,11 @@
 package command
 
 import (
-	"github.com/SAP/jenkins-library/pkg/log"
 	"os/exec"
 	"sync"
+	"sync/atomic"
+
+	"github.com/SAP/jenkins-library/pkg/log"
 )
 
 // errCopyStdout and errCopyStderr are filled after the command execution after Wait() terminates
@@ -13,6 +15,11 @@ type execution struct {
 	errCopyStdout error
 	errCopyStderr error
 	ul            *log.URLLogger
+	// transient is set if the console output indicates a transient failure
+	transient atomic.Bool
+	// stdoutTail and stderrTail keep the end of the output for the transcript
+	stdoutTail *tailBuffer
+	stderrTail *tailBuffer
 }
 
 func (execution *execution) Kill() error {
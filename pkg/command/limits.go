@@ -0,0 +1,132 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// ExecutionOptions define timeouts, retries and resource limits which apply to all calls to executables
+type ExecutionOptions struct {
+	// Timeout after which the executable and its child processes are killed, no timeout is applied if it is zero
+	Timeout time.Duration
+	// Retries defines how often a call which failed with a transient error is repeated
+	Retries int
+	// RetryBackoff is the delay before the first retry, it is doubled for every further retry
+	RetryBackoff time.Duration
+	// RetryCategories are the error categories which are considered transient, defaults to infrastructure.
+	// The category of a failure is detected from the console output via the ErrorCategoryMapping of the command.
+	RetryCategories []string
+	// RetryPatterns are console output patterns which are considered transient, e.g. "Connection reset"
+	RetryPatterns []string
+	// MaxOutputSize limits the bytes of stdout and stderr which are forwarded to the log or the console per call.
+	// Output written to any other writer, e.g. a buffer which is parsed by the step, is not limited.
+	MaxOutputSize int64
+	// MemoryLimit limits the memory of the executable and its child processes in bytes (Linux with cgroup v2 only)
+	MemoryLimit int64
+	// CPULimit limits the CPUs available to the executable and its child processes, e.g. 1.5 (Linux with cgroup v2 only)
+	CPULimit float64
+}
+
+var (
+	executionOptions      ExecutionOptions
+	executionOptionsMutex sync.Mutex
+)
+
+// SetExecutionOptions sets the options applied to all subsequent calls to executables
+func SetExecutionOptions(options ExecutionOptions) {
+	executionOptionsMutex.Lock()
+	defer executionOptionsMutex.Unlock()
+	executionOptions = options
+}
+
+func currentExecutionOptions() ExecutionOptions {
+	executionOptionsMutex.Lock()
+	defer executionOptionsMutex.Unlock()
+	return executionOptions
+}
+
+// isTransient checks whether a console line indicates a transient failure, matchedCategory is the category detected via the ErrorCategoryMapping
+func (o ExecutionOptions) isTransient(line, matchedCategory string) bool {
+	if o.Retries == 0 {
+		return false
+	}
+	categories := o.RetryCategories
+	if len(categories) == 0 {
+		categories = []string{log.ErrorInfrastructure.String()}
+	}
+	for _, category := range categories {
+		if len(matchedCategory) > 0 && category == matchedCategory {
+			return true
+		}
+	}
+	for _, pattern := range o.RetryPatterns {
+		if matchPattern(line, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// transientError marks failures which are worth to be retried
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// limitedWriter forwards output until the limit is reached and drops the remaining output
+type limitedWriter struct {
+	mutex     sync.Mutex
+	writer    io.Writer
+	remaining int64
+	limit     int64
+	truncated bool
+}
+
+func newLimitedWriter(writer io.Writer, limit int64) io.Writer {
+	if limit <= 0 || !isForwardingWriter(writer) {
+		return writer
+	}
+	return &limitedWriter{writer: writer, remaining: limit, limit: limit}
+}
+
+// isForwardingWriter returns true if the writer only forwards the output to the log or the console
+func isForwardingWriter(writer io.Writer) bool {
+	return writer == os.Stdout || writer == os.Stderr || log.IsWriter(writer)
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.remaining > 0 {
+		forward := p
+		if int64(len(forward)) > w.remaining {
+			forward = forward[:w.remaining]
+		}
+		written, err := w.writer.Write(forward)
+		w.remaining -= int64(written)
+		if err != nil {
+			return written, err
+		}
+		if len(forward) == len(p) {
+			return len(p), nil
+		}
+	}
+	if !w.truncated {
+		w.truncated = true
+		fmt.Fprintf(w.writer, "\n[output truncated after %v bytes]\n", w.limit)
+	}
+	// the complete input is reported as written since the command must not fail because of its output
+	return len(p), nil
+}
@@ -0,0 +1,243 @@
+//go:build linux
+
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	cpuPeriod = 100000
+	// cgroupRemoveAttempts limits the time to wait for the killed processes of a cgroup to 1s
+	cgroupRemoveAttempts = 100
+)
+
+var (
+	cgroupRoot     = "/sys/fs/cgroup"
+	procCgroupFile = "/proc/self/cgroup"
+	cgroupCounter  atomic.Int64
+
+	cgroupParentMutex sync.Mutex
+
+	processGroupsMutex sync.Mutex
+	processGroups      = map[int]bool{}
+	forwardSignalsOnce sync.Once
+)
+
+// prepareProcess runs the executable in its own process group, which allows to terminate its child processes as well,
+// and applies the resource limits via a cgroup. The returned function removes the cgroup once the process finished.
+// If the limits cannot be applied, e.g. since the cgroup controllers are not delegated, the executable runs without them.
+func prepareProcess(cmd *exec.Cmd, options ExecutionOptions, cancellable bool) func() {
+	noop := func() {}
+	if !cancellable && options.MemoryLimit <= 0 && options.CPULimit <= 0 {
+		return noop
+	}
+	attr := &syscall.SysProcAttr{}
+	if cmd.SysProcAttr != nil {
+		copied := *cmd.SysProcAttr
+		attr = &copied
+	}
+	// a new session already makes the executable the leader of its own process group
+	attr.Setpgid = !attr.Setsid
+	cmd.SysProcAttr = attr
+
+	if options.MemoryLimit <= 0 && options.CPULimit <= 0 {
+		return noop
+	}
+	dir, err := createCgroup(options)
+	if err != nil {
+		log.Entry().WithError(err).Warn("Resource limits are not supported, the executable runs without them")
+		return noop
+	}
+	fd, err := syscall.Open(dir, syscall.O_DIRECTORY|syscall.O_RDONLY, 0)
+	if err != nil {
+		os.Remove(dir)
+		log.Entry().WithError(err).Warn("Failed to open cgroup, the executable runs without resource limits")
+		return noop
+	}
+	attr.UseCgroupFD = true
+	attr.CgroupFD = fd
+	return func() {
+		syscall.Close(fd)
+		removeCgroup(dir)
+	}
+}
+
+// removeCgroup kills the processes which remain in the cgroup, e.g. daemons started by the executable, and removes it.
+// A cgroup can only be removed once all of its processes terminated.
+func removeCgroup(dir string) {
+	if err := killCgroup(dir); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to kill the remaining processes of cgroup %v", dir)
+	}
+	var err error
+	for attempt := 0; attempt < cgroupRemoveAttempts; attempt++ {
+		// the processes are killed asynchronously, the cgroup is busy until they terminated
+		if err = os.Remove(dir); !errors.Is(err, syscall.EBUSY) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to remove cgroup %v", dir)
+	}
+}
+
+// killCgroup kills all processes of the cgroup via cgroup.kill, kernels before 5.14 do not provide it, thus the processes are killed one by one
+func killCgroup(dir string) error {
+	kill, err := os.OpenFile(filepath.Join(dir, "cgroup.kill"), os.O_WRONLY, 0)
+	if err == nil {
+		defer kill.Close()
+		_, err = kill.Write([]byte("1"))
+		return err
+	}
+	procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	for _, field := range strings.Fields(string(procs)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return fmt.Errorf("invalid process ID '%v': %w", field, err)
+		}
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("failed to kill process %v: %w", pid, err)
+		}
+	}
+	return nil
+}
+
+// watchProcess forwards SIGINT and SIGTERM received by piper to the process group of the started executable.
+// Since the executable runs in its own process group, it would otherwise keep running once piper is terminated, e.g. when a build is aborted.
+// The returned function stops the forwarding once the executable finished.
+func watchProcess(cmd *exec.Cmd) func() {
+	if cmd.SysProcAttr == nil || !(cmd.SysProcAttr.Setpgid || cmd.SysProcAttr.Setsid) {
+		return func() {}
+	}
+	forwardSignalsOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			sig := <-signals
+			processGroupsMutex.Lock()
+			for pgid := range processGroups {
+				syscall.Kill(-pgid, sig.(syscall.Signal))
+			}
+			processGroupsMutex.Unlock()
+			// terminate piper like without the forwarding
+			signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+			syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+		}()
+	})
+	pgid := cmd.Process.Pid
+	processGroupsMutex.Lock()
+	processGroups[pgid] = true
+	processGroupsMutex.Unlock()
+	return func() {
+		processGroupsMutex.Lock()
+		delete(processGroups, pgid)
+		processGroupsMutex.Unlock()
+	}
+}
+
+// createCgroup creates a child cgroup of the cgroup of piper with the memory and CPU limits, only the executable is moved into it
+func createCgroup(options ExecutionOptions) (string, error) {
+	controllers := []string{}
+	if options.MemoryLimit > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if options.CPULimit > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	parent, err := cgroupParent(controllers)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(parent, fmt.Sprintf("piper-%v-%v", os.Getpid(), cgroupCounter.Add(1)))
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	if options.MemoryLimit > 0 {
+		if err := os.WriteFile(filepath.Join(dir, "memory.max"), []byte(fmt.Sprint(options.MemoryLimit)), 0644); err != nil {
+			os.Remove(dir)
+			return "", fmt.Errorf("failed to set memory limit: %w", err)
+		}
+	}
+	if options.CPULimit > 0 {
+		quota := fmt.Sprintf("%v %v", int64(options.CPULimit*cpuPeriod), cpuPeriod)
+		if err := os.WriteFile(filepath.Join(dir, "cpu.max"), []byte(quota), 0644); err != nil {
+			os.Remove(dir)
+			return "", fmt.Errorf("failed to set CPU limit: %w", err)
+		}
+	}
+	return dir, nil
+}
+
+// cgroupParent returns the cgroup of piper below which the cgroups of the executables are created.
+// The controllers need to be enabled for its children. cgroup v2 only allows this for a cgroup without processes of its own,
+// thus enabling them fails unless they have been delegated, piper itself is never moved to another cgroup.
+func cgroupParent(controllers []string) (string, error) {
+	cgroupParentMutex.Lock()
+	defer cgroupParentMutex.Unlock()
+	parent, err := currentCgroup()
+	if err != nil {
+		return "", err
+	}
+	subtreeControl := filepath.Join(parent, "cgroup.subtree_control")
+	enabled, err := os.ReadFile(subtreeControl)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the controllers of cgroup %v: %w", parent, err)
+	}
+	missing := []string{}
+	for _, controller := range controllers {
+		if !piperutils.ContainsString(strings.Fields(string(enabled)), controller) {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return parent, nil
+	}
+	if err := os.WriteFile(subtreeControl, []byte(strings.Join(missing, " ")), 0644); err != nil {
+		return "", fmt.Errorf("failed to enable the controllers %v of cgroup %v, either they are not delegated or processes are part of the cgroup: %w", strings.Join(missing, " "), parent, err)
+	}
+	return parent, nil
+}
+
+// currentCgroup returns the directory of the cgroup v2 the current process belongs to
+func currentCgroup() (string, error) {
+	file, err := os.Open(procCgroupFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cgroup of the process: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// cgroup v2 uses a single hierarchy with the ID 0, e.g. 0::/user.slice/session-1.scope
+		if path, found := strings.CutPrefix(scanner.Text(), "0::"); found {
+			return filepath.Join(cgroupRoot, path), nil
+		}
+	}
+	return "", fmt.Errorf("cgroup v2 is not available")
+}
+
+// terminate kills the process group of the executable
+func terminate(cmd *exec.Cmd) error {
+	if cmd.SysProcAttr != nil && (cmd.SysProcAttr.Setpgid || cmd.SysProcAttr.Setsid) {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,121 @@
+//go:build unit && linux
+// +build unit,linux
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+func TestCreateCgroup(t *testing.T) {
+	root := t.TempDir()
+	procFile := filepath.Join(t.TempDir(), "cgroup")
+	parent := filepath.Join(root, "system.slice", "agent.service")
+	require.NoError(t, os.MkdirAll(parent, 0755))
+	originalRoot, originalProcFile := cgroupRoot, procCgroupFile
+	cgroupRoot, procCgroupFile = root, procFile
+	defer func() { cgroupRoot, procCgroupFile = originalRoot, originalProcFile }()
+	require.NoError(t, os.WriteFile(procFile, []byte("0::/system.slice/agent.service\n"), 0644))
+
+	t.Run("limits", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("cpu memory pids\n"), 0644))
+
+		dir, err := createCgroup(ExecutionOptions{MemoryLimit: 1073741824, CPULimit: 1.5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, parent, filepath.Dir(dir))
+		// piper stays in its cgroup, only the executable is moved into the child cgroup
+		assert.NoFileExists(t, filepath.Join(parent, "piper", "cgroup.procs"))
+		memory, _ := os.ReadFile(filepath.Join(dir, "memory.max"))
+		assert.Equal(t, "1073741824", string(memory))
+		cpu, _ := os.ReadFile(filepath.Join(dir, "cpu.max"))
+		assert.Equal(t, "150000 100000", string(cpu))
+	})
+
+	t.Run("missing controllers are enabled", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("cpu\n"), 0644))
+
+		_, err := createCgroup(ExecutionOptions{MemoryLimit: 1073741824, CPULimit: 1.5})
+
+		assert.NoError(t, err)
+		controllers, _ := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+		assert.Equal(t, "+memory", string(controllers))
+	})
+
+	t.Run("cgroup v1", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(procFile, []byte("12:memory:/docker/abc\n"), 0644))
+		defer os.WriteFile(procFile, []byte("0::/system.slice/agent.service\n"), 0644)
+
+		_, err := createCgroup(ExecutionOptions{MemoryLimit: 1073741824})
+
+		assert.EqualError(t, err, "cgroup v2 is not available")
+	})
+}
+
+func TestPrepareProcess(t *testing.T) {
+	originalProcFile := procCgroupFile
+	procCgroupFile = filepath.Join(t.TempDir(), "cgroup")
+	defer func() { procCgroupFile = originalProcFile }()
+	require.NoError(t, os.WriteFile(procCgroupFile, []byte("12:memory:/docker/abc\n"), 0644))
+
+	t.Run("limits not supported", func(t *testing.T) {
+		cmd := exec.Command("true")
+
+		cleanup := prepareProcess(cmd, ExecutionOptions{MemoryLimit: 1073741824}, false)
+
+		cleanup()
+		// the executable runs without the limits
+		assert.False(t, cmd.SysProcAttr.UseCgroupFD)
+		assert.True(t, cmd.SysProcAttr.Setpgid)
+	})
+
+	t.Run("process group", func(t *testing.T) {
+		cmd := exec.Command("true")
+
+		cleanup := prepareProcess(cmd, ExecutionOptions{}, true)
+
+		cleanup()
+		assert.True(t, cmd.SysProcAttr.Setpgid)
+	})
+}
+
+func TestRemoveCgroup(t *testing.T) {
+	t.Run("empty cgroup", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "piper-1-1")
+		require.NoError(t, os.Mkdir(dir, 0755))
+
+		removeCgroup(dir)
+
+		assert.NoDirExists(t, dir)
+	})
+
+	t.Run("remaining processes are killed", func(t *testing.T) {
+		_, hook := test.NewNullLogger()
+		log.RegisterHook(hook)
+		defer hook.Reset()
+		// a directory of a regular file system keeps its files, unlike a cgroup, thus the removal fails
+		dir := filepath.Join(t.TempDir(), "piper-1-2")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		daemon := exec.Command("sleep", "30")
+		require.NoError(t, daemon.Start())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(fmt.Sprintf("%v\n", daemon.Process.Pid)), 0644))
+
+		removeCgroup(dir)
+
+		assert.EqualError(t, daemon.Wait(), "signal: killed")
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, fmt.Sprintf("Failed to remove cgroup %v", dir), hook.LastEntry().Message)
+	})
+}
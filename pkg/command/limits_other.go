@@ -0,0 +1,27 @@
+//go:build !linux
+
+package command
+
+import (
+	"os/exec"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// prepareProcess ignores the resource limits, since they are only supported on Linux
+func prepareProcess(_ *exec.Cmd, options ExecutionOptions, _ bool) func() {
+	if options.MemoryLimit > 0 || options.CPULimit > 0 {
+		log.Entry().Warn("Resource limits are not supported, they require Linux with cgroup v2, the executable runs without them")
+	}
+	return func() {}
+}
+
+// watchProcess does nothing, the executable is part of the process group of piper
+func watchProcess(_ *exec.Cmd) func() {
+	return func() {}
+}
+
+// terminate kills the executable
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,180 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// transcriptOutputLimit is the number of bytes of stdout and stderr kept per call, the end of the output is kept since it usually explains failures
+const transcriptOutputLimit = 4096
+
+var sensitiveEnvName = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private|_key$|apikey)`)
+
+// Transcript records the calls to executables of a step run
+type Transcript struct {
+	StepName string            `json:"stepName"`
+	Commands []TranscriptEntry `json:"commands"`
+}
+
+// TranscriptEntry describes a single call to an executable, secrets are masked in all values
+type TranscriptEntry struct {
+	Executable string            `json:"executable"`
+	Arguments  []string          `json:"arguments"`
+	Script     string            `json:"script,omitempty"`
+	Dir        string            `json:"dir,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	Attempt    int               `json:"attempt,omitempty"`
+	StartTime  time.Time         `json:"startTime"`
+	DurationMs int64             `json:"durationMs"`
+	ExitCode   int               `json:"exitCode"`
+	Error      string            `json:"error,omitempty"`
+	Stdout     string            `json:"stdout,omitempty"`
+	Stderr     string            `json:"stderr,omitempty"`
+}
+
+var transcript = struct {
+	sync.Mutex
+	file string
+	data Transcript
+}{}
+
+// EnableTranscript records all subsequent calls to executables of the step in the JSON file.
+// The file is rewritten after every call, thus it is complete even if the step terminates abnormally.
+func EnableTranscript(stepName, file string) error {
+	transcript.Lock()
+	defer transcript.Unlock()
+	transcript.file = file
+	transcript.data = Transcript{StepName: stepName, Commands: []TranscriptEntry{}}
+	return writeTranscript()
+}
+
+// DisableTranscript stops recording calls to executables
+func DisableTranscript() {
+	transcript.Lock()
+	defer transcript.Unlock()
+	transcript.file = ""
+	transcript.data = Transcript{}
+}
+
+// TranscriptFile returns the file the transcript is recorded in, it is empty if no transcript is recorded
+func TranscriptFile() string {
+	transcript.Lock()
+	defer transcript.Unlock()
+	return transcript.file
+}
+
+func transcriptEnabled() bool {
+	transcript.Lock()
+	defer transcript.Unlock()
+	return len(transcript.file) > 0
+}
+
+// recordTranscript adds the finished call to the transcript
+func recordTranscript(cmd *exec.Cmd, script string, attempt int, start time.Time, exitCode int, err error, execution *execution) {
+	entry := TranscriptEntry{
+		Executable: log.MaskSecrets(cmd.Args[0]),
+		Arguments:  []string{},
+		Script:     log.MaskSecrets(script),
+		Dir:        cmd.Dir,
+		Env:        environmentDiff(cmd.Env),
+		StartTime:  start.UTC(),
+		DurationMs: time.Since(start).Milliseconds(),
+		ExitCode:   exitCode,
+	}
+	for _, arg := range cmd.Args[1:] {
+		entry.Arguments = append(entry.Arguments, log.MaskSecrets(arg))
+	}
+	if attempt > 1 {
+		entry.Attempt = attempt
+	}
+	if err != nil {
+		entry.Error = log.MaskSecrets(err.Error())
+	}
+	if execution != nil && execution.stdoutTail != nil {
+		entry.Stdout = log.MaskSecrets(execution.stdoutTail.String())
+		entry.Stderr = log.MaskSecrets(execution.stderrTail.String())
+	}
+
+	transcript.Lock()
+	defer transcript.Unlock()
+	if len(transcript.file) == 0 {
+		return
+	}
+	transcript.data.Commands = append(transcript.data.Commands, entry)
+	if err := writeTranscript(); err != nil {
+		log.Entry().WithError(err).Warn("failed to write command transcript")
+	}
+}
+
+func writeTranscript() error {
+	content, err := json.MarshalIndent(transcript.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(transcript.file, content, 0644)
+}
+
+// environmentDiff returns the variables which are set for the call in addition to the environment of the current process
+func environmentDiff(env []string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+	current := map[string]bool{}
+	for _, variable := range os.Environ() {
+		current[variable] = true
+	}
+	diff := map[string]string{}
+	for _, variable := range env {
+		if current[variable] {
+			continue
+		}
+		name, value, _ := strings.Cut(variable, "=")
+		if sensitiveEnvName.MatchString(name) && len(value) > 0 {
+			value = "****"
+		}
+		diff[name] = log.MaskSecrets(value)
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// tailBuffer keeps the last bytes written to it
+type tailBuffer struct {
+	mutex     sync.Mutex
+	limit     int
+	content   []byte
+	truncated bool
+}
+
+func newTailBuffer(limit int) *tailBuffer {
+	return &tailBuffer{limit: limit}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.content = append(b.content, p...)
+	if len(b.content) > b.limit {
+		b.content = append([]byte{}, b.content[len(b.content)-b.limit:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.truncated {
+		return "[...]" + string(b.content)
+	}
+	return string(b.content)
+}
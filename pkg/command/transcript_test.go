@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	defer DisableTranscript()
+
+	file := filepath.Join(t.TempDir(), "mavenBuild_transcript.json")
+	require.NoError(t, EnableTranscript("mavenBuild", file))
+	log.RegisterSecret("transcript-secret")
+
+	ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	ex.SetDir(t.TempDir())
+	ex.SetEnv([]string{"MAVEN_OPTS=-Xmx1g", "DEPLOY_TOKEN=abc"})
+	assert.NoError(t, ex.RunExecutable("echo", "-Dpassword=transcript-secret"))
+	assert.Error(t, ex.RunShell("/bin/sh", "exit 1"))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var recorded Transcript
+	require.NoError(t, json.Unmarshal(content, &recorded))
+
+	assert.Equal(t, "mavenBuild", recorded.StepName)
+	require.Len(t, recorded.Commands, 2)
+	echo := recorded.Commands[0]
+	assert.Equal(t, []string{"-Dpassword=****"}, echo.Arguments[len(echo.Arguments)-1:])
+	// the helper process replaces the environment with GO_WANT_HELPER_PROCESS
+	assert.Equal(t, map[string]string{"GO_WANT_HELPER_PROCESS": "1", "MAVEN_OPTS": "-Xmx1g", "DEPLOY_TOKEN": "****"}, echo.Env)
+	assert.Equal(t, 0, echo.ExitCode)
+	assert.Equal(t, "-Dpassword=****\n", echo.Stdout)
+	assert.Contains(t, echo.Stderr, "Stderr: command echo")
+	assert.NotContains(t, string(content), "transcript-secret")
+
+	shell := recorded.Commands[1]
+	assert.Equal(t, "exit 1", shell.Script)
+	assert.Equal(t, 2, shell.ExitCode)
+	assert.NotEmpty(t, shell.Error)
+}
+
+func TestTranscriptDisabled(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+
+	ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	assert.NoError(t, ex.RunExecutable("echo", "foo"))
+	assert.False(t, transcriptEnabled())
+}
+
+func TestTailBuffer(t *testing.T) {
+	buffer := newTailBuffer(5)
+	buffer.Write([]byte("abc"))
+	assert.Equal(t, "abc", buffer.String())
+
+	buffer.Write([]byte("defg"))
+	assert.Equal(t, "[...]cdefg", buffer.String())
+}
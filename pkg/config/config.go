@@ -31,12 +31,27 @@ type Config struct {
 	accessTokens     map[string]string
 	openFile         func(s string, t map[string]string) (io.ReadCloser, error)
 	vaultCredentials VaultCredentials
+	// skipSecrets disables reading secrets from the secret backend, see DisableSecretResolution
+	skipSecrets bool
+	// temporaryVaultCredentials enables the Vault login via identity token and dynamic secrets, see EnableTemporaryVaultCredentials
+	temporaryVaultCredentials bool
+	// envRootPath is required to resolve references to the commonPipelineEnvironment, see SetEnvRootPath
+	envRootPath string
+	// source tracking, see EnableSourceTracking
+	trackSources        bool
+	defaultsNames       []string
+	customDefaultsCount int
+	resolvedAliases     map[string]string
 }
 
 // StepConfig defines the structure for merged step configuration
 type StepConfig struct {
 	Config     map[string]interface{}
 	HookConfig map[string]interface{}
+	// Sources contains the source of each configuration value, only available if source tracking is enabled
+	Sources map[string]ValueSource
+	// assigned collects the keys written by the merge operation which is currently tracked
+	assigned map[string]bool
 }
 
 // ReadConfig loads config and returns its content
@@ -62,26 +77,34 @@ func (c *Config) ApplyAliasConfig(parameters []StepParameters, secrets []StepSec
 		c.copyStepAliasConfig(stepName, stepAliases)
 	}
 	for _, p := range parameters {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, p.Name, p.Aliases)
-		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, p.Name, p.Aliases)
-		}
-		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, p.Name, p.Aliases)
-		}
+		c.applyParamAlias(filters, stageName, stepName, p.Name, p.Aliases)
 	}
 	for _, s := range secrets {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, s.Name, s.Aliases)
-		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, s.Name, s.Aliases)
-		}
-		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, s.Name, s.Aliases)
-		}
+		c.applyParamAlias(filters, stageName, stepName, s.Name, s.Aliases)
+	}
+}
+
+func (c *Config) applyParamAlias(filters StepFilters, stageName, stepName, name string, aliases []Alias) {
+	var alias string
+	c.General, alias = paramValueFromAlias(stepName, c.General, filters.General, name, aliases)
+	c.recordAlias("general", name, alias)
+	if c.Stages[stageName] != nil {
+		c.Stages[stageName], alias = paramValueFromAlias(stepName, c.Stages[stageName], filters.Stages, name, aliases)
+		c.recordAlias("stages", name, alias)
+	}
+	if c.Steps[stepName] != nil {
+		c.Steps[stepName], alias = paramValueFromAlias(stepName, c.Steps[stepName], filters.Steps, name, aliases)
+		c.recordAlias("steps", name, alias)
 	}
 }
 
 func setParamValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) map[string]interface{} {
+	configMap, _ = paramValueFromAlias(stepName, configMap, filter, name, aliases)
+	return configMap
+}
+
+// paramValueFromAlias sets the parameter value from the first alias providing a value and returns a reference to this alias
+func paramValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) (map[string]interface{}, string) {
 	if configMap != nil && configMap[name] == nil && sliceContains(filter, name) {
 		for _, a := range aliases {
 			aliasVal := getDeepAliasValue(configMap, a.Name)
@@ -92,11 +115,11 @@ func setParamValueFromAlias(stepName string, configMap map[string]interface{}, f
 				}
 			}
 			if configMap[name] != nil {
-				return configMap
+				return configMap, "alias " + a.Name
 			}
 		}
 	}
-	return configMap
+	return configMap, ""
 }
 
 func getDeepAliasValue(configMap map[string]interface{}, key string) interface{} {
@@ -128,6 +151,7 @@ func (c *Config) copyStepAliasConfig(stepName string, stepAliases []Alias) {
 				}
 				if c.Steps[stepName][paramName] == nil {
 					c.Steps[stepName][paramName] = paramValue
+					c.recordAlias("steps", paramName, "step alias "+stepAlias.Name)
 				}
 			}
 		}
@@ -156,6 +180,7 @@ func (c *Config) InitializeConfig(configuration io.ReadCloser, defaults []io.Rea
 			}
 			defaults = append(defaults, fc)
 		}
+		c.customDefaultsCount = len(c.CustomDefaults)
 	}
 
 	if err := c.defaults.ReadPipelineDefaults(defaults); err != nil {
@@ -183,37 +208,73 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 
 	c.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
 
+	if c.trackSources {
+		stepConfig.Sources = map[string]ValueSource{}
+	}
+
 	// initialize with defaults from step.yaml
-	stepConfig.mixInStepDefaults(parameters)
+	stepConfig.trackSource(ValueSource{Layer: SourceStepDefaults}, nil, func() {
+		stepConfig.mixInStepDefaults(parameters)
+	})
 
 	// merge parameters provided by Piper environment
-	stepConfig.mixIn(envParameters, filters.All, metadata)
-	stepConfig.mixIn(envParameters, ReportingParameters.getReportingFilter(), metadata)
+	cpeReferences := resourceReferences(parameters, func(ref ResourceReference) bool { return ref.Name == "commonPipelineEnvironment" })
+	stepConfig.trackSource(ValueSource{Layer: SourceCommonPipelineEnvironment}, cpeReferences, func() {
+		stepConfig.mixIn(envParameters, filters.All, metadata)
+		stepConfig.mixIn(envParameters, ReportingParameters.getReportingFilter(), metadata)
+	})
 
 	// read defaults & merge general -> steps (-> general -> steps ...)
-	for _, def := range c.defaults.Defaults {
+	for i, def := range c.defaults.Defaults {
+		if c.trackSources {
+			def.resolvedAliases = map[string]string{}
+		}
 		def.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
-		stepConfig.mixIn(def.General, filters.General, metadata)
-		stepConfig.mixIn(def.Steps[stepName], filters.Steps, metadata)
-		stepConfig.mixIn(def.Stages[stageName], filters.Steps, metadata)
-		stepConfig.mixinVaultConfig(parameters, def.General, def.Steps[stepName], def.Stages[stageName])
+		stepConfig.trackSource(c.defaultsSource(i, "general"), def.aliasReferences("general"), func() {
+			stepConfig.mixIn(def.General, filters.General, metadata)
+		})
+		stepConfig.trackSource(c.defaultsSource(i, "steps"), def.aliasReferences("steps"), func() {
+			stepConfig.mixIn(def.Steps[stepName], filters.Steps, metadata)
+		})
+		stepConfig.trackSource(c.defaultsSource(i, "stages"), def.aliasReferences("stages"), func() {
+			stepConfig.mixIn(def.Stages[stageName], filters.Steps, metadata)
+		})
+		stepConfig.trackSource(c.defaultsSource(i, "general"), nil, func() {
+			stepConfig.mixinVaultConfig(parameters, def.General)
+		})
+		stepConfig.trackSource(c.defaultsSource(i, "steps"), nil, func() {
+			stepConfig.mixinVaultConfig(parameters, def.Steps[stepName])
+		})
+		stepConfig.trackSource(c.defaultsSource(i, "stages"), nil, func() {
+			stepConfig.mixinVaultConfig(parameters, def.Stages[stageName])
+		})
 		reportingConfig, err := cloneConfig(&def)
 		if err != nil {
 			return StepConfig{}, err
 		}
 		reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
-		stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+		stepConfig.trackSource(c.defaultsSource(i, ""), nil, func() {
+			stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+		})
 
 		stepConfig.mixInHookConfig(def.Hooks, metadata)
 	}
 
 	// read config & merge - general -> steps -> stages
-	stepConfig.mixIn(c.General, filters.General, metadata)
-	stepConfig.mixIn(c.Steps[stepName], filters.Steps, metadata)
-	stepConfig.mixIn(c.Stages[stageName], filters.Stages, metadata)
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "general"}, c.aliasReferences("general"), func() {
+		stepConfig.mixIn(c.General, filters.General, metadata)
+	})
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "steps"}, c.aliasReferences("steps"), func() {
+		stepConfig.mixIn(c.Steps[stepName], filters.Steps, metadata)
+	})
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "stages"}, c.aliasReferences("stages"), func() {
+		stepConfig.mixIn(c.Stages[stageName], filters.Stages, metadata)
+	})
 
 	// merge parameters provided via env vars
-	stepConfig.mixIn(envValues(filters.All), filters.All, metadata)
+	stepConfig.trackSource(ValueSource{Layer: SourceEnvironment}, nil, func() {
+		stepConfig.mixIn(envValues(filters.All), filters.All, metadata)
+	})
 
 	// if parameters are provided in JSON format merge them
 	if len(paramJSON) != 0 {
@@ -230,14 +291,18 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 				params = setParamValueFromAlias(stepName, params, filters.Parameters, s.Name, s.Aliases)
 			}
 
-			stepConfig.mixIn(params, filters.Parameters, metadata)
+			stepConfig.trackSource(ValueSource{Layer: SourceParametersJSON}, nil, func() {
+				stepConfig.mixIn(params, filters.Parameters, metadata)
+			})
 		}
 	}
 
 	// merge command line flags
 	if flagValues != nil {
 		flagFilter := append(filters.Parameters, vaultFilter...)
-		stepConfig.mixIn(flagValues, flagFilter, metadata)
+		stepConfig.trackSource(ValueSource{Layer: SourceFlags}, nil, func() {
+			stepConfig.mixIn(flagValues, flagFilter, metadata)
+		})
 	}
 
 	if verbose, ok := stepConfig.Config["verbose"].(bool); ok && verbose {
@@ -246,51 +311,99 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		log.Entry().Warnf("invalid value for parameter verbose: '%v'", stepConfig.Config["verbose"])
 	}
 
-	stepConfig.mixinVaultConfig(parameters, c.General, c.Steps[stepName], c.Stages[stageName])
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "general"}, nil, func() {
+		stepConfig.mixinVaultConfig(parameters, c.General)
+	})
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "steps"}, nil, func() {
+		stepConfig.mixinVaultConfig(parameters, c.Steps[stepName])
+	})
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig, Section: "stages"}, nil, func() {
+		stepConfig.mixinVaultConfig(parameters, c.Stages[stageName])
+	})
 
 	reportingConfig, err := cloneConfig(c)
 	if err != nil {
 		return StepConfig{}, err
 	}
 	reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
-	stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+	stepConfig.trackSource(ValueSource{Layer: SourceProjectConfig}, nil, func() {
+		stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+	})
+
+	// values read from vault are not resolved, otherwise secrets containing $( could be altered
+	secretKeys := map[string]bool{}
 
 	// check whether vault should be skipped
-	if skip, ok := stepConfig.Config["skipVault"].(bool); !ok || !skip {
-		// fetch secrets from vault
-		vaultClient, err := GetVaultClientFromConfig(stepConfig.Config, c.vaultCredentials)
+	if skip, ok := stepConfig.Config["skipVault"].(bool); !c.skipSecrets && (!ok || !skip) {
+		// fetch secrets from vault or the configured secret backend
+		vaultCredentials := c.vaultCredentials
+		vaultCredentials.IdentityToken = c.temporaryVaultCredentials
+		secretProvider, err := GetSecretProviderFromConfig(stepConfig.Config, vaultCredentials)
 		if err != nil {
 			return StepConfig{}, err
 		}
-		if vaultClient != nil {
-			defer vaultClient.MustRevokeToken()
-			resolveAllVaultReferences(&stepConfig, vaultClient, append(parameters, ReportingParameters.Parameters...))
-			resolveVaultTestCredentialsWrapper(&stepConfig, vaultClient)
-			resolveVaultCredentialsWrapper(&stepConfig, vaultClient)
+		if secretProvider != nil {
+			beforeSecrets := make(map[string]interface{}, len(stepConfig.Config))
+			for key, value := range stepConfig.Config {
+				beforeSecrets[key] = value
+			}
+			leasesCreated := false
+			if vaultClient, ok := secretProvider.(VaultClient); ok {
+				defer func() {
+					// leases of dynamic secrets are revoked together with the token, see RevokeVaultLeases
+					if !leasesCreated {
+						vaultClient.MustRevokeToken()
+					}
+				}()
+			}
+			vaultReferences := resourceReferences(append(parameters, ReportingParameters.Parameters...), func(ref ResourceReference) bool {
+				return ref.Type == "vaultSecret" || ref.Type == "vaultSecretFile"
+			})
+			stepConfig.trackSource(ValueSource{Layer: SourceVault}, vaultReferences, func() {
+				resolveAllVaultReferences(&stepConfig, secretProvider, append(parameters, ReportingParameters.Parameters...))
+				resolveVaultTestCredentialsWrapper(&stepConfig, secretProvider)
+				resolveVaultCredentialsWrapper(&stepConfig, secretProvider)
+			})
+			if dynamicSecretClient, ok := secretProvider.(DynamicSecretClient); ok && c.temporaryVaultCredentials {
+				stepConfig.trackSource(ValueSource{Layer: SourceVault}, nil, func() {
+					leasesCreated = resolveDynamicVaultSecrets(&stepConfig, dynamicSecretClient, parameters)
+				})
+			}
+			secretKeys = changedKeys(beforeSecrets, stepConfig.Config)
 		}
 	}
 
+	// resolve expressions like $(orchestrator.branch | lower), references to other parameters may use values read from vault
+	stepConfig.resolveExpressions(c.expressionResolver(), secretKeys)
+
 	// finally do the condition evaluation post processing
+	stepConfig.trackSource(ValueSource{Layer: SourceCondition}, nil, func() {
+		stepConfig.applyParameterConditions(parameters)
+	})
+	return stepConfig, nil
+}
+
+// applyParameterConditions applies conditional defaults once the complete configuration is known
+func (s *StepConfig) applyParameterConditions(parameters []StepParameters) {
 	for _, p := range parameters {
 		if len(p.Conditions) > 0 {
 			for _, cond := range p.Conditions {
 				for _, param := range cond.Params {
 					// retrieve configuration value of condition parameter
-					dependentValue := stepConfig.Config[param.Name]
+					dependentValue := s.Config[param.Name]
 					// check if configuration of condition parameter matches the value
 					// so far string-equals condition is assumed here
 					// if so and if no config applied yet, then try to apply the value
-					if cmp.Equal(dependentValue, param.Value) && stepConfig.Config[p.Name] == nil {
-						subMap, ok := stepConfig.Config[dependentValue.(string)].(map[string]interface{})
+					if cmp.Equal(dependentValue, param.Value) && s.Config[p.Name] == nil {
+						subMap, ok := s.Config[dependentValue.(string)].(map[string]interface{})
 						if ok && subMap[p.Name] != nil {
-							stepConfig.Config[p.Name] = subMap[p.Name]
+							s.Config[p.Name] = subMap[p.Name]
 						}
 					}
 				}
 			}
 		}
 	}
-	return stepConfig, nil
 }
 
 // SetVaultCredentials sets the appRoleID and the appRoleSecretID or the vaultTokento load additional
@@ -304,6 +417,18 @@ func (c *Config) SetVaultCredentials(appRoleID, appRoleSecretID string, vaultTok
 	}
 }
 
+// DisableSecretResolution disables reading the secrets referenced in the configuration from Vault or the configured secret backend.
+// It is meant for commands like getConfig which print the configuration.
+func (c *Config) DisableSecretResolution() {
+	c.skipSecrets = true
+}
+
+// EnableTemporaryVaultCredentials enables the Vault login with the identity token of the orchestrator and the generation of dynamic secrets.
+// The token and the leases created thereby are revoked by RevokeVaultLeases, thus it must only be enabled for the step process which calls it once the step finished.
+func (c *Config) EnableTemporaryVaultCredentials() {
+	c.temporaryVaultCredentials = true
+}
+
 // GetStepConfigWithJSON provides merged step configuration using a provided stepConfigJSON with additional flags provided
 func GetStepConfigWithJSON(flagValues map[string]interface{}, stepConfigJSON string, filters StepFilters) StepConfig {
 	var stepConfig StepConfig
@@ -410,7 +535,9 @@ func (s *StepConfig) mixIn(mergeData map[string]interface{}, filter []string, me
 		s.Config = map[string]interface{}{}
 	}
 
-	s.Config = merge(s.Config, filterMap(mergeData, filter), metadata)
+	filtered := filterMap(mergeData, filter)
+	s.recordAssignment(filtered)
+	s.Config = merge(s.Config, filtered, metadata)
 }
 
 func (s *StepConfig) mixInHookConfig(mergeData map[string]interface{}, metadata StepData) {
@@ -433,10 +560,12 @@ func (s *StepConfig) mixInStepDefaults(stepParams []StepParameters) {
 		if p.Default != nil {
 			if len(p.Conditions) == 0 {
 				s.Config[p.Name] = p.Default
+				s.recordAssignment(map[string]interface{}{p.Name: p.Default})
 			} else {
 				for _, cond := range p.Conditions {
 					for _, param := range cond.Params {
 						s.Config[param.Value] = map[string]interface{}{p.Name: p.Default}
+						s.recordAssignment(map[string]interface{}{param.Value: nil})
 					}
 				}
 			}
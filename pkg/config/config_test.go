@@ -282,6 +282,19 @@ steps:
 
 	})
 
+	t.Run("Secrets not resolved if disabled", func(t *testing.T) {
+		testConf := "general:\n secretBackend: keepass"
+
+		var c Config
+		_, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(testConf)), nil, false, StepFilters{General: []string{"p0"}}, StepData{}, nil, "stage1", "step1")
+		assert.EqualError(t, err, "secret backend 'keepass' not supported, use one of vault, azureKeyVault, awsSecretsManager, gcpSecretManager, sops")
+
+		c = Config{}
+		c.DisableSecretResolution()
+		_, err = c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(testConf)), nil, false, StepFilters{General: []string{"p0"}}, StepData{}, nil, "stage1", "step1")
+		assert.NoError(t, err)
+	})
+
 	t.Run("Consider defaults from step config", func(t *testing.T) {
 		var c Config
 
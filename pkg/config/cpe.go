@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+)
+
+// CPESchema derives the known keys of the commonPipelineEnvironment and their types from the outputs declared in the step metadata
+func CPESchema(steps map[string]StepData) piperenv.CPESchema {
+	schema := piperenv.CPESchema{}
+	for stepName, step := range steps {
+		for _, resource := range step.Spec.Outputs.Resources {
+			if resource.Type != "piperEnvironment" || resource.Name != piperenv.CommonPipelineEnvironment {
+				continue
+			}
+			for _, param := range resource.Parameters {
+				name, _ := param["name"].(string)
+				if len(name) == 0 {
+					continue
+				}
+				// the generator treats parameters without type as string
+				paramType := "string"
+				if param["type"] != nil {
+					paramType = fmt.Sprint(param["type"])
+				}
+				sensitive, _ := param["sensitive"].(bool)
+				schema.AddKey(name, paramType, stepName, sensitive)
+			}
+		}
+	}
+	return schema
+}
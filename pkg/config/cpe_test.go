@@ -0,0 +1,33 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPESchema(t *testing.T) {
+	cpeOutput := func(params ...map[string]interface{}) StepData {
+		return StepData{Spec: StepSpec{Outputs: StepOutputs{Resources: []StepResources{
+			{Name: "commonPipelineEnvironment", Type: "piperEnvironment", Parameters: params},
+			{Name: "influx", Type: "influx", Parameters: []map[string]interface{}{{"name": "step_data"}}},
+		}}}}
+	}
+	schema := CPESchema(map[string]StepData{
+		"artifactPrepareVersion":                    cpeOutput(map[string]interface{}{"name": "artifactVersion"}, map[string]interface{}{"name": "git/commitId"}),
+		"kanikoExecute":                             cpeOutput(map[string]interface{}{"name": "container/imageNames", "type": "[]string"}),
+		"integrationArtifactTriggerIntegrationTest": cpeOutput(map[string]interface{}{"name": "custom/responseHeaders", "sensitive": true}),
+		"cnbBuild": cpeOutput(map[string]interface{}{"name": "container/imageNames", "type": "[]string"}),
+	})
+
+	assert.Equal(t, piperenv.CPESchema{
+		"artifactVersion":        {Type: "string", Steps: []string{"artifactPrepareVersion"}},
+		"git/commitId":           {Type: "string", Steps: []string{"artifactPrepareVersion"}},
+		"container/imageNames":   {Type: "[]string", Steps: []string{"cnbBuild", "kanikoExecute"}},
+		"custom/responseHeaders": {Type: "string", Steps: []string{"integrationArtifactTriggerIntegrationTest"}, Sensitive: true},
+	}, schema)
+}
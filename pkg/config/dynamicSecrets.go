@@ -0,0 +1,146 @@
+package config
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/config/interpolation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/vault"
+)
+
+const vaultDynamicSecrets = "vaultDynamicSecrets"
+
+// DynamicSecretClient is implemented by Vault clients which support dynamic secrets engines
+type DynamicSecretClient interface {
+	GetDynamicSecret(path string, parameters map[string]interface{}) (*vault.DynamicSecret, error)
+	RevokeLease(leaseID string) error
+	RevokeToken() error
+}
+
+// vaultLeases keeps track of the leases of dynamic secrets, which are revoked once the step finished
+var vaultLeases = struct {
+	sync.Mutex
+	client   DynamicSecretClient
+	leaseIDs []string
+}{}
+
+// resolveDynamicVaultSecrets generates the credentials configured via vaultDynamicSecrets, e.g.
+//
+//	vaultDynamicSecrets:
+//	  username: {path: database/creds/deployer, field: username}
+//	  password: {path: database/creds/deployer, field: password}
+//	  certificate: {path: pki/issue/deployer, field: certificate, file: true, data: {common_name: deployer.example.com}}
+//
+// Parameters referring to the same path share a single credential.
+// It returns true if leases have been created, which need to be revoked by RevokeVaultLeases.
+func resolveDynamicVaultSecrets(config *StepConfig, client DynamicSecretClient, params []StepParameters) bool {
+	references, ok := config.Config[vaultDynamicSecrets].(map[string]interface{})
+	if !ok || len(references) == 0 {
+		return false
+	}
+	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
+
+	names := make([]string, 0, len(references))
+	for name := range references {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	secrets := map[string]*vault.DynamicSecret{}
+	leasesCreated := false
+	for _, name := range names {
+		if !isStepParameter(name, params) {
+			log.Entry().Warnf("Ignoring dynamic secret for '%s' since the step has no such parameter", name)
+			continue
+		}
+		if _, ok := config.Config[name].(string); vaultDisableOverwrite && ok {
+			log.Entry().Debugf("Not fetching '%s' from Vault since it has already been set", name)
+			continue
+		}
+		reference, ok := references[name].(map[string]interface{})
+		secretPath, pathOk := reference["path"].(string)
+		field, fieldOk := reference["field"].(string)
+		if !ok || !pathOk || !fieldOk {
+			log.Entry().Warnf("Dynamic secret for '%s' requires the fields 'path' and 'field'", name)
+			continue
+		}
+		if resolvedPath, ok := interpolation.ResolveString(secretPath, config.Config); ok {
+			secretPath = resolvedPath
+		}
+
+		log.Entry().Infof("Resolving '%s' via dynamic secret '%s'", name, secretPath)
+		secret, ok := secrets[secretPath]
+		if !ok {
+			parameters, _ := reference["data"].(map[string]interface{})
+			var err error
+			secret, err = client.GetDynamicSecret(secretPath, parameters)
+			if err != nil {
+				log.Entry().WithError(err).Warnf("Couldn't generate dynamic secret at '%s'", secretPath)
+				continue
+			}
+			for _, value := range secret.Data {
+				log.RegisterSecret(value)
+			}
+			if len(secret.LeaseID) > 0 {
+				registerVaultLease(client, secret.LeaseID)
+				leasesCreated = true
+				log.Entry().Debugf("  lease '%s' valid for %v", secret.LeaseID, secret.LeaseDuration)
+			}
+			secrets[secretPath] = secret
+		}
+
+		value, ok := secret.Data[field]
+		if !ok {
+			log.Entry().Warnf("Dynamic secret at '%s' did not contain a field '%s'", secretPath, field)
+			continue
+		}
+		if asFile, _ := reference["file"].(bool); asFile {
+			filePath, err := createTemporarySecretFile(name, value)
+			if err != nil {
+				log.Entry().WithError(err).Warnf("Couldn't create temporary secret file for '%s'", name)
+				continue
+			}
+			value = filePath
+		}
+		config.Config[name] = value
+	}
+	return leasesCreated
+}
+
+func isStepParameter(name string, params []StepParameters) bool {
+	for _, param := range params {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func registerVaultLease(client DynamicSecretClient, leaseID string) {
+	vaultLeases.Lock()
+	defer vaultLeases.Unlock()
+	vaultLeases.client = client
+	vaultLeases.leaseIDs = append(vaultLeases.leaseIDs, leaseID)
+}
+
+// RevokeVaultLeases revokes the leases of all dynamic secrets generated for the step as well as the Vault token used to create them
+func RevokeVaultLeases() {
+	vaultLeases.Lock()
+	defer vaultLeases.Unlock()
+	if vaultLeases.client == nil {
+		return
+	}
+	for _, leaseID := range vaultLeases.leaseIDs {
+		if err := vaultLeases.client.RevokeLease(leaseID); err != nil {
+			log.Entry().WithError(err).Warnf("Could not revoke lease '%s'", leaseID)
+		}
+	}
+	log.Entry().Debugf("Revoked %v leases of dynamic secrets", len(vaultLeases.leaseIDs))
+	// revoking the token would also revoke its leases, which is why it has been kept until now
+	if err := vaultLeases.client.RevokeToken(); err != nil {
+		log.Entry().WithError(err).Warn("Could not revoke token")
+	}
+	vaultLeases.client = nil
+	vaultLeases.leaseIDs = nil
+}
@@ -0,0 +1,133 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/vault"
+	"github.com/stretchr/testify/assert"
+)
+
+type dynamicSecretClientMock struct {
+	secrets        map[string]*vault.DynamicSecret
+	requests       []string
+	revokedLeases  []string
+	tokenRevoked   bool
+	revokeLeaseErr error
+}
+
+func (d *dynamicSecretClientMock) GetDynamicSecret(path string, parameters map[string]interface{}) (*vault.DynamicSecret, error) {
+	d.requests = append(d.requests, fmt.Sprintf("%v %v", path, parameters))
+	secret, ok := d.secrets[path]
+	if !ok {
+		return nil, fmt.Errorf("permission denied")
+	}
+	return secret, nil
+}
+
+func (d *dynamicSecretClientMock) RevokeLease(leaseID string) error {
+	d.revokedLeases = append(d.revokedLeases, leaseID)
+	return d.revokeLeaseErr
+}
+
+func (d *dynamicSecretClientMock) RevokeToken() error {
+	d.tokenRevoked = true
+	return nil
+}
+
+func TestResolveDynamicVaultSecrets(t *testing.T) {
+	params := []StepParameters{{Name: "username"}, {Name: "password"}, {Name: "certificate"}, {Name: "token"}}
+
+	t.Run("credentials of the same path share a lease", func(t *testing.T) {
+		defer RevokeVaultLeases()
+		defer RemoveVaultSecretFiles()
+		client := &dynamicSecretClientMock{secrets: map[string]*vault.DynamicSecret{
+			"team1/database/creds/deployer": {LeaseID: "lease1", LeaseDuration: time.Hour, Data: map[string]string{"username": "v-deployer", "password": "secret"}},
+			"pki/issue/deployer":            {Data: map[string]string{"certificate": "-----BEGIN CERTIFICATE-----"}},
+		}}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"vaultBasePath": "team1",
+			"username":      "static",
+			"vaultDynamicSecrets": map[string]interface{}{
+				"username":    map[string]interface{}{"path": "$(vaultBasePath)/database/creds/deployer", "field": "username"},
+				"password":    map[string]interface{}{"path": "$(vaultBasePath)/database/creds/deployer", "field": "password"},
+				"certificate": map[string]interface{}{"path": "pki/issue/deployer", "field": "certificate", "file": true, "data": map[string]interface{}{"common_name": "deployer"}},
+				"unknown":     map[string]interface{}{"path": "aws/creds/deployer", "field": "access_key"},
+			},
+		}}
+
+		leasesCreated := resolveDynamicVaultSecrets(&stepConfig, client, params)
+
+		assert.True(t, leasesCreated)
+		assert.Equal(t, "v-deployer", stepConfig.Config["username"])
+		assert.Equal(t, "secret", stepConfig.Config["password"])
+		certificate, err := os.ReadFile(stepConfig.Config["certificate"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, "-----BEGIN CERTIFICATE-----", string(certificate))
+		assert.Equal(t, []string{"pki/issue/deployer map[common_name:deployer]", "team1/database/creds/deployer map[]"}, client.requests)
+
+		RevokeVaultLeases()
+		assert.Equal(t, []string{"lease1"}, client.revokedLeases)
+		assert.True(t, client.tokenRevoked)
+	})
+
+	t.Run("existing values are kept if overwrite is disabled", func(t *testing.T) {
+		defer RevokeVaultLeases()
+		client := &dynamicSecretClientMock{}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"vaultDisableOverwrite": true,
+			"token":                 "static",
+			"vaultDynamicSecrets": map[string]interface{}{
+				"token": map[string]interface{}{"path": "aws/creds/deployer", "field": "secret_key"},
+			},
+		}}
+
+		assert.False(t, resolveDynamicVaultSecrets(&stepConfig, client, params))
+		assert.Equal(t, "static", stepConfig.Config["token"])
+		assert.Empty(t, client.requests)
+	})
+
+	t.Run("failures are ignored", func(t *testing.T) {
+		defer RevokeVaultLeases()
+		client := &dynamicSecretClientMock{secrets: map[string]*vault.DynamicSecret{
+			"aws/creds/deployer": {Data: map[string]string{"access_key": "AKID"}},
+		}}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"vaultDynamicSecrets": map[string]interface{}{
+				"username": map[string]interface{}{"path": "database/creds/unknown", "field": "username"},
+				"password": map[string]interface{}{"path": "aws/creds/deployer"},
+				"token":    map[string]interface{}{"path": "aws/creds/deployer", "field": "secret_key"},
+			},
+		}}
+
+		assert.False(t, resolveDynamicVaultSecrets(&stepConfig, client, params))
+		assert.Nil(t, stepConfig.Config["username"])
+		assert.Nil(t, stepConfig.Config["password"])
+		assert.Nil(t, stepConfig.Config["token"])
+	})
+}
+
+func TestRevokeVaultLeases(t *testing.T) {
+	t.Run("nothing to revoke", func(t *testing.T) {
+		RevokeVaultLeases()
+	})
+
+	t.Run("token is revoked even if revoking a lease fails", func(t *testing.T) {
+		client := &dynamicSecretClientMock{revokeLeaseErr: fmt.Errorf("lease not found")}
+		registerVaultLease(client, "lease1")
+		registerVaultLease(client, "lease2")
+
+		RevokeVaultLeases()
+
+		assert.Equal(t, []string{"lease1", "lease2"}, client.revokedLeases)
+		assert.True(t, client.tokenRevoked)
+		// leases are only revoked once
+		RevokeVaultLeases()
+		assert.Len(t, client.revokedLeases, 2)
+	})
+}
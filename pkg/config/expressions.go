@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config/interpolation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+)
+
+// orchestratorProvider returns the orchestrator providing the values of orchestrator.* references
+var orchestratorProvider = func() (orchestrator.ConfigProvider, error) {
+	return orchestrator.GetOrchestratorConfigProvider(nil)
+}
+
+// exposedEnvPrefix restricts the environment variables available via env. references, other variables may contain
+// credentials of the agent which must not end up in the configuration
+const exposedEnvPrefix = "PIPER_"
+
+// SetEnvRootPath sets the root path of the Piper environment, which is required to resolve references to the
+// commonPipelineEnvironment like $(cpe.artifactVersion) in configuration values
+func (c *Config) SetEnvRootPath(path string) {
+	c.envRootPath = path
+}
+
+// expressionResolver returns the resolver for expressions in configuration values supporting references to
+// environment variables with the prefix PIPER_ (env.), the commonPipelineEnvironment (cpe.) and the orchestrator (orchestrator.)
+func (c *Config) expressionResolver() *interpolation.Resolver {
+	sources := map[string]interpolation.Lookup{
+		"env":          exposedEnvValue,
+		"orchestrator": orchestratorValue,
+	}
+	if len(c.envRootPath) > 0 {
+		envRootPath := c.envRootPath
+		sources["cpe"] = func(key string) (string, bool) {
+			value := piperenv.GetResourceParameter(envRootPath, piperenv.CommonPipelineEnvironment, key)
+			if len(value) == 0 {
+				// values which are not strings are stored JSON encoded
+				value = piperenv.GetResourceParameter(envRootPath, piperenv.CommonPipelineEnvironment, key+".json")
+			}
+			return value, len(value) > 0
+		}
+	}
+	return &interpolation.Resolver{Sources: sources}
+}
+
+// resolveExpressions resolves the expressions in all string values including the items of string lists.
+// Values which are not valid expressions, e.g. $(pwd) within a shell command, are kept unchanged.
+// The values of the skipped parameters, e.g. secrets, are neither resolved nor changed.
+func (s *StepConfig) resolveExpressions(resolver *interpolation.Resolver, skip map[string]bool) {
+	resolve := func(name, value string) string {
+		resolved, errs := resolver.Expand(value, s.Config)
+		for _, err := range errs {
+			log.Entry().Debugf("Expression in value of parameter '%v' not resolved: %v", name, err)
+		}
+		return resolved
+	}
+	for name, value := range s.Config {
+		if skip[name] {
+			continue
+		}
+		switch typedValue := value.(type) {
+		case string:
+			s.Config[name] = resolve(name, typedValue)
+		case []string:
+			resolved := make([]string, len(typedValue))
+			for i, item := range typedValue {
+				resolved[i] = resolve(name, item)
+			}
+			s.Config[name] = resolved
+		case []interface{}:
+			resolved := make([]interface{}, len(typedValue))
+			for i, item := range typedValue {
+				if str, ok := item.(string); ok {
+					resolved[i] = resolve(name, str)
+				} else {
+					resolved[i] = item
+				}
+			}
+			s.Config[name] = resolved
+		}
+	}
+}
+
+// changedKeys returns the keys of the configuration which have been added or changed compared to the previous state
+func changedKeys(previous, current map[string]interface{}) map[string]bool {
+	changed := map[string]bool{}
+	for key, value := range current {
+		if previousValue, ok := previous[key]; !ok || !reflect.DeepEqual(previousValue, value) {
+			changed[key] = true
+		}
+	}
+	return changed
+}
+
+func exposedEnvValue(key string) (string, bool) {
+	if !strings.HasPrefix(key, exposedEnvPrefix) {
+		log.Entry().Debugf("Environment variable '%v' not available in expressions, only variables with the prefix %v are", key, exposedEnvPrefix)
+		return "", false
+	}
+	return os.LookupEnv(key)
+}
+
+func orchestratorValue(key string) (string, bool) {
+	provider, err := orchestratorProvider()
+	if err != nil {
+		log.Entry().Debugf("Orchestrator values not available: %v", err)
+		return "", false
+	}
+	var value string
+	switch key {
+	case "type":
+		value = provider.OrchestratorType()
+	case "branch":
+		value = provider.Branch()
+	case "gitReference":
+		value = provider.GitReference()
+	case "commitSha":
+		value = provider.CommitSHA()
+	case "repoUrl":
+		value = provider.RepoURL()
+	case "buildId":
+		value = provider.BuildID()
+	case "buildUrl":
+		value = provider.BuildURL()
+	case "buildReason":
+		value = provider.BuildReason()
+	case "jobName":
+		value = provider.JobName()
+	case "jobUrl":
+		value = provider.JobURL()
+	case "stageName":
+		value = provider.StageName()
+	case "isPullRequest":
+		value = strconv.FormatBool(provider.IsPullRequest())
+	case "prNumber":
+		value = provider.PullRequestConfig().Key
+	case "prBranch":
+		value = provider.PullRequestConfig().Branch
+	case "prBase":
+		value = provider.PullRequestConfig().Base
+	default:
+		return "", false
+	}
+	// orchestrators return n/a for values which are not available
+	if value == "n/a" {
+		return "", false
+	}
+	return value, len(value) > 0
+}
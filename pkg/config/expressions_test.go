@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orchestratorMock struct {
+	orchestrator.ConfigProvider
+}
+
+func (o *orchestratorMock) Branch() string  { return "Feature/Login" }
+func (o *orchestratorMock) BuildID() string { return "n/a" }
+func (o *orchestratorMock) PullRequestConfig() orchestrator.PullRequestConfig {
+	return orchestrator.PullRequestConfig{Key: "42"}
+}
+
+func TestGetStepConfigExpressions(t *testing.T) {
+	originalProvider := orchestratorProvider
+	orchestratorProvider = func() (orchestrator.ConfigProvider, error) { return &orchestratorMock{}, nil }
+	defer func() { orchestratorProvider = originalProvider }()
+
+	envRootPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(envRootPath, "commonPipelineEnvironment"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(envRootPath, "commonPipelineEnvironment", "artifactVersion"), []byte("1.4.2"), 0666))
+	t.Setenv("PIPER_TEST_REGION", "EU10")
+	t.Setenv("TEST_AGENT_TOKEN", "agentToken")
+
+	projectConfig := `general:
+  space: dev-$(orchestrator.branch | lower | replace / -)
+steps:
+  step1:
+    version: $(cpe.artifactVersion | bump minor)
+    buildId: $(orchestrator.buildId | default local)
+    region: $(env.PIPER_TEST_REGION | lower)
+    command: echo $(git rev-parse HEAD) pr-$(orchestrator.prNumber)
+    token: $(env.TEST_AGENT_TOKEN)
+    tags:
+      - $(space)
+      - latest
+`
+	metadata := StepData{
+		Spec: StepSpec{
+			Inputs: StepInputs{
+				Parameters: []StepParameters{
+					{Name: "space", Type: "string"},
+					{Name: "version", Type: "string"},
+					{Name: "buildId", Type: "string"},
+					{Name: "region", Type: "string"},
+					{Name: "command", Type: "string"},
+					{Name: "token", Type: "string"},
+					{Name: "tags", Type: "[]string"},
+				},
+			},
+		},
+	}
+	filters := StepFilters{
+		General: []string{"space"},
+		Steps:   []string{"space", "version", "buildId", "region", "command", "token", "tags"},
+	}
+
+	c := Config{}
+	c.SetEnvRootPath(envRootPath)
+	stepConfig, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader(projectConfig)), nil, false, filters, metadata, nil, "", "step1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "dev-feature-login", stepConfig.Config["space"])
+	assert.Equal(t, "1.5.0", stepConfig.Config["version"])
+	assert.Equal(t, "local", stepConfig.Config["buildId"])
+	assert.Equal(t, "eu10", stepConfig.Config["region"])
+	assert.Equal(t, "echo $(git rev-parse HEAD) pr-42", stepConfig.Config["command"])
+	assert.Equal(t, "$(env.TEST_AGENT_TOKEN)", stepConfig.Config["token"], "only environment variables with the prefix PIPER_ are available")
+	assert.Equal(t, []interface{}{"dev-feature-login", "latest"}, stepConfig.Config["tags"])
+}
+
+func TestResolveExpressionsSkipsSecrets(t *testing.T) {
+	stepConfig := StepConfig{Config: map[string]interface{}{
+		"user": "admin",
+		"url":  "https://$(user)@example.com",
+	}}
+	beforeSecrets := map[string]interface{}{"user": "admin", "url": "https://$(user)@example.com"}
+	// secret read from vault
+	stepConfig.Config["password"] = "pa$(user)"
+
+	c := Config{}
+	stepConfig.resolveExpressions(c.expressionResolver(), changedKeys(beforeSecrets, stepConfig.Config))
+
+	assert.Equal(t, "pa$(user)", stepConfig.Config["password"])
+	assert.Equal(t, "https://admin@example.com", stepConfig.Config["url"])
+}
@@ -0,0 +1,132 @@
+package interpolation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// function transforms the value of an expression, args contains the arguments following the name of the function
+type function func(value string, args []string) (string, error)
+
+var functions = map[string]function{
+	"default": defaultValue,
+	"lower":   lower,
+	"upper":   upper,
+	"replace": replace,
+	"bump":    bump,
+}
+
+// defaultValue returns the first argument if the value is empty or the reference does not exist
+func defaultValue(value string, args []string) (string, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return "", err
+	}
+	if len(value) == 0 {
+		return args[0], nil
+	}
+	return value, nil
+}
+
+func lower(value string, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return strings.ToLower(value), nil
+}
+
+func upper(value string, args []string) (string, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(value), nil
+}
+
+// replace replaces all occurrences of the first argument with the second one
+func replace(value string, args []string) (string, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(value, args[0], args[1]), nil
+}
+
+// bump increments the major, minor or patch part of a semantic version, a leading v is kept
+func bump(value string, args []string) (string, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return "", err
+	}
+	version, err := semver.NewVersion(value)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a semantic version: %w", value, err)
+	}
+	var bumped semver.Version
+	switch args[0] {
+	case "major":
+		bumped = version.IncMajor()
+	case "minor":
+		bumped = version.IncMinor()
+	case "patch":
+		bumped = version.IncPatch()
+	default:
+		return "", fmt.Errorf("unknown version part '%s', use one of major, minor, patch", args[0])
+	}
+	if strings.HasPrefix(value, "v") {
+		return "v" + bumped.String(), nil
+	}
+	return bumped.String(), nil
+}
+
+func expectArgs(args []string, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("expected %d arguments but got %d", count, len(args))
+	}
+	return nil
+}
+
+// splitArguments splits a segment of an expression at whitespace.
+// Arguments may be quoted with double quotes supporting Go escape sequences or with single quotes taking the content literally.
+func splitArguments(segment string) ([]string, error) {
+	args := []string{}
+	runes := []rune(segment)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'':
+			end := strings.IndexRune(string(runes[i+1:]), '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			token := string(runes[i+1:])[:end]
+			args = append(args, token)
+			i += len([]rune(token)) + 2
+		case c == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			token, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument %s", string(runes[i:end+1]))
+			}
+			args = append(args, token)
+			i = end + 1
+		default:
+			end := i
+			for ; end < len(runes) && !unicode.IsSpace(runes[end]); end++ {
+			}
+			args = append(args, string(runes[i:end]))
+			i = end
+		}
+	}
+	return args, nil
+}
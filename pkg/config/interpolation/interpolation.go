@@ -13,15 +13,38 @@ const (
 )
 
 var (
-	lookupRegex   *regexp.Regexp = regexp.MustCompile(`\$\((?P<property>[a-zA-Z0-9\.]*)\)`)
-	captureGroups                = setupCaptureGroups(lookupRegex.SubexpNames())
+	// an expression is enclosed in $( and ), quoted arguments may contain parentheses
+	expressionRegex *regexp.Regexp = regexp.MustCompile(`\$\(((?:[^()"']|"(?:[^"\\]|\\.)*"|'[^']*')*)\)`)
+	referenceRegex  *regexp.Regexp = regexp.MustCompile(`^[a-zA-Z0-9_\.\-/]*$`)
 )
 
+// Lookup returns the value of a key provided by a source, e.g. an environment variable
+type Lookup func(key string) (string, bool)
+
+// Resolver resolves expressions of the form $(reference | function arg ...).
+// A reference either names another property of the map or is prefixed with the name of a source, e.g. $(env.HOME).
+// The value of the reference is passed through the functions from left to right.
+type Resolver struct {
+	// Sources maps a prefix to the lookup of its values, e.g. env to the environment variables
+	Sources map[string]Lookup
+}
+
 // ResolveMap interpolates every string value of a map and tries to lookup references to other properties of that map
 func ResolveMap(config map[string]interface{}) bool {
+	return (&Resolver{}).ResolveMap(config)
+}
+
+// ResolveString takes a string and replaces all references inside of it with values from the given lookupMap.
+// This is being done recursively until the maxLookupDepth is reached.
+func ResolveString(str string, lookupMap map[string]interface{}) (string, bool) {
+	return (&Resolver{}).ResolveString(str, lookupMap)
+}
+
+// ResolveMap interpolates every string value of a map, it fails if any expression cannot be resolved
+func (r *Resolver) ResolveMap(config map[string]interface{}) bool {
 	for key, value := range config {
 		if str, ok := value.(string); ok {
-			resolvedStr, ok := ResolveString(str, config)
+			resolvedStr, ok := r.ResolveString(str, config)
 			if !ok {
 				return false
 			}
@@ -31,41 +54,128 @@ func ResolveMap(config map[string]interface{}) bool {
 	return true
 }
 
-func resolveString(str string, lookupMap map[string]interface{}, n int) (string, bool) {
-	matches := lookupRegex.FindAllStringSubmatch(str, -1)
-	if len(matches) == 0 {
-		return str, true
-	}
-	if n == maxLookupDepth {
-		log.Entry().Errorf("Property could not be resolved with a depth of %d. '%s' is still left to resolve", n, str)
+// ResolveString replaces all expressions inside of str, it fails if any expression cannot be resolved
+func (r *Resolver) ResolveString(str string, lookupMap map[string]interface{}) (string, bool) {
+	resolved, errs := r.resolveString(str, lookupMap, 0, true)
+	if len(errs) > 0 {
+		log.Entry().Debugf("Can't interpolate '%s': %v", str, errs[0])
 		return "", false
 	}
-	for _, match := range matches {
-		property := match[captureGroups["property"]]
-		if propVal, ok := lookupMap[property]; ok {
-			str = strings.ReplaceAll(str, fmt.Sprintf("$(%s)", property), propVal.(string))
-		} else {
-			// value not found
-			log.Entry().Debugf("Can't interploate '%s'. Missing property '%s'", str, property)
-			return "", false
+	return resolved, true
+}
+
+// Expand replaces all expressions inside of str which can be resolved and keeps the other ones unchanged.
+// This allows to use expressions in values which contain $( for other purposes, e.g. shell commands.
+// The errors explain why expressions could not be resolved.
+func (r *Resolver) Expand(str string, lookupMap map[string]interface{}) (string, []error) {
+	return r.resolveString(str, lookupMap, 0, false)
+}
+
+func (r *Resolver) resolveString(str string, lookupMap map[string]interface{}, depth int, strict bool) (string, []error) {
+	if !strings.Contains(str, "$(") {
+		return str, nil
+	}
+	if depth == maxLookupDepth {
+		return str, []error{fmt.Errorf("property could not be resolved with a depth of %d, '%s' is still left to resolve", depth, str)}
+	}
+	errs := []error{}
+	resolved := expressionRegex.ReplaceAllStringFunc(str, func(match string) string {
+		if len(errs) > 0 && strict {
+			return match
+		}
+		value, err := r.evaluate(expressionRegex.FindStringSubmatch(match)[1], lookupMap, depth, strict)
+		if err != nil {
+			errs = append(errs, err)
+			return match
 		}
+		return value
+	})
+	return resolved, errs
+}
+
+func (r *Resolver) evaluate(expression string, lookupMap map[string]interface{}, depth int, strict bool) (string, error) {
+	segments := splitPipeline(expression)
+	reference := strings.TrimSpace(segments[0])
+	if !referenceRegex.MatchString(reference) {
+		return "", fmt.Errorf("invalid reference '%s'", reference)
 	}
-	return resolveString(str, lookupMap, n+1)
+	value, found, err := r.lookup(reference, lookupMap, depth, strict)
+	if err != nil {
+		return "", err
+	}
+
+	for _, segment := range segments[1:] {
+		args, err := splitArguments(segment)
+		if err != nil {
+			return "", fmt.Errorf("invalid expression '%s': %w", expression, err)
+		}
+		if len(args) == 0 {
+			return "", fmt.Errorf("invalid expression '%s': missing function", expression)
+		}
+		function, ok := functions[args[0]]
+		if !ok {
+			return "", fmt.Errorf("unknown function '%s' in expression '%s'", args[0], expression)
+		}
+		if !found {
+			if args[0] != "default" {
+				return "", fmt.Errorf("missing property '%s'", reference)
+			}
+			found = true
+		}
+		if value, err = function(value, args[1:]); err != nil {
+			return "", fmt.Errorf("%s: %w", args[0], err)
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("missing property '%s'", reference)
+	}
+	return value, nil
 }
 
-// ResolveString takes a string and replaces all references inside of it with values from the given lookupMap.
-// This is being done recursively until the maxLookupDepth is reached.
-func ResolveString(str string, lookupMap map[string]interface{}) (string, bool) {
-	return resolveString(str, lookupMap, 0)
+// lookup prefers properties of the map over sources, i.e. a property called env.HOME hides the environment variable
+func (r *Resolver) lookup(reference string, lookupMap map[string]interface{}, depth int, strict bool) (string, bool, error) {
+	if propVal, ok := lookupMap[reference]; ok {
+		str, isString := propVal.(string)
+		if !isString {
+			return fmt.Sprint(propVal), true, nil
+		}
+		resolved, errs := r.resolveString(str, lookupMap, depth+1, strict)
+		if len(errs) > 0 {
+			return "", false, errs[0]
+		}
+		return resolved, true, nil
+	}
+	if prefix, key, ok := strings.Cut(reference, "."); ok {
+		if source, ok := r.Sources[prefix]; ok {
+			value, found := source(key)
+			return value, found, nil
+		}
+	}
+	return "", false, nil
 }
 
-func setupCaptureGroups(captureGroupsList []string) map[string]int {
-	groups := make(map[string]int, len(captureGroupsList))
-	for i, captureGroupName := range captureGroupsList {
-		if i == 0 {
-			continue
+// splitPipeline splits an expression at every | which is not part of a quoted argument
+func splitPipeline(expression string) []string {
+	segments := []string{}
+	var quote rune
+	escaped := false
+	start := 0
+	for i, c := range expression {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				escaped = true
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '|':
+			segments = append(segments, expression[start:i])
+			start = i + 1
 		}
-		groups[captureGroupName] = i
 	}
-	return groups
+	return append(segments, expression[start:])
 }
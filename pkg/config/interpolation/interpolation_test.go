@@ -46,3 +46,78 @@ func TestResolveMap(t *testing.T) {
 	})
 
 }
+
+func TestResolverExpressions(t *testing.T) {
+	t.Parallel()
+
+	resolver := &Resolver{Sources: map[string]Lookup{
+		"env": func(key string) (string, bool) {
+			value, ok := map[string]string{"HOME": "/home/piper", "EMPTY": ""}[key]
+			return value, ok
+		},
+		"orchestrator": func(key string) (string, bool) {
+			value, ok := map[string]string{"branch": "Feature/ABC"}[key]
+			return value, ok
+		},
+	}}
+	lookupMap := map[string]interface{}{
+		"space":   "dev",
+		"nested":  "$(space)-$(orchestrator.branch | lower)",
+		"version": "v1.2.3",
+		"count":   3,
+	}
+
+	tt := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{name: "source", str: "$(env.HOME)/.m2", expected: "/home/piper/.m2"},
+		{name: "function", str: "dev-$(orchestrator.branch | lower)", expected: "dev-feature/abc"},
+		{name: "function chain", str: "$(orchestrator.branch | replace / - | upper)", expected: "FEATURE-ABC"},
+		{name: "quoted arguments", str: `$(orchestrator.branch | replace "Feature/" 'f(x)-')`, expected: "f(x)-ABC"},
+		{name: "default for missing reference", str: "$(env.MISSING | default none)", expected: "none"},
+		{name: "default for empty value", str: "$(env.EMPTY | default \"n a\")", expected: "n a"},
+		{name: "default for existing value", str: "$(space | default prod)", expected: "dev"},
+		{name: "nested properties", str: "$(nested)", expected: "dev-feature/abc"},
+		{name: "non string property", str: "$(count)", expected: "3"},
+		{name: "bump major", str: "$(version | bump major)", expected: "v2.0.0"},
+		{name: "bump minor", str: "$(version | bump minor)", expected: "v1.3.0"},
+		{name: "bump patch", str: "$(env.VERSION | default 1.0.0 | bump patch)", expected: "1.0.1"},
+	}
+	for _, test := range tt {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			resolved, ok := resolver.ResolveString(test.str, lookupMap)
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, resolved)
+		})
+	}
+
+	t.Run("invalid expressions fail", func(t *testing.T) {
+		t.Parallel()
+		for _, str := range []string{
+			"$(env.MISSING)",
+			"$(env.MISSING | lower)",
+			"$(space | unknown)",
+			"$(space | replace a)",
+			"$(version | bump build)",
+			"$(space | bump major)",
+			"$(git rev-parse HEAD)",
+		} {
+			_, ok := resolver.ResolveString(str, lookupMap)
+			assert.False(t, ok, str)
+		}
+	})
+
+	t.Run("expand keeps unresolvable expressions", func(t *testing.T) {
+		t.Parallel()
+		expanded, errs := resolver.Expand("echo $(git rev-parse HEAD) > $(space).txt $(env.MISSING)", lookupMap)
+		assert.Equal(t, "echo $(git rev-parse HEAD) > dev.txt $(env.MISSING)", expanded)
+		if assert.Len(t, errs, 2) {
+			assert.EqualError(t, errs[0], "invalid reference 'git rev-parse HEAD'")
+			assert.EqualError(t, errs[1], "missing property 'env.MISSING'")
+		}
+	})
+}
@@ -0,0 +1,384 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules checked by the ConfigLinter
+const (
+	LintRuleInvalidYaml     = "invalidYaml"
+	LintRuleUnknownSection  = "unknownSection"
+	LintRuleUnknownStep     = "unknownStep"
+	LintRuleUnknownStage    = "unknownStage"
+	LintRuleUnknownKey      = "unknownKey"
+	LintRuleInvalidScope    = "invalidScope"
+	LintRuleWrongType       = "wrongType"
+	LintRuleDeprecatedAlias = "deprecatedAlias"
+	LintRuleInvalidValue    = "invalidValue"
+)
+
+// Severity levels of lint findings, they correspond to the SARIF result levels
+const (
+	LintLevelError   = "error"
+	LintLevelWarning = "warning"
+)
+
+// LintRules contains the description of all rules checked by the ConfigLinter
+var LintRules = map[string]string{
+	LintRuleInvalidYaml:     "The configuration file is not a valid YAML document.",
+	LintRuleUnknownSection:  "The configuration contains a top-level section which is not known.",
+	LintRuleUnknownStep:     "The steps section contains a step which is not known.",
+	LintRuleUnknownStage:    "The stages section contains a stage which is not part of the stage conditions.",
+	LintRuleUnknownKey:      "The configuration contains a key which is not a parameter of any step and is ignored.",
+	LintRuleInvalidScope:    "The parameter is configured in a section where it is not allowed and is ignored.",
+	LintRuleWrongType:       "The configured value does not match the type of the parameter.",
+	LintRuleDeprecatedAlias: "The parameter is configured via a deprecated alias.",
+	LintRuleInvalidValue:    "The configured value is not one of the possible values of the parameter.",
+}
+
+// LintFinding describes a single issue within a configuration file
+type LintFinding struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// ConfigLinter validates configuration files against the metadata of all steps
+type ConfigLinter struct {
+	// keys contains the definitions of a configuration key across all steps
+	keys map[string][]lintKey
+	// stepKeys contains the definitions of the configuration keys per step
+	stepKeys map[string]map[string][]lintKey
+	// stages contains the known stage names, nil if they are not known
+	stages map[string]bool
+}
+
+// lintKey is the definition of a configuration key, i.e. a parameter, one of its aliases or a context key
+type lintKey struct {
+	// parameter is nil for keys which are not described by a parameter, e.g. container configuration
+	parameter *StepParameters
+	alias     *Alias
+	// scopes is nil if the key is allowed in all scopes
+	scopes []string
+}
+
+// NewConfigLinter creates a linter based on the metadata of the given steps.
+// If stageNames is nil the stage names are not checked.
+func NewConfigLinter(steps map[string]StepData, stageNames []string) *ConfigLinter {
+	l := &ConfigLinter{keys: map[string][]lintKey{}, stepKeys: map[string]map[string][]lintKey{}}
+
+	generalKeys := append([]string{"verbose"}, ReportingParameters.getReportingFilter()...)
+	for _, key := range vaultFilter {
+		if key != vaultSecretName {
+			generalKeys = append(generalKeys, key)
+		}
+	}
+
+	for stepName, metadata := range steps {
+		l.stepKeys[stepName] = map[string][]lintKey{}
+		for _, key := range generalKeys {
+			l.addKey(stepName, key, lintKey{})
+		}
+		for _, key := range metadata.GetContextParameterFilters().Steps {
+			l.addKey(stepName, key, lintKey{})
+		}
+		for i := range metadata.Spec.Inputs.Parameters {
+			param := &metadata.Spec.Inputs.Parameters[i]
+			scopes := append([]string{}, param.Scope...)
+			l.addKey(stepName, param.Name, lintKey{parameter: param, scopes: scopes})
+			for j := range param.Aliases {
+				alias := &param.Aliases[j]
+				if strings.Contains(alias.Name, "/") {
+					// deep aliases point into a nested configuration map which is not validated
+					l.addKey(stepName, strings.Split(alias.Name, "/")[0], lintKey{scopes: scopes})
+					continue
+				}
+				l.addKey(stepName, alias.Name, lintKey{parameter: param, alias: alias, scopes: scopes})
+			}
+			for _, condition := range param.Conditions {
+				for _, dependentParam := range condition.Params {
+					l.addKey(stepName, dependentParam.Value, lintKey{scopes: scopes})
+				}
+			}
+		}
+	}
+
+	if stageNames != nil {
+		l.stages = map[string]bool{}
+		for _, stage := range stageNames {
+			l.stages[stage] = true
+		}
+	}
+	return l
+}
+
+func (l *ConfigLinter) addKey(stepName, name string, key lintKey) {
+	l.stepKeys[stepName][name] = append(l.stepKeys[stepName][name], key)
+	l.keys[name] = append(l.keys[name], key)
+}
+
+// Lint validates the content of a project configuration or defaults file and returns the findings sorted by their position
+func (l *ConfigLinter) Lint(fileName string, content []byte) []LintFinding {
+	r := lintRun{linter: l, fileName: fileName}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		r.report(LintRuleInvalidYaml, LintLevelError, nil, "invalid YAML: %v", err)
+		return r.findings
+	}
+	if len(document.Content) == 0 {
+		return r.findings
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		r.report(LintRuleInvalidYaml, LintLevelError, root, "the configuration needs to be a map")
+		return r.findings
+	}
+
+	forEachEntry(root, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "general":
+			forEachEntry(value, func(key, value *yaml.Node) {
+				r.lintKey("GENERAL", "section 'general'", key, value, l.keys[key.Value])
+			})
+		case "steps":
+			forEachEntry(value, r.lintStep)
+		case "stages":
+			forEachEntry(value, r.lintStage)
+		case "customDefaults":
+			if !matchesType(value, "[]string") {
+				r.report(LintRuleWrongType, LintLevelError, value, "'customDefaults' needs to be a list of strings")
+			}
+		case "hooks":
+			// hook configuration is not described by step metadata
+		default:
+			r.report(LintRuleUnknownSection, LintLevelWarning, key, "unknown section '%v', allowed are general, steps, stages, customDefaults and hooks", key.Value)
+		}
+	})
+
+	sort.SliceStable(r.findings, func(i, j int) bool {
+		if r.findings[i].Line != r.findings[j].Line {
+			return r.findings[i].Line < r.findings[j].Line
+		}
+		return r.findings[i].Column < r.findings[j].Column
+	})
+	return r.findings
+}
+
+// lintRun collects the findings of linting a single file
+type lintRun struct {
+	linter   *ConfigLinter
+	fileName string
+	findings []LintFinding
+}
+
+func (r *lintRun) report(ruleID, level string, node *yaml.Node, format string, args ...interface{}) {
+	finding := LintFinding{RuleID: ruleID, Level: level, Message: fmt.Sprintf(format, args...), File: r.fileName}
+	if node != nil {
+		finding.Line = node.Line
+		finding.Column = node.Column
+	}
+	r.findings = append(r.findings, finding)
+}
+
+func (r *lintRun) lintStep(stepKey, stepConfig *yaml.Node) {
+	keys, ok := r.linter.stepKeys[stepKey.Value]
+	if !ok {
+		r.report(LintRuleUnknownStep, LintLevelWarning, stepKey, "unknown step '%v'", stepKey.Value)
+		return
+	}
+	forEachEntry(stepConfig, func(key, value *yaml.Node) {
+		r.lintKey("STEPS", fmt.Sprintf("step '%v'", stepKey.Value), key, value, keys[key.Value])
+	})
+}
+
+func (r *lintRun) lintStage(stageKey, stageConfig *yaml.Node) {
+	if r.linter.stages != nil && !r.linter.stages[stageKey.Value] {
+		r.report(LintRuleUnknownStage, LintLevelWarning, stageKey, "unknown stage '%v', it is not part of the stage conditions", stageKey.Value)
+	}
+	forEachEntry(stageConfig, func(key, value *yaml.Node) {
+		if _, isStep := r.linter.stepKeys[key.Value]; isStep {
+			// steps can be activated or deactivated explicitly within a stage
+			if !matchesType(value, "bool") {
+				r.report(LintRuleWrongType, LintLevelError, value, "activation of step '%v' in stage '%v' needs to be of type bool", key.Value, stageKey.Value)
+			}
+			return
+		}
+		r.lintKey("STAGES", fmt.Sprintf("stage '%v'", stageKey.Value), key, value, r.linter.keys[key.Value])
+	})
+}
+
+// lintKey validates a single configuration entry against the given definitions of the key
+func (r *lintRun) lintKey(scope, location string, key, value *yaml.Node, definitions []lintKey) {
+	if len(definitions) == 0 {
+		if !strings.HasSuffix(key.Value, "VaultSecretName") {
+			r.report(LintRuleUnknownKey, LintLevelWarning, key, "unknown key '%v' in %v", key.Value, location)
+		}
+		return
+	}
+
+	allowed := []lintKey{}
+	allowedScopes := []string{}
+	for _, definition := range definitions {
+		if definition.scopes == nil || piperutils.ContainsString(definition.scopes, scope) {
+			allowed = append(allowed, definition)
+			continue
+		}
+		for _, s := range definition.scopes {
+			if !piperutils.ContainsString(allowedScopes, s) {
+				allowedScopes = append(allowedScopes, s)
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		r.report(LintRuleInvalidScope, LintLevelWarning, key, "key '%v' is not allowed in %v, allowed scopes: %v", key.Value, location, strings.Join(allowedScopes, ", "))
+		return
+	}
+
+	parameters := []*StepParameters{}
+	deprecatedAlias := true
+	for _, definition := range allowed {
+		if definition.alias == nil || !definition.alias.Deprecated {
+			deprecatedAlias = false
+		}
+		if definition.parameter == nil {
+			// the key is not described by a parameter, thus the value cannot be validated
+			return
+		}
+		parameters = append(parameters, definition.parameter)
+	}
+	if deprecatedAlias {
+		r.report(LintRuleDeprecatedAlias, LintLevelWarning, key, "key '%v' in %v is deprecated, use '%v' instead", key.Value, location, parameters[0].Name)
+	}
+
+	if value.Tag == "!!null" {
+		return
+	}
+	typed := []*StepParameters{}
+	types := []string{}
+	for _, param := range parameters {
+		if matchesType(value, param.Type) {
+			typed = append(typed, param)
+		} else if !piperutils.ContainsString(types, param.Type) {
+			types = append(types, param.Type)
+		}
+	}
+	if len(typed) == 0 {
+		sort.Strings(types)
+		r.report(LintRuleWrongType, LintLevelError, value, "value of key '%v' in %v needs to be of type %v", key.Value, location, strings.Join(types, " or "))
+		return
+	}
+
+	possibleValues := []interface{}{}
+	for _, param := range typed {
+		if len(param.PossibleValues) == 0 {
+			// at least one step accepts any value
+			return
+		}
+		possibleValues = append(possibleValues, param.PossibleValues...)
+	}
+	for _, node := range scalarNodes(value) {
+		if !isPossibleValue(node.Value, possibleValues) {
+			r.report(LintRuleInvalidValue, LintLevelError, node, "value '%v' of key '%v' in %v is not allowed, possible values: %v", node.Value, key.Value, location, possibleValues)
+		}
+	}
+}
+
+// forEachEntry calls the function for each key/value pair of a map node, other nodes are ignored
+func forEachEntry(node *yaml.Node, f func(key, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		f(node.Content[i], node.Content[i+1])
+	}
+}
+
+// matchesType checks whether the node matches the Go type of a parameter, unknown types always match.
+// Values are accepted if the step converts them when checking the configuration types, e.g. numbers for strings or "true" for booleans.
+func matchesType(node *yaml.Node, paramType string) bool {
+	switch paramType {
+	case "string":
+		return isScalar(node, "!!str", "!!int", "!!float")
+	case "bool":
+		return isScalar(node, "!!bool") || (isScalar(node, "!!str") && piperutils.ContainsString([]string{"true", "false"}, strings.ToLower(node.Value)))
+	case "int", "int64":
+		return isScalar(node, "!!int") || (isScalar(node, "!!float") && isIntegral(node.Value))
+	case "float64":
+		return isScalar(node, "!!int", "!!float")
+	case "[]string":
+		return isSequenceOf(node, func(item *yaml.Node) bool { return isScalar(item, "!!str") })
+	case "map[string]interface{}":
+		return node.Kind == yaml.MappingNode
+	case "[]map[string]interface{}":
+		return isSequenceOf(node, func(item *yaml.Node) bool { return item.Kind == yaml.MappingNode })
+	}
+	return true
+}
+
+// isIntegral checks whether a float value can be converted to an int without loss
+func isIntegral(value string) bool {
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && float64(int(f)) == f
+}
+
+func isScalar(node *yaml.Node, tags ...string) bool {
+	return node.Kind == yaml.ScalarNode && piperutils.ContainsString(tags, scalarTag(node))
+}
+
+// scalarTag returns the tag of a scalar node as resolved by YAML 1.1 which is used for reading the configuration,
+// i.e. unquoted values like yes or off are booleans.
+func scalarTag(node *yaml.Node) string {
+	if node.Tag == "!!str" && node.Style == 0 && piperutils.ContainsString(yaml11Booleans, node.Value) {
+		return "!!bool"
+	}
+	return node.Tag
+}
+
+var yaml11Booleans = []string{"y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO", "on", "On", "ON", "off", "Off", "OFF"}
+
+func isSequenceOf(node *yaml.Node, matches func(item *yaml.Node) bool) bool {
+	if node.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, item := range node.Content {
+		if !matches(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarNodes returns the node itself for scalars and the scalar items for lists
+func scalarNodes(node *yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.ScalarNode {
+		return []*yaml.Node{node}
+	}
+	nodes := []*yaml.Node{}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				nodes = append(nodes, item)
+			}
+		}
+	}
+	return nodes
+}
+
+func isPossibleValue(value string, possibleValues []interface{}) bool {
+	for _, possibleValue := range possibleValues {
+		if fmt.Sprint(possibleValue) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigLinter(t *testing.T) {
+	steps := map[string]StepData{
+		"step1": {
+			Spec: StepSpec{
+				Inputs: StepInputs{
+					Parameters: []StepParameters{
+						{Name: "p1", Type: "string", Scope: []string{"GENERAL", "STEPS", "STAGES"}},
+						{Name: "p2", Type: "bool", Scope: []string{"STEPS"}, Aliases: []Alias{{Name: "p2Old", Deprecated: true}, {Name: "p2Alias"}}},
+						{Name: "p3", Type: "[]string", Scope: []string{"STEPS"}, PossibleValues: []interface{}{"a", "b"}},
+						{Name: "p4", Type: "int", Scope: []string{"PARAMETERS"}},
+					},
+				},
+			},
+		},
+		"step2": {
+			Spec: StepSpec{
+				Inputs: StepInputs{
+					Parameters: []StepParameters{
+						{Name: "p1", Type: "bool", Scope: []string{"GENERAL", "STEPS"}},
+					},
+				},
+				Containers: []Container{{Name: "container", Image: "image"}},
+			},
+		},
+	}
+	linter := NewConfigLinter(steps, []string{"Build", "build"})
+
+	t.Run("valid configuration", func(t *testing.T) {
+		content := `customDefaults:
+  - defaults.yml
+general:
+  p1: general
+  vaultBasePath: piper
+  verbose: true
+steps:
+  step1:
+    p1: step
+    p2Alias: yes
+    p3: [a, b]
+  step2:
+    p1: false
+    dockerImage: image
+stages:
+  Build:
+    p1: stage
+    step2: off
+  build:
+    testVaultSecretName: secret
+`
+		assert.Empty(t, linter.Lint("config.yml", []byte(content)))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		content := `customDefaults: defaults.yml
+genral:
+  p1: general
+general:
+  p5: unknown
+  p2: true
+steps:
+  step1:
+    p2Old: true
+    p3: [a, c]
+    p4: 1
+    p1: {a: b}
+  step3:
+    p1: unknown
+stages:
+  Test:
+    step1: yes please
+`
+		findings := linter.Lint("config.yml", []byte(content))
+		assert.Equal(t, []LintFinding{
+			{RuleID: LintRuleWrongType, Level: LintLevelError, Message: "'customDefaults' needs to be a list of strings", File: "config.yml", Line: 1, Column: 17},
+			{RuleID: LintRuleUnknownSection, Level: LintLevelWarning, Message: "unknown section 'genral', allowed are general, steps, stages, customDefaults and hooks", File: "config.yml", Line: 2, Column: 1},
+			{RuleID: LintRuleUnknownKey, Level: LintLevelWarning, Message: "unknown key 'p5' in section 'general'", File: "config.yml", Line: 5, Column: 3},
+			{RuleID: LintRuleInvalidScope, Level: LintLevelWarning, Message: "key 'p2' is not allowed in section 'general', allowed scopes: STEPS", File: "config.yml", Line: 6, Column: 3},
+			{RuleID: LintRuleDeprecatedAlias, Level: LintLevelWarning, Message: "key 'p2Old' in step 'step1' is deprecated, use 'p2' instead", File: "config.yml", Line: 9, Column: 5},
+			{RuleID: LintRuleInvalidValue, Level: LintLevelError, Message: "value 'c' of key 'p3' in step 'step1' is not allowed, possible values: [a b]", File: "config.yml", Line: 10, Column: 13},
+			{RuleID: LintRuleInvalidScope, Level: LintLevelWarning, Message: "key 'p4' is not allowed in step 'step1', allowed scopes: PARAMETERS", File: "config.yml", Line: 11, Column: 5},
+			{RuleID: LintRuleWrongType, Level: LintLevelError, Message: "value of key 'p1' in step 'step1' needs to be of type string", File: "config.yml", Line: 12, Column: 9},
+			{RuleID: LintRuleUnknownStep, Level: LintLevelWarning, Message: "unknown step 'step3'", File: "config.yml", Line: 13, Column: 3},
+			{RuleID: LintRuleUnknownStage, Level: LintLevelWarning, Message: "unknown stage 'Test', it is not part of the stage conditions", File: "config.yml", Line: 16, Column: 3},
+			{RuleID: LintRuleWrongType, Level: LintLevelError, Message: "activation of step 'step1' in stage 'Test' needs to be of type bool", File: "config.yml", Line: 17, Column: 12},
+		}, findings)
+	})
+
+	t.Run("values converted by the type check of the step", func(t *testing.T) {
+		content := `steps:
+  step1:
+    p1: 1.5
+    p2: "True"
+  step2:
+    p1: "false"
+`
+		assert.Empty(t, linter.Lint("config.yml", []byte(content)))
+		findings := linter.Lint("config.yml", []byte("steps:\n  step2:\n    p1: \"yes\"\n"))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "value of key 'p1' in step 'step2' needs to be of type bool", findings[0].Message)
+		}
+	})
+
+	t.Run("type of parameter differs between steps", func(t *testing.T) {
+		findings := linter.Lint("config.yml", []byte("general:\n  p1: [a]\n"))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "value of key 'p1' in section 'general' needs to be of type bool or string", findings[0].Message)
+		}
+	})
+
+	t.Run("stage names unknown", func(t *testing.T) {
+		linter := NewConfigLinter(steps, nil)
+		assert.Empty(t, linter.Lint("config.yml", []byte("stages:\n  Test:\n    p1: stage\n")))
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		findings := linter.Lint("config.yml", []byte("general:\n  p1: [a\n"))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, LintRuleInvalidYaml, findings[0].RuleID)
+			assert.Equal(t, LintLevelError, findings[0].Level)
+		}
+	})
+}
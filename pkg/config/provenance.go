@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Layers of the configuration merge chain a configuration value can originate from
+const (
+	SourceStepDefaults              = "stepDefaults"
+	SourceCommonPipelineEnvironment = "commonPipelineEnvironment"
+	SourceDefaults                  = "defaults"
+	SourceCustomDefaults            = "customDefaults"
+	SourceProjectConfig             = "projectConfig"
+	SourceEnvironment               = "environment"
+	SourceParametersJSON            = "parametersJSON"
+	SourceFlags                     = "flags"
+	SourceVault                     = "vault"
+	SourceCondition                 = "condition"
+)
+
+// ValueSource describes where a configuration value has been taken from
+type ValueSource struct {
+	// Layer is the layer of the merge chain, e.g. defaults or projectConfig
+	Layer string `json:"layer"`
+	// Origin identifies the concrete source within a layer, e.g. the name of a defaults file
+	Origin string `json:"origin,omitempty"`
+	// Section is the section of a configuration file, i.e. general, steps or stages
+	Section string `json:"section,omitempty"`
+	// Reference is the alias or resource reference which resolved the value
+	Reference string `json:"reference,omitempty"`
+}
+
+// String returns a human readable representation of the source
+func (v ValueSource) String() string {
+	result := v.Layer
+	if len(v.Origin) > 0 {
+		result = fmt.Sprintf("%v '%v'", result, v.Origin)
+	}
+	if len(v.Section) > 0 {
+		result = fmt.Sprintf("%v (%v)", result, v.Section)
+	}
+	if len(v.Reference) > 0 {
+		result = fmt.Sprintf("%v via %v", result, v.Reference)
+	}
+	return result
+}
+
+// EnableSourceTracking enables the recording of the source of each configuration value in StepConfig.Sources.
+// defaultsNames contains the names of the defaults passed to GetStepConfig in the same order.
+func (c *Config) EnableSourceTracking(defaultsNames []string) {
+	c.trackSources = true
+	c.defaultsNames = defaultsNames
+	c.resolvedAliases = map[string]string{}
+}
+
+// recordAlias keeps track of the alias which resolved a parameter within a section of the configuration
+func (c *Config) recordAlias(section, name, reference string) {
+	if c.resolvedAliases != nil && len(reference) > 0 {
+		c.resolvedAliases[section+"/"+name] = reference
+	}
+}
+
+// aliasReferences returns the aliases which resolved parameters in the given section
+func (c *Config) aliasReferences(section string) map[string]string {
+	references := map[string]string{}
+	prefix := section + "/"
+	for key, reference := range c.resolvedAliases {
+		if strings.HasPrefix(key, prefix) {
+			references[strings.TrimPrefix(key, prefix)] = reference
+		}
+	}
+	return references
+}
+
+// defaultsSource returns the source description of the defaults with the given index.
+// Custom defaults are always appended after the defaults passed to GetStepConfig.
+func (c *Config) defaultsSource(index int, section string) ValueSource {
+	if customIndex := index - (len(c.defaults.Defaults) - c.customDefaultsCount); customIndex >= 0 {
+		return ValueSource{Layer: SourceCustomDefaults, Origin: c.CustomDefaults[customIndex], Section: section}
+	}
+	if index < len(c.defaultsNames) {
+		return ValueSource{Layer: SourceDefaults, Origin: c.defaultsNames[index], Section: section}
+	}
+	return ValueSource{Layer: SourceDefaults, Origin: fmt.Sprintf("#%v", index), Section: section}
+}
+
+// trackSource executes the merge operation and records the source for all values which have been set by it.
+// A value counts as set even if it equals the value of a previous layer, thus the source is always the last layer providing the key.
+// Recording only takes place if source tracking is enabled, i.e. Sources is not nil.
+func (s *StepConfig) trackSource(source ValueSource, references map[string]string, mergeOperation func()) {
+	if s.Sources == nil {
+		mergeOperation()
+		return
+	}
+
+	before := make(map[string]interface{}, len(s.Config))
+	for key, value := range s.Config {
+		before[key] = value
+	}
+
+	s.assigned = map[string]bool{}
+	mergeOperation()
+	assigned := s.assigned
+	s.assigned = nil
+
+	for key, value := range s.Config {
+		// values which are not merged via mixIn are detected by comparing them with the previous state
+		if previous, ok := before[key]; !assigned[key] && ok && reflect.DeepEqual(previous, value) {
+			continue
+		}
+		keySource := source
+		if reference, ok := references[key]; ok {
+			keySource.Reference = reference
+		}
+		s.Sources[key] = keySource
+	}
+}
+
+// recordAssignment records the keys of the data merged by the currently tracked merge operation
+func (s *StepConfig) recordAssignment(data map[string]interface{}) {
+	if s.assigned == nil {
+		return
+	}
+	for key := range data {
+		s.assigned[key] = true
+	}
+}
+
+// resourceReferences returns for each parameter the reference to the resource of the given type or name
+func resourceReferences(parameters []StepParameters, match func(ResourceReference) bool) map[string]string {
+	references := map[string]string{}
+	for _, param := range parameters {
+		for _, ref := range param.ResourceRef {
+			if !match(ref) {
+				continue
+			}
+			if len(ref.Param) > 0 {
+				references[param.Name] = fmt.Sprintf("%v/%v", ref.Name, ref.Param)
+			} else {
+				references[param.Name] = fmt.Sprintf("%v %v", ref.Type, ref.Name)
+			}
+			break
+		}
+	}
+	return references
+}
@@ -0,0 +1,106 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepConfigSources(t *testing.T) {
+	projectConfig := `customDefaults:
+  - custom-defaults.yml
+general:
+  p1: p1_general
+  p9: p9_same
+steps:
+  step1:
+    p2Alias: p2_step
+  stepAlias:
+    p3: p3_stepAlias
+stages:
+  stage1:
+    p4: p4_stage
+`
+	defaults := `general:
+  p1: p1_default
+  p5: p5_default
+  p9: p9_same
+`
+	customDefaults := `steps:
+  step1:
+    p6: p6_custom
+`
+	metadata := StepData{
+		Metadata: StepMetadata{Aliases: []Alias{{Name: "stepAlias"}}},
+		Spec: StepSpec{
+			Inputs: StepInputs{
+				Parameters: []StepParameters{
+					{Name: "p0", Default: "p0_stepDefault"},
+					{Name: "p1"},
+					{Name: "p2", Aliases: []Alias{{Name: "p2Alias"}}},
+					{Name: "p3"},
+					{Name: "p4"},
+					{Name: "p5"},
+					{Name: "p6"},
+					{Name: "p7", ResourceRef: []ResourceReference{{Name: "commonPipelineEnvironment", Param: "custom/p7"}}},
+					{Name: "p8"},
+					{Name: "p9"},
+				},
+			},
+		},
+	}
+	filters := StepFilters{
+		All:        []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9"},
+		General:    []string{"p1", "p5", "p9"},
+		Steps:      []string{"p2", "p3", "p6"},
+		Stages:     []string{"p4"},
+		Parameters: []string{"p8"},
+	}
+
+	c := Config{openFile: func(name string, _ map[string]string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(customDefaults)), nil
+	}}
+	c.EnableSourceTracking([]string{"defaults.yml"})
+
+	stepConfig, err := c.GetStepConfig(
+		map[string]interface{}{"p8": "p8_flag"},
+		"",
+		io.NopCloser(strings.NewReader(projectConfig)),
+		[]io.ReadCloser{io.NopCloser(strings.NewReader(defaults))},
+		false,
+		filters,
+		metadata,
+		map[string]interface{}{"p7": "p7_cpe"},
+		"stage1",
+		"step1",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ValueSource{Layer: SourceStepDefaults}, stepConfig.Sources["p0"])
+	assert.Equal(t, ValueSource{Layer: SourceProjectConfig, Section: "general"}, stepConfig.Sources["p1"])
+	assert.Equal(t, ValueSource{Layer: SourceProjectConfig, Section: "steps", Reference: "alias p2Alias"}, stepConfig.Sources["p2"])
+	assert.Equal(t, ValueSource{Layer: SourceProjectConfig, Section: "steps", Reference: "step alias stepAlias"}, stepConfig.Sources["p3"])
+	assert.Equal(t, ValueSource{Layer: SourceProjectConfig, Section: "stages"}, stepConfig.Sources["p4"])
+	assert.Equal(t, ValueSource{Layer: SourceDefaults, Origin: "defaults.yml", Section: "general"}, stepConfig.Sources["p5"])
+	assert.Equal(t, ValueSource{Layer: SourceCustomDefaults, Origin: "custom-defaults.yml", Section: "steps"}, stepConfig.Sources["p6"])
+	assert.Equal(t, ValueSource{Layer: SourceCommonPipelineEnvironment, Reference: "commonPipelineEnvironment/custom/p7"}, stepConfig.Sources["p7"])
+	assert.Equal(t, ValueSource{Layer: SourceFlags}, stepConfig.Sources["p8"])
+	// the last layer providing a value wins, even if the value is unchanged
+	assert.Equal(t, ValueSource{Layer: SourceProjectConfig, Section: "general"}, stepConfig.Sources["p9"])
+
+	assert.Equal(t, "projectConfig (steps) via alias p2Alias", stepConfig.Sources["p2"].String())
+	assert.Equal(t, "defaults 'defaults.yml' (general)", stepConfig.Sources["p5"].String())
+
+	t.Run("tracking disabled", func(t *testing.T) {
+		var c Config
+		stepConfig, err := c.GetStepConfig(nil, "", io.NopCloser(strings.NewReader("general:\n  p1: p1_general\n")), nil, true, filters, metadata, nil, "stage1", "step1")
+		assert.NoError(t, err)
+		assert.Equal(t, "p1_general", stepConfig.Config["p1"])
+		assert.Nil(t, stepConfig.Sources)
+	})
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/secrets"
+)
+
+const (
+	secretBackend             = "secretBackend"
+	azureKeyVaultUrl          = "azureKeyVaultUrl"
+	awsSecretsManagerRegion   = "awsSecretsManagerRegion"
+	awsSecretsManagerEndpoint = "awsSecretsManagerEndpoint"
+	gcpSecretManagerProject   = "gcpSecretManagerProject"
+	sopsSecretFile            = "sopsSecretFile"
+)
+
+// SecretProvider resolves the secrets referenced in the configuration, e.g. via the resource references vaultSecret and vaultSecretFile.
+// A secret is addressed by a path and contains several fields.
+type SecretProvider interface {
+	// GetKvSecret returns the fields of the secret at the given path, nil is returned if the secret does not exist
+	GetKvSecret(string) (map[string]string, error)
+}
+
+// GetSecretProviderFromConfig returns the secret provider selected via the parameter secretBackend, Vault is used by default.
+// Nil is returned if the selected backend is not configured.
+func GetSecretProviderFromConfig(config map[string]interface{}, creds VaultCredentials) (SecretProvider, error) {
+	backend, _ := config[secretBackend].(string)
+	switch backend {
+	case "", "vault":
+		return GetVaultClientFromConfig(config, creds)
+	case "azureKeyVault":
+		vaultURL, _ := config[azureKeyVaultUrl].(string)
+		if len(vaultURL) == 0 {
+			log.Entry().Debugf("Azure Key Vault not configured, %v missing", azureKeyVaultUrl)
+			return nil, nil
+		}
+		log.Entry().Infof("Using Azure Key Vault %v", vaultURL)
+		return secrets.NewAzureKeyVault(vaultURL)
+	case "awsSecretsManager":
+		region, _ := config[awsSecretsManagerRegion].(string)
+		endpoint, _ := config[awsSecretsManagerEndpoint].(string)
+		log.Entry().Info("Using AWS Secrets Manager")
+		return secrets.NewAWSSecretsManager(region, endpoint)
+	case "gcpSecretManager":
+		project, _ := config[gcpSecretManagerProject].(string)
+		if len(project) == 0 {
+			log.Entry().Debugf("Google Cloud Secret Manager not configured, %v missing", gcpSecretManagerProject)
+			return nil, nil
+		}
+		log.Entry().Infof("Using Google Cloud Secret Manager of project %v", project)
+		return secrets.NewGCPSecretManager(project)
+	case "sops":
+		file, _ := config[sopsSecretFile].(string)
+		if len(file) == 0 {
+			log.Entry().Debugf("SOPS not configured, %v missing", sopsSecretFile)
+			return nil, nil
+		}
+		log.Entry().Infof("Using SOPS encrypted file %v", file)
+		return secrets.NewSopsFile(file), nil
+	}
+	return nil, fmt.Errorf("secret backend '%v' not supported, use one of vault, azureKeyVault, awsSecretsManager, gcpSecretManager, sops", backend)
+}
@@ -0,0 +1,71 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+type secretProviderMock map[string]map[string]string
+
+func (s secretProviderMock) GetKvSecret(path string) (map[string]string, error) {
+	return s[path], nil
+}
+
+func TestGetSecretProviderFromConfig(t *testing.T) {
+	t.Run("vault not configured", func(t *testing.T) {
+		provider, err := GetSecretProviderFromConfig(map[string]interface{}{}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("sops", func(t *testing.T) {
+		provider, err := GetSecretProviderFromConfig(map[string]interface{}{"secretBackend": "sops", "sopsSecretFile": "secrets.enc.yaml"}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.IsType(t, &secrets.SopsFile{}, provider)
+	})
+
+	t.Run("backend not configured", func(t *testing.T) {
+		for _, backend := range []string{"azureKeyVault", "gcpSecretManager", "sops"} {
+			provider, err := GetSecretProviderFromConfig(map[string]interface{}{"secretBackend": backend}, VaultCredentials{})
+			assert.NoError(t, err)
+			assert.Nil(t, provider, backend)
+		}
+	})
+
+	t.Run("unsupported backend", func(t *testing.T) {
+		_, err := GetSecretProviderFromConfig(map[string]interface{}{"secretBackend": "keepass"}, VaultCredentials{})
+		assert.EqualError(t, err, "secret backend 'keepass' not supported, use one of vault, azureKeyVault, awsSecretsManager, gcpSecretManager, sops")
+	})
+}
+
+func TestResolveReferencesWithSecretProvider(t *testing.T) {
+	provider := secretProviderMock{
+		"team1/pipeline1/sonar":  {"sonarToken": "token"},
+		"team1/pipeline1/github": {"user": "piper", "password": "secret"},
+	}
+	stepConfig := StepConfig{Config: map[string]interface{}{
+		"vaultBasePath":            "team1",
+		"vaultPipelineName":        "pipeline1",
+		"vaultCredentialPath":      "github",
+		"vaultCredentialKeys":      []interface{}{"user"},
+		"vaultCredentialEnvPrefix": "PIPER_TEST_SECRETS_",
+	}}
+	params := []StepParameters{{Name: "sonarToken", ResourceRef: []ResourceReference{{Type: "vaultSecret", Default: "sonar"}}}}
+	// restore the environment variables exposing the credentials afterwards
+	for _, env := range []string{"PIPER_TEST_SECRETS_USER", "PIPER_TEST_SECRETS_USER_BASE64", "PIPER_VAULTCREDENTIAL_USER", "PIPER_VAULTCREDENTIAL_USER_BASE64"} {
+		t.Setenv(env, "")
+	}
+
+	resolveAllVaultReferences(&stepConfig, provider, params)
+	resolveVaultCredentialsWrapper(&stepConfig, provider)
+
+	assert.Equal(t, "token", stepConfig.Config["sonarToken"])
+	assert.Equal(t, "piper", os.Getenv("PIPER_TEST_SECRETS_USER"))
+	assert.Equal(t, "piper", os.Getenv("PIPER_VAULTCREDENTIAL_USER"))
+}
@@ -27,6 +27,8 @@ type StepMetadata struct {
 	Aliases         []Alias `json:"aliases,omitempty"`
 	Description     string  `json:"description"`
 	LongDescription string  `json:"longDescription,omitempty"`
+	// DryRunSimulation marks steps whose execution can be simulated in dry-run mode since they only call executables and send http requests via the piper clients
+	DryRunSimulation bool `json:"dryRunSimulation,omitempty"`
 }
 
 // StepSpec defines the spec details for a step, like step inputs, containers, sidecars, ...
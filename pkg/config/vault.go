@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"regexp"
@@ -33,6 +34,9 @@ const (
 	vaultTestCredentialEnvPrefixDefault = "PIPER_TESTCREDENTIAL_"
 	VaultCredentialEnvPrefixDefault     = "PIPER_VAULTCREDENTIAL_"
 	vaultSecretName                     = ".+VaultSecretName$"
+	vaultJwtRole                        = "vaultJwtRole"
+	vaultJwtMountPoint                  = "vaultJwtMountPoint"
+	vaultJwtAudience                    = "vaultJwtAudience"
 )
 
 var (
@@ -54,6 +58,16 @@ var (
 		vaultCredentialKeys,
 		vaultCredentialEnvPrefix,
 		vaultSecretName,
+		secretBackend,
+		azureKeyVaultUrl,
+		awsSecretsManagerRegion,
+		awsSecretsManagerEndpoint,
+		gcpSecretManagerProject,
+		sopsSecretFile,
+		vaultDynamicSecrets,
+		vaultJwtRole,
+		vaultJwtMountPoint,
+		vaultJwtAudience,
 	}
 
 	// VaultRootPaths are the lookup paths piper tries to use during the vault lookup.
@@ -73,11 +87,14 @@ type VaultCredentials struct {
 	AppRoleID       string
 	AppRoleSecretID string
 	VaultToken      string
+	// IdentityToken allows the login with the identity token of the orchestrator if vaultJwtRole is configured.
+	// The token created by the login needs to be revoked, thus it is only allowed for the step process.
+	IdentityToken bool
 }
 
 // VaultClient interface for mocking
 type VaultClient interface {
-	GetKvSecret(string) (map[string]string, error)
+	SecretProvider
 	MustRevokeToken()
 	GetOIDCTokenByValidation(string) (string, error)
 }
@@ -94,8 +111,12 @@ func (s *StepConfig) mixinVaultConfig(parameters []StepParameters, configs ...ma
 
 func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredentials) (VaultClient, error) {
 	address, addressOk := config["vaultServerUrl"].(string)
+	jwtRole, _ := config[vaultJwtRole].(string)
+	if !creds.IdentityToken {
+		jwtRole = ""
+	}
 	// if vault isn't used it's not an error
-	if !addressOk || creds.VaultToken == "" && (creds.AppRoleID == "" || creds.AppRoleSecretID == "") {
+	if !addressOk || creds.VaultToken == "" && (creds.AppRoleID == "" || creds.AppRoleSecretID == "") && jwtRole == "" {
 		log.Entry().Debug("Vault not configured")
 		return nil, nil
 	}
@@ -113,9 +134,12 @@ func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredenti
 	if creds.VaultToken != "" {
 		log.Entry().Debugf("  with Token authentication")
 		client, err = vault.NewClient(clientConfig, creds.VaultToken)
-	} else {
+	} else if creds.AppRoleID != "" && creds.AppRoleSecretID != "" {
 		log.Entry().Debugf("  with AppRole authentication")
 		client, err = vault.NewClientWithAppRole(clientConfig, creds.AppRoleID, creds.AppRoleSecretID)
+	} else {
+		log.Entry().Debugf("  with JWT authentication for role %s", jwtRole)
+		client, err = newVaultClientWithIdentityToken(clientConfig, config, jwtRole)
 	}
 	if err != nil {
 		log.Entry().Info("  failed")
@@ -125,7 +149,23 @@ func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredenti
 	return client, nil
 }
 
-func resolveAllVaultReferences(config *StepConfig, client VaultClient, params []StepParameters) {
+// newVaultClientWithIdentityToken logs into Vault with the identity token the orchestrator issued for the pipeline run,
+// which removes the need to store and rotate credentials for Vault
+func newVaultClientWithIdentityToken(clientConfig *vault.Config, config map[string]interface{}, role string) (VaultClient, error) {
+	provider, err := orchestratorProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect orchestrator: %w", err)
+	}
+	audience, _ := config[vaultJwtAudience].(string)
+	token, err := provider.IdentityToken(audience)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity token from %v: %w", provider.OrchestratorType(), err)
+	}
+	clientConfig.JWTMountPoint, _ = config[vaultJwtMountPoint].(string)
+	return vault.NewClientWithJWT(clientConfig, role, token)
+}
+
+func resolveAllVaultReferences(config *StepConfig, client SecretProvider, params []StepParameters) {
 	for _, param := range params {
 		if ref := param.GetReference("vaultSecret"); ref != nil {
 			resolveVaultReference(ref, config, client, param)
@@ -136,7 +176,7 @@ func resolveAllVaultReferences(config *StepConfig, client VaultClient, params []
 	}
 }
 
-func resolveVaultReference(ref *ResourceReference, config *StepConfig, client VaultClient, param StepParameters) {
+func resolveVaultReference(ref *ResourceReference, config *StepConfig, client SecretProvider, param StepParameters) {
 	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
 	if _, ok := config.Config[param.Name].(string); vaultDisableOverwrite && ok {
 		log.Entry().Debugf("Not fetching '%s' from Vault since it has already been set", param.Name)
@@ -174,20 +214,20 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client Va
 	}
 }
 
-func resolveVaultTestCredentialsWrapper(config *StepConfig, client VaultClient) {
+func resolveVaultTestCredentialsWrapper(config *StepConfig, client SecretProvider) {
 	log.Entry().Infof("Resolving test credentials wrapper")
 	resolveVaultCredentialsWrapperBase(config, client, vaultTestCredentialPath, vaultTestCredentialKeys, vaultTestCredentialEnvPrefix, resolveVaultTestCredentials)
 }
 
-func resolveVaultCredentialsWrapper(config *StepConfig, client VaultClient) {
+func resolveVaultCredentialsWrapper(config *StepConfig, client SecretProvider) {
 	log.Entry().Infof("Resolving credentials wrapper")
 	resolveVaultCredentialsWrapperBase(config, client, vaultCredentialPath, vaultCredentialKeys, vaultCredentialEnvPrefix, resolveVaultCredentials)
 }
 
 func resolveVaultCredentialsWrapperBase(
-	config *StepConfig, client VaultClient,
+	config *StepConfig, client SecretProvider,
 	vaultCredPath, vaultCredKeys, vaultCredEnvPrefix string,
-	resolveVaultCredentials func(config *StepConfig, client VaultClient),
+	resolveVaultCredentials func(config *StepConfig, client SecretProvider),
 ) {
 	switch config.Config[vaultCredPath].(type) {
 	case string:
@@ -231,7 +271,7 @@ func resolveVaultCredentialsWrapperBase(
 }
 
 // resolve test credential keys and expose as environment variables
-func resolveVaultTestCredentials(config *StepConfig, client VaultClient) {
+func resolveVaultTestCredentials(config *StepConfig, client SecretProvider) {
 	credPath, pathOk := config.Config[vaultTestCredentialPath].(string)
 	keys := getTestCredentialKeys(config)
 	if !(pathOk && keys != nil) || credPath == "" || len(keys) == 0 {
@@ -268,7 +308,7 @@ func resolveVaultTestCredentials(config *StepConfig, client VaultClient) {
 	}
 }
 
-func resolveVaultCredentials(config *StepConfig, client VaultClient) {
+func resolveVaultCredentials(config *StepConfig, client SecretProvider) {
 	credPath, pathOk := config.Config[vaultCredentialPath].(string)
 	keys := getCredentialKeys(config)
 	if !(pathOk && keys != nil) || credPath == "" || len(keys) == 0 {
@@ -425,6 +465,7 @@ func ConvertEnvVar(s string) string {
 func RemoveVaultSecretFiles() {
 	if VaultSecretFileDirectory != "" {
 		os.RemoveAll(VaultSecretFileDirectory)
+		VaultSecretFileDirectory = ""
 	}
 }
 
@@ -450,7 +491,7 @@ func createTemporarySecretFile(namePattern string, content string) (string, erro
 	return file.Name(), nil
 }
 
-func lookupPath(client VaultClient, path string, param *StepParameters) *string {
+func lookupPath(client SecretProvider, path string, param *StepParameters) *string {
 	log.Entry().Debugf("  with Vault path '%s'", path)
 	secret, err := client.GetKvSecret(path)
 	if err != nil {
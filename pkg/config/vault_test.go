@@ -4,7 +4,11 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
@@ -14,6 +18,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -502,6 +507,67 @@ func TestResolveVaultTestCredentials(t *testing.T) {
 	})
 }
 
+type identityTokenMock struct {
+	orchestrator.ConfigProvider
+	audience string
+	err      error
+}
+
+func (i *identityTokenMock) OrchestratorType() string { return "GitHubActions" }
+func (i *identityTokenMock) IdentityToken(audience string) (string, error) {
+	i.audience = audience
+	return "eyJhbGciOi", i.err
+}
+
+func TestGetVaultClientFromConfig(t *testing.T) {
+	var login map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/auth/github/login", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&login))
+		fmt.Fprint(w, `{"auth": {"client_token": "s.token"}}`)
+	}))
+	defer server.Close()
+
+	provider := &identityTokenMock{}
+	originalProvider := orchestratorProvider
+	orchestratorProvider = func() (orchestrator.ConfigProvider, error) { return provider, nil }
+	defer func() { orchestratorProvider = originalProvider }()
+
+	t.Run("not configured", func(t *testing.T) {
+		client, err := GetVaultClientFromConfig(map[string]interface{}{"vaultServerUrl": server.URL}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("JWT authentication not allowed", func(t *testing.T) {
+		client, err := GetVaultClientFromConfig(map[string]interface{}{"vaultServerUrl": server.URL, "vaultJwtRole": "piper"}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("JWT authentication", func(t *testing.T) {
+		config := map[string]interface{}{
+			"vaultServerUrl":     server.URL,
+			"vaultJwtRole":       "piper",
+			"vaultJwtMountPoint": "auth/github",
+			"vaultJwtAudience":   "https://vault.example.com",
+		}
+		client, err := GetVaultClientFromConfig(config, VaultCredentials{IdentityToken: true})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.Equal(t, "https://vault.example.com", provider.audience)
+		assert.Equal(t, map[string]interface{}{"role": "piper", "jwt": "eyJhbGciOi"}, login)
+	})
+
+	t.Run("no identity token", func(t *testing.T) {
+		provider.err = errors.New("the workflow requires the permission 'id-token: write'")
+		defer func() { provider.err = nil }()
+
+		_, err := GetVaultClientFromConfig(map[string]interface{}{"vaultServerUrl": server.URL, "vaultJwtRole": "piper"}, VaultCredentials{IdentityToken: true})
+		assert.EqualError(t, err, "failed to get identity token from GitHubActions: the workflow requires the permission 'id-token: write'")
+	})
+}
+
 func Test_convertEnvVar(t *testing.T) {
 	type args struct {
 		s string
@@ -28,6 +28,9 @@ type VulnerabilitiesResponse struct {
 }
 
 type Vulnerability struct {
+	Uuid     string `json:"uuid,omitempty"`
+	Title    string `json:"title,omitempty"`
+	RuleName string `json:"ruleName,omitempty"`
 	Severity string `json:"severity"`
 	Status   string `json:"status"`
 }
@@ -42,7 +45,7 @@ type ApplicationResponse struct {
 }
 
 type Contrast interface {
-	GetVulnerabilities() error
+	GetVulnerabilities() ([]ContrastFindings, []Vulnerability, error)
 	GetAppInfo(appUIUrl, server string)
 }
 
@@ -60,7 +63,7 @@ func NewContrastInstance(url, apiKey, auth string) ContrastInstance {
 	}
 }
 
-func (contrast *ContrastInstance) GetVulnerabilities() ([]ContrastFindings, error) {
+func (contrast *ContrastInstance) GetVulnerabilities() ([]ContrastFindings, []Vulnerability, error) {
 	url := contrast.url + "/vulnerabilities"
 	client := NewContrastHttpClient(contrast.apiKey, contrast.auth)
 
@@ -92,7 +95,7 @@ func getApplicationFromClient(client ContrastHttpClient, url string) (*Applicati
 	}, nil
 }
 
-func getVulnerabilitiesFromClient(client ContrastHttpClient, url string, page int) ([]ContrastFindings, error) {
+func getVulnerabilitiesFromClient(client ContrastHttpClient, url string, page int) ([]ContrastFindings, []Vulnerability, error) {
 	params := map[string]string{
 		"page": fmt.Sprintf("%d", page),
 		"size": fmt.Sprintf("%d", pageSize),
@@ -100,25 +103,25 @@ func getVulnerabilitiesFromClient(client ContrastHttpClient, url string, page in
 	var vulnsResponse VulnerabilitiesResponse
 	err := client.ExecuteRequest(url, params, &vulnsResponse)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if vulnsResponse.Empty {
 		log.Entry().Info("empty vulnerabilities response")
-		return []ContrastFindings{}, nil
+		return []ContrastFindings{}, []Vulnerability{}, nil
 	}
 
 	auditAllFindings, optionalFindings := getFindings(vulnsResponse.Vulnerabilities)
 
 	if !vulnsResponse.Last {
-		findings, err := getVulnerabilitiesFromClient(client, url, page+1)
+		findings, vulnerabilities, err := getVulnerabilitiesFromClient(client, url, page+1)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		accumulateFindings(auditAllFindings, optionalFindings, findings)
-		return findings, nil
+		return findings, append(vulnsResponse.Vulnerabilities, vulnerabilities...), nil
 	}
-	return []ContrastFindings{auditAllFindings, optionalFindings}, nil
+	return []ContrastFindings{auditAllFindings, optionalFindings}, vulnsResponse.Vulnerabilities, nil
 }
 
 func getFindings(vulnerabilities []Vulnerability) (ContrastFindings, ContrastFindings) {
@@ -106,10 +106,11 @@ func TestGetVulnerabilitiesFromClient(t *testing.T) {
 	t.Parallel()
 	t.Run("Success", func(t *testing.T) {
 		contrastClient := &contrastHttpClientMock{}
-		findings, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrl, 0)
+		findings, vulnerabilities, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrl, 0)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, findings)
 		assert.Equal(t, 2, len(findings))
+		assert.Equal(t, 6, len(vulnerabilities))
 		for _, f := range findings {
 			assert.True(t, f.ClassificationName == AuditAll || f.ClassificationName == Optional)
 			if f.ClassificationName == AuditAll {
@@ -126,10 +127,11 @@ func TestGetVulnerabilitiesFromClient(t *testing.T) {
 	t.Run("Success with pagination results", func(t *testing.T) {
 		page := 0
 		contrastClient := &contrastHttpClientMock{page: &page}
-		findings, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrlPaginated, 0)
+		findings, vulnerabilities, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrlPaginated, 0)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, findings)
 		assert.Equal(t, 2, len(findings))
+		assert.Equal(t, 300, len(vulnerabilities))
 		for _, f := range findings {
 			assert.True(t, f.ClassificationName == AuditAll || f.ClassificationName == Optional)
 			if f.ClassificationName == AuditAll {
@@ -145,15 +147,16 @@ func TestGetVulnerabilitiesFromClient(t *testing.T) {
 
 	t.Run("Empty response", func(t *testing.T) {
 		contrastClient := &contrastHttpClientMock{}
-		findings, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrlEmpty, 0)
+		findings, vulnerabilities, err := getVulnerabilitiesFromClient(contrastClient, vulnsUrlEmpty, 0)
 		assert.NoError(t, err)
 		assert.Empty(t, findings)
 		assert.Equal(t, 0, len(findings))
+		assert.Empty(t, vulnerabilities)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		contrastClient := &contrastHttpClientMock{}
-		_, err := getVulnerabilitiesFromClient(contrastClient, errorUrl, 0)
+		_, _, err := getVulnerabilitiesFromClient(contrastClient, errorUrl, 0)
 		assert.Error(t, err)
 	})
 }
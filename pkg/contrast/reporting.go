@@ -2,10 +2,14 @@ package contrast
 
 import (
 	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/pkg/errors"
 )
@@ -49,6 +53,54 @@ func WriteJSONReport(jsonReport ContrastAudit, modulePath string) ([]piperutils.
 	return reportPaths, nil
 }
 
+// CreateCustomReport creates the scan report of the findings of the application
+func CreateCustomReport(appInfo *ApplicationInfo, findings []reporting.Finding) reporting.ScanReport {
+	subheaders := []reporting.Subheader{
+		{Description: "Application", Details: appInfo.Name},
+		{Description: "Contrast URL", Details: fmt.Sprintf(`<a href="%v" target="_blank">%v</a>`, appInfo.Url, appInfo.Url)},
+	}
+	return reporting.CreateFindingsScanReport("Contrast Assess Report", subheaders, findings, reporting.SeverityHigh)
+}
+
+// CreateSarif creates the SARIF report of the findings
+func CreateSarif(findings []reporting.Finding) format.SARIF {
+	tool := format.Driver{
+		Name:           "Contrast Assess",
+		InformationUri: "https://docs.contrastsecurity.com/en/assess.html",
+	}
+	return reporting.CreateFindingsSarif(tool, findings)
+}
+
+// WriteCustomReports writes the scan report as HTML and the SARIF report into the contrast directory of the module
+func WriteCustomReports(scanReport reporting.ScanReport, sarif format.SARIF, modulePath string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	reportsDirectory := filepath.Join(modulePath, "contrast")
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(reportsDirectory, "piper_contrast_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Contrast Report", Target: htmlReportPath})
+
+	sarifReport, err := json.MarshalIndent(sarif, "", "  ")
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to marshal SARIF report")
+	}
+	sarifReportPath := filepath.Join(reportsDirectory, "piper_contrast.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write SARIF report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Contrast SARIF Report", Target: sarifReportPath})
+	return reportPaths, nil
+}
+
 func CreateAndPersistToolRecord(utils piperutils.FileUtils, appInfo *ApplicationInfo, modulePath string) (string, error) {
 	toolRecord, err := createToolRecordContrast(utils, appInfo, modulePath)
 	if err != nil {
@@ -87,3 +139,27 @@ func persistToolRecord(toolrecord *toolrecord.Toolrecord) (string, error) {
 	}
 	return toolrecord.GetFileName(), nil
 }
+
+// ToFinding converts the vulnerability into the scanner independent finding
+func (v Vulnerability) ToFinding() reporting.Finding {
+	finding := reporting.Finding{
+		Scanner:  "contrast",
+		Type:     reporting.FindingTypeCode,
+		ID:       v.RuleName,
+		Name:     v.Title,
+		Severity: reporting.ParseSeverity(v.Severity),
+		Status:   reporting.FindingStatusOpen,
+	}
+	if len(finding.ID) == 0 {
+		finding.ID = v.Uuid
+	}
+	switch strings.ToUpper(v.Status) {
+	case "SUSPICIOUS":
+		finding.Status = reporting.FindingStatusInTriage
+	case "CONFIRMED":
+		finding.Status = reporting.FindingStatusConfirmed
+	case "NOT_A_PROBLEM", "REMEDIATED", "FIXED", "AUTO_REMEDIATED":
+		finding.Status = reporting.FindingStatusNotAffected
+	}
+	return finding
+}
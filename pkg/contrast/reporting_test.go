@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -109,3 +110,47 @@ func TestCreateToolRecordContrast(t *testing.T) {
 		assert.Equal(t, appInfo.Name, toolRecord.Keys[0].DisplayName)
 	})
 }
+
+func TestVulnerabilityToFinding(t *testing.T) {
+	t.Parallel()
+	vuln := Vulnerability{Uuid: "ABC-123", Title: "SQL Injection from parameter id", RuleName: "sql-injection", Severity: "CRITICAL", Status: "REPORTED"}
+	finding := vuln.ToFinding()
+	assert.Equal(t, "contrast", finding.Scanner)
+	assert.Equal(t, "sql-injection", finding.ID)
+	assert.Equal(t, "SQL Injection from parameter id", finding.Name)
+	assert.Equal(t, "critical", finding.Severity.String())
+	assert.Equal(t, "open", string(finding.Status))
+
+	vuln.Status = "NOT_A_PROBLEM"
+	assert.Equal(t, "notAffected", string(vuln.ToFinding().Status))
+	vuln.Status = "Confirmed"
+	assert.Equal(t, "confirmed", string(vuln.ToFinding().Status))
+}
+
+func TestWriteCustomReports(t *testing.T) {
+	appInfo := &ApplicationInfo{Url: "https://server.com/application", Name: "app name"}
+	findings := []reporting.Finding{
+		Vulnerability{RuleName: "sql-injection", Title: "SQL Injection", Severity: "CRITICAL", Status: "Reported"}.ToFinding(),
+		Vulnerability{RuleName: "xss", Severity: "LOW", Status: "NOT_A_PROBLEM"}.ToFinding(),
+	}
+
+	scanReport := CreateCustomReport(appInfo, findings)
+	assert.Equal(t, "Contrast Assess Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Len(t, scanReport.DetailTable.Rows, 2)
+
+	sarif := CreateSarif(findings)
+	if assert.Len(t, sarif.Runs, 1) {
+		assert.Equal(t, "Contrast Assess", sarif.Runs[0].Tool.Driver.Name)
+		assert.Len(t, sarif.Runs[0].Results, 2)
+	}
+
+	utils := newContrastExecuteScanTestsUtils()
+	paths, err := WriteCustomReports(scanReport, sarif, "./", utils)
+	assert.NoError(t, err)
+	if assert.Len(t, paths, 2) {
+		assert.True(t, utils.HasWrittenFile(paths[0].Target))
+		assert.True(t, utils.HasWrittenFile(paths[1].Target))
+		assert.Equal(t, "contrast/piper_contrast.sarif", paths[1].Target)
+	}
+}
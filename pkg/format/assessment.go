@@ -103,7 +103,7 @@ func (a Assessment) ToImpactAnalysisResponse() *[]cdx.ImpactAnalysisResponse {
 	return &[]cdx.ImpactAnalysisResponse{cdx.IARWillNotFix}
 }
 
-// ReadAssessment loads the assessments and returns their contents
+// ReadAssessments loads the assessments and returns their contents, OpenVEX and CycloneDX VEX documents are converted into assessments
 func ReadAssessments(assessmentFile io.ReadCloser) (*[]Assessment, error) {
 	defer assessmentFile.Close()
 	ignore := struct {
@@ -117,6 +117,14 @@ func ReadAssessments(assessmentFile io.ReadCloser) (*[]Assessment, error) {
 		return nil, errors.Wrapf(err, "error reading %v", assessmentFile)
 	}
 
+	if IsVEX(content) {
+		assessments, err := ReadVEX(content)
+		if err != nil {
+			return nil, err
+		}
+		return &assessments, nil
+	}
+
 	err = yaml.Unmarshal(content, &ignore)
 	if err != nil {
 		return nil, NewParseError(fmt.Sprintf("format of assessment file is invalid %q: %v", content, err))
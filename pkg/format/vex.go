@@ -0,0 +1,331 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+)
+
+// OpenVEXContext is the JSON-LD context of OpenVEX documents
+const OpenVEXContext = "https://openvex.dev/ns/v0.2.0"
+
+// VEXStatus is the status of an OpenVEX statement
+type VEXStatus string
+
+const (
+	VEXNotAffected        VEXStatus = "not_affected"
+	VEXAffected           VEXStatus = "affected"
+	VEXFixed              VEXStatus = "fixed"
+	VEXUnderInvestigation VEXStatus = "under_investigation"
+)
+
+// VEXJustification explains why a product is not affected
+type VEXJustification string
+
+const (
+	VEXComponentNotPresent                         VEXJustification = "component_not_present"
+	VEXVulnerableCodeNotPresent                    VEXJustification = "vulnerable_code_not_present"
+	VEXVulnerableCodeNotInExecutePath              VEXJustification = "vulnerable_code_not_in_execute_path"
+	VEXVulnerableCodeCannotBeControlledByAdversary VEXJustification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	VEXInlineMitigationsAlreadyExist               VEXJustification = "inline_mitigations_already_exist"
+)
+
+// OpenVEX is a document according to the OpenVEX specification, see https://github.com/openvex/spec
+type OpenVEX struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Role       string             `json:"role,omitempty"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Version    int                `json:"version"`
+	Tooling    string             `json:"tooling,omitempty"`
+	Statements []OpenVEXStatement `json:"statements"`
+}
+
+// OpenVEXStatement asserts the status of a vulnerability in the products
+type OpenVEXStatement struct {
+	Vulnerability   OpenVEXVulnerability `json:"vulnerability"`
+	Products        []OpenVEXProduct     `json:"products,omitempty"`
+	Status          VEXStatus            `json:"status"`
+	StatusNotes     string               `json:"status_notes,omitempty"`
+	Justification   VEXJustification     `json:"justification,omitempty"`
+	ImpactStatement string               `json:"impact_statement,omitempty"`
+	ActionStatement string               `json:"action_statement,omitempty"`
+}
+
+// OpenVEXVulnerability identifies the vulnerability of a statement
+type OpenVEXVulnerability struct {
+	ID      string   `json:"@id,omitempty"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// UnmarshalJSON also accepts the plain vulnerability name of earlier OpenVEX versions
+func (v *OpenVEXVulnerability) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*v = OpenVEXVulnerability{Name: name}
+		return nil
+	}
+	type vulnerability OpenVEXVulnerability
+	return json.Unmarshal(data, (*vulnerability)(v))
+}
+
+// OpenVEXProduct is a product or a subcomponent of a product, usually identified by its package URL
+type OpenVEXProduct struct {
+	ID            string            `json:"@id,omitempty"`
+	Identifiers   map[string]string `json:"identifiers,omitempty"`
+	Subcomponents []OpenVEXProduct  `json:"subcomponents,omitempty"`
+}
+
+// UnmarshalJSON also accepts the plain product identifier of earlier OpenVEX versions
+func (p *OpenVEXProduct) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*p = OpenVEXProduct{ID: id}
+		return nil
+	}
+	type product OpenVEXProduct
+	return json.Unmarshal(data, (*product)(p))
+}
+
+func (p OpenVEXProduct) purl() string {
+	if purl, ok := p.Identifiers["purl"]; ok {
+		return purl
+	}
+	if strings.HasPrefix(p.ID, "pkg:") {
+		return p.ID
+	}
+	return ""
+}
+
+// IsVEX returns whether the content is an OpenVEX or a CycloneDX document
+func IsVEX(content []byte) bool {
+	kind, _ := vexKind(content)
+	return len(kind) > 0
+}
+
+func vexKind(content []byte) (string, error) {
+	header := struct {
+		Context   string `json:"@context"`
+		BOMFormat string `json:"bomFormat"`
+	}{}
+	if err := json.Unmarshal(content, &header); err != nil {
+		return "", err
+	}
+	switch {
+	case strings.HasPrefix(header.Context, "https://openvex.dev/ns"):
+		return "openvex", nil
+	case header.BOMFormat == cdx.BOMFormat:
+		return "cyclonedx", nil
+	}
+	return "", nil
+}
+
+// ReadVEX converts the statements of an OpenVEX or a CycloneDX VEX document into assessments.
+// Statements which confirm that a product is affected do not assess the vulnerability and are skipped.
+// Statements about vulnerabilities under investigation are skipped as well, they must not suppress the vulnerability.
+func ReadVEX(content []byte) ([]Assessment, error) {
+	kind, err := vexKind(content)
+	if err != nil {
+		return nil, NewParseError(fmt.Sprintf("format of VEX document is invalid: %v", err))
+	}
+	switch kind {
+	case "openvex":
+		var document OpenVEX
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, NewParseError(fmt.Sprintf("format of OpenVEX document is invalid: %v", err))
+		}
+		return assessmentsFromOpenVEX(document), nil
+	case "cyclonedx":
+		var bom cdx.BOM
+		if err := json.Unmarshal(content, &bom); err != nil {
+			return nil, NewParseError(fmt.Sprintf("format of CycloneDX document is invalid: %v", err))
+		}
+		return assessmentsFromCycloneDX(bom), nil
+	}
+	return nil, NewParseError("document is neither an OpenVEX nor a CycloneDX document")
+}
+
+func assessmentsFromOpenVEX(document OpenVEX) []Assessment {
+	assessments := []Assessment{}
+	for _, statement := range document.Statements {
+		assessment := Assessment{}
+		switch statement.Status {
+		case VEXNotAffected:
+			assessment.Status = NotRelevant
+			assessment.Analysis = analysisFromJustification(statement.Justification)
+		case VEXFixed:
+			assessment.Status = NotRelevant
+			assessment.Analysis = FixedByDevTeam
+		default:
+			continue
+		}
+		// the vulnerable packages are the subcomponents of the products
+		for _, product := range statement.Products {
+			purls := []string{}
+			for _, subcomponent := range product.Subcomponents {
+				if purl := subcomponent.purl(); len(purl) > 0 {
+					purls = append(purls, purl)
+				}
+			}
+			if len(purls) == 0 && len(product.purl()) > 0 {
+				purls = append(purls, product.purl())
+			}
+			for _, purl := range purls {
+				assessment.Purls = append(assessment.Purls, Purl{Purl: purl})
+			}
+		}
+		for _, name := range append([]string{statement.Vulnerability.Name}, statement.Vulnerability.Aliases...) {
+			if len(name) > 0 {
+				assessment.Vulnerability = name
+				assessments = append(assessments, assessment)
+			}
+		}
+	}
+	return assessments
+}
+
+func analysisFromJustification(justification VEXJustification) AssessmentAnalysis {
+	switch justification {
+	case VEXComponentNotPresent, VEXVulnerableCodeNotPresent:
+		return NotPresent
+	case VEXVulnerableCodeNotInExecutePath:
+		return NotUsed
+	}
+	return Mitigated
+}
+
+func assessmentsFromCycloneDX(bom cdx.BOM) []Assessment {
+	assessments := []Assessment{}
+	if bom.Vulnerabilities == nil {
+		return assessments
+	}
+	purls := map[string]string{}
+	if bom.Components != nil {
+		collectPurls(*bom.Components, purls)
+	}
+	for _, vulnerability := range *bom.Vulnerabilities {
+		if vulnerability.Analysis == nil {
+			continue
+		}
+		assessment := Assessment{Vulnerability: vulnerability.ID}
+		switch vulnerability.Analysis.State {
+		case cdx.IASNotAffected:
+			assessment.Status = NotRelevant
+			assessment.Analysis = analysisFromImpactJustification(vulnerability.Analysis.Justification)
+		case cdx.IASFalsePositive:
+			assessment.Status = NotRelevant
+			assessment.Analysis = WronglyReported
+		case cdx.IASResolved, cdx.IASResolvedWithPedigree:
+			assessment.Status = NotRelevant
+			assessment.Analysis = FixedByDevTeam
+		default:
+			continue
+		}
+		if vulnerability.Affects != nil {
+			for _, affects := range *vulnerability.Affects {
+				purl, known := purls[affects.Ref]
+				if !known && strings.HasPrefix(affects.Ref, "pkg:") {
+					purl = affects.Ref
+				}
+				if len(purl) > 0 {
+					assessment.Purls = append(assessment.Purls, Purl{Purl: purl})
+				}
+			}
+		}
+		assessments = append(assessments, assessment)
+	}
+	return assessments
+}
+
+func collectPurls(components []cdx.Component, purls map[string]string) {
+	for _, component := range components {
+		if len(component.BOMRef) > 0 && len(component.PackageURL) > 0 {
+			purls[component.BOMRef] = component.PackageURL
+		}
+		if component.Components != nil {
+			collectPurls(*component.Components, purls)
+		}
+	}
+}
+
+func analysisFromImpactJustification(justification cdx.ImpactAnalysisJustification) AssessmentAnalysis {
+	switch justification {
+	case cdx.IAJCodeNotPresent:
+		return NotPresent
+	case cdx.IAJCodeNotReachable, cdx.IAJRequiresConfiguration, cdx.IAJRequiresDependency, cdx.IAJRequiresEnvironment:
+		return NotUsed
+	}
+	return Mitigated
+}
+
+// ToVEXStatus maps the assessment to the status of a VEX statement
+func (a Assessment) ToVEXStatus() VEXStatus {
+	switch a.Status {
+	case NotRelevant:
+		if a.Analysis == FixedByDevTeam {
+			return VEXFixed
+		}
+		return VEXNotAffected
+	case InProcess:
+		return VEXUnderInvestigation
+	}
+	return VEXAffected
+}
+
+// ToVEXJustification maps the analysis of the assessment to the justification of a VEX statement
+func (a Assessment) ToVEXJustification() VEXJustification {
+	switch a.Analysis {
+	case NotPresent, WronglyReported:
+		return VEXVulnerableCodeNotPresent
+	case NotUsed:
+		return VEXVulnerableCodeNotInExecutePath
+	case Mitigated:
+		return VEXInlineMitigationsAlreadyExist
+	}
+	return ""
+}
+
+// Assesses returns whether the assessment applies to one of the vulnerability names in the package.
+// Qualifiers are ignored and a package URL without type, e.g. of a scanner which only knows name and version, matches any type.
+// Package URLs of the assessment which cannot be parsed are reported as configuration error and ignored.
+func (a Assessment) Assesses(purl packageurl.PackageURL, vulnerabilities ...string) bool {
+	named := false
+	for _, vulnerability := range vulnerabilities {
+		named = named || (len(vulnerability) > 0 && strings.EqualFold(a.Vulnerability, vulnerability))
+	}
+	if !named {
+		return false
+	}
+	for _, assessed := range a.Purls {
+		assessedPurl, err := assessed.ToPackageUrl()
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			log.Entry().WithError(err).Errorf("assessment from file ignored due to invalid packageUrl '%s'", assessed.Purl)
+			continue
+		}
+		if len(purl.Type) > 0 && (!strings.EqualFold(assessedPurl.Type, purl.Type) || !strings.EqualFold(assessedPurl.Namespace, purl.Namespace)) {
+			continue
+		}
+		if strings.EqualFold(assessedPurl.Name, purl.Name) && assessedPurl.Version == purl.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAssessment returns the first assessment which applies to one of the vulnerability names in the package
+func FindAssessment(assessments []Assessment, purl packageurl.PackageURL, vulnerabilities ...string) *Assessment {
+	for i := range assessments {
+		if assessments[i].Assesses(purl, vulnerabilities...) {
+			return &assessments[i]
+		}
+	}
+	return nil
+}
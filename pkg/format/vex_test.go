@@ -0,0 +1,149 @@
+//go:build unit
+// +build unit
+
+package format
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVEX(t *testing.T) {
+	t.Run("OpenVEX", func(t *testing.T) {
+		document := `{
+			"@context": "https://openvex.dev/ns/v0.2.0",
+			"@id": "https://example.com/vex-1",
+			"author": "ACME",
+			"timestamp": "2023-10-18T10:00:00Z",
+			"version": 1,
+			"statements": [
+				{
+					"vulnerability": {"name": "CVE-2023-1", "aliases": ["GHSA-1"]},
+					"products": [{"@id": "pkg:oci/app@sha256:abc", "subcomponents": [{"@id": "pkg:npm/lodash@4.17.20"}]}],
+					"status": "not_affected",
+					"justification": "vulnerable_code_not_in_execute_path"
+				},
+				{
+					"vulnerability": {"name": "CVE-2023-2"},
+					"products": [{"@id": "pkg:maven/org.example/lib@1.0.0"}],
+					"status": "fixed"
+				},
+				{
+					"vulnerability": {"name": "CVE-2023-3"},
+					"products": [{"@id": "pkg:maven/org.example/lib@1.0.0"}],
+					"status": "affected",
+					"action_statement": "Update to 1.0.1"
+				}
+			]
+		}`
+
+		assessments, err := ReadVEX([]byte(document))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Assessment{
+			{Vulnerability: "CVE-2023-1", Status: NotRelevant, Analysis: NotUsed, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}},
+			{Vulnerability: "GHSA-1", Status: NotRelevant, Analysis: NotUsed, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}},
+			{Vulnerability: "CVE-2023-2", Status: NotRelevant, Analysis: FixedByDevTeam, Purls: []Purl{{Purl: "pkg:maven/org.example/lib@1.0.0"}}},
+		}, assessments)
+	})
+
+	t.Run("OpenVEX with plain identifiers", func(t *testing.T) {
+		document := `{
+			"@context": "https://openvex.dev/ns",
+			"statements": [
+				{"vulnerability": "CVE-2023-1", "products": ["pkg:npm/lodash@4.17.20"], "status": "not_affected", "justification": "component_not_present"},
+				{"vulnerability": "CVE-2023-2", "products": ["pkg:npm/lodash@4.17.20"], "status": "under_investigation"}
+			]
+		}`
+
+		assessments, err := ReadVEX([]byte(document))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Assessment{{Vulnerability: "CVE-2023-1", Status: NotRelevant, Analysis: NotPresent, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}}}, assessments)
+	})
+
+	t.Run("CycloneDX", func(t *testing.T) {
+		document := `{
+			"bomFormat": "CycloneDX",
+			"specVersion": "1.4",
+			"version": 1,
+			"components": [{"bom-ref": "lodash", "type": "library", "name": "lodash", "version": "4.17.20", "purl": "pkg:npm/lodash@4.17.20"}],
+			"vulnerabilities": [
+				{"id": "CVE-2023-1", "analysis": {"state": "not_affected", "justification": "code_not_present"}, "affects": [{"ref": "lodash"}]},
+				{"id": "CVE-2023-2", "analysis": {"state": "false_positive"}, "affects": [{"ref": "pkg:npm/express@4.0.0"}]},
+				{"id": "CVE-2023-3", "analysis": {"state": "in_triage"}, "affects": [{"ref": "lodash"}]},
+				{"id": "CVE-2023-4", "analysis": {"state": "exploitable"}, "affects": [{"ref": "lodash"}]},
+				{"id": "CVE-2023-5", "affects": [{"ref": "lodash"}]}
+			]
+		}`
+
+		assessments, err := ReadVEX([]byte(document))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Assessment{
+			{Vulnerability: "CVE-2023-1", Status: NotRelevant, Analysis: NotPresent, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}},
+			{Vulnerability: "CVE-2023-2", Status: NotRelevant, Analysis: WronglyReported, Purls: []Purl{{Purl: "pkg:npm/express@4.0.0"}}},
+		}, assessments)
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		_, err := ReadVEX([]byte(`{"ignore": []}`))
+
+		assert.EqualError(t, err, "document is neither an OpenVEX nor a CycloneDX document")
+	})
+}
+
+func TestReadAssessments(t *testing.T) {
+	t.Run("assessment file", func(t *testing.T) {
+		content := "ignore:\n  - vulnerability: CVE-2023-1\n    status: notRelevant\n    analysis: mitigated\n    purls:\n      - purl: pkg:npm/lodash@4.17.20\n"
+
+		assessments, err := ReadAssessments(io.NopCloser(strings.NewReader(content)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &[]Assessment{{Vulnerability: "CVE-2023-1", Status: NotRelevant, Analysis: Mitigated, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}}}, assessments)
+	})
+
+	t.Run("VEX document", func(t *testing.T) {
+		content := `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": {"name": "CVE-2023-1"}, "products": [{"@id": "pkg:npm/lodash@4.17.20"}], "status": "not_affected", "justification": "inline_mitigations_already_exist"}]}`
+
+		assessments, err := ReadAssessments(io.NopCloser(strings.NewReader(content)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &[]Assessment{{Vulnerability: "CVE-2023-1", Status: NotRelevant, Analysis: Mitigated, Purls: []Purl{{Purl: "pkg:npm/lodash@4.17.20"}}}}, assessments)
+	})
+}
+
+func TestAssesses(t *testing.T) {
+	assessment := Assessment{Vulnerability: "CVE-2023-1", Status: NotRelevant, Purls: []Purl{{Purl: "pkg:maven/org.example/lib@1.0.0?type=jar"}}}
+
+	assert.True(t, assessment.Assesses(*packageurl.NewPackageURL("maven", "org.example", "lib", "1.0.0", nil, ""), "cve-2023-1"))
+	assert.True(t, assessment.Assesses(packageurl.PackageURL{Name: "lib", Version: "1.0.0"}, "BDSA-1", "CVE-2023-1"))
+	assert.False(t, assessment.Assesses(*packageurl.NewPackageURL("npm", "", "lib", "1.0.0", nil, ""), "CVE-2023-1"))
+	assert.False(t, assessment.Assesses(*packageurl.NewPackageURL("maven", "org.example", "lib", "1.0.1", nil, ""), "CVE-2023-1"))
+	assert.False(t, assessment.Assesses(*packageurl.NewPackageURL("maven", "org.example", "lib", "1.0.0", nil, ""), "CVE-2023-2"))
+
+	assert.Nil(t, FindAssessment([]Assessment{assessment}, packageurl.PackageURL{Name: "other", Version: "1.0.0"}, "CVE-2023-1"))
+	assert.Equal(t, &assessment, FindAssessment([]Assessment{assessment}, packageurl.PackageURL{Name: "lib", Version: "1.0.0"}, "CVE-2023-1"))
+
+	t.Run("invalid package URL", func(t *testing.T) {
+		defer log.SetErrorCategory(log.ErrorUndefined)
+		invalid := Assessment{Vulnerability: "CVE-2023-1", Purls: []Purl{{Purl: "lib@1.0.0"}}}
+		assert.False(t, invalid.Assesses(packageurl.PackageURL{Name: "lib", Version: "1.0.0"}, "CVE-2023-1"))
+		assert.Equal(t, log.ErrorConfiguration, log.GetErrorCategory())
+	})
+}
+
+func TestToVEXStatus(t *testing.T) {
+	assert.Equal(t, VEXNotAffected, Assessment{Status: NotRelevant, Analysis: NotUsed}.ToVEXStatus())
+	assert.Equal(t, VEXFixed, Assessment{Status: NotRelevant, Analysis: FixedByDevTeam}.ToVEXStatus())
+	assert.Equal(t, VEXUnderInvestigation, Assessment{Status: InProcess}.ToVEXStatus())
+	assert.Equal(t, VEXAffected, Assessment{Status: Relevant, Analysis: RiskAccepted}.ToVEXStatus())
+
+	assert.Equal(t, VEXVulnerableCodeNotInExecutePath, Assessment{Analysis: NotUsed}.ToVEXJustification())
+	assert.Equal(t, VEXJustification(""), Assessment{Analysis: RiskAccepted}.ToVEXJustification())
+}
@@ -10,7 +10,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -51,53 +50,37 @@ type SpotChecksAuditCount struct {
 	Type    string `json:"type"`
 }
 
-func CreateCustomReport(data FortifyReportData, issueGroups []*models.ProjectVersionIssueGroup) reporting.ScanReport {
-
-	scanReport := reporting.ScanReport{
-		ReportTitle: "Fortify SAST Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "Fortify project name", Details: data.ProjectName},
-			{Description: "Fortify project version", Details: data.ProjectVersion},
-			{Description: "Fortify URL", Details: data.URL},
-		},
-		Overview: []reporting.OverviewRow{
-			{Description: "Number of compliance violations", Details: fmt.Sprint(data.Violations)},
-			{Description: "Number of issues suppressed", Details: fmt.Sprint(data.Suppressed)},
-			{Description: "Unaudited corporate issues", Details: fmt.Sprint(data.CorporateTotal - data.CorporateAudited)},
-			{Description: "Unaudited audit all issues", Details: fmt.Sprint(data.AuditAllTotal - data.AuditAllAudited)},
-			{Description: "Unaudited spot check issues", Details: fmt.Sprint(data.SpotChecksTotal - data.SpotChecksAudited)},
-			{Description: "Number of suspicious issues", Details: fmt.Sprint(data.Suspicious)},
-			{Description: "Number of exploitable issues", Details: fmt.Sprint(data.Exploitable)},
-		},
-		ReportTime: time.Now(),
+// CreateCustomReport creates the scan report of the findings, the audit status of the project version is listed at the beginning of the overview
+func CreateCustomReport(data FortifyReportData, findings []reporting.Finding) reporting.ScanReport {
+	subheaders := []reporting.Subheader{
+		{Description: "Fortify project name", Details: data.ProjectName},
+		{Description: "Fortify project version", Details: data.ProjectVersion},
+		{Description: "Fortify URL", Details: data.URL},
 	}
-
-	detailTable := reporting.ScanDetailTable{
-		NoRowsMessage: "No findings detected",
-		Headers: []string{
-			"Issue group",
-			"Total count",
-			"Audited count",
-		},
-		WithCounter:   true,
-		CounterHeader: "Entry #",
-	}
-
-	for _, group := range issueGroups {
-		row := reporting.ScanRow{}
-		row.AddColumn(fmt.Sprint(*group.CleanName), 0)
-		row.AddColumn(fmt.Sprint(*group.TotalCount), 0)
-		row.AddColumn(fmt.Sprint(*group.AuditedCount), 0)
-
-		detailTable.Rows = append(detailTable.Rows, row)
-	}
-
-	scanReport.DetailTable = detailTable
+	scanReport := reporting.CreateFindingsScanReport("Fortify SAST Report", subheaders, findings, reporting.SeverityHigh)
+	scanReport.Overview = append([]reporting.OverviewRow{
+		{Description: "Number of compliance violations", Details: fmt.Sprint(data.Violations)},
+		{Description: "Number of issues suppressed", Details: fmt.Sprint(data.Suppressed)},
+		{Description: "Unaudited corporate issues", Details: fmt.Sprint(data.CorporateTotal - data.CorporateAudited)},
+		{Description: "Unaudited audit all issues", Details: fmt.Sprint(data.AuditAllTotal - data.AuditAllAudited)},
+		{Description: "Unaudited spot check issues", Details: fmt.Sprint(data.SpotChecksTotal - data.SpotChecksAudited)},
+		{Description: "Number of suspicious issues", Details: fmt.Sprint(data.Suspicious)},
+		{Description: "Number of exploitable issues", Details: fmt.Sprint(data.Exploitable)},
+	}, scanReport.Overview...)
+	// compliance is defined by the audit requirements of the step
 	scanReport.SuccessfulScan = data.Violations == 0
-
 	return scanReport
 }
 
+// CreateFindingsSarif creates the SARIF report of the findings in the scanner independent format
+func CreateFindingsSarif(findings []reporting.Finding) format.SARIF {
+	tool := format.Driver{
+		Name:           "MicroFocus Fortify SCA",
+		InformationUri: "https://www.microfocus.com/documentation/fortify-static-code-analyzer-and-tools/2020/SCA_Guide_20.2.0.pdf",
+	}
+	return reporting.CreateFindingsSarif(tool, findings)
+}
+
 func CreateJSONReport(reportData FortifyReportData, spotChecksCountByCategory []SpotChecksAuditCount, serverURL string) FortifyReportData {
 	reportData.AtleastOneSpotChecksCategoryAudited = true
 	reportData.IsSpotChecksPerCategoryAudited = true
@@ -238,6 +221,53 @@ func WriteCustomReports(scanReport reporting.ScanReport) ([]piperutils.Path, err
 	return reportPaths, nil
 }
 
+// Findings converts the issues of the project version into scanner independent findings
+func Findings(issues []*models.ProjectVersionIssue) []reporting.Finding {
+	findings := []reporting.Finding{}
+	for _, issue := range issues {
+		if issue == nil || (issue.Removed != nil && *issue.Removed) {
+			continue
+		}
+		finding := reporting.Finding{
+			Scanner: "fortify",
+			Type:    reporting.FindingTypeCode,
+			Status:  issueStatus(issue),
+		}
+		if issue.IssueName != nil {
+			finding.ID = *issue.IssueName
+			finding.Name = *issue.IssueName
+		}
+		if issue.Friority != nil {
+			finding.Severity = reporting.ParseSeverity(*issue.Friority)
+		}
+		if issue.FullFileName != nil {
+			finding.Location = &reporting.Location{File: *issue.FullFileName}
+			if issue.LineNumber != nil {
+				finding.Location.Line = int(*issue.LineNumber)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// issueStatus maps the suppression and the analysis tag of the issue, e.g. Not an Issue
+func issueStatus(issue *models.ProjectVersionIssue) reporting.FindingStatus {
+	if issue.Suppressed != nil && *issue.Suppressed {
+		return reporting.FindingStatusNotAffected
+	}
+	if !issue.Audited || issue.PrimaryTag == nil {
+		return reporting.FindingStatusOpen
+	}
+	switch *issue.PrimaryTag {
+	case "Not an Issue":
+		return reporting.FindingStatusNotAffected
+	case "Exploitable", "Suspicious":
+		return reporting.FindingStatusConfirmed
+	}
+	return reporting.FindingStatusOpen
+}
+
 func reportShaFortify(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
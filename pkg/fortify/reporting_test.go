@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package fortify
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindings(t *testing.T) {
+	name := "SQL Injection"
+	file := "src/main/java/Db.java"
+	line := int32(12)
+	critical, low := "Critical", "Low"
+	exploitable, notAnIssue, badPractice := "Exploitable", "Not an Issue", "Bad Practice"
+	yes := true
+	issues := []*models.ProjectVersionIssue{
+		{IssueName: &name, FullFileName: &file, LineNumber: &line, Friority: &critical, Audited: true, PrimaryTag: &exploitable},
+		{IssueName: &name, Friority: &low, Audited: true, PrimaryTag: &notAnIssue},
+		{IssueName: &name, Friority: &low, Audited: true, PrimaryTag: &badPractice},
+		{IssueName: &name, Friority: &low, Suppressed: &yes},
+		{IssueName: &name, Friority: &low},
+		{IssueName: &name, Friority: &critical, Removed: &yes},
+	}
+
+	findings := Findings(issues)
+	if assert.Len(t, findings, 5) {
+		assert.Equal(t, "SQL Injection", findings[0].ID)
+		assert.Equal(t, "src/main/java/Db.java", findings[0].Location.File)
+		assert.Equal(t, 12, findings[0].Location.Line)
+		assert.Equal(t, "critical", findings[0].Severity.String())
+		assert.Equal(t, "confirmed", string(findings[0].Status))
+		assert.Nil(t, findings[1].Location)
+		assert.Equal(t, "notAffected", string(findings[1].Status))
+		assert.Equal(t, "open", string(findings[2].Status))
+		assert.Equal(t, "notAffected", string(findings[3].Status))
+		assert.Equal(t, "open", string(findings[4].Status))
+	}
+}
+
+func TestCreateCustomReport(t *testing.T) {
+	findings := []reporting.Finding{
+		{Scanner: "fortify", Type: reporting.FindingTypeCode, ID: "SQL Injection", Location: &reporting.Location{File: "src/main/java/Db.java", Line: 12}, Severity: reporting.SeverityCritical, Status: reporting.FindingStatusConfirmed},
+		{Scanner: "fortify", Type: reporting.FindingTypeCode, ID: "Dead Code", Severity: reporting.SeverityLow, Status: reporting.FindingStatusNotAffected},
+	}
+
+	scanReport := CreateCustomReport(FortifyReportData{ProjectName: "product", ProjectVersion: "1", Violations: 2, Exploitable: 1}, findings)
+
+	assert.Equal(t, "Fortify SAST Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Len(t, scanReport.Overview, 10)
+	assert.Equal(t, "Number of compliance violations", scanReport.Overview[0].Description)
+	assert.Equal(t, "2", scanReport.Overview[0].Details)
+	if assert.Len(t, scanReport.DetailTable.Rows, 2) {
+		assert.Equal(t, "src/main/java/Db.java:12", scanReport.DetailTable.Rows[0].Columns[2].Content)
+	}
+
+	scanReport = CreateCustomReport(FortifyReportData{}, findings)
+	assert.True(t, scanReport.SuccessfulScan)
+
+	sarif := CreateFindingsSarif(findings)
+	if assert.Len(t, sarif.Runs, 1) {
+		assert.Equal(t, "MicroFocus Fortify SCA", sarif.Runs[0].Tool.Driver.Name)
+		assert.Len(t, sarif.Runs[0].Results, 2)
+	}
+}
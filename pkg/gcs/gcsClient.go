@@ -3,12 +3,14 @@ package gcs
 import (
 	"context"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"cloud.google.com/go/storage"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
@@ -21,6 +23,13 @@ type Client interface {
 	Close() error
 }
 
+// ObjectClient is an interface to read and write single objects with optimistic locking based on the object generation
+type ObjectClient interface {
+	ReadObject(bucketID string, objectPath string) ([]byte, int64, error)
+	WriteObject(bucketID string, objectPath string, content []byte, generation int64) (int64, error)
+	Close() error
+}
+
 // gcsClient provides functions to interact with google cloud storage API
 type gcsClient struct {
 	context       context.Context
@@ -218,3 +227,46 @@ func createFileOnFS(name string) (io.WriteCloser, error) {
 	}
 	return os.Create(name)
 }
+
+// ErrPreconditionFailed is returned by WriteObject if the generation of the object does not match
+var ErrPreconditionFailed = errors.New("generation of the object does not match")
+
+// ReadObject reads the content of an object together with its generation.
+// The generation is 0 if the object does not exist.
+func (g *gcsClient) ReadObject(bucketID string, objectPath string) ([]byte, int64, error) {
+	reader, err := g.client.Bucket(bucketID).Object(objectPath).NewReader(g.context)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "could not open object %v", objectPath)
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "could not read object %v", objectPath)
+	}
+	return content, reader.Attrs.Generation, nil
+}
+
+// WriteObject writes the content of an object if its generation still matches and returns the new generation.
+// A generation of 0 requires that the object does not exist yet.
+func (g *gcsClient) WriteObject(bucketID string, objectPath string, content []byte, generation int64) (int64, error) {
+	conditions := storage.Conditions{GenerationMatch: generation}
+	if generation == 0 {
+		conditions = storage.Conditions{DoesNotExist: true}
+	}
+	writer := g.client.Bucket(bucketID).Object(objectPath).If(conditions).NewWriter(g.context)
+	if _, err := writer.Write(content); err != nil {
+		writer.Close()
+		return 0, errors.Wrapf(err, "could not write object %v", objectPath)
+	}
+	if err := writer.Close(); err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return 0, ErrPreconditionFailed
+		}
+		return 0, errors.Wrapf(err, "could not write object %v", objectPath)
+	}
+	return writer.Attrs().Generation, nil
+}
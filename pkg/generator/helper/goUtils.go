@@ -14,6 +14,8 @@ type StepHelperData struct {
 	OpenFile     func(s string) (io.ReadCloser, error)
 	WriteFile    func(filename string, data []byte, perm os.FileMode) error
 	ExportPrefix string
+	// SchemaFile is the path of the JSON schema for the project configuration, no schema is written if empty
+	SchemaFile string
 }
 
 // ContextDefaultData holds the meta data and the default data for the context default parameter descriptions
@@ -30,6 +30,7 @@ type stepInfo struct {
 	StepFunc         string
 	StepName         string
 	StepSecrets      []string
+	DryRunSimulation bool
 	Containers       []config.Container
 	Sidecars         []config.Container
 	Outputs          config.StepOutputs
@@ -78,7 +79,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	{{ end -}}
 	"github.com/SAP/jenkins-library/pkg/telemetry"
-	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
@@ -112,7 +113,6 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 	{{- range $notused, $oRes := .OutputResources }}
 	var {{ index $oRes "name" }} {{ index $oRes "objectname" }}{{ end }}
 	var logCollector *log.CollectorHook
-	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
 
 	var {{.CreateCmdVar}} = &cobra.Command{
@@ -144,8 +144,7 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 				log.RegisterHook(&sentryHook)
 			}
 
-			if len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-				splunkClient = &splunk.Splunk{}
+			if len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 || len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.TelemetryConfig.Sinks) > 0 {
 				logCollector = &log.CollectorHook{CorrelationID: {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.CorrelationID}
 				log.RegisterHook(logCollector)
 			}
@@ -165,9 +164,19 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 
 			return nil
 		},
-		Run: func(_ *cobra.Command, _ []string) {
+		RunE: func(_ *cobra.Command, _ []string) error {
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
+			if {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.DryRun {
+				{{- if .DryRunSimulation}}
+				{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}RunStepDryRun(STEP_NAME, &metadata, stepConfig, func() {
+					{{.StepName}}(stepConfig, &stepTelemetryData{{ range $notused, $oRes := .OutputResources}}{{ if ne (index $oRes "type") "reports" }}, &{{ index $oRes "name" }}{{ end }}{{ end }})
+				})
+				{{- else}}
+				{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}RunStepDryRun(STEP_NAME, &metadata, stepConfig, nil)
+				{{- end}}
+				return nil
+			}
 			handler := func() {
 				{{- range $notused, $oRes := .OutputResources }}
 				{{ index $oRes "name" }}.persist(
@@ -179,35 +188,32 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 				{{- else -}}
 					{{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, {{ index $oRes "name" | quote }}{{- end -}}
 				){{- end }}
+				{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}PushCommonPipelineEnvironment()
+				{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}PublishCommandTranscript(STEP_NAME)
 				config.RemoveVaultSecretFiles()
+				config.RevokeVaultLeases()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
 				stepTelemetryData.PiperCommitHash = {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GitCommit
+				tracing.EndStep(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 				telemetryClient.SetData(&stepTelemetryData)
 				telemetryClient.Send()
-				if len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
-					splunkClient.Initialize({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.CorrelationID,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Dsn,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Token,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.Index,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
-				if len({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
-					splunkClient.Initialize({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.CorrelationID,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
-					{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.SplunkConfig.SendLogs)
-					splunkClient.Send(telemetryClient.GetData(), logCollector)
-				}
 			}
-			log.DeferExitHandler(handler)
+			removeExitHandler := log.DeferExitHandler(handler)
+			defer removeExitHandler()
 			defer handler()
+			{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}StartStepTracing(STEP_NAME, startTime)
 			telemetryClient.Initialize({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.NoTelemetry, STEP_NAME, {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.HookConfig.PendoConfig.Token)
-			{{.StepName}}(stepConfig, &stepTelemetryData{{ range $notused, $oRes := .OutputResources}}{{ if ne (index $oRes "type") "reports" }}, &{{ index $oRes "name" }}{{ end }}{{ end }})
+			{{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}AddTelemetrySinks(telemetryClient, logCollector)
+			err := log.RunRecoverable(func() {
+				{{.StepName}}(stepConfig, &stepTelemetryData{{ range $notused, $oRes := .OutputResources}}{{ if ne (index $oRes "type") "reports" }}, &{{ index $oRes "name" }}{{ end }}{{ end }})
+			})
+			if err != nil {
+				return err
+			}
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
+			return nil
 		},
 	}
 
@@ -288,6 +294,9 @@ func {{ .StepName }}Metadata() config.StepData {
 						Aliases:   []config.Alias{{ "{" }}{{ range $notused, $alias := $value.Aliases }}{{ "{" }}Name: {{ $alias.Name | quote }}{{ if $alias.Deprecated }}, Deprecated: {{$alias.Deprecated}}{{ end }}{{ "}" }},{{ end }}{{ "}" }},
 						{{ if $value.Default -}} Default:   {{ $value.Default }}, {{- end}}{{ if $value.Conditions }}
 						Conditions: []config.Condition{ {{- range $i, $cond := $value.Conditions }} {ConditionRef: {{ $cond.ConditionRef | quote }}, Params: []config.Param{ {{- range $j, $p := $cond.Params}} { Name: {{ $p.Name | quote }}, Value: {{ $p.Value | quote }} }, {{end -}} } }, {{ end -}} },{{- end }}
+						{{- if $value.Secret }}
+						Secret: true,
+						{{- end}}
 						{{- if $value.DeprecationMessage }}
 						DeprecationMessage: {{ $value.DeprecationMessage | quote }},
 						{{- end}}
@@ -335,6 +344,7 @@ func {{ .StepName }}Metadata() config.StepData {
 							{{ if $p.tags}}"tags": []map[string]string{ {{- range $j, $t := $p.tags}} {"name": {{ $t.name | quote }}}, {{end -}} },{{ end -}}
 							{{ if $p.filePattern}}"filePattern": {{ $p.filePattern | quote }},{{ end -}}
 							{{ if $p.type}}"type": {{ $p.type | quote }},{{ end -}}
+							{{ if $p.sensitive}}"sensitive": true,{{ end -}}
 							{{ if $p.subFolder}}"subFolder": {{ $p.subFolder | quote }},{{ end -}}
 							{{ if $p }}}, {{- end -}}
 						{{ end }}
@@ -511,7 +521,10 @@ const metadataGeneratedTemplate = `// Code generated by piper's step-generator.
 
 package cmd
 
-import "github.com/SAP/jenkins-library/pkg/config"
+import (
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/spf13/cobra"
+)
 
 // GetStepMetadata return a map with all the step metadata mapped to their stepName
 func GetAllStepMetadata() map[string]config.StepData {
@@ -520,12 +533,21 @@ func GetAllStepMetadata() map[string]config.StepData {
 		{{end}}
 	}
 }
+
+// GetAllStepCommands return a map with the command constructors of all steps mapped to their stepName
+func GetAllStepCommands() map[string]func() *cobra.Command {
+	return map[string]func() *cobra.Command{
+		{{range $stepName := .Steps }} {{ $stepName | quote }}: {{$stepName | title}}Command,
+		{{end}}
+	}
+}
 `
 
 // ProcessMetaFiles generates step coding based on step configuration provided in yaml files
 func ProcessMetaFiles(metadataFiles []string, targetDir string, stepHelperData StepHelperData) error {
 
 	allSteps := struct{ Steps []string }{}
+	schema := newConfigSchema()
 	for key := range metadataFiles {
 
 		var stepData config.StepData
@@ -557,6 +579,8 @@ func ProcessMetaFiles(metadataFiles []string, targetDir string, stepHelperData S
 			}
 		}
 
+		schema.addStep(&stepData)
+
 		osImport := false
 		osImport, err = setDefaultParameters(&stepData)
 		checkError(err)
@@ -592,6 +616,13 @@ func ProcessMetaFiles(metadataFiles []string, targetDir string, stepHelperData S
 	err := stepHelperData.WriteFile(filepath.Join(targetDir, metadataGeneratedFileName), code, 0644)
 	checkError(err)
 
+	if len(stepHelperData.SchemaFile) > 0 {
+		content, err := schema.toJSON()
+		checkError(err)
+		err = stepHelperData.WriteFile(stepHelperData.SchemaFile, append(content, '\n'), 0644)
+		checkError(err)
+	}
+
 	return nil
 }
 
@@ -664,6 +695,7 @@ func getStepInfo(stepData *config.StepData, osImport bool, exportPrefix string)
 			OutputResources:  oRes,
 			ExportPrefix:     exportPrefix,
 			StepSecrets:      getSecretFields(stepData),
+			DryRunSimulation: stepData.Metadata.DryRunSimulation,
 			Containers:       stepData.Spec.Containers,
 			Sidecars:         stepData.Spec.Sidecars,
 			Outputs:          stepData.Spec.Outputs,
@@ -24,6 +24,7 @@ func configOpenFileMock(name string) (io.ReadCloser, error) {
   description: Test description
   longDescription: |
     Long Test description
+  dryRunSimulation: true
 spec:
   outputs:
     resources:
@@ -124,7 +125,7 @@ func writeFileMock(filename string, data []byte, perm os.FileMode) error {
 
 func TestProcessMetaFiles(t *testing.T) {
 
-	stepHelperData := StepHelperData{configOpenFileMock, writeFileMock, ""}
+	stepHelperData := StepHelperData{configOpenFileMock, writeFileMock, "", "schema.json"}
 	ProcessMetaFiles([]string{"testStep.yaml"}, "./cmd", stepHelperData)
 
 	t.Run("step code", func(t *testing.T) {
@@ -148,8 +149,13 @@ func TestProcessMetaFiles(t *testing.T) {
 		assert.Equal(t, string(expected), string(files[resultFilePath]))
 	})
 
+	t.Run("config schema", func(t *testing.T) {
+		assert.Contains(t, string(files["schema.json"]), `"testStep": {`)
+		assert.Contains(t, string(files["schema.json"]), `"description": "param0 description"`)
+	})
+
 	t.Run("custom step code", func(t *testing.T) {
-		stepHelperData = StepHelperData{configOpenFileMock, writeFileMock, "piperOsCmd"}
+		stepHelperData = StepHelperData{configOpenFileMock, writeFileMock, "piperOsCmd", ""}
 		ProcessMetaFiles([]string{"testStep.yaml"}, "./cmd", stepHelperData)
 
 		goldenFilePath := filepath.Join("testdata", t.Name()+"_generated.golden")
@@ -0,0 +1,226 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+)
+
+// schemaProperty is a subset of JSON schema (draft-07) sufficient to describe the configuration of the steps
+type schemaProperty struct {
+	Schema               string                     `json:"$schema,omitempty"`
+	Title                string                     `json:"title,omitempty"`
+	Description          string                     `json:"description,omitempty"`
+	Type                 interface{}                `json:"type,omitempty"`
+	Enum                 []interface{}              `json:"enum,omitempty"`
+	Default              interface{}                `json:"default,omitempty"`
+	Deprecated           bool                       `json:"deprecated,omitempty"`
+	Items                *schemaProperty            `json:"items,omitempty"`
+	Properties           map[string]*schemaProperty `json:"properties,omitempty"`
+	AdditionalProperties *schemaProperty            `json:"additionalProperties,omitempty"`
+}
+
+// configSchema collects the parameters of all steps in order to create a JSON schema for the project configuration
+type configSchema struct {
+	general map[string]*schemaProperty
+	stages  map[string]*schemaProperty
+	steps   map[string]*schemaProperty
+}
+
+func newConfigSchema() *configSchema {
+	return &configSchema{
+		general: map[string]*schemaProperty{},
+		stages:  map[string]*schemaProperty{},
+		steps:   map[string]*schemaProperty{},
+	}
+}
+
+// addStep adds the parameters of a step to the schema.
+// It needs to be called before the defaults of the step data are converted to Go code.
+func (s *configSchema) addStep(stepData *config.StepData) {
+	stepProperties := map[string]*schemaProperty{}
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		for name, property := range parameterProperties(param) {
+			for _, scope := range param.Scope {
+				switch scope {
+				case "GENERAL":
+					mergeProperty(s.general, name, property)
+				case "STAGES":
+					mergeProperty(s.stages, name, property)
+				case "STEPS":
+					stepProperties[name] = property
+				}
+			}
+		}
+	}
+	// steps can be activated or deactivated explicitly within a stage
+	mergeProperty(s.stages, stepData.Metadata.Name, &schemaProperty{
+		Type:        "boolean",
+		Description: fmt.Sprintf("Activates or deactivates the step %v in the stage.", stepData.Metadata.Name),
+	})
+	s.steps[stepData.Metadata.Name] = &schemaProperty{
+		Type:        "object",
+		Description: stepData.Metadata.Description,
+		Properties:  stepProperties,
+	}
+}
+
+// toJSON returns the JSON schema of the project configuration
+func (s *configSchema) toJSON() ([]byte, error) {
+	schema := schemaProperty{
+		Schema: "http://json-schema.org/draft-07/schema#",
+		Title:  "Project Piper Configuration",
+		Type:   "object",
+		Properties: map[string]*schemaProperty{
+			"customDefaults": {
+				Type:        "array",
+				Description: "List of additional defaults files, either as path or as URL.",
+				Items:       &schemaProperty{Type: "string"},
+			},
+			"general": {
+				Type:        "object",
+				Description: "Configuration valid for all steps and stages.",
+				Properties:  s.general,
+			},
+			"stages": {
+				Type:        "object",
+				Description: "Configuration per stage, valid for all steps executed within the stage.",
+				AdditionalProperties: &schemaProperty{
+					Type:       "object",
+					Properties: s.stages,
+				},
+			},
+			"steps": {
+				Type:        "object",
+				Description: "Configuration per step.",
+				Properties:  s.steps,
+			},
+			"hooks": {
+				Type:        "object",
+				Description: "Configuration of hooks, e.g. for reporting.",
+			},
+		},
+	}
+	return json.MarshalIndent(schema, "", "    ")
+}
+
+// parameterProperties returns the schema properties of a parameter and its aliases
+func parameterProperties(param config.StepParameters) map[string]*schemaProperty {
+	property := &schemaProperty{
+		Description: param.Description,
+		Type:        schemaType(param.Type),
+		Enum:        param.PossibleValues,
+		Default:     param.Default,
+		Deprecated:  len(param.DeprecationMessage) > 0,
+	}
+	if strings.HasPrefix(param.Type, "[]") {
+		property.Items = &schemaProperty{Type: schemaType(strings.TrimPrefix(param.Type, "[]"))}
+		property.Enum = nil
+		if len(param.PossibleValues) > 0 {
+			property.Items.Enum = param.PossibleValues
+		}
+	}
+	if reflect.ValueOf(property.Default).Kind() == reflect.Slice && reflect.ValueOf(property.Default).Len() == 0 {
+		property.Default = nil
+	}
+
+	properties := map[string]*schemaProperty{param.Name: property}
+	for _, alias := range param.Aliases {
+		if strings.Contains(alias.Name, "/") {
+			// deep aliases point into a nested configuration map
+			continue
+		}
+		aliasProperty := *property
+		aliasProperty.Description = fmt.Sprintf("Alias of %v. %v", param.Name, param.Description)
+		aliasProperty.Deprecated = alias.Deprecated
+		properties[alias.Name] = &aliasProperty
+	}
+	return properties
+}
+
+// mergeProperty adds the property or merges it with an existing one of another step.
+// Types and possible values are combined, description and default are kept from the first step.
+func mergeProperty(properties map[string]*schemaProperty, name string, property *schemaProperty) {
+	existing, ok := properties[name]
+	if !ok {
+		merged := *property
+		merged.Enum = append([]interface{}{}, property.Enum...)
+		properties[name] = &merged
+		return
+	}
+	if !reflect.DeepEqual(existing.Type, property.Type) {
+		existing.Type = mergeTypes(existing.Type, property.Type)
+		existing.Items = nil
+	}
+	if len(existing.Enum) == 0 || len(property.Enum) == 0 {
+		existing.Enum = nil
+	} else {
+		for _, value := range property.Enum {
+			if !containsValue(existing.Enum, value) {
+				existing.Enum = append(existing.Enum, value)
+			}
+		}
+	}
+	existing.Deprecated = existing.Deprecated && property.Deprecated
+}
+
+func mergeTypes(types ...interface{}) interface{} {
+	result := []string{}
+	for _, t := range types {
+		switch typed := t.(type) {
+		case nil:
+			// unknown type, any value is allowed
+			return nil
+		case string:
+			result = appendUnique(result, typed)
+		case []string:
+			for _, item := range typed {
+				result = appendUnique(result, item)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// schemaType maps the Go type of a parameter to the JSON schema type, nil if there is no matching type
+func schemaType(paramType string) interface{} {
+	switch paramType {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int64":
+		return "integer"
+	case "float64":
+		return "number"
+	case "map[string]interface{}":
+		return "object"
+	}
+	if strings.HasPrefix(paramType, "[]") {
+		return "array"
+	}
+	return nil
+}
+
+func appendUnique(slice []string, value string) []string {
+	for _, item := range slice {
+		if item == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
+
+func containsValue(slice []interface{}, value interface{}) bool {
+	for _, item := range slice {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
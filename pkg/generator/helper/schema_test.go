@@ -0,0 +1,73 @@
+//go:build unit
+// +build unit
+
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+)
+
+func TestConfigSchema(t *testing.T) {
+	schema := newConfigSchema()
+	schema.addStep(&config.StepData{
+		Metadata: config.StepMetadata{Name: "step1", Description: "step1 description"},
+		Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+			{Name: "p1", Type: "string", Description: "p1 description", Scope: []string{"GENERAL", "STEPS"}, PossibleValues: []interface{}{"a", "b"}, Default: "a"},
+			{Name: "p2", Type: "[]string", Description: "p2 description", Scope: []string{"STEPS", "STAGES"}, Default: []interface{}{}},
+			{Name: "p3", Type: "bool", Description: "p3 description", Scope: []string{"PARAMETERS", "STEPS"}, Default: true, Aliases: []config.Alias{{Name: "oldP3", Deprecated: true}, {Name: "deep/p3"}}},
+		}}},
+	})
+	schema.addStep(&config.StepData{
+		Metadata: config.StepMetadata{Name: "step2"},
+		Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+			{Name: "p1", Type: "bool", Description: "p1 description of step2", Scope: []string{"GENERAL"}},
+		}}},
+	})
+
+	content, err := schema.toJSON()
+	assert.NoError(t, err)
+
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &result))
+	properties := result["properties"].(map[string]interface{})
+
+	general := properties["general"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"description": "p1 description",
+		"type":        []interface{}{"boolean", "string"},
+		"default":     "a",
+	}, general["p1"])
+
+	steps := properties["steps"].(map[string]interface{})["properties"].(map[string]interface{})
+	step1 := steps["step1"].(map[string]interface{})
+	assert.Equal(t, "step1 description", step1["description"])
+	step1Properties := step1["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"description": "p1 description",
+		"type":        "string",
+		"enum":        []interface{}{"a", "b"},
+		"default":     "a",
+	}, step1Properties["p1"])
+	assert.Equal(t, map[string]interface{}{
+		"description": "p2 description",
+		"type":        "array",
+		"items":       map[string]interface{}{"type": "string"},
+	}, step1Properties["p2"])
+	assert.Equal(t, map[string]interface{}{
+		"description": "Alias of p3. p3 description",
+		"type":        "boolean",
+		"default":     true,
+		"deprecated":  true,
+	}, step1Properties["oldP3"])
+	assert.NotContains(t, step1Properties, "deep/p3")
+
+	stages := properties["stages"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, stages, "p2")
+	assert.NotContains(t, stages, "p1")
+	assert.Equal(t, "boolean", stages["step2"].(map[string]interface{})["type"])
+}
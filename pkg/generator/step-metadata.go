@@ -14,9 +14,11 @@ import (
 func main() {
 	var metadataPath string
 	var targetDir string
+	var schemaFile string
 
 	flag.StringVar(&metadataPath, "metadataDir", "./resources/metadata", "The directory containing the step metadata. Default points to \\'resources/metadata\\'.")
 	flag.StringVar(&targetDir, "targetDir", "./cmd", "The target directory for the generated commands.")
+	flag.StringVar(&schemaFile, "schemaFile", "./resources/schemas/config.json", "The target file for the JSON schema of the project configuration. No schema is generated if empty.")
 	flag.Parse()
 
 	fmt.Printf("metadataDir: %v\n, targetDir: %v\n", metadataPath, targetDir)
@@ -27,6 +29,7 @@ func main() {
 		OpenFile:     openMetaFile,
 		WriteFile:    fileWriter,
 		ExportPrefix: "",
+		SchemaFile:   schemaFile,
 	})
 	checkError(err)
 
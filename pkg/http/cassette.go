@@ -0,0 +1,302 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// errNotRecorded is returned in replay mode for requests without a recorded response, these requests are not retried
+var errNotRecorded = errors.New("no response recorded")
+
+var sensitiveHeaderName = regexp.MustCompile(`(?i)(authorization|cookie|token|secret|password|api-?key|session)`)
+
+// tokens issued in responses, e.g. by OAuth token endpoints, are not yet registered as secrets when the interaction is recorded
+var (
+	sensitiveJSONField = regexp.MustCompile(`(?i)("(?:access_token|id_token|refresh_token|token|authorization)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	sensitiveFormField = regexp.MustCompile(`(?i)((?:^|&)(?:access_token|id_token|refresh_token|token|authorization)=)[^&]*`)
+)
+
+// Cassette contains the recorded requests of a step run and their responses
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request and its response, secrets are replaced by ****
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest describes a recorded request
+type CassetteRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// CassetteResponse describes a recorded response, Error is set if no response was received
+type CassetteResponse struct {
+	StatusCode   int         `json:"statusCode,omitempty"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+const (
+	cassetteRecord = "record"
+	cassetteReplay = "replay"
+)
+
+var cassette = struct {
+	sync.Mutex
+	mode     string
+	file     string
+	data     Cassette
+	replayed []bool
+}{}
+
+// RecordCassette records all subsequent requests and their responses in the cassette file.
+// The file is rewritten after every request, thus it is complete even if the step terminates abnormally.
+func RecordCassette(file string) error {
+	cassette.Lock()
+	defer cassette.Unlock()
+	cassette.mode = cassetteRecord
+	cassette.file = file
+	cassette.data = Cassette{Interactions: []Interaction{}}
+	cassette.replayed = nil
+	return writeCassette()
+}
+
+// ReplayCassette answers all subsequent requests with the responses recorded in the cassette file instead of sending them.
+// Requests are matched by method and URL in the recorded order, the last matching response is repeated once all matching responses were used.
+func ReplayCassette(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var data Cassette
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to parse cassette %v: %w", file, err)
+	}
+	cassette.Lock()
+	defer cassette.Unlock()
+	cassette.mode = cassetteReplay
+	cassette.file = file
+	cassette.data = data
+	cassette.replayed = make([]bool, len(data.Interactions))
+	return nil
+}
+
+// StopCassette stops recording or replaying requests
+func StopCassette() {
+	cassette.Lock()
+	defer cassette.Unlock()
+	cassette.mode = ""
+	cassette.file = ""
+	cassette.data = Cassette{}
+	cassette.replayed = nil
+}
+
+func isCassetteActive() bool {
+	cassette.Lock()
+	defer cassette.Unlock()
+	return len(cassette.mode) > 0
+}
+
+func isCassetteRecording() bool {
+	cassette.Lock()
+	defer cassette.Unlock()
+	return cassette.mode == cassetteRecord
+}
+
+// writeCassette writes the cassette file, which is only readable by the owner since masking cannot catch every credential.
+// All interactions are masked again, since secrets may have been registered after an interaction has been recorded.
+func writeCassette() error {
+	for i := range cassette.data.Interactions {
+		maskInteraction(&cassette.data.Interactions[i])
+	}
+	content, err := json.MarshalIndent(cassette.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cassette.file, content, 0600); err != nil {
+		return err
+	}
+	// the permissions of an existing file are not changed by WriteFile
+	return os.Chmod(cassette.file, 0600)
+}
+
+// maskInteraction replaces all known secrets in the interaction by ****
+func maskInteraction(interaction *Interaction) {
+	interaction.Request.URL = log.MaskSecrets(interaction.Request.URL)
+	maskHeader(interaction.Request.Header)
+	if len(interaction.Request.BodyEncoding) == 0 {
+		interaction.Request.Body = log.MaskSecrets(interaction.Request.Body)
+	}
+	maskHeader(interaction.Response.Header)
+	if len(interaction.Response.BodyEncoding) == 0 {
+		interaction.Response.Body = log.MaskSecrets(interaction.Response.Body)
+	}
+	interaction.Response.Error = log.MaskSecrets(interaction.Response.Error)
+}
+
+func maskHeader(header http.Header) {
+	for name, values := range header {
+		for i, value := range values {
+			values[i] = log.MaskSecrets(value)
+		}
+		header[name] = values
+	}
+}
+
+// replayCassette returns the recorded response of the request in case replay mode is enabled
+func replayCassette(req *http.Request) (*http.Response, bool, error) {
+	cassette.Lock()
+	defer cassette.Unlock()
+	if cassette.mode != cassetteReplay {
+		return nil, false, nil
+	}
+	url := log.MaskSecrets(req.URL.String())
+	match := -1
+	for i, interaction := range cassette.data.Interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.URL != url {
+			continue
+		}
+		match = i
+		if !cassette.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, true, fmt.Errorf("%w in cassette %v for %v %v", errNotRecorded, cassette.file, req.Method, url)
+	}
+	cassette.replayed[match] = true
+	recorded := cassette.data.Interactions[match].Response
+	log.Entry().Debugf("replaying response %v for %v %v", recorded.StatusCode, req.Method, url)
+	if len(recorded.Error) > 0 {
+		return nil, true, fmt.Errorf("%v", recorded.Error)
+	}
+	body, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid body recorded for %v %v: %w", req.Method, url, err)
+	}
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, true, nil
+}
+
+// readRequestBody returns the body of the request and replaces it, so that it can still be sent
+func readRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		log.Entry().WithError(err).Debug("failed to read request body for the cassette")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body
+}
+
+// recordCassette adds the request and its response to the cassette, the body of the response is replaced so that it can still be read
+func recordCassette(req *http.Request, requestBody []byte, resp *http.Response, err error) {
+	interaction := Interaction{Request: CassetteRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: scrubHeader(req.Header),
+	}}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(requestBody)
+	if err != nil {
+		interaction.Response.Error = err.Error()
+	} else {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			log.Entry().WithError(readErr).Debug("failed to read response body for the cassette")
+		}
+		interaction.Response.StatusCode = resp.StatusCode
+		interaction.Response.Header = scrubHeader(resp.Header)
+		interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(body)
+	}
+
+	cassette.Lock()
+	defer cassette.Unlock()
+	if cassette.mode != cassetteRecord {
+		return
+	}
+	cassette.data.Interactions = append(cassette.data.Interactions, interaction)
+	if err := writeCassette(); err != nil {
+		log.Entry().WithError(err).Warn("failed to write cassette")
+	}
+}
+
+// scrubHeader masks the values of headers which contain credentials, known secrets are masked when the cassette is written
+func scrubHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbed := http.Header{}
+	for name, values := range header {
+		for _, value := range values {
+			if sensitiveHeaderName.MatchString(name) {
+				value = "****"
+			}
+			scrubbed.Add(name, value)
+		}
+	}
+	// the length may differ from the masked body
+	scrubbed.Del("Content-Length")
+	return scrubbed
+}
+
+// encodeBody masks token fields in textual bodies and encodes binary bodies with base64
+func encodeBody(body []byte) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(body) {
+		text := sensitiveJSONField.ReplaceAllString(string(body), `$1"****"`)
+		return sensitiveFormField.ReplaceAllString(text, "${1}****"), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown encoding %v", strconv.Quote(encoding))
+	}
+}
@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette(t *testing.T) {
+	defer StopCassette()
+	log.RegisterSecret("cassette-secret")
+	file := filepath.Join(t.TempDir(), "cassette.json")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		switch req.URL.Path {
+		case "/scan":
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, `{"password":"cassette-secret"}`, string(body))
+			rw.Header().Set("Set-Cookie", "session=abc")
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte(`{"id":1}`))
+		case "/status":
+			rw.Write([]byte{byte(calls), 0xff})
+		case "/token":
+			rw.Write([]byte(`{"access_token": "issued-token", "id_token":"issued-id", "user": "late-secret"}`))
+		}
+	}))
+
+	require.NoError(t, RecordCassette(file))
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1, Token: "Bearer cassette-secret"})
+	response, err := client.SendRequest(http.MethodPost, server.URL+"/scan", bytes.NewBufferString(`{"password":"cassette-secret"}`), nil, nil)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, `{"id":1}`, string(body))
+	_, err = client.SendRequest(http.MethodPost, server.URL+"/token", bytes.NewBufferString("grant_type=refresh_token&refresh_token=refresh-secret"), nil, nil)
+	require.NoError(t, err)
+	// secrets registered after an interaction has been recorded are masked as well
+	log.RegisterSecret("late-secret")
+	for i := 0; i < 2; i++ {
+		_, err = client.SendRequest(http.MethodGet, server.URL+"/status", nil, nil, nil)
+		require.NoError(t, err)
+	}
+	server.Close()
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "cassette-secret")
+	assert.NotContains(t, string(content), "late-secret")
+	assert.NotContains(t, string(content), "issued-token")
+	assert.NotContains(t, string(content), "issued-id")
+	assert.NotContains(t, string(content), "refresh-secret")
+	assert.Contains(t, string(content), `refresh_token=****"`)
+	assert.NotContains(t, string(content), "session=abc")
+	assert.Contains(t, string(content), `"body": "{\"password\":\"****\"}"`)
+	assert.Contains(t, string(content), `"bodyEncoding": "base64"`)
+
+	t.Run("replay", func(t *testing.T) {
+		require.NoError(t, ReplayCassette(file))
+		client := Client{}
+		client.SetOptions(ClientOptions{UseDefaultTransport: true})
+
+		response, err := client.SendRequest(http.MethodPost, server.URL+"/scan", nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, `{"id":1}`, string(body))
+
+		// responses are replayed in the recorded order, the last one is repeated
+		for _, expected := range [][]byte{{3, 0xff}, {4, 0xff}, {4, 0xff}} {
+			response, err := client.SendRequest(http.MethodGet, server.URL+"/status", nil, nil, nil)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(response.Body)
+			assert.Equal(t, expected, body)
+		}
+
+		_, err = client.SendRequest(http.MethodGet, server.URL+"/unknown", nil, nil, nil)
+		assert.ErrorContains(t, err, "no response recorded in cassette")
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("invalid cassette", func(t *testing.T) {
+		assert.ErrorContains(t, ReplayCassette(filepath.Join(t.TempDir(), "missing.json")), "failed to read cassette")
+	})
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// DryRunRecord contains the details of a http request which has been recorded instead of being sent
+type DryRunRecord struct {
+	Method string
+	URL    string
+}
+
+// String returns the method and the url of the recorded request
+func (r DryRunRecord) String() string {
+	return fmt.Sprintf("%v %v", r.Method, r.URL)
+}
+
+var (
+	dryRun           bool
+	dryRunMutex      sync.Mutex
+	dryRunRecords    []DryRunRecord
+	defaultTransport http.RoundTripper
+)
+
+// SetDryRun enables or disables the dry-run mode.
+// In dry-run mode requests are only recorded and answered with an empty response instead of being sent.
+// Besides the requests of this package's client, the requests of all clients using http.DefaultTransport are recorded.
+func SetDryRun(enabled bool) {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	if enabled && !dryRun {
+		defaultTransport = http.DefaultTransport
+		http.DefaultTransport = &dryRunTransport{next: defaultTransport}
+	} else if !enabled && dryRun {
+		http.DefaultTransport = defaultTransport
+	}
+	dryRun = enabled
+	dryRunRecords = nil
+}
+
+// DryRunRecords returns the requests which have been recorded in dry-run mode
+func DryRunRecords() []DryRunRecord {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	return append([]DryRunRecord{}, dryRunRecords...)
+}
+
+func isDryRun() bool {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	return dryRun
+}
+
+// dryRunTransport records the requests of clients which do not use the transport of this package
+type dryRunTransport struct {
+	next http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if resp, recorded := recordDryRun(req); recorded {
+		return resp, nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// recordDryRun records the request and returns an empty response in case dry-run mode is enabled
+func recordDryRun(req *http.Request) (*http.Response, bool) {
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+	if !dryRun {
+		return nil, false
+	}
+	record := DryRunRecord{Method: req.Method, URL: req.URL.String()}
+	dryRunRecords = append(dryRunRecords, record)
+	log.Entry().Infof("dry-run: skipping request: %v", record)
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		Request:    req,
+	}, true
+}
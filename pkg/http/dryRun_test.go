@@ -0,0 +1,62 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	transport := http.DefaultTransport
+	SetDryRun(true)
+	defer func() {
+		SetDryRun(false)
+		assert.Equal(t, transport, http.DefaultTransport)
+	}()
+
+	t.Run("with retries", func(t *testing.T) {
+		client := Client{}
+		client.SetOptions(ClientOptions{})
+		response, err := client.SendRequest(http.MethodPost, server.URL+"/api", nil, nil, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			content, err := io.ReadAll(response.Body)
+			assert.NoError(t, err)
+			assert.Empty(t, content)
+		}
+	})
+
+	t.Run("without retries using default transport", func(t *testing.T) {
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		_, err := client.SendRequest(http.MethodGet, server.URL+"/status", nil, nil, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("other client", func(t *testing.T) {
+		response, err := (&http.Client{}).Get(server.URL + "/other")
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		}
+	})
+
+	assert.False(t, called, "request must not be sent in dry-run mode")
+	assert.Equal(t, []DryRunRecord{
+		{Method: http.MethodPost, URL: server.URL + "/api"},
+		{Method: http.MethodGet, URL: server.URL + "/status"},
+		{Method: http.MethodGet, URL: server.URL + "/other"},
+	}, DryRunRecords())
+	assert.Equal(t, "GET "+server.URL+"/status", DryRunRecords()[1].String())
+}
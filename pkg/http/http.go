@@ -21,10 +21,14 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/motemen/go-nuts/roundtime"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client defines an http client object
@@ -46,6 +50,7 @@ type Client struct {
 	certificates              []tls.Certificate // contains one or more certificate chains to present to the other side of the connection (client-authentication)
 	fileUtils                 piperutils.FileUtils
 	httpClient                *http.Client
+	policy                    *RequestPolicy
 }
 
 // ClientOptions defines the options to be set on the client
@@ -71,6 +76,8 @@ type ClientOptions struct {
 	UseDefaultTransport       bool
 	TrustedCerts              []string          // defines the set of root certificate authorities that clients use when verifying server certificates
 	Certificates              []tls.Certificate // contains one or more certificate chains to present to the other side of the connection (client-authentication)
+	// Policy defines rate limits and circuit breaking for the requests, the policy set via SetDefaultRequestPolicy is used if it is nil
+	Policy *RequestPolicy
 }
 
 // TransportWrapper is a wrapper for central round trip capabilities
@@ -81,6 +88,7 @@ type TransportWrapper struct {
 	username                 string
 	password                 string
 	token                    string
+	policy                   *RequestPolicy
 }
 
 // UploadRequestData encapsulates the parameters for calling uploader.Upload()
@@ -264,6 +272,7 @@ func (c *Client) SetOptions(options ClientOptions) {
 	c.trustedCerts = options.TrustedCerts
 	c.fileUtils = &piperutils.Files{}
 	c.certificates = options.Certificates
+	c.policy = options.Policy
 }
 
 // SetFileUtils can be used to overwrite the default file utils
@@ -314,6 +323,9 @@ func (c *Client) initializeHttpClient() *http.Client {
 		log.Entry().Debug("no trusted certs found / using default transport / insecure skip set to true / : continuing with existing tls config")
 	}
 
+	policy := c.requestPolicy()
+	transport.policy = policy
+
 	if c.maxRetries > 0 {
 		retryClient := retryablehttp.NewClient()
 		localLogger := log.Entry()
@@ -331,9 +343,25 @@ func (c *Client) initializeHttpClient() *http.Client {
 				doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 				token:                    c.token,
 				username:                 c.username,
-				password:                 c.password}
+				password:                 c.password,
+				policy:                   policy}
 		}
+		retryClient.Backoff = retryAfterBackoff
 		retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			if errors.Is(err, ErrCircuitOpen) || errors.Is(err, errNotRecorded) {
+				return false, err
+			}
+			if wait, ok := retryAfter(resp); ok {
+				maxWait := defaultMaxRetryAfter
+				if policy != nil {
+					maxWait = policy.maxRetryAfter()
+				}
+				if wait > maxWait {
+					log.Entry().Warnf("not retrying the request since %v asked to wait %v which exceeds %v", resp.Request.URL.Host, wait.Round(time.Second), maxWait)
+					return false, nil
+				}
+				return ctx.Err() == nil, ctx.Err()
+			}
 			if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
 				// Assuming timeouts, resets, and similar could be retried
 				return true, nil
@@ -346,8 +374,18 @@ func (c *Client) initializeHttpClient() *http.Client {
 			Timeout: c.maxRequestDuration,
 			Jar:     c.cookieJar,
 		}
-		if !c.useDefaultTransport {
+		// in dry-run mode the wrapper is always required since it records the requests
+		if !c.useDefaultTransport || isDryRun() {
 			c.httpClient.Transport = transport
+		} else if policy != nil || isCassetteActive() {
+			c.httpClient.Transport = &TransportWrapper{
+				Transport:                http.DefaultTransport,
+				doLogRequestBodyOnDebug:  c.doLogRequestBodyOnDebug,
+				doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
+				token:                    c.token,
+				username:                 c.username,
+				password:                 c.password,
+				policy:                   policy}
 		}
 	}
 
@@ -360,6 +398,21 @@ func (c *Client) initializeHttpClient() *http.Client {
 	return c.httpClient
 }
 
+// requestPolicy returns the policy of the client or the default policy, nil if neither limits requests
+func (c *Client) requestPolicy() *RequestPolicy {
+	if c.policy != nil {
+		if c.policy.active() {
+			return c.policy
+		}
+		return nil
+	}
+	policy := getDefaultRequestPolicy()
+	if policy.active() {
+		return &policy
+	}
+	return nil
+}
+
 type contextKey struct {
 	name string
 }
@@ -377,7 +430,50 @@ func (t *TransportWrapper) RoundTrip(req *http.Request) (*http.Response, error)
 
 	t.logRequest(req)
 
+	if resp, recorded := recordDryRun(req); recorded {
+		return resp, nil
+	}
+
+	if resp, replayed, err := replayCassette(req); replayed {
+		t.logResponse(resp)
+		return resp, err
+	}
+	recording := isCassetteRecording()
+	var requestBody []byte
+	if recording {
+		requestBody = readRequestBody(req)
+	}
+
+	probe := false
+	if t.policy != nil {
+		var err error
+		if probe, err = t.policy.beforeRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	_, span := tracing.StartSpan(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.request.method", req.Method), attribute.String("server.address", req.URL.Hostname()), attribute.String("url.path", req.URL.Path)))
+	defer span.End()
+	// the span is attached to the context of the request, which keeps its deadline and cancellation
+	req = req.WithContext(trace.ContextWithSpan(req.Context(), span))
+
 	resp, err := t.Transport.RoundTrip(req)
+	if recording {
+		recordCassette(req, requestBody, resp, err)
+	}
+	if t.policy != nil {
+		t.policy.afterResponse(req, resp, err, probe)
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
 
 	t.logResponse(resp)
 
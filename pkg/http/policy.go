@@ -0,0 +1,254 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRetryAfter         = 5 * time.Minute
+	defaultCircuitBreakerTimeout = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned for requests to a host which failed repeatedly, see RequestPolicy.CircuitBreakerThreshold
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// RequestPolicy defines how requests deal with rate limits and failing hosts.
+// The limits apply per host and are shared by all clients of the process, since many clients may talk to the same service.
+type RequestPolicy struct {
+	// RateLimit is the maximum number of requests per second per host, no limit is applied if it is zero
+	RateLimit float64
+	// RateBurst is the number of requests which may exceed the rate limit at once, defaults to 1
+	RateBurst int
+	// MaxRetryAfter is the longest time a retry waits for a host which asked to wait via Retry-After or an exhausted rate limit.
+	// Requests are not retried if the host asks to wait longer. Defaults to 5 minutes.
+	MaxRetryAfter time.Duration
+	// CircuitBreakerThreshold is the number of consecutive 5xx responses or connection errors of a host,
+	// after which requests to the host fail immediately. The circuit breaker is disabled if it is zero.
+	CircuitBreakerThreshold int
+	// CircuitBreakerTimeout is the time after which a single request is let through to check whether the host recovered, defaults to 30 seconds
+	CircuitBreakerTimeout time.Duration
+}
+
+// PolicyMetrics counts how often the request policy delayed or rejected requests
+type PolicyMetrics struct {
+	RateLimitWaits           int64 `json:"rateLimitWaits"`
+	RateLimitWaitMs          int64 `json:"rateLimitWaitMs"`
+	RetryAfterWaits          int64 `json:"retryAfterWaits"`
+	RetryAfterWaitMs         int64 `json:"retryAfterWaitMs"`
+	CircuitBreakerOpened     int64 `json:"circuitBreakerOpened"`
+	CircuitBreakerRejections int64 `json:"circuitBreakerRejections"`
+}
+
+type hostState struct {
+	limiter  *rate.Limiter
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+var (
+	policyMutex   sync.Mutex
+	defaultPolicy RequestPolicy
+	hostStates    = map[string]*hostState{}
+	policyMetrics PolicyMetrics
+)
+
+// SetDefaultRequestPolicy sets the policy of all clients which do not define their own policy via ClientOptions
+func SetDefaultRequestPolicy(policy RequestPolicy) {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	defaultPolicy = policy
+	hostStates = map[string]*hostState{}
+	policyMetrics = PolicyMetrics{}
+}
+
+// GetPolicyMetrics returns the metrics of all requests of the process
+func GetPolicyMetrics() PolicyMetrics {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	return policyMetrics
+}
+
+func getDefaultRequestPolicy() RequestPolicy {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	return defaultPolicy
+}
+
+func (p *RequestPolicy) maxRetryAfter() time.Duration {
+	if p.MaxRetryAfter > 0 {
+		return p.MaxRetryAfter
+	}
+	return defaultMaxRetryAfter
+}
+
+func (p *RequestPolicy) circuitBreakerTimeout() time.Duration {
+	if p.CircuitBreakerTimeout > 0 {
+		return p.CircuitBreakerTimeout
+	}
+	return defaultCircuitBreakerTimeout
+}
+
+// active returns whether requests need to pass the policy before being sent
+func (p *RequestPolicy) active() bool {
+	return p != nil && (p.RateLimit > 0 || p.CircuitBreakerThreshold > 0)
+}
+
+// hostState returns the state of the host, the caller needs to hold the policyMutex
+func (p *RequestPolicy) hostState(host string) *hostState {
+	state, ok := hostStates[host]
+	if !ok {
+		state = &hostState{}
+		if p.RateLimit > 0 {
+			burst := p.RateBurst
+			if burst < 1 {
+				burst = 1
+			}
+			state.limiter = rate.NewLimiter(rate.Limit(p.RateLimit), burst)
+		}
+		hostStates[host] = state
+	}
+	return state
+}
+
+// beforeRequest rejects the request if the circuit of the host is open and waits until the rate limit allows the request.
+// probe is true if the request checks whether the host of an open circuit recovered, it needs to be passed to afterResponse.
+func (p *RequestPolicy) beforeRequest(request *http.Request) (probe bool, err error) {
+	host := request.URL.Host
+	policyMutex.Lock()
+	state := p.hostState(host)
+	if p.CircuitBreakerThreshold > 0 && !state.openedAt.IsZero() {
+		if state.probing || time.Since(state.openedAt) < p.circuitBreakerTimeout() {
+			policyMetrics.CircuitBreakerRejections++
+			policyMutex.Unlock()
+			return false, fmt.Errorf("request to %v rejected: %w", host, ErrCircuitOpen)
+		}
+		// half-open: a single request checks whether the host recovered
+		state.probing = true
+		probe = true
+	}
+	var reservation *rate.Reservation
+	if state.limiter != nil {
+		reservation = state.limiter.Reserve()
+	}
+	policyMutex.Unlock()
+
+	if reservation == nil {
+		return probe, nil
+	}
+	delay := reservation.Delay()
+	if delay <= 0 {
+		return probe, nil
+	}
+	log.Entry().Debugf("waiting %v for the rate limit of %v", delay, host)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-request.Context().Done():
+		reservation.Cancel()
+		if probe {
+			p.cancelProbe(host)
+		}
+		return false, request.Context().Err()
+	}
+	policyMutex.Lock()
+	policyMetrics.RateLimitWaits++
+	policyMetrics.RateLimitWaitMs += delay.Milliseconds()
+	policyMutex.Unlock()
+	return probe, nil
+}
+
+// afterResponse updates the circuit of the host with the result of the request
+func (p *RequestPolicy) afterResponse(request *http.Request, response *http.Response, err error, probe bool) {
+	if p.CircuitBreakerThreshold <= 0 {
+		return
+	}
+	host := request.URL.Host
+	if errors.Is(err, context.Canceled) {
+		// a cancelled request tells nothing about the host, the next request checks it again
+		if probe {
+			p.cancelProbe(host)
+		}
+		return
+	}
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	state := p.hostState(host)
+	if err == nil && response.StatusCode < http.StatusInternalServerError {
+		state.failures = 0
+		state.openedAt = time.Time{}
+		state.probing = false
+		return
+	}
+	state.failures++
+	if state.probing || state.failures >= p.CircuitBreakerThreshold {
+		if !state.probing {
+			policyMetrics.CircuitBreakerOpened++
+			log.Entry().Warnf("opening circuit for %v after %v failed requests, requests are rejected for %v", host, state.failures, p.circuitBreakerTimeout())
+		}
+		state.openedAt = time.Now()
+		state.probing = false
+	}
+}
+
+// cancelProbe keeps the circuit of the host half-open if its probe did not complete
+func (p *RequestPolicy) cancelProbe(host string) {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	p.hostState(host).probing = false
+}
+
+// retryAfter returns how long the host asked to wait before the next request.
+// Besides Retry-After of 429 and 503 responses, exhausted rate limits are detected via X-RateLimit-Remaining and X-RateLimit-Reset, e.g. of GitHub.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable {
+		if value := response.Header.Get("Retry-After"); len(value) > 0 {
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return time.Duration(seconds) * time.Second, true
+			}
+			if date, err := http.ParseTime(value); err == nil {
+				return maxDuration(time.Until(date), 0), true
+			}
+		}
+	}
+	if (response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusTooManyRequests) && response.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return maxDuration(time.Until(time.Unix(reset, 0)), 0), true
+		}
+	}
+	return 0, false
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// retryAfterBackoff waits as long as the host asked for and uses the default backoff otherwise
+func retryAfterBackoff(min, max time.Duration, attemptNum int, response *http.Response) time.Duration {
+	if wait, ok := retryAfter(response); ok {
+		log.Entry().Infof("%v asked to wait %v before retrying", response.Request.URL.Host, wait.Round(time.Second))
+		policyMutex.Lock()
+		policyMetrics.RetryAfterWaits++
+		policyMetrics.RetryAfterWaitMs += wait.Milliseconds()
+		policyMutex.Unlock()
+		return wait
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, response)
+}
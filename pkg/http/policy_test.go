@@ -0,0 +1,190 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	response := func(status int, header map[string]string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		for key, value := range header {
+			r.Header.Set(key, value)
+		}
+		return r
+	}
+
+	t.Run("seconds", func(t *testing.T) {
+		wait, ok := retryAfter(response(http.StatusTooManyRequests, map[string]string{"Retry-After": "120"}))
+		assert.True(t, ok)
+		assert.Equal(t, 2*time.Minute, wait)
+	})
+
+	t.Run("HTTP date", func(t *testing.T) {
+		date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+		wait, ok := retryAfter(response(http.StatusServiceUnavailable, map[string]string{"Retry-After": date}))
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute.Seconds(), wait.Seconds(), 2)
+	})
+
+	t.Run("exhausted GitHub rate limit", func(t *testing.T) {
+		reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+		wait, ok := retryAfter(response(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}))
+		assert.True(t, ok)
+		assert.InDelta(t, 30, wait.Seconds(), 2)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		_, ok := retryAfter(response(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "10"}))
+		assert.False(t, ok)
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		_, ok := retryAfter(nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestRequestPolicy(t *testing.T) {
+	defer SetDefaultRequestPolicy(RequestPolicy{})
+
+	t.Run("retry after", func(t *testing.T) {
+		SetDefaultRequestPolicy(RequestPolicy{})
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if calls.Add(1) == 1 {
+				rw.Header().Set("Retry-After", "1")
+				rw.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		defer server.Close()
+
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: 2})
+		start := time.Now()
+		response, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, int64(1), GetPolicyMetrics().RetryAfterWaits)
+		assert.Equal(t, int64(1000), GetPolicyMetrics().RetryAfterWaitMs)
+	})
+
+	t.Run("retry after exceeds maximum", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			calls.Add(1)
+			rw.Header().Set("Retry-After", "3600")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: 2, Policy: &RequestPolicy{MaxRetryAfter: time.Minute}})
+		_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		SetDefaultRequestPolicy(RequestPolicy{RateLimit: 10})
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+		defer server.Close()
+
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+		assert.Equal(t, int64(2), GetPolicyMetrics().RateLimitWaits)
+	})
+
+	t.Run("circuit breaker", func(t *testing.T) {
+		SetDefaultRequestPolicy(RequestPolicy{})
+		var calls atomic.Int32
+		var healthy atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			calls.Add(1)
+			if !healthy.Load() {
+				rw.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer server.Close()
+
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, UseDefaultTransport: true, Policy: &RequestPolicy{CircuitBreakerThreshold: 2, CircuitBreakerTimeout: 100 * time.Millisecond}})
+		for i := 0; i < 2; i++ {
+			_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+			assert.Error(t, err)
+		}
+		_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.True(t, errors.Is(err, ErrCircuitOpen), "unexpected error %v", err)
+		assert.Equal(t, int32(2), calls.Load())
+
+		// a single request is let through once the timeout passed
+		time.Sleep(150 * time.Millisecond)
+		healthy.Store(true)
+		_, err = client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.NoError(t, err)
+		_, err = client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(4), calls.Load())
+
+		metrics := GetPolicyMetrics()
+		assert.Equal(t, int64(1), metrics.CircuitBreakerOpened)
+		assert.Equal(t, int64(1), metrics.CircuitBreakerRejections)
+	})
+
+	t.Run("cancelled probe", func(t *testing.T) {
+		SetDefaultRequestPolicy(RequestPolicy{})
+		var calls atomic.Int32
+		var healthy atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if calls.Add(1) == 2 {
+				// the probe hangs until it is cancelled
+				<-req.Context().Done()
+				return
+			}
+			if !healthy.Load() {
+				rw.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer server.Close()
+
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, UseDefaultTransport: true, Policy: &RequestPolicy{CircuitBreakerThreshold: 1, CircuitBreakerTimeout: 50 * time.Millisecond}})
+		_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.Error(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = client.Send(request)
+		assert.True(t, errors.Is(err, context.Canceled), "unexpected error %v", err)
+
+		// the next request probes the host again instead of being rejected
+		healthy.Store(true)
+		_, err = client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+}
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	exitHandlers        []*func()
+	exitHandlersMutex   sync.Mutex
+	registerExitHandler sync.Once
+	recoverable         bool
+	lastFatal           error
+)
+
+// fatalExit is raised instead of exiting the process if fatal errors are recoverable
+type fatalExit struct {
+	err error
+}
+
+// DeferExitHandler registers an exit handler to allow cleanup activities in case of a fatal error.
+// The returned function removes the handler again, e.g. once the step which registered it has finished.
+func DeferExitHandler(handler func()) func() {
+	registerExitHandler.Do(func() { logrus.DeferExitHandler(runExitHandlers) })
+	registered := &handler
+	exitHandlersMutex.Lock()
+	exitHandlers = append(exitHandlers, registered)
+	exitHandlersMutex.Unlock()
+	return func() {
+		exitHandlersMutex.Lock()
+		defer exitHandlersMutex.Unlock()
+		for i, h := range exitHandlers {
+			if h == registered {
+				exitHandlers = append(exitHandlers[:i], exitHandlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func runExitHandlers() {
+	// a recoverable fatal error unwinds the stack, thus the handlers are executed by the deferred calls of their owners
+	if recoverable {
+		return
+	}
+	exitHandlersMutex.Lock()
+	handlers := append([]*func(){}, exitHandlers...)
+	exitHandlersMutex.Unlock()
+	for _, handler := range handlers {
+		(*handler)()
+	}
+}
+
+// SetFatalRecoverable defines whether a fatal error exits the process, which is the default, or whether it can be recovered via RunRecoverable.
+// The latter is required when several steps are executed within the same process.
+func SetFatalRecoverable(enabled bool) {
+	recoverable = enabled
+	if !enabled {
+		logrus.StandardLogger().ExitFunc = os.Exit
+		return
+	}
+	if !fatalCaptureRegistered() {
+		logrus.AddHook(&fatalCaptureHook{})
+	}
+	logrus.StandardLogger().ExitFunc = func(code int) {
+		err := lastFatal
+		if err == nil {
+			err = fmt.Errorf("fatal error with exit code %v", code)
+		}
+		panic(fatalExit{err: err})
+	}
+}
+
+// FatalRecoverable returns whether fatal errors can be recovered via RunRecoverable
+func FatalRecoverable() bool {
+	return recoverable
+}
+
+// RunRecoverable executes the function and returns a fatal error logged by it as error value, provided that fatal errors are recoverable
+func RunRecoverable(run func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			exit, ok := r.(fatalExit)
+			if !ok {
+				panic(r)
+			}
+			err = exit.err
+		}
+	}()
+	lastFatal = nil
+	run()
+	return nil
+}
+
+// fatalCaptureRegistered returns whether the fatalCaptureHook is registered, the hooks may have been replaced via IsolateHooks
+func fatalCaptureRegistered() bool {
+	for _, hook := range logrus.StandardLogger().Hooks[logrus.FatalLevel] {
+		if _, ok := hook.(*fatalCaptureHook); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fatalCaptureHook keeps the message of the last fatal error in order to return it by RunRecoverable
+type fatalCaptureHook struct{}
+
+func (f *fatalCaptureHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.FatalLevel}
+}
+
+func (f *fatalCaptureHook) Fire(entry *logrus.Entry) error {
+	if cause, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		lastFatal = fmt.Errorf("%v: %w", entry.Message, cause)
+	} else {
+		lastFatal = fmt.Errorf("%v", entry.Message)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package log
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeferExitHandler(t *testing.T) {
+	calls := []string{}
+	removeFirst := DeferExitHandler(func() { calls = append(calls, "first") })
+	removeSecond := DeferExitHandler(func() { calls = append(calls, "second") })
+	defer removeSecond()
+
+	removeFirst()
+	runExitHandlers()
+
+	assert.Equal(t, []string{"second"}, calls)
+}
+
+func TestRunRecoverable(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		SetFatalRecoverable(true)
+		defer SetFatalRecoverable(false)
+
+		assert.NoError(t, RunRecoverable(func() {}))
+	})
+
+	t.Run("fatal error", func(t *testing.T) {
+		SetFatalRecoverable(true)
+		defer SetFatalRecoverable(false)
+		handlerCalled := false
+		removeHandler := DeferExitHandler(func() { handlerCalled = true })
+		defer removeHandler()
+
+		err := RunRecoverable(func() {
+			Entry().WithError(errors.New("cause")).Fatal("step failed")
+		})
+
+		assert.EqualError(t, err, "step failed: cause")
+		assert.False(t, handlerCalled, "exit handlers must not run for recoverable errors")
+	})
+
+	t.Run("other panics are not recovered", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = RunRecoverable(func() { panic("boom") })
+		})
+	})
+}
+
+func TestRunRecoverableIsolatedHooks(t *testing.T) {
+	restoreHooks := IsolateHooks()
+	defer restoreHooks()
+	SetFatalRecoverable(true)
+	defer SetFatalRecoverable(false)
+
+	err := RunRecoverable(func() {
+		Entry().Fatal("step failed")
+	})
+
+	assert.EqualError(t, err, "step failed")
+}
@@ -1,10 +1,13 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -19,8 +22,16 @@ const (
 	logFormatPlain         = "plain"
 	logFormatDefault       = "default"
 	logFormatWithTimestamp = "timestamp"
+	logFormatJSON          = "json"
 )
 
+// pipelineContext contains the fields which correlate the log entries of a step with the pipeline run
+type pipelineContext struct {
+	stageName     string
+	buildID       string
+	correlationID string
+}
+
 // Format the log message
 func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, err error) {
 	message := ""
@@ -42,6 +53,8 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 	}
 
 	switch formatter.logFormat {
+	case logFormatJSON:
+		return formatter.formatJSON(entry, levelString)
 	case logFormatDefault:
 		message = fmt.Sprintf("%-5s %-6s - %s%s\n", levelString, stepName, entry.Message, errorMessageSnippet)
 	case logFormatWithTimestamp:
@@ -56,11 +69,67 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 		message = string(formattedMessage)
 	}
 
+	return []byte(maskSecrets(message)), nil
+}
+
+// formatJSON formats the log entry as a single line JSON object.
+// Secrets are masked in the serialized entry in order to cover values of any type, e.g. maps or structs.
+func (formatter *PiperLogFormatter) formatJSON(entry *logrus.Entry, level string) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Data)+9)
+	for key, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			// errors are usually structs without exported fields
+			data[key] = err.Error()
+			continue
+		}
+		data[key] = value
+	}
+	if _, ok := data["stepName"]; !ok {
+		data["stepName"] = "(noStepName)"
+	}
+	data["time"] = entry.Time.Format(time.RFC3339Nano)
+	data["level"] = level
+	data["message"] = entry.Message
+	data["stageName"] = runContext.stageName
+	data["buildId"] = runContext.buildID
+	data["correlationId"] = runContext.correlationID
+	data["errorCategory"] = GetErrorCategory().String()
+
+	message := &bytes.Buffer{}
+	encoder := json.NewEncoder(message)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal log entry to JSON: %w", err)
+	}
+	return []byte(maskSecretsInJSON(message.String())), nil
+}
+
+// maskSecretsInJSON replaces the registered secrets in serialized JSON, also if they contain characters which are escaped in JSON strings
+func maskSecretsInJSON(message string) string {
 	for _, secret := range secrets {
+		escaped := &bytes.Buffer{}
+		encoder := json.NewEncoder(escaped)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(secret); err == nil {
+			// only the enclosing quotes are removed, quotes at the start or the end of the secret are part of the escaped value
+			quoted := strings.TrimSpace(escaped.String())
+			message = strings.Replace(message, quoted[1:len(quoted)-1], "****", -1)
+		}
 		message = strings.Replace(message, secret, "****", -1)
 	}
+	return message
+}
 
-	return []byte(message), nil
+// MaskSecrets replaces the registered secrets in the text, e.g. for tool output which is persisted outside of the log
+func MaskSecrets(text string) string {
+	return maskSecrets(text)
+}
+
+func maskSecrets(message string) string {
+	for _, secret := range secrets {
+		message = strings.Replace(message, secret, "****", -1)
+	}
+	return message
 }
 
 // LibraryRepository that is passed into with -ldflags
@@ -68,6 +137,7 @@ var LibraryRepository string
 var LibraryName string
 var logger *logrus.Entry
 var secrets []string
+var runContext pipelineContext
 
 // Entry returns the logger entry or creates one if none is present.
 func Entry() *logrus.Entry {
@@ -84,6 +154,12 @@ func Writer() io.Writer {
 	return &logrusWriter{logger: Entry()}
 }
 
+// IsWriter returns true if w forwards the output to the log, i.e. it has been created via Writer().
+func IsWriter(w io.Writer) bool {
+	_, ok := w.(*logrusWriter)
+	return ok
+}
+
 // SetVerbose sets the log level with respect to verbose flag.
 func SetVerbose(verbose bool) {
 	if verbose {
@@ -107,9 +183,10 @@ func SetStepName(stepName string) {
 	logger = Entry().WithField("stepName", stepName)
 }
 
-// DeferExitHandler registers a logrus exit handler to allow cleanup activities.
-func DeferExitHandler(handler func()) {
-	logrus.DeferExitHandler(handler)
+// SetPipelineContext sets the stage name, the build ID and the correlation ID of the pipeline run.
+// They are added to every log entry when using the log format json.
+func SetPipelineContext(stageName, buildID, correlationID string) {
+	runContext = pipelineContext{stageName: stageName, buildID: buildID, correlationID: correlationID}
 }
 
 // RegisterHook registers a logrus hook
@@ -117,6 +194,19 @@ func RegisterHook(hook logrus.Hook) {
 	logrus.AddHook(hook)
 }
 
+// IsolateHooks removes all registered hooks and returns a function which restores them.
+// This allows each step to register its own hooks when several steps are executed within the same process.
+func IsolateHooks() func() {
+	previous := make(logrus.LevelHooks, len(logrus.StandardLogger().Hooks))
+	for level, hooks := range logrus.StandardLogger().Hooks {
+		previous[level] = append([]logrus.Hook{}, hooks...)
+	}
+	logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	return func() {
+		logrus.StandardLogger().ReplaceHooks(previous)
+	}
+}
+
 // RegisterSecret registers a value which should be masked in every log message
 func RegisterSecret(secret string) {
 	if len(secret) > 0 {
@@ -5,9 +5,13 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,3 +62,79 @@ func TestWriteLargeBuffer(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestJSONFormat(t *testing.T) {
+	outWriter := Entry().Logger.Out
+	outFormatter := Entry().Logger.Formatter
+	var buffer bytes.Buffer
+	Entry().Logger.SetOutput(&buffer)
+	defer func() {
+		Entry().Logger.SetOutput(outWriter)
+		Entry().Logger.SetFormatter(outFormatter)
+		SetPipelineContext("", "", "")
+		SetErrorCategory(ErrorUndefined)
+	}()
+
+	SetFormatter("json")
+	SetPipelineContext("Build", "1234", "https://ci.example.com/job/1234")
+	SetErrorCategory(ErrorBuild)
+	RegisterSecret("jsonSecret")
+
+	Entry().WithField("stepName", "mavenBuild").WithError(fmt.Errorf("failed with jsonSecret")).Error("build failed with key jsonSecret")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "build failed with key ****", entry["message"])
+	assert.Equal(t, "failed with ****", entry["error"])
+	assert.Equal(t, "mavenBuild", entry["stepName"])
+	assert.Equal(t, "Build", entry["stageName"])
+	assert.Equal(t, "1234", entry["buildId"])
+	assert.Equal(t, "https://ci.example.com/job/1234", entry["correlationId"])
+	assert.Equal(t, "build", entry["errorCategory"])
+	assert.NotEmpty(t, entry["time"])
+	assert.Equal(t, 1, strings.Count(buffer.String(), "\n"))
+
+	t.Run("secrets in values of any type", func(t *testing.T) {
+		buffer.Reset()
+		RegisterSecret(`quoted"Secret\`)
+
+		Entry().WithField("request", map[string]interface{}{"header": []string{"Bearer jsonSecret"}}).
+			WithField("credentials", struct{ Password string }{Password: `quoted"Secret\`}).
+			Info("sending request")
+
+		assert.NotContains(t, buffer.String(), "jsonSecret")
+		assert.NotContains(t, buffer.String(), "quoted")
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+		assert.Equal(t, map[string]interface{}{"header": []interface{}{"Bearer ****"}}, entry["request"])
+		assert.Equal(t, map[string]interface{}{"Password": "****"}, entry["credentials"])
+	})
+
+	t.Run("secrets enclosed in quotes", func(t *testing.T) {
+		buffer.Reset()
+		RegisterSecret(`"leadingQuote`)
+		RegisterSecret(`trailingQuote"`)
+
+		Entry().WithField("credentials", []string{`"leadingQuote`, `trailingQuote"`}).Info("sending request")
+
+		assert.NotContains(t, buffer.String(), "Quote")
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+		assert.Equal(t, []interface{}{"****", "****"}, entry["credentials"])
+	})
+}
+
+func TestIsolateHooks(t *testing.T) {
+	defer IsolateHooks()()
+	hook := &CollectorHook{}
+	RegisterHook(hook)
+
+	restoreHooks := IsolateHooks()
+	for _, hooks := range logrus.StandardLogger().Hooks {
+		assert.NotContains(t, hooks, hook)
+	}
+
+	restoreHooks()
+	assert.Contains(t, logrus.StandardLogger().Hooks[logrus.InfoLevel], hook)
+}
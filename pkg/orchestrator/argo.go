@@ -0,0 +1,170 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// argoWorkflowsConfigProvider reads the workflow information which is exposed to the step container.
+// Argo injects ARGO_TEMPLATE and ARGO_NODE_ID into each container, further information needs to be passed
+// as env variables via workflow variables, e.g.
+//
+//	env:
+//	  - name: ARGO_WORKFLOW_NAME
+//	    value: "{{workflow.name}}"
+//
+// or is read from the pod labels mounted as downward API volume, see PIPER_PODINFO_LABELS.
+type argoWorkflowsConfigProvider struct{}
+
+func newArgoWorkflowsConfigProvider() *argoWorkflowsConfigProvider {
+	return &argoWorkflowsConfigProvider{}
+}
+
+// Configure is not required for Argo Workflows
+func (a *argoWorkflowsConfigProvider) Configure(_ *Options) error {
+	log.Entry().Debug("Successfully initialized Argo Workflows config provider")
+	return nil
+}
+
+func (a *argoWorkflowsConfigProvider) OrchestratorVersion() string {
+	log.Entry().Debugf("OrchestratorVersion() for Argo Workflows is not applicable.")
+	return "n/a"
+}
+
+// OrchestratorType returns the orchestrator type ArgoWorkflows
+func (a *argoWorkflowsConfigProvider) OrchestratorType() string {
+	return "ArgoWorkflows"
+}
+
+// BuildStatus returns the workflow status, e.g. provided via {{workflow.status}} in an exit handler.
+func (a *argoWorkflowsConfigProvider) BuildStatus() string {
+	switch getEnv("ARGO_WORKFLOW_STATUS", "") {
+	case "Succeeded":
+		return BuildStatusSuccess
+	case "Failed", "Error":
+		return BuildStatusFailure
+	default:
+		return BuildStatusInProgress
+	}
+}
+
+// FullLogs returns the logs from the mounted log directory
+func (a *argoWorkflowsConfigProvider) FullLogs() ([]byte, error) {
+	return logsFromDirectory()
+}
+
+// PipelineStartTime returns the workflow start time in UTC if provided via ARGO_WORKFLOW_CREATION_TIMESTAMP
+func (a *argoWorkflowsConfigProvider) PipelineStartTime() time.Time {
+	return parseStartTime("ARGO_WORKFLOW_CREATION_TIMESTAMP")
+}
+
+// BuildID returns the name of the workflow, e.g. build-x7k2p
+func (a *argoWorkflowsConfigProvider) BuildID() string {
+	return getEnvOrLabel("ARGO_WORKFLOW_NAME", "workflows.argoproj.io/workflow", "n/a")
+}
+
+func (a *argoWorkflowsConfigProvider) ChangeSets() []ChangeSet {
+	log.Entry().Debug("ChangeSets for Argo Workflows not implemented")
+	return []ChangeSet{}
+}
+
+// StageName returns the name of the workflow step provided via ARGO_STEP_NAME, the name of the template otherwise
+func (a *argoWorkflowsConfigProvider) StageName() string {
+	if stepName, found := os.LookupEnv("ARGO_STEP_NAME"); found {
+		return stepName
+	}
+	var template struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(getEnv("ARGO_TEMPLATE", "{}")), &template); err != nil || len(template.Name) == 0 {
+		log.Entry().Debug("Could not read the template name from ARGO_TEMPLATE")
+		return "n/a"
+	}
+	return template.Name
+}
+
+func (a *argoWorkflowsConfigProvider) BuildReason() string {
+	log.Entry().Debug("BuildReason for Argo Workflows not implemented")
+	return BuildReasonUnknown
+}
+
+// Branch returns the branch provided via GIT_BRANCH, e.g. main
+func (a *argoWorkflowsConfigProvider) Branch() string {
+	return getEnv("GIT_BRANCH", "n/a")
+}
+
+// GitReference returns the git reference of the branch, e.g. refs/heads/main
+func (a *argoWorkflowsConfigProvider) GitReference() string {
+	return gitReference(a.Branch())
+}
+
+// BuildURL returns the URL of the workflow in the Argo UI, e.g. https://argo.example.com/workflows/ci/build-x7k2p
+func (a *argoWorkflowsConfigProvider) BuildURL() string {
+	serverURL := getEnv("ARGO_SERVER_URL", "")
+	if len(serverURL) == 0 {
+		return "n/a"
+	}
+	return strings.TrimSuffix(serverURL, "/") + "/workflows/" + a.namespace() + "/" + a.BuildID()
+}
+
+// JobURL returns the URL of the workflow template in the Argo UI
+func (a *argoWorkflowsConfigProvider) JobURL() string {
+	serverURL := getEnv("ARGO_SERVER_URL", "")
+	if len(serverURL) == 0 {
+		return "n/a"
+	}
+	return strings.TrimSuffix(serverURL, "/") + "/workflow-templates/" + a.namespace() + "/" + a.JobName()
+}
+
+// JobName returns the name of the workflow template, the name of the workflow if it is not based on a template
+func (a *argoWorkflowsConfigProvider) JobName() string {
+	if template := getEnvOrLabel("ARGO_WORKFLOW_TEMPLATE", "workflows.argoproj.io/workflow-template", "n/a"); template != "n/a" {
+		return template
+	}
+	return a.BuildID()
+}
+
+// CommitSHA returns the commit SHA provided via GIT_COMMIT
+func (a *argoWorkflowsConfigProvider) CommitSHA() string {
+	return getEnv("GIT_COMMIT", "n/a")
+}
+
+// RepoURL returns the repository URL provided via GIT_URL
+func (a *argoWorkflowsConfigProvider) RepoURL() string {
+	return getEnv("GIT_URL", "n/a")
+}
+
+func (a *argoWorkflowsConfigProvider) PullRequestConfig() PullRequestConfig {
+	log.Entry().Debug("PullRequestConfig for Argo Workflows not implemented")
+	return PullRequestConfig{
+		Branch: "n/a",
+		Base:   "n/a",
+		Key:    "n/a",
+	}
+}
+
+func (a *argoWorkflowsConfigProvider) IsPullRequest() bool {
+	return false
+}
+
+func (a *argoWorkflowsConfigProvider) namespace() string {
+	return getEnv("ARGO_WORKFLOW_NAMESPACE", "default")
+}
+
+func isArgoWorkflows() bool {
+	envVars := []string{"ARGO_NODE_ID", "ARGO_WORKFLOW_NAME"}
+	if envVarsAreSet(envVars) {
+		return true
+	}
+	_, found := podLabels()["workflows.argoproj.io/workflow"]
+	return found
+}
+
+// IdentityToken returns the token of the Kubernetes service account, the audience is defined by the projected token
+func (a *argoWorkflowsConfigProvider) IdentityToken(_ string) (string, error) {
+	return serviceAccountToken()
+}
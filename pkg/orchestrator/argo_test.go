@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgoWorkflows(t *testing.T) {
+	t.Run("detection", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("ARGO_NODE_ID", "build-x7k2p-1234")
+
+		assert.Equal(t, ArgoWorkflows, DetectOrchestrator())
+		assert.Equal(t, "ArgoWorkflows", DetectOrchestrator().String())
+	})
+
+	t.Run("env variables", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("ARGO_NODE_ID", "build-x7k2p-1234")
+		os.Setenv("ARGO_WORKFLOW_NAME", "build-x7k2p")
+		os.Setenv("ARGO_WORKFLOW_NAMESPACE", "ci")
+		os.Setenv("ARGO_WORKFLOW_TEMPLATE", "build")
+		os.Setenv("ARGO_SERVER_URL", "https://argo.example.com")
+		os.Setenv("ARGO_STEP_NAME", "Build")
+		os.Setenv("ARGO_WORKFLOW_STATUS", "Succeeded")
+		os.Setenv("ARGO_WORKFLOW_CREATION_TIMESTAMP", "2023-11-06T10:30:31Z")
+		os.Setenv("GIT_BRANCH", "main")
+		os.Setenv("GIT_COMMIT", "abcdef42713")
+		os.Setenv("GIT_URL", "https://github.com/foo/bar")
+
+		p := newArgoWorkflowsConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "build-x7k2p", p.BuildID())
+		assert.Equal(t, "Build", p.StageName())
+		assert.Equal(t, "build", p.JobName())
+		assert.Equal(t, "https://argo.example.com/workflows/ci/build-x7k2p", p.BuildURL())
+		assert.Equal(t, "https://argo.example.com/workflow-templates/ci/build", p.JobURL())
+		assert.Equal(t, BuildStatusSuccess, p.BuildStatus())
+		assert.Equal(t, "refs/heads/main", p.GitReference())
+		assert.Equal(t, "abcdef42713", p.CommitSHA())
+		assert.Equal(t, "https://github.com/foo/bar", p.RepoURL())
+		assert.Equal(t, "ArgoWorkflows", p.OrchestratorType())
+		assert.Equal(t, time.Date(2023, time.November, 6, 10, 30, 31, 0, time.UTC), p.PipelineStartTime())
+	})
+
+	t.Run("pod labels and template", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		labelsFile := filepath.Join(t.TempDir(), "labels")
+		assert.NoError(t, os.WriteFile(labelsFile, []byte("workflows.argoproj.io/workflow=\"build-x7k2p\"\n"), 0644))
+		os.Setenv("PIPER_PODINFO_LABELS", labelsFile)
+		ResetConfigProvider()
+		os.Setenv("ARGO_TEMPLATE", `{"name":"build","container":{"image":"golang"}}`)
+
+		p := newArgoWorkflowsConfigProvider()
+
+		assert.Equal(t, ArgoWorkflows, DetectOrchestrator())
+		assert.Equal(t, "build-x7k2p", p.BuildID())
+		assert.Equal(t, "build", p.StageName())
+		assert.Equal(t, "build-x7k2p", p.JobName())
+		assert.Equal(t, "n/a", p.BuildURL())
+		assert.Equal(t, BuildStatusInProgress, p.BuildStatus())
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("ARGO_TEMPLATE", "{")
+
+		assert.Equal(t, "n/a", newArgoWorkflowsConfigProvider().StageName())
+	})
+}
@@ -1,7 +1,10 @@
 package orchestrator
 
 import (
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -9,6 +12,7 @@ import (
 
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
 )
 
 type azureDevopsConfigProvider struct {
@@ -261,3 +265,15 @@ func isAzure() bool {
 	envVars := []string{"AZURE_HTTP_USER_AGENT"}
 	return envVarsAreSet(envVars)
 }
+
+// IdentityToken requests an OIDC token via the service connection defined by AZURESUBSCRIPTION_SERVICE_CONNECTION_ID,
+// the audience is defined by the service connection. System.AccessToken needs to be mapped to SYSTEM_ACCESSTOKEN.
+func (a *azureDevopsConfigProvider) IdentityToken(_ string) (string, error) {
+	requestURI, accessToken := getEnv("SYSTEM_OIDCREQUESTURI", ""), getEnv("SYSTEM_ACCESSTOKEN", "")
+	serviceConnection := getEnv("AZURESUBSCRIPTION_SERVICE_CONNECTION_ID", "")
+	if len(requestURI) == 0 || len(accessToken) == 0 || len(serviceConnection) == 0 {
+		return "", errors.New("no identity token available, SYSTEM_OIDCREQUESTURI, SYSTEM_ACCESSTOKEN and AZURESUBSCRIPTION_SERVICE_CONNECTION_ID need to be set")
+	}
+	requestURL := fmt.Sprintf("%v?api-version=7.1&serviceConnectionId=%v", requestURI, url.QueryEscape(serviceConnection))
+	return requestIdentityToken(http.MethodPost, requestURL, accessToken, "oidcToken")
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -352,3 +354,15 @@ func workflowFileName() string {
 	log.Entry().Debugf("unable to determine workflow file name from GITHUB_WORKFLOW_REF: %s", workflowRef)
 	return ""
 }
+
+// IdentityToken requests an OIDC token for the audience, which requires the workflow permission id-token: write
+func (g *githubActionsConfigProvider) IdentityToken(audience string) (string, error) {
+	requestURL, requestToken := getEnv("ACTIONS_ID_TOKEN_REQUEST_URL", ""), getEnv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	if len(requestURL) == 0 || len(requestToken) == 0 {
+		return "", errors.New("no identity token available, the workflow requires the permission 'id-token: write'")
+	}
+	if len(audience) > 0 {
+		requestURL += "&audience=" + url.QueryEscape(audience)
+	}
+	return requestIdentityToken(http.MethodGet, requestURL, requestToken, "value")
+}
@@ -0,0 +1,341 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type gitlabConfigProvider struct {
+	client       piperHttp.Client
+	header       http.Header
+	pipelineData gitlabPipeline
+}
+
+type gitlabPipeline struct {
+	fetched bool
+	Status  string `json:"status"`
+}
+
+type gitlabJob struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Stage  string `json:"stage"`
+	Status string `json:"status"`
+}
+
+type gitlabCommit struct {
+	ID          string `json:"id"`
+	CommittedAt string `json:"committed_date"`
+}
+
+// newGitlabConfigProvider creates a provider which authenticates with the job token of the current job.
+// The job token only grants access to a subset of the API, e.g. job logs require a GitLab token, see Configure.
+func newGitlabConfigProvider() *gitlabConfigProvider {
+	g := &gitlabConfigProvider{header: http.Header{}}
+	g.client.SetOptions(piperHttp.ClientOptions{
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	})
+	g.header.Set("JOB-TOKEN", os.Getenv("CI_JOB_TOKEN"))
+	return g
+}
+
+// Configure sets the GitLab token used for API requests
+func (g *gitlabConfigProvider) Configure(opts *Options) error {
+	if len(opts.GitLabToken) > 0 {
+		g.header = http.Header{}
+		g.header.Set("PRIVATE-TOKEN", opts.GitLabToken)
+	}
+
+	log.Entry().Debug("Successfully initialized GitLab config provider")
+	return nil
+}
+
+// OrchestratorVersion returns the version of the GitLab instance
+func (g *gitlabConfigProvider) OrchestratorVersion() string {
+	return getEnv("CI_SERVER_VERSION", "n/a")
+}
+
+// OrchestratorType returns the orchestrator type GitLab
+func (g *gitlabConfigProvider) OrchestratorType() string {
+	return "GitLab"
+}
+
+// projectURL returns the API URL of the current project, e.g. https://gitlab.com/api/v4/projects/42
+func (g *gitlabConfigProvider) projectURL() string {
+	return getEnv("CI_API_V4_URL", "n/a") + "/projects/" + getEnv("CI_PROJECT_ID", "n/a")
+}
+
+// getJSON sends a GET request to the GitLab API and parses the JSON response into result
+func (g *gitlabConfigProvider) getJSON(URL string, result interface{}) error {
+	response, err := g.get(URL)
+	if err != nil {
+		return err
+	}
+	return piperHttp.ParseHTTPResponseBodyJSON(response, result)
+}
+
+// getJSONPages sends GET requests for all pages of a paginated list of the GitLab API.
+// The pages are requested as long as the X-Next-Page header references a further page and the JSON response of each page is parsed by parsePage.
+func (g *gitlabConfigProvider) getJSONPages(URL string, parsePage func(response *http.Response) error) error {
+	separator := "?"
+	if strings.Contains(URL, "?") {
+		separator = "&"
+	}
+	for page := "1"; len(page) > 0; {
+		response, err := g.get(URL + separator + "page=" + url.QueryEscape(page))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get page %v", page)
+		}
+		if err := parsePage(response); err != nil {
+			return errors.Wrapf(err, "failed to parse page %v", page)
+		}
+		nextPage := response.Header.Get("X-Next-Page")
+		if nextPage == page {
+			return errors.Errorf("pagination of %v does not advance beyond page %v", URL, page)
+		}
+		page = nextPage
+	}
+	return nil
+}
+
+func (g *gitlabConfigProvider) get(URL string) (*http.Response, error) {
+	response, err := g.client.GetRequest(URL, g.header, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get HTTP response")
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("response code is %v, could not get data from GitLab API", response.StatusCode)
+	}
+	return response, nil
+}
+
+func (g *gitlabConfigProvider) fetchPipelineData() {
+	if g.pipelineData.fetched {
+		return
+	}
+	URL := g.projectURL() + "/pipelines/" + g.BuildID()
+	if err := g.getJSON(URL, &g.pipelineData); err != nil {
+		log.Entry().WithError(err).Error("failed to get pipeline information from GitLab")
+		return
+	}
+	g.pipelineData.fetched = true
+}
+
+// BuildStatus returns status of the pipeline. Return variables are aligned with Jenkins build statuses.
+func (g *gitlabConfigProvider) BuildStatus() string {
+	g.fetchPipelineData()
+	switch g.pipelineData.Status {
+	case "success":
+		return BuildStatusSuccess
+	case "canceled":
+		return BuildStatusAborted
+	case "created", "waiting_for_resource", "preparing", "pending", "running":
+		return BuildStatusInProgress
+	default:
+		return BuildStatusFailure
+	}
+}
+
+// FullLogs returns the logs of all jobs of the current pipeline which have been started so far
+func (g *gitlabConfigProvider) FullLogs() ([]byte, error) {
+	var jobs []gitlabJob
+	err := g.getJSONPages(g.projectURL()+"/pipelines/"+g.BuildID()+"/jobs?per_page=100&include_retried=true", func(response *http.Response) error {
+		var page []gitlabJob
+		err := piperHttp.ParseHTTPResponseBodyJSON(response, &page)
+		jobs = append(jobs, page...)
+		return err
+	})
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "failed to get jobs of pipeline")
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+	var logs []byte
+	for _, job := range jobs {
+		switch job.Status {
+		case "created", "pending", "manual", "skipped", "scheduled", "waiting_for_resource":
+			// no log available
+			continue
+		}
+		logURL := fmt.Sprintf("%v/jobs/%v/trace", g.projectURL(), job.ID)
+		log.Entry().Debugf("Getting log of job %v from %v", job.Name, logURL)
+		response, err := g.client.GetRequest(logURL, g.header, nil)
+		if err != nil {
+			return []byte{}, errors.Wrapf(err, "failed to get log of job %v", job.Name)
+		}
+		if response.StatusCode != http.StatusOK {
+			log.Entry().Errorf("response code is %v, could not get log of job %v from GitLab", response.StatusCode, job.Name)
+			continue
+		}
+		content, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return []byte{}, errors.Wrapf(err, "failed to read log of job %v", job.Name)
+		}
+		logs = append(logs, content...)
+	}
+	return logs, nil
+}
+
+// PipelineStartTime returns the pipeline start time in UTC
+func (g *gitlabConfigProvider) PipelineStartTime() time.Time {
+	return parseStartTime("CI_PIPELINE_CREATED_AT")
+}
+
+// BuildID returns the ID of the pipeline, e.g. 1234
+func (g *gitlabConfigProvider) BuildID() string {
+	return getEnv("CI_PIPELINE_ID", "n/a")
+}
+
+// ChangeSets returns the commits of the merge request or the commits which have been pushed
+func (g *gitlabConfigProvider) ChangeSets() []ChangeSet {
+	var commits []gitlabCommit
+	prNumber := 0
+	if g.IsPullRequest() {
+		prNumber, _ = strconv.Atoi(getEnv("CI_MERGE_REQUEST_IID", ""))
+		URL := g.projectURL() + "/merge_requests/" + getEnv("CI_MERGE_REQUEST_IID", "n/a") + "/commits?per_page=100"
+		err := g.getJSONPages(URL, func(response *http.Response) error {
+			var page []gitlabCommit
+			err := piperHttp.ParseHTTPResponseBodyJSON(response, &page)
+			commits = append(commits, page...)
+			return err
+		})
+		if err != nil {
+			log.Entry().WithError(err).Debug("could not get commits of merge request")
+			return []ChangeSet{}
+		}
+	} else {
+		before := getEnv("CI_COMMIT_BEFORE_SHA", "")
+		if len(before) == 0 || before == "0000000000000000000000000000000000000000" {
+			// new branch or no push event, commits cannot be determined
+			return []ChangeSet{}
+		}
+		var comparison struct {
+			Commits []gitlabCommit `json:"commits"`
+		}
+		URL := g.projectURL() + "/repository/compare?from=" + url.QueryEscape(before) + "&to=" + url.QueryEscape(g.CommitSHA())
+		if err := g.getJSON(URL, &comparison); err != nil {
+			log.Entry().WithError(err).Debug("could not compare commits")
+			return []ChangeSet{}
+		}
+		commits = comparison.Commits
+	}
+
+	changeSets := []ChangeSet{}
+	for _, commit := range commits {
+		changeSets = append(changeSets, ChangeSet{CommitId: commit.ID, Timestamp: commit.CommittedAt, PrNumber: prNumber})
+	}
+	return changeSets
+}
+
+// StageName returns the name of the stage the current job belongs to
+func (g *gitlabConfigProvider) StageName() string {
+	return getEnv("CI_JOB_STAGE", "n/a")
+}
+
+// BuildReason returns the source of the pipeline.
+// BuildReasons are unified with AzureDevOps build reasons, see
+// https://docs.microsoft.com/en-us/azure/devops/pipelines/build/variables?view=azure-devops&tabs=yaml#build-variables-devops-services
+func (g *gitlabConfigProvider) BuildReason() string {
+	switch getEnv("CI_PIPELINE_SOURCE", "") {
+	case "web", "api", "chat":
+		return BuildReasonManual
+	case "schedule":
+		return BuildReasonSchedule
+	case "merge_request_event", "external_pull_request_event":
+		return BuildReasonPullRequest
+	case "pipeline", "parent_pipeline", "trigger":
+		return BuildReasonResourceTrigger
+	case "push":
+		return BuildReasonIndividualCI
+	default:
+		return BuildReasonUnknown
+	}
+}
+
+// Branch returns the source branch name, e.g. main. For tag pipelines the tag name is returned.
+func (g *gitlabConfigProvider) Branch() string {
+	if g.IsPullRequest() {
+		return getEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "n/a")
+	}
+	return getEnv("CI_COMMIT_REF_NAME", "n/a")
+}
+
+// GitReference returns the git reference, e.g. refs/heads/main, refs/tags/v1.0.0 or refs/merge-requests/42/head
+func (g *gitlabConfigProvider) GitReference() string {
+	if g.IsPullRequest() {
+		return "refs/merge-requests/" + getEnv("CI_MERGE_REQUEST_IID", "n/a") + "/head"
+	}
+	if tag := getEnv("CI_COMMIT_TAG", ""); len(tag) > 0 {
+		return "refs/tags/" + tag
+	}
+	return "refs/heads/" + getEnv("CI_COMMIT_REF_NAME", "n/a")
+}
+
+// BuildURL returns the URL of the pipeline, e.g. https://gitlab.com/foo/bar/-/pipelines/1234
+func (g *gitlabConfigProvider) BuildURL() string {
+	return getEnv("CI_PIPELINE_URL", "n/a")
+}
+
+// JobURL returns the URL of the pipelines of the project, e.g. https://gitlab.com/foo/bar/-/pipelines
+func (g *gitlabConfigProvider) JobURL() string {
+	return getEnv("CI_PROJECT_URL", "n/a") + "/-/pipelines"
+}
+
+// JobName returns the path of the project, e.g. foo/bar
+func (g *gitlabConfigProvider) JobName() string {
+	return getEnv("CI_PROJECT_PATH", "n/a")
+}
+
+// CommitSHA returns commit SHA of current build
+func (g *gitlabConfigProvider) CommitSHA() string {
+	return getEnv("CI_COMMIT_SHA", "n/a")
+}
+
+// RepoURL returns the URL of the project, e.g. https://gitlab.com/foo/bar
+func (g *gitlabConfigProvider) RepoURL() string {
+	return getEnv("CI_PROJECT_URL", "n/a")
+}
+
+// PullRequestConfig returns the merge request configuration
+func (g *gitlabConfigProvider) PullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: getEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "n/a"),
+		Base:   getEnv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "n/a"),
+		Key:    getEnv("CI_MERGE_REQUEST_IID", "n/a"),
+	}
+}
+
+// IsPullRequest indicates whether the current pipeline is a merge request pipeline
+func (g *gitlabConfigProvider) IsPullRequest() bool {
+	return envVarIsTrue("CI_MERGE_REQUEST_IID")
+}
+
+func isGitLab() bool {
+	envVars := []string{"GITLAB_CI"}
+	return envVarsAreSet(envVars)
+}
+
+// IdentityToken returns the ID token defined via id_tokens as VAULT_ID_TOKEN, the audience is part of the job definition.
+// The deprecated CI_JOB_JWT variables are used as fallback for older GitLab versions.
+func (g *gitlabConfigProvider) IdentityToken(_ string) (string, error) {
+	for _, name := range []string{"VAULT_ID_TOKEN", "CI_JOB_JWT_V2", "CI_JOB_JWT"} {
+		if token := os.Getenv(name); len(token) > 0 {
+			log.RegisterSecret(token)
+			return token, nil
+		}
+	}
+	return "", errors.New("no identity token available, define it via id_tokens as VAULT_ID_TOKEN")
+}
@@ -0,0 +1,227 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGitlabTestProvider() *gitlabConfigProvider {
+	g := newGitlabConfigProvider()
+	g.client.SetOptions(piperhttp.ClientOptions{
+		UseDefaultTransport: true, // need to use default transport for http mock
+		MaxRetries:          -1,
+	})
+	return g
+}
+
+func TestGitLab(t *testing.T) {
+	t.Run("detection", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITLAB_CI", "true")
+
+		assert.Equal(t, GitLab, DetectOrchestrator())
+		assert.Equal(t, "GitLab", DetectOrchestrator().String())
+	})
+
+	t.Run("branch build", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("CI_COMMIT_REF_NAME", "feat/test-gitlab")
+		os.Setenv("CI_COMMIT_SHA", "abcdef42713")
+		os.Setenv("CI_PROJECT_URL", "https://gitlab.com/foo/bar")
+		os.Setenv("CI_PROJECT_PATH", "foo/bar")
+		os.Setenv("CI_PIPELINE_ID", "1234")
+		os.Setenv("CI_PIPELINE_URL", "https://gitlab.com/foo/bar/-/pipelines/1234")
+		os.Setenv("CI_PIPELINE_SOURCE", "push")
+		os.Setenv("CI_PIPELINE_CREATED_AT", "2023-11-06T10:30:31Z")
+		os.Setenv("CI_JOB_STAGE", "Build")
+		os.Setenv("CI_SERVER_VERSION", "16.5.1")
+
+		p := newGitlabConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-gitlab", p.Branch())
+		assert.Equal(t, "refs/heads/feat/test-gitlab", p.GitReference())
+		assert.Equal(t, "https://gitlab.com/foo/bar/-/pipelines/1234", p.BuildURL())
+		assert.Equal(t, "1234", p.BuildID())
+		assert.Equal(t, "abcdef42713", p.CommitSHA())
+		assert.Equal(t, "https://gitlab.com/foo/bar", p.RepoURL())
+		assert.Equal(t, "https://gitlab.com/foo/bar/-/pipelines", p.JobURL())
+		assert.Equal(t, "foo/bar", p.JobName())
+		assert.Equal(t, "Build", p.StageName())
+		assert.Equal(t, BuildReasonIndividualCI, p.BuildReason())
+		assert.Equal(t, "GitLab", p.OrchestratorType())
+		assert.Equal(t, "16.5.1", p.OrchestratorVersion())
+		assert.Equal(t, time.Date(2023, time.November, 6, 10, 30, 31, 0, time.UTC), p.PipelineStartTime())
+	})
+
+	t.Run("tag build", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_COMMIT_REF_NAME", "v1.0.0")
+		os.Setenv("CI_COMMIT_TAG", "v1.0.0")
+
+		p := newGitlabConfigProvider()
+
+		assert.Equal(t, "v1.0.0", p.Branch())
+		assert.Equal(t, "refs/tags/v1.0.0", p.GitReference())
+	})
+
+	t.Run("merge request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_COMMIT_REF_NAME", "feat/test-gitlab")
+		os.Setenv("CI_MERGE_REQUEST_IID", "42")
+		os.Setenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "feat/test-gitlab")
+		os.Setenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "main")
+		os.Setenv("CI_PIPELINE_SOURCE", "merge_request_event")
+
+		p := newGitlabConfigProvider()
+		c := p.PullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-gitlab", p.Branch())
+		assert.Equal(t, "refs/merge-requests/42/head", p.GitReference())
+		assert.Equal(t, BuildReasonPullRequest, p.BuildReason())
+		assert.Equal(t, PullRequestConfig{Branch: "feat/test-gitlab", Base: "main", Key: "42"}, c)
+	})
+}
+
+func TestGitLabConfigProvider_API(t *testing.T) {
+	apiURL := "https://gitlab.com/api/v4/projects/42"
+	setup := func() {
+		os.Clearenv()
+		os.Setenv("CI_API_V4_URL", "https://gitlab.com/api/v4")
+		os.Setenv("CI_PROJECT_ID", "42")
+		os.Setenv("CI_PIPELINE_ID", "1234")
+		os.Setenv("CI_JOB_TOKEN", "jobToken")
+		os.Setenv("CI_COMMIT_SHA", "abc")
+	}
+
+	t.Run("BuildStatus", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		p := newGitlabTestProvider()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/pipelines/1234",
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "jobToken", req.Header.Get("JOB-TOKEN"))
+				return httpmock.NewJsonResponse(200, map[string]interface{}{"status": "running"})
+			})
+
+		assert.Equal(t, BuildStatusInProgress, p.BuildStatus())
+	})
+
+	t.Run("FullLogs", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		p := newGitlabTestProvider()
+		assert.NoError(t, p.Configure(&Options{GitLabToken: "privateToken"}))
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/pipelines/1234/jobs?per_page=100&include_retried=true&page=1",
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "privateToken", req.Header.Get("PRIVATE-TOKEN"))
+				response, err := httpmock.NewJsonResponse(200, []map[string]interface{}{
+					{"id": 3, "name": "test", "status": "running"},
+					{"id": 1, "name": "build", "status": "success"},
+				})
+				response.Header.Set("X-Next-Page", "2")
+				return response, err
+			})
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/pipelines/1234/jobs?per_page=100&include_retried=true&page=2",
+			httpmock.NewJsonResponderOrPanic(200, []map[string]interface{}{
+				{"id": 4, "name": "deploy", "status": "created"},
+				{"id": 2, "name": "lint", "status": "failed"},
+			}))
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/jobs/1/trace", httpmock.NewStringResponder(200, "build log\n"))
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/jobs/2/trace", httpmock.NewStringResponder(200, "lint log\n"))
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/jobs/3/trace", httpmock.NewStringResponder(200, "test log\n"))
+
+		logs, err := p.FullLogs()
+		assert.NoError(t, err)
+		assert.Equal(t, "build log\nlint log\ntest log\n", string(logs))
+	})
+
+	t.Run("FullLogs - error", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		p := newGitlabTestProvider()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/pipelines/1234/jobs?per_page=100&include_retried=true&page=1", httpmock.NewStringResponder(401, ""))
+
+		_, err := p.FullLogs()
+		assert.ErrorContains(t, err, "failed to get jobs of pipeline")
+		assert.ErrorContains(t, err, "returned with response 401")
+	})
+
+	t.Run("ChangeSets - push", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "xyz")
+		p := newGitlabTestProvider()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/repository/compare?from=xyz&to=abc",
+			httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"commits": []map[string]interface{}{{"id": "abc", "committed_date": "2023-11-06T10:30:31Z"}},
+			}))
+
+		assert.Equal(t, []ChangeSet{{CommitId: "abc", Timestamp: "2023-11-06T10:30:31Z"}}, p.ChangeSets())
+	})
+
+	t.Run("ChangeSets - merge request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		os.Setenv("CI_MERGE_REQUEST_IID", "7")
+		p := newGitlabTestProvider()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/merge_requests/7/commits?per_page=100&page=1",
+			func(req *http.Request) (*http.Response, error) {
+				response, err := httpmock.NewJsonResponse(200, []map[string]interface{}{
+					{"id": "abc", "committed_date": "2023-11-06T10:30:31Z"},
+					{"id": "def", "committed_date": "2023-11-05T10:30:31Z"},
+				})
+				response.Header.Set("X-Next-Page", "2")
+				return response, err
+			})
+		httpmock.RegisterResponder(http.MethodGet, apiURL+"/merge_requests/7/commits?per_page=100&page=2",
+			httpmock.NewJsonResponderOrPanic(200, []map[string]interface{}{
+				{"id": "ghi", "committed_date": "2023-11-04T10:30:31Z"},
+			}))
+
+		assert.Equal(t, []ChangeSet{
+			{CommitId: "abc", Timestamp: "2023-11-06T10:30:31Z", PrNumber: 7},
+			{CommitId: "def", Timestamp: "2023-11-05T10:30:31Z", PrNumber: 7},
+			{CommitId: "ghi", Timestamp: "2023-11-04T10:30:31Z", PrNumber: 7},
+		}, p.ChangeSets())
+	})
+
+	t.Run("ChangeSets - new branch", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		setup()
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "0000000000000000000000000000000000000000")
+		p := newGitlabTestProvider()
+
+		assert.Empty(t, p.ChangeSets())
+	})
+}
@@ -1,10 +1,32 @@
 package orchestrator
 
 import (
-	"github.com/SAP/jenkins-library/pkg/log"
+	"bufio"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
 )
 
+// podLabelsFileEnv is the env variable which points to the labels file of a Kubernetes downward API volume
+const podLabelsFileEnv = "PIPER_PODINFO_LABELS"
+
+const defaultPodLabelsFile = "/etc/podinfo/labels"
+
+var (
+	cachedPodLabels map[string]string
+	podLabelsOnce   sync.Once
+)
+
+// logDirectoryEnv is the env variable which points to a mounted directory containing the logs of the pipeline run
+const logDirectoryEnv = "PIPER_LOG_DIR"
+
 // envVarsAreSet verifies if any envvar from the list has nona non-empty, non-false value
 func envVarsAreSet(envVars []string) bool {
 	for _, v := range envVars {
@@ -38,3 +60,102 @@ func getEnv(key, fallback string) string {
 	log.Entry().Debugf("Could not read env variable %v using fallback value %v", key, fallback)
 	return fallback
 }
+
+// podLabels returns the labels of the current pod, they are read only once since they do not change during the run
+func podLabels() map[string]string {
+	podLabelsOnce.Do(func() { cachedPodLabels = readPodLabels() })
+	return cachedPodLabels
+}
+
+// readPodLabels reads the labels of the current pod from a Kubernetes downward API volume.
+// The file contains one label per line in the format key="value". An empty map is returned if the file is not available.
+func readPodLabels() map[string]string {
+	labels := map[string]string{}
+	file, err := os.Open(getEnv(podLabelsFileEnv, defaultPodLabelsFile))
+	if err != nil {
+		log.Entry().Debugf("Pod labels not available: %v", err)
+		return labels
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// getEnvOrLabel reads the env variable and falls back to the pod label if the variable is not set
+func getEnvOrLabel(key, label, fallback string) string {
+	if value, found := os.LookupEnv(key); found {
+		return value
+	}
+	if value, found := podLabels()[label]; found {
+		return value
+	}
+	log.Entry().Debugf("Could not read env variable %v or pod label %v using fallback value %v", key, label, fallback)
+	return fallback
+}
+
+// logsFromDirectory returns the content of all files in the log directory defined via PIPER_LOG_DIR ordered by their path
+func logsFromDirectory() ([]byte, error) {
+	dir, found := os.LookupEnv(logDirectoryEnv)
+	if !found {
+		log.Entry().Debugf("No log directory available, %v is not set", logDirectoryEnv)
+		return []byte{}, nil
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return []byte{}, errors.Wrapf(err, "failed to read log directory %v", dir)
+	}
+	sort.Strings(files)
+
+	var logs []byte
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return []byte{}, errors.Wrapf(err, "failed to read log file %v", file)
+		}
+		logs = append(logs, content...)
+	}
+	return logs, nil
+}
+
+// parseStartTime parses the RFC3339 timestamp of the env variable and returns it in UTC
+func parseStartTime(key string) time.Time {
+	value := getEnv(key, "")
+	if len(value) == 0 {
+		return time.Time{}.UTC()
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Entry().Errorf("could not parse timestamp, %v", err)
+		return time.Time{}.UTC()
+	}
+	return parsed.UTC()
+}
+
+// gitReference returns the git reference of the branch, e.g. refs/heads/main
+func gitReference(branch string) string {
+	if branch == "n/a" {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
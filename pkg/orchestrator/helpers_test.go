@@ -6,6 +6,7 @@ package orchestrator
 import (
 	"github.com/stretchr/testify/assert"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -69,3 +70,18 @@ func Test_getEnv(t *testing.T) {
 		})
 	}
 }
+
+func Test_podLabels(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	ResetConfigProvider()
+	labelsFile := filepath.Join(t.TempDir(), "labels")
+	assert.NoError(t, os.WriteFile(labelsFile, []byte("app=\"piper\"\n"), 0644))
+	os.Setenv("PIPER_PODINFO_LABELS", labelsFile)
+
+	assert.Equal(t, map[string]string{"app": "piper"}, podLabels())
+
+	// the labels are read only once
+	assert.NoError(t, os.Remove(labelsFile))
+	assert.Equal(t, "piper", getEnvOrLabel("APP", "app", "n/a"))
+}
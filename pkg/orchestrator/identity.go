@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// serviceAccountTokenFileEnv is the env variable which points to a projected Kubernetes service account token
+const serviceAccountTokenFileEnv = "PIPER_serviceAccountTokenFile"
+
+const defaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// requestIdentityToken requests an OIDC token from the token endpoint of the orchestrator
+// and returns the value of the given field of the JSON response.
+func requestIdentityToken(method, url, bearerToken, field string) (string, error) {
+	client := piperHttp.Client{}
+	client.SetOptions(piperHttp.ClientOptions{
+		Token:            "Bearer " + bearerToken,
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	})
+	response, err := client.SendRequest(method, url, nil, http.Header{"Accept": []string{"application/json"}}, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request identity token")
+	}
+	defer response.Body.Close()
+
+	result := map[string]interface{}{}
+	if err := piperHttp.ParseHTTPResponseBodyJSON(response, &result); err != nil {
+		return "", errors.Wrap(err, "failed to parse identity token response")
+	}
+	token, ok := result[field].(string)
+	if !ok || len(token) == 0 {
+		return "", fmt.Errorf("identity token response does not contain the field '%v'", field)
+	}
+	log.RegisterSecret(token)
+	return token, nil
+}
+
+// serviceAccountToken reads the token of the Kubernetes service account the step container is running with.
+// A token with a dedicated audience can be provided as projected volume, see PIPER_serviceAccountTokenFile.
+func serviceAccountToken() (string, error) {
+	file := getEnv(serviceAccountTokenFileEnv, defaultServiceAccountTokenFile)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read service account token %v", file)
+	}
+	token := strings.TrimSpace(string(content))
+	if len(token) == 0 {
+		return "", fmt.Errorf("service account token %v is empty", file)
+	}
+	log.RegisterSecret(token)
+	return token, nil
+}
@@ -0,0 +1,131 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityToken(t *testing.T) {
+	t.Run("GitHub Actions", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "Bearer request-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "https://vault.example.com", r.URL.Query().Get("audience"))
+			fmt.Fprint(w, `{"count": 1, "value": "github-jwt"}`)
+		}))
+		defer server.Close()
+		os.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token?api-version=2.0")
+		os.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+		token, err := newGithubActionsConfigProvider().IdentityToken("https://vault.example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "github-jwt", token)
+	})
+
+	t.Run("GitHub Actions - missing permission", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		_, err := newGithubActionsConfigProvider().IdentityToken("")
+
+		assert.EqualError(t, err, "no identity token available, the workflow requires the permission 'id-token: write'")
+	})
+
+	t.Run("Azure DevOps", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "1234-abcd", r.URL.Query().Get("serviceConnectionId"))
+			fmt.Fprint(w, `{"oidcToken": "azure-jwt"}`)
+		}))
+		defer server.Close()
+		os.Setenv("SYSTEM_OIDCREQUESTURI", server.URL+"/oidctoken")
+		os.Setenv("SYSTEM_ACCESSTOKEN", "access-token")
+		os.Setenv("AZURESUBSCRIPTION_SERVICE_CONNECTION_ID", "1234-abcd")
+
+		token, err := newAzureDevopsConfigProvider().IdentityToken("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "azure-jwt", token)
+	})
+
+	t.Run("Azure DevOps - invalid response", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{}`)
+		}))
+		defer server.Close()
+		os.Setenv("SYSTEM_OIDCREQUESTURI", server.URL)
+		os.Setenv("SYSTEM_ACCESSTOKEN", "access-token")
+		os.Setenv("AZURESUBSCRIPTION_SERVICE_CONNECTION_ID", "1234-abcd")
+
+		_, err := newAzureDevopsConfigProvider().IdentityToken("")
+
+		assert.EqualError(t, err, "identity token response does not contain the field 'oidcToken'")
+	})
+
+	t.Run("GitLab", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_JOB_JWT", "gitlab-v1-jwt")
+		os.Setenv("VAULT_ID_TOKEN", "gitlab-jwt")
+
+		token, err := newGitlabConfigProvider().IdentityToken("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gitlab-jwt", token)
+	})
+
+	t.Run("GitLab - no token", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		_, err := newGitlabConfigProvider().IdentityToken("")
+
+		assert.EqualError(t, err, "no identity token available, define it via id_tokens as VAULT_ID_TOKEN")
+	})
+
+	t.Run("Tekton", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		assert.NoError(t, os.WriteFile(tokenFile, []byte("k8s-jwt\n"), 0600))
+		os.Setenv("PIPER_serviceAccountTokenFile", tokenFile)
+
+		token, err := newTektonConfigProvider().IdentityToken("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "k8s-jwt", token)
+	})
+
+	t.Run("Argo Workflows - no service account token", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_serviceAccountTokenFile", filepath.Join(t.TempDir(), "token"))
+
+		_, err := newArgoWorkflowsConfigProvider().IdentityToken("")
+
+		assert.ErrorContains(t, err, "failed to read service account token")
+	})
+
+	t.Run("Jenkins", func(t *testing.T) {
+		_, err := newJenkinsConfigProvider().IdentityToken("")
+
+		assert.EqualError(t, err, "identity tokens are not supported for Jenkins")
+	})
+}
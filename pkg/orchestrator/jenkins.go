@@ -287,3 +287,8 @@ func isJenkins() bool {
 	envVars := []string{"JENKINS_HOME", "JENKINS_URL"}
 	return envVarsAreSet(envVars)
 }
+
+// IdentityToken is not supported for Jenkins
+func (j *jenkinsConfigProvider) IdentityToken(_ string) (string, error) {
+	return "", errors.New("identity tokens are not supported for Jenkins")
+}
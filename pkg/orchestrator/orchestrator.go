@@ -12,6 +12,9 @@ const (
 	AzureDevOps
 	GitHubActions
 	Jenkins
+	GitLab
+	Tekton
+	ArgoWorkflows
 )
 
 const (
@@ -53,6 +56,9 @@ type ConfigProvider interface {
 	FullLogs() ([]byte, error)
 	PipelineStartTime() time.Time
 	ChangeSets() []ChangeSet
+	// IdentityToken returns an OIDC token issued by the orchestrator for the current run, which allows
+	// to authenticate against e.g. Vault without stored credentials
+	IdentityToken(audience string) (string, error)
 }
 
 type (
@@ -64,6 +70,7 @@ type (
 		JenkinsToken string
 		AzureToken   string
 		GitHubToken  string
+		GitLabToken  string
 	}
 
 	PullRequestConfig struct {
@@ -89,9 +96,15 @@ func GetOrchestratorConfigProvider(opts *Options) (ConfigProvider, error) {
 			provider = newGithubActionsConfigProvider()
 		case Jenkins:
 			provider = newJenkinsConfigProvider()
+		case GitLab:
+			provider = newGitlabConfigProvider()
+		case Tekton:
+			provider = newTektonConfigProvider()
+		case ArgoWorkflows:
+			provider = newArgoWorkflowsConfigProvider()
 		default:
 			provider = newUnknownOrchestratorConfigProvider()
-			err = errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins)")
+			err = errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins, GitLab, Tekton, Argo Workflows)")
 		}
 	})
 	if err != nil {
@@ -119,13 +132,19 @@ func DetectOrchestrator() Orchestrator {
 		return GitHubActions
 	} else if isJenkins() {
 		return Jenkins
+	} else if isGitLab() {
+		return GitLab
+	} else if isTekton() {
+		return Tekton
+	} else if isArgoWorkflows() {
+		return ArgoWorkflows
 	} else {
 		return Unknown
 	}
 }
 
 func (o Orchestrator) String() string {
-	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins"}[o]
+	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins", "GitLab", "Tekton", "ArgoWorkflows"}[o]
 }
 
 // ResetConfigProvider is intended to be used only for unit tests because some of these tests
@@ -134,4 +153,6 @@ func (o Orchestrator) String() string {
 func ResetConfigProvider() {
 	provider = nil
 	providerOnce = sync.Once{}
+	cachedPodLabels = nil
+	podLabelsOnce = sync.Once{}
 }
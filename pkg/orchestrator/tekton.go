@@ -0,0 +1,164 @@
+package orchestrator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// tektonConfigProvider reads the pipeline run information which is exposed to the step container.
+// Tekton does not provide env variables itself, thus they need to be defined via the downward API, e.g.
+//
+//	env:
+//	  - name: TEKTON_PIPELINE_RUN
+//	    valueFrom:
+//	      fieldRef:
+//	        fieldPath: metadata.labels['tekton.dev/pipelineRun']
+//
+// Alternatively the pod labels can be mounted as downward API volume, see PIPER_PODINFO_LABELS.
+type tektonConfigProvider struct{}
+
+func newTektonConfigProvider() *tektonConfigProvider {
+	return &tektonConfigProvider{}
+}
+
+// Configure is not required for Tekton
+func (t *tektonConfigProvider) Configure(_ *Options) error {
+	log.Entry().Debug("Successfully initialized Tekton config provider")
+	return nil
+}
+
+func (t *tektonConfigProvider) OrchestratorVersion() string {
+	log.Entry().Debugf("OrchestratorVersion() for Tekton is not applicable.")
+	return "n/a"
+}
+
+// OrchestratorType returns the orchestrator type Tekton
+func (t *tektonConfigProvider) OrchestratorType() string {
+	return "Tekton"
+}
+
+// BuildStatus returns the aggregated status of the pipeline tasks, e.g. provided via $(tasks.status) in a finally task.
+func (t *tektonConfigProvider) BuildStatus() string {
+	switch getEnv("TEKTON_PIPELINE_STATUS", "") {
+	case "Succeeded", "Completed":
+		return BuildStatusSuccess
+	case "Failed":
+		return BuildStatusFailure
+	default:
+		return BuildStatusInProgress
+	}
+}
+
+// FullLogs returns the logs from the mounted log directory
+func (t *tektonConfigProvider) FullLogs() ([]byte, error) {
+	return logsFromDirectory()
+}
+
+// PipelineStartTime returns the pipeline start time in UTC if provided via TEKTON_PIPELINE_RUN_START_TIME
+func (t *tektonConfigProvider) PipelineStartTime() time.Time {
+	return parseStartTime("TEKTON_PIPELINE_RUN_START_TIME")
+}
+
+// BuildID returns the name of the PipelineRun or of the TaskRun if the task is not executed as part of a pipeline
+func (t *tektonConfigProvider) BuildID() string {
+	if pipelineRun := t.pipelineRun(); pipelineRun != "n/a" {
+		return pipelineRun
+	}
+	return getEnvOrLabel("TEKTON_TASK_RUN", "tekton.dev/taskRun", "n/a")
+}
+
+func (t *tektonConfigProvider) ChangeSets() []ChangeSet {
+	log.Entry().Debug("ChangeSets for Tekton not implemented")
+	return []ChangeSet{}
+}
+
+// StageName returns the name of the task within the pipeline, e.g. Build
+func (t *tektonConfigProvider) StageName() string {
+	return getEnvOrLabel("TEKTON_PIPELINE_TASK", "tekton.dev/pipelineTask", "n/a")
+}
+
+func (t *tektonConfigProvider) BuildReason() string {
+	log.Entry().Debug("BuildReason for Tekton not implemented")
+	return BuildReasonUnknown
+}
+
+// Branch returns the branch provided via GIT_BRANCH, e.g. main
+func (t *tektonConfigProvider) Branch() string {
+	return getEnv("GIT_BRANCH", "n/a")
+}
+
+// GitReference returns the git reference of the branch, e.g. refs/heads/main
+func (t *tektonConfigProvider) GitReference() string {
+	return gitReference(t.Branch())
+}
+
+// BuildURL returns the URL of the PipelineRun in the Tekton dashboard, e.g.
+// https://tekton.example.com/#/namespaces/ci/pipelineruns/build-run-x7k2p
+func (t *tektonConfigProvider) BuildURL() string {
+	dashboardURL := getEnv("TEKTON_DASHBOARD_URL", "")
+	if len(dashboardURL) == 0 {
+		return "n/a"
+	}
+	resource := "pipelineruns"
+	if t.pipelineRun() == "n/a" {
+		resource = "taskruns"
+	}
+	return strings.TrimSuffix(dashboardURL, "/") + "/#/namespaces/" + getEnv("TEKTON_NAMESPACE", "default") + "/" + resource + "/" + t.BuildID()
+}
+
+// JobURL returns the URL of the Pipeline in the Tekton dashboard
+func (t *tektonConfigProvider) JobURL() string {
+	dashboardURL := getEnv("TEKTON_DASHBOARD_URL", "")
+	if len(dashboardURL) == 0 {
+		return "n/a"
+	}
+	return strings.TrimSuffix(dashboardURL, "/") + "/#/namespaces/" + getEnv("TEKTON_NAMESPACE", "default") + "/pipelines/" + t.JobName()
+}
+
+// JobName returns the name of the Pipeline, e.g. build-pipeline
+func (t *tektonConfigProvider) JobName() string {
+	return getEnvOrLabel("TEKTON_PIPELINE", "tekton.dev/pipeline", "n/a")
+}
+
+// CommitSHA returns the commit SHA provided via GIT_COMMIT
+func (t *tektonConfigProvider) CommitSHA() string {
+	return getEnv("GIT_COMMIT", "n/a")
+}
+
+// RepoURL returns the repository URL provided via GIT_URL
+func (t *tektonConfigProvider) RepoURL() string {
+	return getEnv("GIT_URL", "n/a")
+}
+
+func (t *tektonConfigProvider) PullRequestConfig() PullRequestConfig {
+	log.Entry().Debug("PullRequestConfig for Tekton not implemented")
+	return PullRequestConfig{
+		Branch: "n/a",
+		Base:   "n/a",
+		Key:    "n/a",
+	}
+}
+
+func (t *tektonConfigProvider) IsPullRequest() bool {
+	return false
+}
+
+func (t *tektonConfigProvider) pipelineRun() string {
+	return getEnvOrLabel("TEKTON_PIPELINE_RUN", "tekton.dev/pipelineRun", "n/a")
+}
+
+func isTekton() bool {
+	envVars := []string{"TEKTON_PIPELINE_RUN", "TEKTON_TASK_RUN"}
+	if envVarsAreSet(envVars) {
+		return true
+	}
+	_, found := podLabels()["tekton.dev/taskRun"]
+	return found
+}
+
+// IdentityToken returns the token of the Kubernetes service account, the audience is defined by the projected token
+func (t *tektonConfigProvider) IdentityToken(_ string) (string, error) {
+	return serviceAccountToken()
+}
@@ -0,0 +1,108 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTekton(t *testing.T) {
+	t.Run("detection", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-run-x7k2p")
+
+		assert.Equal(t, Tekton, DetectOrchestrator())
+		assert.Equal(t, "Tekton", DetectOrchestrator().String())
+	})
+
+	t.Run("detection via pod labels", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		labelsFile := filepath.Join(t.TempDir(), "labels")
+		assert.NoError(t, os.WriteFile(labelsFile, []byte("app=\"piper\"\ntekton.dev/taskRun=\"build-run-x7k2p-build\"\n"), 0644))
+		os.Setenv("PIPER_PODINFO_LABELS", labelsFile)
+		ResetConfigProvider()
+
+		assert.Equal(t, Tekton, DetectOrchestrator())
+	})
+
+	t.Run("env variables", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-run-x7k2p")
+		os.Setenv("TEKTON_PIPELINE_TASK", "Build")
+		os.Setenv("TEKTON_PIPELINE", "build-pipeline")
+		os.Setenv("TEKTON_NAMESPACE", "ci")
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com/")
+		os.Setenv("TEKTON_PIPELINE_STATUS", "Failed")
+		os.Setenv("TEKTON_PIPELINE_RUN_START_TIME", "2023-11-06T10:30:31Z")
+		os.Setenv("GIT_BRANCH", "main")
+		os.Setenv("GIT_COMMIT", "abcdef42713")
+		os.Setenv("GIT_URL", "https://github.com/foo/bar")
+
+		p := newTektonConfigProvider()
+
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "build-run-x7k2p", p.BuildID())
+		assert.Equal(t, "Build", p.StageName())
+		assert.Equal(t, "build-pipeline", p.JobName())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-run-x7k2p", p.BuildURL())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelines/build-pipeline", p.JobURL())
+		assert.Equal(t, BuildStatusFailure, p.BuildStatus())
+		assert.Equal(t, "main", p.Branch())
+		assert.Equal(t, "refs/heads/main", p.GitReference())
+		assert.Equal(t, "abcdef42713", p.CommitSHA())
+		assert.Equal(t, "https://github.com/foo/bar", p.RepoURL())
+		assert.Equal(t, "Tekton", p.OrchestratorType())
+		assert.Equal(t, time.Date(2023, time.November, 6, 10, 30, 31, 0, time.UTC), p.PipelineStartTime())
+	})
+
+	t.Run("pod labels", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		labelsFile := filepath.Join(t.TempDir(), "labels")
+		assert.NoError(t, os.WriteFile(labelsFile, []byte("tekton.dev/pipeline=\"build-pipeline\"\ntekton.dev/pipelineTask=\"Build\"\ntekton.dev/taskRun=\"build-run-x7k2p-build\"\n"), 0644))
+		os.Setenv("PIPER_PODINFO_LABELS", labelsFile)
+		ResetConfigProvider()
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com")
+
+		p := newTektonConfigProvider()
+
+		assert.Equal(t, "build-run-x7k2p-build", p.BuildID())
+		assert.Equal(t, "Build", p.StageName())
+		assert.Equal(t, "build-pipeline", p.JobName())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/default/taskruns/build-run-x7k2p-build", p.BuildURL())
+		assert.Equal(t, BuildStatusInProgress, p.BuildStatus())
+		assert.Equal(t, "n/a", p.GitReference())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		logDir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(logDir, "2-test"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(logDir, "1-build.log"), []byte("build log\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(logDir, "2-test", "step.log"), []byte("test log\n"), 0644))
+		os.Setenv("PIPER_LOG_DIR", logDir)
+
+		logs, err := newTektonConfigProvider().FullLogs()
+		assert.NoError(t, err)
+		assert.Equal(t, "build log\ntest log\n", string(logs))
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		logs, err := newTektonConfigProvider().FullLogs()
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+}
@@ -13,4 +13,6 @@ func resetEnv(e []string) {
 		tmp := strings.Split(val, "=")
 		os.Setenv(tmp[0], tmp[1])
 	}
+	// the pod labels are cached and need to be read again with the restored environment
+	ResetConfigProvider()
 }
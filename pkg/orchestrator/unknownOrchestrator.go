@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"errors"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -112,3 +113,8 @@ func (u *UnknownOrchestratorConfigProvider) IsPullRequest() bool {
 	log.Entry().Warning(unknownOrchestratorWarning)
 	return false
 }
+
+func (u *UnknownOrchestratorConfigProvider) IdentityToken(_ string) (string, error) {
+	log.Entry().Warning(unknownOrchestratorWarning)
+	return "", errors.New("identity tokens are not supported for unknown orchestrators")
+}
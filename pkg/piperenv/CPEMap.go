@@ -81,6 +81,9 @@ func dirToMap(m map[string]interface{}, dirPath, prefix string) error {
 			}
 			continue
 		}
+		if dirItem.Name() == JournalFile {
+			continue
+		}
 		// load file content and unmarshal it if needed
 		mapKey, value, toBeEmptied, err := readFileContent(path.Join(dirPath, dirItem.Name()))
 		if err != nil {
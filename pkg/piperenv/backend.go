@@ -0,0 +1,240 @@
+package piperenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// ErrRevisionConflict is returned by Backend.Store if the stored environment has been changed since it was loaded
+var ErrRevisionConflict = errors.New("the common pipeline environment has been changed concurrently")
+
+// Backend stores the common pipeline environment outside of the workspace, e.g. to share it between agents
+type Backend interface {
+	// Load returns the stored environment and its revision. The revision is empty if nothing has been stored yet.
+	Load() (CPEMap, string, error)
+	// Store writes the environment if the stored revision still matches the given one and returns the new revision.
+	// ErrRevisionConflict is returned if the revision does not match.
+	Store(cpe CPEMap, revision string) (string, error)
+}
+
+// Revision calculates a revision based on the content of the environment
+func Revision(cpe CPEMap) (string, error) {
+	if len(cpe) == 0 {
+		return "", nil
+	}
+	content, err := json.Marshal(cpe)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal common pipeline environment: %w", err)
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// FileSystemBackend stores the common pipeline environment in a directory, e.g. on a volume shared between agents
+type FileSystemBackend struct {
+	Path string
+	// LockTimeout defines how long Store waits for a concurrent write to finish
+	LockTimeout time.Duration
+}
+
+// Load reads the environment from the directory
+func (b *FileSystemBackend) Load() (CPEMap, string, error) {
+	cpe := CPEMap{}
+	if err := cpe.LoadFromDisk(b.Path); err != nil {
+		return nil, "", err
+	}
+	revision, err := Revision(cpe)
+	return cpe, revision, err
+}
+
+// Store writes the environment to the directory. A lock file guarantees that the revision check and the write happen atomically.
+func (b *FileSystemBackend) Store(cpe CPEMap, revision string) (string, error) {
+	unlock, err := b.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	current, currentRevision, err := b.Load()
+	if err != nil {
+		return "", err
+	}
+	if currentRevision != revision {
+		return "", ErrRevisionConflict
+	}
+	if err := removeFromDisk(b.Path, removedKeys(current, cpe)); err != nil {
+		return "", err
+	}
+	if err := cpe.WriteToDisk(b.Path); err != nil {
+		return "", err
+	}
+	_, newRevision, err := b.Load()
+	return newRevision, err
+}
+
+func (b *FileSystemBackend) lock() (func(), error) {
+	lockFile := b.Path + ".lock"
+	if err := os.MkdirAll(b.Path, 0777); err != nil {
+		return nil, err
+	}
+	timeout := b.LockTimeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		file, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockFile) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %v: %w", b.Path, err)
+		}
+		if stat, err := os.Stat(lockFile); err == nil && time.Since(stat.ModTime()) > timeout {
+			// the writer holding the lock did not finish in time, e.g. since its pod has been terminated
+			log.Entry().Warnf("Removing stale lock file %v", lockFile)
+			os.Remove(lockFile)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock %v: lock file %v exists", b.Path, lockFile)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// RemoteEnvironment keeps the common pipeline environment of the workspace in sync with a backend
+type RemoteEnvironment struct {
+	backend    Backend
+	path       string
+	base       CPEMap
+	revision   string
+	maxRetries int
+}
+
+// NewRemoteEnvironment creates a RemoteEnvironment for the common pipeline environment located at path
+func NewRemoteEnvironment(backend Backend, path string) *RemoteEnvironment {
+	return &RemoteEnvironment{backend: backend, path: path, base: CPEMap{}, maxRetries: 5}
+}
+
+// Pull loads the environment from the backend and writes it into the workspace
+func (r *RemoteEnvironment) Pull() error {
+	cpe, revision, err := r.backend.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load common pipeline environment: %w", err)
+	}
+	if err := cpe.WriteToDisk(r.path); err != nil {
+		return fmt.Errorf("failed to write common pipeline environment: %w", err)
+	}
+	r.base = cpe
+	r.revision = revision
+	log.Entry().Debugf("Pulled %v values of the common pipeline environment", len(cpe))
+	return nil
+}
+
+// Push stores the values changed or removed in the workspace since the last pull in the backend.
+// If the backend has been changed concurrently, the changes are applied on top of the current state of the backend.
+func (r *RemoteEnvironment) Push() error {
+	local := CPEMap{}
+	if err := local.LoadFromDisk(r.path); err != nil {
+		return fmt.Errorf("failed to read common pipeline environment: %w", err)
+	}
+	changed := changesSince(r.base, local)
+	removed := removedKeys(r.base, local)
+	if len(changed) == 0 && len(removed) == 0 {
+		log.Entry().Debug("No changes of the common pipeline environment to push")
+		return nil
+	}
+
+	merged := local
+	revision := r.revision
+	for attempt := 0; ; attempt++ {
+		newRevision, err := r.backend.Store(merged, revision)
+		if err == nil {
+			revision = newRevision
+			break
+		}
+		if !errors.Is(err, ErrRevisionConflict) || attempt >= r.maxRetries {
+			return fmt.Errorf("failed to store common pipeline environment: %w", err)
+		}
+		log.Entry().Debug("Common pipeline environment has been changed concurrently, merging changes")
+		var current CPEMap
+		current, revision, err = r.backend.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load common pipeline environment: %w", err)
+		}
+		merged = applyChanges(current, changed, removed)
+	}
+
+	// values removed concurrently by other agents are removed from the workspace as well
+	if err := removeFromDisk(r.path, removedKeys(local, merged)); err != nil {
+		return fmt.Errorf("failed to write common pipeline environment: %w", err)
+	}
+	if err := merged.WriteToDisk(r.path); err != nil {
+		return fmt.Errorf("failed to write common pipeline environment: %w", err)
+	}
+	r.base = merged
+	r.revision = revision
+	log.Entry().Debugf("Pushed %v changed and %v removed values of the common pipeline environment", len(changed), len(removed))
+	return nil
+}
+
+// changesSince returns the values which have been added or changed compared to base
+func changesSince(base, current CPEMap) CPEMap {
+	changed := CPEMap{}
+	for key, value := range current {
+		if baseValue, ok := base[key]; !ok || !reflect.DeepEqual(baseValue, value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// removedKeys returns the keys of base which do not exist in current anymore
+func removedKeys(base, current CPEMap) []string {
+	removed := []string{}
+	for key := range base {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+func applyChanges(cpe, changed CPEMap, removed []string) CPEMap {
+	result := CPEMap{}
+	for key, value := range cpe {
+		result[key] = value
+	}
+	for key, value := range changed {
+		result[key] = value
+	}
+	for _, key := range removed {
+		delete(result, key)
+	}
+	return result
+}
+
+// removeFromDisk removes the files of the keys regardless whether they are stored as plain string or JSON encoded
+func removeFromDisk(rootDirectory string, keys []string) error {
+	for _, key := range keys {
+		for _, file := range []string{key, key + ".json"} {
+			if err := os.Remove(filepath.Join(rootDirectory, file)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %v: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
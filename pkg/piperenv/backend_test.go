@@ -0,0 +1,158 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystemBackend(t *testing.T) {
+	backend := &FileSystemBackend{Path: filepath.Join(t.TempDir(), "shared"), LockTimeout: time.Second}
+
+	cpe, revision, err := backend.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cpe)
+	assert.Empty(t, revision)
+
+	revision, err = backend.Store(CPEMap{"artifactVersion": "1.0.0"}, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, revision)
+
+	_, err = backend.Store(CPEMap{"artifactVersion": "1.0.1"}, "")
+	assert.ErrorIs(t, err, ErrRevisionConflict)
+
+	newRevision, err := backend.Store(CPEMap{"artifactVersion": "1.0.1"}, revision)
+	require.NoError(t, err)
+	assert.NotEqual(t, revision, newRevision)
+
+	cpe, loadedRevision, err := backend.Load()
+	require.NoError(t, err)
+	assert.Equal(t, CPEMap{"artifactVersion": "1.0.1"}, cpe)
+	assert.Equal(t, newRevision, loadedRevision)
+
+	t.Run("removed value", func(t *testing.T) {
+		_, revision, err := backend.Load()
+		require.NoError(t, err)
+		revision, err = backend.Store(CPEMap{"artifactVersion": "1.0.1", "custom/list": []interface{}{"a"}}, revision)
+		require.NoError(t, err)
+
+		_, err = backend.Store(CPEMap{"artifactVersion": "1.0.1"}, revision)
+		require.NoError(t, err)
+
+		cpe, _, err := backend.Load()
+		require.NoError(t, err)
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.1"}, cpe)
+		assert.NoFileExists(t, filepath.Join(backend.Path, "custom", "list.json"))
+		_, newRevision, err = backend.Load()
+		require.NoError(t, err)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(backend.Path+".lock", []byte{}, 0666))
+		defer os.Remove(backend.Path + ".lock")
+		backend.LockTimeout = 200 * time.Millisecond
+		_, err := backend.Store(CPEMap{"artifactVersion": "1.0.2"}, newRevision)
+		assert.NoError(t, err, "stale lock is removed")
+	})
+}
+
+type backendMock struct {
+	cpe       CPEMap
+	revision  int
+	conflicts int
+}
+
+func (b *backendMock) Load() (CPEMap, string, error) {
+	revision, _ := Revision(b.cpe)
+	return applyChanges(b.cpe, CPEMap{}, nil), revision, nil
+}
+
+func (b *backendMock) Store(cpe CPEMap, revision string) (string, error) {
+	if b.conflicts > 0 {
+		// simulate a concurrent write of another agent
+		b.conflicts--
+		b.cpe["custom/parallel"] = "written concurrently"
+		return "", ErrRevisionConflict
+	}
+	if current, _ := Revision(b.cpe); current != revision {
+		return "", ErrRevisionConflict
+	}
+	b.cpe = applyChanges(cpe, CPEMap{}, nil)
+	return Revision(b.cpe)
+}
+
+func TestRemoteEnvironment(t *testing.T) {
+	t.Run("pull and push", func(t *testing.T) {
+		path := t.TempDir()
+		backend := &backendMock{cpe: CPEMap{"artifactVersion": "1.0.0"}}
+		remote := NewRemoteEnvironment(backend, path)
+
+		require.NoError(t, remote.Pull())
+		assert.Equal(t, "1.0.0", GetResourceParameter(filepath.Dir(path), filepath.Base(path), "artifactVersion"))
+
+		// nothing changed
+		require.NoError(t, remote.Push())
+
+		require.NoError(t, SetResourceParameter(filepath.Dir(path), filepath.Base(path), "git/commitId", "abc"))
+		require.NoError(t, remote.Push())
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.0", "git/commitId": "abc"}, backend.cpe)
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		path := t.TempDir()
+		backend := &backendMock{cpe: CPEMap{"artifactVersion": "1.0.0"}}
+		remote := NewRemoteEnvironment(backend, path)
+		require.NoError(t, remote.Pull())
+
+		backend.conflicts = 2
+		require.NoError(t, SetResourceParameter(filepath.Dir(path), filepath.Base(path), "artifactVersion", "1.0.1"))
+		require.NoError(t, remote.Push())
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.1", "custom/parallel": "written concurrently"}, backend.cpe)
+
+		// the workspace contains the merged state
+		local := CPEMap{}
+		require.NoError(t, local.LoadFromDisk(path))
+		assert.Equal(t, backend.cpe, local)
+	})
+
+	t.Run("removed value", func(t *testing.T) {
+		path := t.TempDir()
+		backend := &backendMock{cpe: CPEMap{"artifactVersion": "1.0.0", "custom/obsolete": "x"}}
+		remote := NewRemoteEnvironment(backend, path)
+		require.NoError(t, remote.Pull())
+
+		require.NoError(t, os.Remove(filepath.Join(path, "custom", "obsolete")))
+		require.NoError(t, remote.Push())
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.0"}, backend.cpe)
+	})
+
+	t.Run("removed value with concurrent change", func(t *testing.T) {
+		path := t.TempDir()
+		backend := &backendMock{cpe: CPEMap{"artifactVersion": "1.0.0", "custom/obsolete": "x"}}
+		remote := NewRemoteEnvironment(backend, path)
+		require.NoError(t, remote.Pull())
+
+		backend.conflicts = 1
+		require.NoError(t, os.Remove(filepath.Join(path, "custom", "obsolete")))
+		require.NoError(t, remote.Push())
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.0", "custom/parallel": "written concurrently"}, backend.cpe)
+	})
+
+	t.Run("too many conflicts", func(t *testing.T) {
+		path := t.TempDir()
+		backend := &backendMock{cpe: CPEMap{}}
+		remote := NewRemoteEnvironment(backend, path)
+		require.NoError(t, remote.Pull())
+
+		backend.conflicts = 10
+		require.NoError(t, SetResourceParameter(filepath.Dir(path), filepath.Base(path), "artifactVersion", "1.0.1"))
+		assert.ErrorIs(t, remote.Push(), ErrRevisionConflict)
+	})
+}
@@ -0,0 +1,186 @@
+package piperenv
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// EncryptedSuffix is appended to the file name of sensitive values, which are stored encrypted
+const EncryptedSuffix = ".enc"
+
+var (
+	encryptionMutex       sync.Mutex
+	encryptionKeyProvider func() (string, error)
+	encryptionKey         []byte
+)
+
+// errNoEncryptionKey indicates that no secret is provided, in this case sensitive values are stored in plaintext
+var errNoEncryptionKey = errors.New("no encryption key available for sensitive values")
+
+// SetEncryptionKeyProvider sets the function providing the secret used to encrypt the sensitive values of the common pipeline environment.
+// The provider is only called once a sensitive value is written or read, all values of a pipeline run need to use the same secret.
+// If the provider returns an empty secret, sensitive values are stored in plaintext.
+func SetEncryptionKeyProvider(provider func() (string, error)) {
+	encryptionMutex.Lock()
+	defer encryptionMutex.Unlock()
+	encryptionKeyProvider = provider
+	encryptionKey = nil
+}
+
+func getEncryptionKey() ([]byte, error) {
+	encryptionMutex.Lock()
+	defer encryptionMutex.Unlock()
+	if encryptionKey != nil {
+		return encryptionKey, nil
+	}
+	if encryptionKeyProvider == nil {
+		return nil, errNoEncryptionKey
+	}
+	secret, err := encryptionKeyProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve encryption key for sensitive values: %w", err)
+	}
+	if len(secret) == 0 {
+		return nil, errNoEncryptionKey
+	}
+	// use SHA256 as key, like for the encryption of the whole environment by readPipelineEnv
+	key := sha256.Sum256([]byte(secret))
+	encryptionKey = key[:]
+	return encryptionKey, nil
+}
+
+// encryptValue encrypts the content using AES-GCM, the result is base64 encoded
+func encryptValue(content []byte) ([]byte, error) {
+	key, err := getEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to init nonce: %w", err)
+	}
+	cipherText := gcm.Seal(nonce, nonce, content, nil)
+	return []byte(base64.StdEncoding.EncodeToString(cipherText)), nil
+}
+
+// decryptValue decrypts a value encrypted by encryptValue
+func decryptValue(encrypted []byte) ([]byte, error) {
+	key, err := getEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encrypted)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sensitive value: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(cipherText) < gcm.NonceSize() {
+		return nil, fmt.Errorf("failed to decrypt sensitive value: invalid cipher text")
+	}
+	content, err := gcm.Open(nil, cipherText[:gcm.NonceSize()], cipherText[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sensitive value, the encryption key of the pipeline run is required: %w", err)
+	}
+	return content, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Decrypt returns a copy of the map in which the encrypted sensitive values are replaced by their decrypted values.
+// The decrypted values are registered as secrets in order to mask them in the log.
+func (c CPEMap) Decrypt() (CPEMap, error) {
+	result := CPEMap{}
+	for key, value := range c {
+		encrypted, isString := value.(string)
+		if !strings.HasSuffix(key, EncryptedSuffix) || !isString {
+			result[key] = value
+			continue
+		}
+		content, err := decryptValue([]byte(encrypted))
+		if errors.Is(err, errNoEncryptionKey) {
+			log.Entry().Warnf("No encryption key available, the sensitive value of %v is omitted", strings.TrimSuffix(key, EncryptedSuffix))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", strings.TrimSuffix(key, EncryptedSuffix), err)
+		}
+		plainKey := strings.TrimSuffix(key, EncryptedSuffix)
+		if strings.HasSuffix(plainKey, ".json") {
+			var decoded interface{}
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.UseNumber()
+			if err := decoder.Decode(&decoded); err != nil {
+				return nil, fmt.Errorf("%v: failed to unmarshal sensitive value: %w", plainKey, err)
+			}
+			result[strings.TrimSuffix(plainKey, ".json")] = decoded
+		} else {
+			result[plainKey] = string(content)
+		}
+		log.RegisterSecret(string(content))
+	}
+	return result, nil
+}
+
+// EncryptSensitive returns a copy of the map in which the values of the keys declared as sensitive in the schema are encrypted
+func (c CPEMap) EncryptSensitive(schema CPESchema) (CPEMap, error) {
+	result := CPEMap{}
+	for key, value := range c {
+		if !schema[key].Sensitive {
+			result[key] = value
+			continue
+		}
+		content, encryptedKey, err := storedContent(key, value)
+		if err != nil {
+			return nil, err
+		}
+		log.RegisterSecret(string(content))
+		encrypted, err := encryptValue(content)
+		if errors.Is(err, errNoEncryptionKey) {
+			log.Entry().Warnf("No encryption key available, the sensitive value of %v is stored in plaintext", key)
+			result[key] = value
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", key, err)
+		}
+		result[encryptedKey+EncryptedSuffix] = string(encrypted)
+	}
+	return result, nil
+}
+
+// storedContent returns the content and the file name of a value like it is stored on disk
+func storedContent(key string, value interface{}) ([]byte, string, error) {
+	if stringValue, ok := value.(string); ok {
+		return []byte(stringValue), key, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal value of %v: %w", key, err)
+	}
+	return content, key + ".json", nil
+}
@@ -0,0 +1,143 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSensitiveSchema(t *testing.T, secret string) {
+	schema := CPESchema{}
+	schema.AddKey("custom/token", "string", "stepA", true)
+	schema.AddKey("custom/headers", "map[string]interface{}", "stepA", true)
+	schema.AddKey("artifactVersion", "string", "stepA", false)
+	SetCPESchema(schema)
+	SetEncryptionKeyProvider(func() (string, error) { return secret, nil })
+	t.Cleanup(func() {
+		SetCPESchema(nil)
+		SetEncryptionKeyProvider(nil)
+	})
+}
+
+func TestSensitiveValues(t *testing.T) {
+	setupSensitiveSchema(t, "runKey")
+	dir := t.TempDir()
+	cpePath := filepath.Join(dir, CommonPipelineEnvironment)
+
+	// a plaintext value written before is replaced
+	require.NoError(t, os.MkdirAll(filepath.Join(cpePath, "custom"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(cpePath, "custom", "token"), []byte("oldToken"), 0666))
+
+	require.NoError(t, SetResourceParameter(dir, CommonPipelineEnvironment, "custom/token", "secretToken"))
+	require.NoError(t, SetResourceParameter(dir, CommonPipelineEnvironment, "custom/headers", map[string]interface{}{"Authorization": "Bearer abc"}))
+	require.NoError(t, SetResourceParameter(dir, CommonPipelineEnvironment, "artifactVersion", "1.0.0"))
+
+	assert.NoFileExists(t, filepath.Join(cpePath, "custom", "token"))
+	encrypted, err := os.ReadFile(filepath.Join(cpePath, "custom", "token.enc"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "secretToken")
+	assert.FileExists(t, filepath.Join(cpePath, "custom", "headers.json.enc"))
+
+	t.Run("read single value", func(t *testing.T) {
+		assert.Equal(t, "secretToken", GetResourceParameter(dir, CommonPipelineEnvironment, "custom/token"))
+		assert.Equal(t, `{"Authorization":"Bearer abc"}`, GetResourceParameter(dir, CommonPipelineEnvironment, "custom/headers.json"))
+	})
+
+	t.Run("journal does not contain sensitive values", func(t *testing.T) {
+		journal, err := ReadJournal(cpePath)
+		require.NoError(t, err)
+		history := journal.History("custom/token")
+		require.Len(t, history, 1)
+		assert.Equal(t, "****", history[0].Value)
+		assert.Equal(t, "****", history[0].Previous)
+		content, err := os.ReadFile(filepath.Join(cpePath, JournalFile))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "secretToken")
+
+		// unchanged sensitive values are not recorded again
+		require.NoError(t, SetResourceParameter(dir, CommonPipelineEnvironment, "custom/token", "secretToken"))
+		journal, err = ReadJournal(cpePath)
+		require.NoError(t, err)
+		assert.Len(t, journal.History("custom/token"), 1)
+	})
+
+	t.Run("load environment", func(t *testing.T) {
+		cpe := CPEMap{}
+		require.NoError(t, cpe.LoadFromDisk(cpePath))
+		assert.NotEqual(t, "secretToken", cpe["custom/token.enc"])
+
+		decrypted, err := cpe.Decrypt()
+		require.NoError(t, err)
+		assert.Equal(t, CPEMap{
+			"artifactVersion": "1.0.0",
+			"custom/token":    "secretToken",
+			"custom/headers":  map[string]interface{}{"Authorization": "Bearer abc"},
+		}, decrypted)
+
+		encrypted, err := decrypted.EncryptSensitive(activeCPESchema())
+		require.NoError(t, err)
+		assert.Contains(t, encrypted, "custom/token.enc")
+		assert.Contains(t, encrypted, "custom/headers.json.enc")
+		assert.NotContains(t, encrypted, "custom/token")
+		roundTrip, err := encrypted.Decrypt()
+		require.NoError(t, err)
+		assert.Equal(t, decrypted, roundTrip)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		SetEncryptionKeyProvider(func() (string, error) { return "otherRunKey", nil })
+		defer SetEncryptionKeyProvider(func() (string, error) { return "runKey", nil })
+
+		cpe := CPEMap{}
+		require.NoError(t, cpe.LoadFromDisk(cpePath))
+		_, err := cpe.Decrypt()
+		assert.ErrorContains(t, err, "the encryption key of the pipeline run is required")
+		assert.Empty(t, GetResourceParameter(dir, CommonPipelineEnvironment, "custom/token"))
+	})
+}
+
+func TestSensitiveValuesWithoutKey(t *testing.T) {
+	t.Run("stored in plaintext", func(t *testing.T) {
+		setupSensitiveSchema(t, "")
+		dir := t.TempDir()
+		cpePath := filepath.Join(dir, CommonPipelineEnvironment)
+
+		require.NoError(t, SetResourceParameter(dir, CommonPipelineEnvironment, "custom/token", "secretToken"))
+
+		assert.NoFileExists(t, filepath.Join(cpePath, "custom", "token.enc"))
+		assert.Equal(t, "secretToken", GetResourceParameter(dir, CommonPipelineEnvironment, "custom/token"))
+		journal, err := ReadJournal(cpePath)
+		require.NoError(t, err)
+		require.Len(t, journal.History("custom/token"), 1)
+		assert.Equal(t, "****", journal.History("custom/token")[0].Value)
+
+		cpe := CPEMap{"custom/token": "secretToken"}
+		stored, err := cpe.EncryptSensitive(activeCPESchema())
+		require.NoError(t, err)
+		assert.Equal(t, cpe, stored)
+	})
+
+	t.Run("encrypted value", func(t *testing.T) {
+		setupSensitiveSchema(t, "")
+		decrypted, err := CPEMap{"custom/token.enc": "c2VjcmV0", "artifactVersion": "1.0.0"}.Decrypt()
+		require.NoError(t, err)
+		assert.Equal(t, CPEMap{"artifactVersion": "1.0.0"}, decrypted)
+	})
+
+	t.Run("failing key provider", func(t *testing.T) {
+		setupSensitiveSchema(t, "")
+		SetEncryptionKeyProvider(func() (string, error) { return "", errors.New("no key") })
+		dir := t.TempDir()
+
+		err := SetResourceParameter(dir, CommonPipelineEnvironment, "custom/token", "secretToken")
+		assert.EqualError(t, err, "failed to encrypt sensitive value of custom/token: failed to retrieve encryption key for sensitive values: no key")
+		assert.NoFileExists(t, filepath.Join(dir, CommonPipelineEnvironment, "custom", "token"))
+	})
+}
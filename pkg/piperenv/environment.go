@@ -14,8 +14,13 @@ import (
 // This file contains functions used to read/write pipeline environment data from/to disk.
 // The content of a written file is the value. For the custom parameters this could for example also be a JSON representation of a more complex value.
 
-// SetResourceParameter sets a resource parameter in the environment stored in the file system
+// SetResourceParameter sets a resource parameter in the environment stored in the file system.
+// Values of the common pipeline environment are checked against the active schema and changes are recorded in its journal.
 func SetResourceParameter(path, resourceName, paramName string, value interface{}) error {
+	isCPE := resourceName == CommonPipelineEnvironment
+	key := filepath.ToSlash(paramName)
+	schema := activeCPESchema()
+
 	var content []byte
 	paramPath := filepath.Join(path, resourceName, paramName)
 	switch typedValue := value.(type) {
@@ -29,14 +34,62 @@ func SetResourceParameter(path, resourceName, paramName string, value interface{
 			return errors.Wrapf(err, "failed to marshal resource parameter value %v", typedValue)
 		}
 	}
+	if isCPE && schema != nil {
+		if err := schema.CheckType(key, value); err != nil {
+			// the rejected value is kept in the journal for analysis
+			recordRejectedChange(filepath.Join(path, resourceName), key, content, schema[key].Sensitive, err)
+			return err
+		}
+	}
+	if isCPE && schema[key].Sensitive && len(content) > 0 {
+		return setSensitiveParameter(filepath.Join(path, resourceName), key, paramPath, content)
+	}
+	if isCPE && len(content) > 0 {
+		recordChange(filepath.Join(path, resourceName), key, content, false)
+	}
 	return writeToDisk(paramPath, content)
 }
 
-// GetResourceParameter reads a resource parameter from the environment stored in the file system
+// setSensitiveParameter stores the value encrypted and removes a plaintext value written before.
+// Without encryption key the value is stored in plaintext, it is masked in the log and in the journal nevertheless.
+func setSensitiveParameter(cpePath, key, paramPath string, content []byte) error {
+	log.RegisterSecret(string(content))
+	encrypted, err := encryptValue(content)
+	if errors.Is(err, errNoEncryptionKey) {
+		log.Entry().Warnf("No encryption key available, the sensitive value of %v is stored in plaintext", key)
+		recordChange(cpePath, key, content, true)
+		if err := os.Remove(paramPath + EncryptedSuffix); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove encrypted value of %v", key)
+		}
+		return writeToDisk(paramPath, content)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to encrypt sensitive value of %v", key)
+	}
+	recordChange(cpePath, key, content, true)
+	if err := os.Remove(paramPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove plaintext value of %v", key)
+	}
+	return writeToDisk(paramPath+EncryptedSuffix, encrypted)
+}
+
+// GetResourceParameter reads a resource parameter from the environment stored in the file system.
+// Sensitive values are decrypted and registered as secrets.
 func GetResourceParameter(path, resourceName, paramName string) string {
 	//TODO: align JSON un/marshalling, currently done in pkg/config/stepmeta.go#getParameterValue
 
 	paramPath := filepath.Join(path, resourceName, paramName)
+	if _, err := os.Stat(paramPath); os.IsNotExist(err) {
+		if encrypted, err := os.ReadFile(paramPath + EncryptedSuffix); err == nil {
+			content, err := decryptValue(encrypted)
+			if err != nil {
+				log.Entry().WithError(err).Warnf("failed to read sensitive value %v", paramName)
+				return ""
+			}
+			log.RegisterSecret(string(content))
+			return strings.TrimSpace(string(content))
+		}
+	}
 	return readFromDisk(paramPath)
 }
 
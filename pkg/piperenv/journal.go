@@ -0,0 +1,230 @@
+package piperenv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// CommonPipelineEnvironment is the name of the resource containing the common pipeline environment
+const CommonPipelineEnvironment = "commonPipelineEnvironment"
+
+// sensitiveJournalValue replaces sensitive values in the journal
+const sensitiveJournalValue = "****"
+
+// JournalFile is the file inside the common pipeline environment directory which records all changes of its keys
+const JournalFile = ".journal.jsonl"
+
+// JournalEntry records a change of a key of the common pipeline environment
+type JournalEntry struct {
+	Time  time.Time `json:"time"`
+	Stage string    `json:"stage,omitempty"`
+	Step  string    `json:"step,omitempty"`
+	Key   string    `json:"key"`
+	// Value is the content as stored on disk, i.e. values which are not strings are JSON encoded
+	Value       string `json:"value"`
+	Previous    string `json:"previous,omitempty"`
+	Overwritten bool   `json:"overwritten"`
+	// Error is the reason why the value has been rejected, e.g. a type mismatch. Rejected values are not part of the environment.
+	Error string `json:"error,omitempty"`
+}
+
+// Journal contains the changes of the common pipeline environment in the order they happened
+type Journal []JournalEntry
+
+// CPEChange describes the difference of a key between two states of the common pipeline environment
+type CPEChange struct {
+	Key  string
+	From string
+	To   string
+	// Added is true if the key did not exist in the first state
+	Added bool
+	// Removed is true if the key does not exist in the second state
+	Removed bool
+}
+
+var (
+	journalMutex sync.Mutex
+	journalStage string
+	journalStep  string
+)
+
+// SetJournalContext sets the stage and step which are recorded for subsequent writes to the common pipeline environment
+func SetJournalContext(stageName, stepName string) {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	journalStage = stageName
+	journalStep = stepName
+}
+
+// ReadJournal reads the journal of the common pipeline environment located at path.
+// A missing journal results in an empty journal.
+func ReadJournal(path string) (Journal, error) {
+	journal := Journal{}
+	file, err := os.Open(filepath.Join(path, JournalFile))
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse line %v of journal: %w", line, err)
+		}
+		journal = append(journal, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return journal, nil
+}
+
+// History returns the changes of a key, all changes are returned if key is empty
+func (j Journal) History(key string) Journal {
+	history := Journal{}
+	for _, entry := range j {
+		if len(key) == 0 || entry.Key == key {
+			history = append(history, entry)
+		}
+	}
+	return history
+}
+
+// State replays the whole journal and returns the resulting value of each key
+func (j Journal) State() map[string]string {
+	return j.replay(len(j))
+}
+
+// StateAfter replays the journal up to the last change written by the step
+func (j Journal) StateAfter(stepName string) (map[string]string, error) {
+	for i := len(j) - 1; i >= 0; i-- {
+		if j[i].Step == stepName {
+			return j.replay(i + 1), nil
+		}
+	}
+	return nil, fmt.Errorf("no changes of step '%v' recorded in the journal", stepName)
+}
+
+func (j Journal) replay(count int) map[string]string {
+	state := map[string]string{}
+	for _, entry := range j[:count] {
+		if len(entry.Error) > 0 {
+			continue
+		}
+		state[entry.Key] = entry.Value
+	}
+	return state
+}
+
+// DiffStates returns the changed keys between two states sorted by key
+func DiffStates(from, to map[string]string) []CPEChange {
+	changes := []CPEChange{}
+	for key, toValue := range to {
+		fromValue, ok := from[key]
+		if !ok {
+			changes = append(changes, CPEChange{Key: key, To: toValue, Added: true})
+		} else if fromValue != toValue {
+			changes = append(changes, CPEChange{Key: key, From: fromValue, To: toValue})
+		}
+	}
+	for key, fromValue := range from {
+		if _, ok := to[key]; !ok {
+			changes = append(changes, CPEChange{Key: key, From: fromValue, Removed: true})
+		}
+	}
+	sort.Slice(changes, func(i, k int) bool { return changes[i].Key < changes[k].Key })
+	return changes
+}
+
+// recordChange appends an entry to the journal if the value of the key changed.
+// The journal does not contain sensitive values, i.e. values of keys declared as sensitive and registered secrets are masked.
+func recordChange(path, key string, value []byte, sensitive bool) {
+	previous, existed := readStoredValue(filepath.Join(path, key))
+	if existed && previous == string(value) {
+		return
+	}
+	appendJournalEntry(path, JournalEntry{
+		Key:         key,
+		Value:       journalValue(value, sensitive),
+		Previous:    journalValue([]byte(previous), sensitive && existed),
+		Overwritten: existed,
+	})
+}
+
+// recordRejectedChange appends an entry for a value which has not been written to the environment
+func recordRejectedChange(path, key string, value []byte, sensitive bool, reason error) {
+	appendJournalEntry(path, JournalEntry{
+		Key:   key,
+		Value: journalValue(value, sensitive),
+		Error: log.MaskSecrets(reason.Error()),
+	})
+}
+
+func journalValue(value []byte, sensitive bool) string {
+	if sensitive {
+		return sensitiveJournalValue
+	}
+	return log.MaskSecrets(string(value))
+}
+
+func appendJournalEntry(path string, entry JournalEntry) {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	entry.Time = time.Now().UTC()
+	entry.Stage = journalStage
+	entry.Step = journalStep
+	// the journal is diagnostic information, failures must not break the step
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to record change of the common pipeline environment")
+		return
+	}
+	if err := os.MkdirAll(path, 0777); err != nil {
+		log.Entry().WithError(err).Warn("failed to create directory of the common pipeline environment")
+		return
+	}
+	file, err := os.OpenFile(filepath.Join(path, JournalFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to open journal of the common pipeline environment")
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Entry().WithError(err).Warn("failed to record change of the common pipeline environment")
+	}
+}
+
+// readStoredValue reads the current content of a key regardless whether it is stored as plain string, JSON encoded or encrypted
+func readStoredValue(keyPath string) (string, bool) {
+	for _, file := range []string{keyPath, keyPath + ".json", keyPath + EncryptedSuffix, keyPath + ".json" + EncryptedSuffix} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(file, EncryptedSuffix) {
+			if content, err = decryptValue(content); err != nil {
+				// an undecryptable value is treated like a changed value
+				return "", true
+			}
+		}
+		return string(content), true
+	}
+	return "", false
+}
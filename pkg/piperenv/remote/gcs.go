@@ -0,0 +1,62 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+)
+
+// GCSBackend stores the common pipeline environment as object in a Google Cloud Storage bucket.
+// Optimistic locking relies on the generation of the object.
+type GCSBackend struct {
+	Bucket string
+	Object string
+	client gcs.ObjectClient
+}
+
+// NewGCSBackend creates a GCSBackend using the application default credentials, e.g. GOOGLE_APPLICATION_CREDENTIALS
+func NewGCSBackend(bucket, object string) (*GCSBackend, error) {
+	client, err := gcs.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Cloud Storage client: %w", err)
+	}
+	return &GCSBackend{Bucket: bucket, Object: object, client: client}, nil
+}
+
+// Load reads the object, the generation is used as revision
+func (b *GCSBackend) Load() (piperenv.CPEMap, string, error) {
+	content, generation, err := b.client.ReadObject(b.Bucket, b.Object)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read gs://%v/%v: %w", b.Bucket, b.Object, err)
+	}
+	if generation == 0 {
+		return piperenv.CPEMap{}, "", nil
+	}
+	cpe, err := unmarshal(content)
+	return cpe, strconv.FormatInt(generation, 10), err
+}
+
+// Store writes the object if its generation still matches the revision
+func (b *GCSBackend) Store(cpe piperenv.CPEMap, revision string) (string, error) {
+	content, err := marshal(cpe)
+	if err != nil {
+		return "", err
+	}
+	var generation int64
+	if len(revision) > 0 {
+		if generation, err = strconv.ParseInt(revision, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid revision '%v': %w", revision, err)
+		}
+	}
+	newGeneration, err := b.client.WriteObject(b.Bucket, b.Object, content, generation)
+	if errors.Is(err, gcs.ErrPreconditionFailed) {
+		return "", piperenv.ErrRevisionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write gs://%v/%v: %w", b.Bucket, b.Object, err)
+	}
+	return strconv.FormatInt(newGeneration, 10), nil
+}
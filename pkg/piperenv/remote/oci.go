@@ -0,0 +1,138 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Media types of the OCI artifact containing the common pipeline environment
+const (
+	OCIConfigMediaType types.MediaType = "application/vnd.sap.piper.cpe.config.v1+json"
+	OCILayerMediaType  types.MediaType = "application/vnd.sap.piper.cpe.v1+json"
+)
+
+// OCIBackend stores the common pipeline environment as OCI artifact in a container registry.
+// The manifest is pushed with a precondition on the digest the tag pointed to when the environment has been loaded.
+// Since not all registries evaluate conditional requests, the digest of the tag is compared right before pushing as well.
+type OCIBackend struct {
+	Reference name.Reference
+	options   []remote.Option
+}
+
+// NewOCIBackend creates an OCIBackend for a reference like registry/repository:tag.
+// The credentials are taken from the docker config, e.g. ~/.docker/config.json or DOCKER_CONFIG.
+func NewOCIBackend(reference string) (*OCIBackend, error) {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference '%v': %w", reference, err)
+	}
+	return &OCIBackend{Reference: ref, options: []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}}, nil
+}
+
+// Load pulls the artifact, the digest of its manifest is used as revision
+func (b *OCIBackend) Load() (piperenv.CPEMap, string, error) {
+	descriptor, err := remote.Get(b.Reference, b.options...)
+	if isNotFound(err) {
+		return piperenv.CPEMap{}, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to pull %v: %w", b.Reference, err)
+	}
+	image, err := descriptor.Image()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %v: %w", b.Reference, err)
+	}
+	layers, err := image.Layers()
+	if err != nil || len(layers) != 1 {
+		return nil, "", fmt.Errorf("failed to read %v: artifact needs to contain exactly one layer", b.Reference)
+	}
+	reader, err := layers[0].Compressed()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %v: %w", b.Reference, err)
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %v: %w", b.Reference, err)
+	}
+	cpe, err := unmarshal(content)
+	return cpe, descriptor.Digest.String(), err
+}
+
+// Store pushes a new artifact if the tag still points to the revision
+func (b *OCIBackend) Store(cpe piperenv.CPEMap, revision string) (string, error) {
+	current, err := remote.Head(b.Reference, b.options...)
+	if err != nil && !isNotFound(err) {
+		return "", fmt.Errorf("failed to check %v: %w", b.Reference, err)
+	}
+	currentRevision := ""
+	if current != nil {
+		currentRevision = current.Digest.String()
+	}
+	if currentRevision != revision {
+		return "", piperenv.ErrRevisionConflict
+	}
+
+	content, err := marshal(cpe)
+	if err != nil {
+		return "", err
+	}
+	image, err := mutate.AppendLayers(mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), OCIConfigMediaType), static.NewLayer(content, OCILayerMediaType))
+	if err != nil {
+		return "", fmt.Errorf("failed to create artifact: %w", err)
+	}
+	options := append([]remote.Option{remote.WithTransport(&conditionalTransport{base: remote.DefaultTransport, revision: revision})}, b.options...)
+	err = remote.Write(b.Reference, image, options...)
+	if hasTransportStatusCode(err, http.StatusPreconditionFailed) {
+		return "", piperenv.ErrRevisionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to push %v: %w", b.Reference, err)
+	}
+	digest, err := image.Digest()
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate digest of %v: %w", b.Reference, err)
+	}
+	return digest.String(), nil
+}
+
+func isNotFound(err error) bool {
+	return hasTransportStatusCode(err, http.StatusNotFound)
+}
+
+func hasTransportStatusCode(err error, statusCode int) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == statusCode
+}
+
+// conditionalTransport adds the precondition on the current digest of the tag to the push of the manifest
+type conditionalTransport struct {
+	base     http.RoundTripper
+	revision string
+}
+
+func (t *conditionalTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodPut || !strings.Contains(request.URL.Path, "/manifests/") {
+		return t.base.RoundTrip(request)
+	}
+	request = request.Clone(request.Context())
+	if len(t.revision) > 0 {
+		request.Header.Set("If-Match", fmt.Sprintf("%q", t.revision))
+	} else {
+		request.Header.Set("If-None-Match", "*")
+	}
+	return t.base.RoundTrip(request)
+}
@@ -0,0 +1,63 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+)
+
+// objectName is the name of the object containing the common pipeline environment in the bucket based backends
+const objectName = "commonPipelineEnvironment.json"
+
+// NewBackend creates the backend for the given URL. Environment variables within the URL are expanded, e.g. to include the id of the pipeline run.
+// Supported are
+//   - file:///shared/path for a directory, e.g. on a volume shared between agents
+//   - s3://bucket/path for an S3 compatible bucket, the query parameters region and endpoint are optional
+//   - gs://bucket/path for a Google Cloud Storage bucket
+//   - oci://registry/repository:tag for an OCI artifact in a container registry
+func NewBackend(backendURL string) (piperenv.Backend, error) {
+	expandedURL := os.ExpandEnv(backendURL)
+	parsedURL, err := url.Parse(expandedURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend URL '%v': %w", backendURL, err)
+	}
+	objectPath := strings.Trim(strings.TrimPrefix(parsedURL.Path, "/")+"/"+objectName, "/")
+
+	switch parsedURL.Scheme {
+	case "file":
+		return &piperenv.FileSystemBackend{Path: parsedURL.Path}, nil
+	case "s3":
+		return NewS3Backend(parsedURL.Host, objectPath, parsedURL.Query().Get("region"), parsedURL.Query().Get("endpoint"))
+	case "gs":
+		return NewGCSBackend(parsedURL.Host, objectPath)
+	case "oci":
+		return NewOCIBackend(strings.TrimPrefix(expandedURL, "oci://"))
+	}
+	return nil, fmt.Errorf("backend '%v' not supported, use one of file, s3, gs, oci", parsedURL.Scheme)
+}
+
+func marshal(cpe piperenv.CPEMap) ([]byte, error) {
+	content, err := json.Marshal(cpe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal common pipeline environment: %w", err)
+	}
+	return content, nil
+}
+
+func unmarshal(content []byte) (piperenv.CPEMap, error) {
+	cpe := piperenv.CPEMap{}
+	if len(content) == 0 {
+		return cpe, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cpe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal common pipeline environment: %w", err)
+	}
+	return cpe, nil
+}
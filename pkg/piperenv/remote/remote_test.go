@@ -0,0 +1,200 @@
+//go:build unit
+// +build unit
+
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBackend(t *testing.T) {
+	t.Setenv("BUILD_ID", "42")
+
+	backend, err := NewBackend("file:///shared/cpe/${BUILD_ID}")
+	require.NoError(t, err)
+	assert.Equal(t, &piperenv.FileSystemBackend{Path: "/shared/cpe/42"}, backend)
+
+	backend, err = NewBackend("oci://registry.example.com/piper/cpe:${BUILD_ID}")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/piper/cpe:42", backend.(*OCIBackend).Reference.String())
+
+	_, err = NewBackend("ftp://example.com/cpe")
+	assert.EqualError(t, err, "backend 'ftp' not supported, use one of file, s3, gs, oci")
+}
+
+// testBackend verifies the optimistic locking which all backends have in common
+func testBackend(t *testing.T, backend piperenv.Backend) {
+	cpe, revision, err := backend.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cpe)
+	assert.Empty(t, revision)
+
+	revision, err = backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.0", "custom/count": 1}, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, revision)
+
+	_, err = backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.1"}, "")
+	assert.ErrorIs(t, err, piperenv.ErrRevisionConflict)
+
+	cpe, loadedRevision, err := backend.Load()
+	require.NoError(t, err)
+	assert.Equal(t, revision, loadedRevision)
+	assert.Equal(t, "1.0.0", cpe["artifactVersion"])
+	assert.Equal(t, "1", fmt.Sprint(cpe["custom/count"]))
+
+	newRevision, err := backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.1"}, revision)
+	require.NoError(t, err)
+	assert.NotEqual(t, revision, newRevision)
+
+	_, err = backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.2"}, revision)
+	assert.ErrorIs(t, err, piperenv.ErrRevisionConflict)
+}
+
+// s3Server emulates conditional writes of S3 for a single bucket
+type s3Server struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+	etags   map[string]string
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	etag, exists := s.etags[r.URL.Path]
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(s.objects[r.URL.Path])
+	case http.MethodPut:
+		if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			fmt.Fprint(w, "<Error><Code>PreconditionFailed</Code></Error>")
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = content
+		s.etags[r.URL.Path] = fmt.Sprintf(`"%d"`, len(s.etags)+len(content))
+		w.Header().Set("ETag", s.etags[r.URL.Path])
+	}
+}
+
+func TestS3Backend(t *testing.T) {
+	server := httptest.NewServer(&s3Server{objects: map[string][]byte{}, etags: map[string]string{}})
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "eu-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	backend := newS3BackendFromConfig("piper", "pipelines/42/commonPipelineEnvironment.json", server.URL, cfg)
+	testBackend(t, backend)
+}
+
+// gcsObjectMock emulates the generation based preconditions of Google Cloud Storage
+type gcsObjectMock struct {
+	content    []byte
+	generation int64
+}
+
+func (g *gcsObjectMock) ReadObject(bucketID string, objectPath string) ([]byte, int64, error) {
+	if bucketID != "piper" || objectPath != "pipelines/42/commonPipelineEnvironment.json" {
+		return nil, 0, errors.New("unexpected object")
+	}
+	return g.content, g.generation, nil
+}
+
+func (g *gcsObjectMock) WriteObject(bucketID string, objectPath string, content []byte, generation int64) (int64, error) {
+	if generation != g.generation {
+		return 0, gcs.ErrPreconditionFailed
+	}
+	g.content = content
+	g.generation++
+	return g.generation, nil
+}
+
+func (g *gcsObjectMock) Close() error {
+	return nil
+}
+
+func TestGCSBackend(t *testing.T) {
+	backend := &GCSBackend{Bucket: "piper", Object: "pipelines/42/commonPipelineEnvironment.json", client: &gcsObjectMock{}}
+	testBackend(t, backend)
+}
+
+// conditionalRegistry emulates a registry which evaluates the preconditions of manifest pushes
+type conditionalRegistry struct {
+	registry http.Handler
+	// beforePush is called once before the next manifest push is evaluated, e.g. to simulate a concurrent push
+	beforePush func()
+}
+
+func (c *conditionalRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+		if c.beforePush != nil {
+			beforePush := c.beforePush
+			c.beforePush = nil
+			beforePush()
+		}
+		current := httptest.NewRecorder()
+		c.registry.ServeHTTP(current, httptest.NewRequest(http.MethodHead, r.URL.String(), nil))
+		digest := fmt.Sprintf("%q", current.Header().Get("Docker-Content-Digest"))
+		if (r.Header.Get("If-None-Match") == "*" && current.Code == http.StatusOK) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != digest) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+	}
+	c.registry.ServeHTTP(w, r)
+}
+
+func TestOCIBackend(t *testing.T) {
+	t.Run("registry without conditional requests", func(t *testing.T) {
+		server := httptest.NewServer(registry.New())
+		defer server.Close()
+
+		backend, err := NewOCIBackend(strings.TrimPrefix(server.URL, "http://") + "/piper/cpe:42")
+		require.NoError(t, err)
+		testBackend(t, backend)
+	})
+
+	t.Run("concurrent push", func(t *testing.T) {
+		conditional := &conditionalRegistry{registry: registry.New()}
+		server := httptest.NewServer(conditional)
+		defer server.Close()
+		backend, err := NewOCIBackend(strings.TrimPrefix(server.URL, "http://") + "/piper/cpe:42")
+		require.NoError(t, err)
+		revision, err := backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.0"}, "")
+		require.NoError(t, err)
+
+		// another agent pushes after the digest has been compared but before the manifest is pushed
+		conditional.beforePush = func() {
+			_, err := backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.1"}, revision)
+			require.NoError(t, err)
+		}
+		_, err = backend.Store(piperenv.CPEMap{"artifactVersion": "1.0.2"}, revision)
+		assert.ErrorIs(t, err, piperenv.ErrRevisionConflict)
+
+		cpe, _, err := backend.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.1", cpe["artifactVersion"])
+	})
+}
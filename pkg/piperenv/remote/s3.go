@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// S3Backend stores the common pipeline environment as object in an S3 compatible bucket.
+// Optimistic locking relies on conditional writes using the ETag of the object.
+type S3Backend struct {
+	Bucket string
+	Key    string
+	client *s3.Client
+}
+
+// NewS3Backend creates an S3Backend. The credentials are taken from the default AWS credential chain, e.g. AWS_ACCESS_KEY_ID.
+// The endpoint allows to use S3 compatible storages like MinIO.
+func NewS3Backend(bucket, key, region, endpoint string) (*S3Backend, error) {
+	options := []func(*awsconfig.LoadOptions) error{}
+	if len(region) > 0 {
+		options = append(options, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	return newS3BackendFromConfig(bucket, key, endpoint, cfg), nil
+}
+
+func newS3BackendFromConfig(bucket, key, endpoint string, cfg aws.Config) *S3Backend {
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if len(endpoint) > 0 {
+			o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &S3Backend{Bucket: bucket, Key: key, client: client}
+}
+
+// Load reads the object, the ETag is used as revision
+func (b *S3Backend) Load() (piperenv.CPEMap, string, error) {
+	output, err := b.client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String(b.Bucket), Key: aws.String(b.Key)})
+	if hasStatusCode(err, http.StatusNotFound) {
+		return piperenv.CPEMap{}, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read s3://%v/%v: %w", b.Bucket, b.Key, err)
+	}
+	defer output.Body.Close()
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read s3://%v/%v: %w", b.Bucket, b.Key, err)
+	}
+	cpe, err := unmarshal(content)
+	return cpe, aws.ToString(output.ETag), err
+}
+
+// Store writes the object if its ETag still matches the revision
+func (b *S3Backend) Store(cpe piperenv.CPEMap, revision string) (string, error) {
+	content, err := marshal(cpe)
+	if err != nil {
+		return "", err
+	}
+	condition := smithyhttp.SetHeaderValue("If-None-Match", "*")
+	if len(revision) > 0 {
+		condition = smithyhttp.SetHeaderValue("If-Match", revision)
+	}
+	output, err := b.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(b.Bucket),
+		Key:         aws.String(b.Key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String("application/json"),
+	}, s3.WithAPIOptions(condition))
+	// 409 is returned if a concurrent conditional write is in progress
+	if hasStatusCode(err, http.StatusPreconditionFailed) || hasStatusCode(err, http.StatusConflict) {
+		return "", piperenv.ErrRevisionConflict
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write s3://%v/%v: %w", b.Bucket, b.Key, err)
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var responseErr *smithyhttp.ResponseError
+	return errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == statusCode
+}
@@ -0,0 +1,161 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CPEKey declares a key of the common pipeline environment
+type CPEKey struct {
+	// Type is the Go type of the value as declared in the step metadata, e.g. string, bool or []string
+	Type string `json:"type"`
+	// Steps contains the steps which declare the key as output
+	Steps []string `json:"steps"`
+	// Sensitive values are stored encrypted
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// CPESchema declares the known keys of the common pipeline environment and their types
+type CPESchema map[string]CPEKey
+
+var (
+	schemaMutex    sync.Mutex
+	cpeSchema      CPESchema
+	schemaProvider func() CPESchema
+)
+
+// SetCPESchema activates the type checks on write for the common pipeline environment.
+// Passing nil disables the checks.
+func SetCPESchema(schema CPESchema) {
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+	cpeSchema = schema
+	schemaProvider = nil
+}
+
+// SetCPESchemaProvider activates the type checks on write for the common pipeline environment.
+// The provider is only called once a value is written or checked, since building the schema requires the metadata of all steps.
+func SetCPESchemaProvider(provider func() CPESchema) {
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+	cpeSchema = nil
+	schemaProvider = provider
+}
+
+func activeCPESchema() CPESchema {
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+	if cpeSchema == nil && schemaProvider != nil {
+		cpeSchema = schemaProvider()
+		schemaProvider = nil
+	}
+	return cpeSchema
+}
+
+// AddKey declares a key of the given type for a step.
+// If steps declare the same key with different types, any value is accepted for the key.
+// A key is sensitive as soon as one step declares it as sensitive.
+func (s CPESchema) AddKey(key, keyType, stepName string, sensitive bool) {
+	entry, ok := s[key]
+	if !ok {
+		entry.Type = keyType
+	} else if entry.Type != keyType {
+		entry.Type = "interface{}"
+	}
+	entry.Sensitive = entry.Sensitive || sensitive
+	entry.Steps = append(entry.Steps, stepName)
+	sort.Strings(entry.Steps)
+	s[key] = entry
+}
+
+// CheckType verifies that the value matches the declared type of the key.
+// Keys which are not declared by any step accept any value.
+func (s CPESchema) CheckType(key string, value interface{}) error {
+	entry, ok := s[key]
+	if !ok || value == nil {
+		return nil
+	}
+	if !matchesType(entry.Type, value) {
+		return fmt.Errorf("value of type %T does not match type %v declared for key '%v' by %v", value, entry.Type, key, strings.Join(entry.Steps, ", "))
+	}
+	return nil
+}
+
+// CheckTypes verifies all values of the map against the schema
+func (s CPESchema) CheckTypes(c CPEMap) []error {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		if err := s.CheckType(key, c[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// matchesType accepts the Go values written by the steps as well as the values decoded from JSON
+func matchesType(declaredType string, value interface{}) bool {
+	switch declaredType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "int", "int64":
+		return isInteger(value)
+	case "[]string":
+		return isListOf(value, func(item interface{}) bool { _, ok := item.(string); return ok })
+	case "map[string]interface{}":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "[]map[string]interface{}", "piperenv.Artifacts":
+		if _, ok := value.(Artifacts); ok {
+			return true
+		}
+		return isListOf(value, func(item interface{}) bool { _, ok := item.(map[string]interface{}); return ok })
+	}
+	// types which cannot be checked, e.g. interface{}
+	return true
+}
+
+func isInteger(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case json.Number:
+		_, err := typedValue.Int64()
+		return err == nil
+	case float64:
+		return typedValue == math.Trunc(typedValue)
+	}
+	return false
+}
+
+func isListOf(value interface{}, matchesItem func(interface{}) bool) bool {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	if _, ok := value.([]interface{}); !ok {
+		// a typed slice like []string is checked by its element type
+		element := list.Type().Elem()
+		return matchesItem(reflect.Zero(element).Interface())
+	}
+	for i := 0; i < list.Len(); i++ {
+		if !matchesItem(list.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
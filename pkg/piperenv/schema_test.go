@@ -0,0 +1,71 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPESchema_CheckType(t *testing.T) {
+	schema := CPESchema{}
+	schema.AddKey("artifactVersion", "string", "artifactPrepareVersion", false)
+	schema.AddKey("custom/isChangeInDevelopment", "bool", "isChangeInDevelopment", false)
+	schema.AddKey("custom/count", "int", "stepA", false)
+	schema.AddKey("container/imageNames", "[]string", "kanikoExecute", false)
+	schema.AddKey("container/imageNames", "[]string", "cnbBuild", false)
+	schema.AddKey("custom/artifacts", "piperenv.Artifacts", "mavenBuild", false)
+	schema.AddKey("custom/mixed", "string", "stepA", false)
+	schema.AddKey("custom/mixed", "bool", "stepB", false)
+
+	tests := []struct {
+		key   string
+		value interface{}
+		valid bool
+	}{
+		{key: "artifactVersion", value: "1.0.0", valid: true},
+		{key: "artifactVersion", value: true},
+		{key: "custom/isChangeInDevelopment", value: false, valid: true},
+		{key: "custom/isChangeInDevelopment", value: "false"},
+		{key: "custom/count", value: 3, valid: true},
+		{key: "custom/count", value: json.Number("3"), valid: true},
+		{key: "custom/count", value: json.Number("3.5")},
+		{key: "container/imageNames", value: []string{"a"}, valid: true},
+		{key: "container/imageNames", value: []interface{}{"a", "b"}, valid: true},
+		{key: "container/imageNames", value: []interface{}{"a", 1}},
+		{key: "container/imageNames", value: "a"},
+		{key: "custom/artifacts", value: Artifacts{{Name: "a"}}, valid: true},
+		{key: "custom/artifacts", value: []interface{}{map[string]interface{}{"name": "a"}}, valid: true},
+		{key: "custom/mixed", value: 1, valid: true},
+		{key: "unknown", value: 1, valid: true},
+	}
+	for _, test := range tests {
+		err := schema.CheckType(test.key, test.value)
+		if test.valid {
+			assert.NoError(t, err, "%v: %v", test.key, test.value)
+		} else {
+			assert.Error(t, err, "%v: %v", test.key, test.value)
+		}
+	}
+
+	assert.Equal(t, []string{"cnbBuild", "kanikoExecute"}, schema["container/imageNames"].Steps)
+	assert.EqualError(t, schema.CheckType("artifactVersion", 1), "value of type int does not match type string declared for key 'artifactVersion' by artifactPrepareVersion")
+	assert.Len(t, schema.CheckTypes(CPEMap{"artifactVersion": "1.0.0", "custom/count": "3", "custom/isChangeInDevelopment": "no"}), 2)
+}
+
+func TestSetCPESchemaProvider(t *testing.T) {
+	defer SetCPESchema(nil)
+	calls := 0
+	SetCPESchemaProvider(func() CPESchema {
+		calls++
+		return CPESchema{"custom/flag": {Type: "bool"}}
+	})
+	assert.Equal(t, 0, calls, "schema must only be built on first use")
+
+	assert.Equal(t, CPESchema{"custom/flag": {Type: "bool"}}, activeCPESchema())
+	assert.Equal(t, CPESchema{"custom/flag": {Type: "bool"}}, activeCPESchema())
+	assert.Equal(t, 1, calls)
+}
@@ -59,3 +59,38 @@ func PersistReportsAndLinks(stepName, workspace string, files fileWriter, report
 	}
 	return nil
 }
+
+type reportFiles interface {
+	fileWriter
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+}
+
+// AddReport adds the report to the reports persisted by the step via PersistReportsAndLinks, the reports file is created if it does not exist.
+// It allows to publish reports which are created for every step, e.g. the command transcript.
+func AddReport(stepName, workspace string, files reportFiles, report Path) error {
+	reportsFile := filepath.Join(workspace, fmt.Sprintf("%v_reports.json", stepName))
+	reports := []Path{}
+	if exists, _ := files.FileExists(reportsFile); exists {
+		content, err := files.FileRead(reportsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read reports.json: %w", err)
+		}
+		if err := json.Unmarshal(content, &reports); err != nil {
+			return fmt.Errorf("failed to unmarshal reports.json: %w", err)
+		}
+	}
+	for _, existing := range reports {
+		if existing.Target == report.Target {
+			return nil
+		}
+	}
+	reportList, err := json.Marshal(append(reports, report))
+	if err != nil {
+		return fmt.Errorf("failed to marshall reports.json data for archiving: %w", err)
+	}
+	if err := files.WriteFile(reportsFile, reportList, 0666); err != nil {
+		return fmt.Errorf("failed to write reports.json: %w", err)
+	}
+	return nil
+}
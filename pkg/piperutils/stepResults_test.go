@@ -127,3 +127,40 @@ func TestPersistReportAndLinks(t *testing.T) {
 		assert.EqualError(t, err, "failed to write links.json: write error")
 	})
 }
+
+func TestAddReport(t *testing.T) {
+	workspace := t.TempDir()
+	files := Files{}
+	transcript := Path{Name: "Command transcript", Target: "mavenBuild_transcript.json"}
+
+	t.Run("success - reports of the step", func(t *testing.T) {
+		require.NoError(t, PersistReportsAndLinks("mavenBuild", workspace, files, []Path{{Target: "TEST-report.xml"}}, nil))
+
+		require.NoError(t, AddReport("mavenBuild", workspace, files, transcript))
+		require.NoError(t, AddReport("mavenBuild", workspace, files, transcript))
+
+		var reports []Path
+		content, err := os.ReadFile(filepath.Join(workspace, "mavenBuild_reports.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(content, &reports))
+		assert.Equal(t, []Path{{Target: "TEST-report.xml"}, transcript}, reports)
+	})
+
+	t.Run("success - no reports of the step", func(t *testing.T) {
+		require.NoError(t, AddReport("npmExecuteScripts", workspace, files, transcript))
+
+		var reports []Path
+		content, err := os.ReadFile(filepath.Join(workspace, "npmExecuteScripts_reports.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(content, &reports))
+		assert.Equal(t, []Path{transcript}, reports)
+	})
+
+	t.Run("error - invalid reports file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "kanikoExecute_reports.json"), []byte("{"), 0666))
+
+		err := AddReport("kanikoExecute", workspace, files, transcript)
+
+		assert.ErrorContains(t, err, "failed to unmarshal reports.json")
+	})
+}
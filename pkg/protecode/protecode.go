@@ -11,8 +11,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/package-url/packageurl-go"
 	"github.com/sirupsen/logrus"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
@@ -46,7 +48,9 @@ type Result struct {
 
 // Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib     string          `json:"lib,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Vulns   []Vulnerability `json:"vulns,omitempty"`
 }
 
 // Vulnerability the protecode vulnerability information
@@ -54,6 +58,8 @@ type Vulnerability struct {
 	Exact  bool     `json:"exact,omitempty"`
 	Vuln   Vuln     `json:"vuln,omitempty"`
 	Triage []Triage `json:"triage,omitempty"`
+	// Assessment is set if the vulnerability is assessed in the assessment file
+	Assessment *format.Assessment `json:"-"`
 }
 
 // Vuln holds the information about the vulnerability
@@ -277,7 +283,24 @@ func isExcluded(vulnerability Vulnerability, excludeCVEs string) bool {
 }
 
 func isTriaged(vulnerability Vulnerability) bool {
-	return len(vulnerability.Triage) > 0
+	return len(vulnerability.Triage) > 0 || vulnerability.Assessment != nil
+}
+
+// ApplyAssessments attaches the assessments to the vulnerabilities of the components, assessed vulnerabilities are treated as triaged.
+// It returns the number of assessed vulnerabilities.
+func ApplyAssessments(result *Result, assessments []format.Assessment) int {
+	assessed := 0
+	for i, component := range result.Components {
+		purl := packageurl.PackageURL{Name: component.Lib, Version: component.Version}
+		for j, vulnerability := range component.Vulns {
+			if assessment := format.FindAssessment(assessments, purl, vulnerability.Vuln.Cve); assessment != nil {
+				log.Entry().Debugf("Matched assessment with status %v and analysis %v to vulnerability %v of component %v", assessment.Status, assessment.Analysis, vulnerability.Vuln.Cve, component.Lib)
+				result.Components[i].Vulns[j].Assessment = assessment
+				assessed++
+			}
+		}
+	}
+	return assessed
 }
 
 func isSevereCVSS3(vulnerability Vulnerability) bool {
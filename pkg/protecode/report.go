@@ -5,9 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/package-url/packageurl-go"
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -57,45 +58,13 @@ func writeJSON(path, name string, data interface{}, fileUtils piperutils.FileUti
 	return fileUtils.FileWrite(filepath.Join(path, name), jsonData, 0644)
 }
 
-func CreateCustomReport(productName string, productID int, data map[string]int, vulns []Vuln) reporting.ScanReport {
-	scanReport := reporting.ScanReport{
-		ReportTitle: "Protecode Vulnerability Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "Product name", Details: productName},
-			{Description: "Product ID", Details: fmt.Sprint(productID)},
-		},
-		Overview: []reporting.OverviewRow{
-			{Description: "Vulnerabilities", Details: fmt.Sprint(data["vulnerabilities"])},
-			{Description: "Major Vulnerabilities", Details: fmt.Sprint(data["major_vulnerabilities"])},
-			{Description: "Minor Vulnerabilities", Details: fmt.Sprint(data["minor_vulnerabilities"])},
-			{Description: "Triaged Vulnerabilities", Details: fmt.Sprint(data["triaged_vulnerabilities"])},
-			{Description: "Excluded Vulnerabilities", Details: fmt.Sprint(data["excluded_vulnerabilities"])},
-		},
-		ReportTime: time.Now(),
+// CreateCustomReport creates a vulnerability ScanReport of the findings, vulnerabilities with a CVSS v3 score of at least 7.0 are considered severe
+func CreateCustomReport(productName string, productID int, findings []reporting.Finding) reporting.ScanReport {
+	subheaders := []reporting.Subheader{
+		{Description: "Product name", Details: productName},
+		{Description: "Product ID", Details: fmt.Sprint(productID)},
 	}
-
-	detailTable := reporting.ScanDetailTable{
-		NoRowsMessage: "No findings detected",
-		Headers: []string{
-			"Issue CVE",
-			"CVSS Score",
-			"CVSS v3 Score",
-		},
-		WithCounter:   true,
-		CounterHeader: "Entry #",
-	}
-
-	for _, vuln := range vulns {
-		row := reporting.ScanRow{}
-		row.AddColumn(fmt.Sprint(*&vuln.Cve), 0)
-		row.AddColumn(fmt.Sprint(*&vuln.Cvss), 0)
-		row.AddColumn(fmt.Sprint(*&vuln.Cvss3Score), 0)
-
-		detailTable.Rows = append(detailTable.Rows, row)
-	}
-	scanReport.DetailTable = detailTable
-
-	return scanReport
+	return reporting.CreateFindingsScanReport("Protecode Vulnerability Report", subheaders, findings, reporting.SeverityHigh)
 }
 
 func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID string, fileUtils piperutils.FileUtils) ([]piperutils.Path, error) {
@@ -137,3 +106,53 @@ func reportShaProtecode(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
 }
+
+// ToFinding converts the vulnerability into the scanner independent finding, triaged vulnerabilities are not affected and assessed ones get the status of the assessment
+func (v Vulnerability) ToFinding() reporting.Finding {
+	finding := reporting.Finding{
+		Scanner: "protecode",
+		Type:    reporting.FindingTypeVulnerability,
+		ID:      v.Vuln.Cve,
+		Status:  reporting.FindingStatusOpen,
+	}
+	if strings.HasPrefix(v.Vuln.Cve, "CVE-") {
+		finding.CVE = v.Vuln.Cve
+	}
+	if score, err := strconv.ParseFloat(v.Vuln.Cvss3Score, 64); err == nil && score > 0 {
+		finding.CVSSScore = score
+	} else if score, err := strconv.ParseFloat(v.Vuln.Cvss, 64); err == nil {
+		finding.CVSSScore = score
+	}
+	finding.Severity = reporting.SeverityFromCVSS(finding.CVSSScore)
+	if v.Assessment != nil {
+		finding.Status = reporting.FindingStatusFromAssessment(v.Assessment)
+		finding.StatusComment = string(v.Assessment.Analysis)
+	} else if isTriaged(v) {
+		finding.Status = reporting.FindingStatusNotAffected
+		finding.StatusComment = v.Triage[0].Description
+	}
+	return finding
+}
+
+// Findings returns the vulnerabilities of the installed versions of the components, excluded CVEs are not affected
+func Findings(result Result, excludeCVEs string) []reporting.Finding {
+	findings := []reporting.Finding{}
+	for _, component := range result.Components {
+		for _, vulnerability := range component.Vulns {
+			if !isExact(vulnerability) {
+				continue
+			}
+			finding := vulnerability.ToFinding()
+			// the vulnerability itself does not name the component it has been found in
+			finding.ComponentName = component.Lib
+			finding.ComponentVersion = component.Version
+			finding.PackageURL = packageurl.NewPackageURL(packageurl.TypeGeneric, "", component.Lib, component.Version, nil, "").ToString()
+			if finding.Open() && isExcluded(vulnerability, excludeCVEs) {
+				finding.Status = reporting.FindingStatusNotAffected
+				finding.StatusComment = "excluded by configuration"
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
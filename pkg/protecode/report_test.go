@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/mock"
 )
 
@@ -32,3 +33,62 @@ func TestWriteReport(t *testing.T) {
 		assert.Equal(t, expected, string(content))
 	}
 }
+
+func TestFindings(t *testing.T) {
+	result := Result{Components: []Component{{Lib: "openssl", Version: "1.1.1", Vulns: []Vulnerability{
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-1", Cvss: "5.0", Cvss3Score: "9.8"}},
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-2", Cvss: "7.5"}},
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-3", Cvss3Score: "4.0"}},
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-4", Cvss3Score: "8.0"}, Triage: []Triage{{Component: "openssl", Version: "1.1.1", Description: "not used"}}},
+		{Exact: false, Vuln: Vuln{Cve: "CVE-2021-5", Cvss3Score: "8.0"}},
+	}}}}
+
+	findings := Findings(result, "CVE-2021-3")
+	if assert.Len(t, findings, 4) {
+		assert.Equal(t, "CVE-2021-1", findings[0].CVE)
+		assert.Equal(t, 9.8, findings[0].CVSSScore)
+		assert.Equal(t, "critical", findings[0].Severity.String())
+		assert.Equal(t, "open", string(findings[0].Status))
+		assert.Equal(t, "openssl", findings[0].ComponentName)
+		assert.Equal(t, "1.1.1", findings[0].ComponentVersion)
+		assert.Equal(t, "pkg:generic/openssl@1.1.1", findings[0].PackageURL)
+		assert.Equal(t, 7.5, findings[1].CVSSScore)
+		assert.Equal(t, "high", findings[1].Severity.String())
+		assert.Equal(t, "notAffected", string(findings[2].Status))
+		assert.Equal(t, "excluded by configuration", findings[2].StatusComment)
+		assert.Equal(t, "notAffected", string(findings[3].Status))
+		assert.Equal(t, "openssl", findings[3].ComponentName)
+		assert.Equal(t, "not used", findings[3].StatusComment)
+
+		scanReport := CreateCustomReport("product", 4711, findings)
+		assert.Equal(t, "4711", scanReport.Subheaders[1].Details)
+		assert.Len(t, scanReport.DetailTable.Rows, 4)
+		assert.False(t, scanReport.SuccessfulScan)
+	}
+}
+
+func TestApplyAssessments(t *testing.T) {
+	result := Result{Components: []Component{{Lib: "busybox", Version: "1.27.2", Vulns: []Vulnerability{
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-1", Cvss3Score: "9.8"}},
+		{Exact: true, Vuln: Vuln{Cve: "CVE-2021-2", Cvss3Score: "7.5"}},
+	}}}}
+	assessments := []format.Assessment{
+		{Vulnerability: "CVE-2021-1", Status: format.InProcess, Purls: []format.Purl{{Purl: "pkg:apk/alpine/busybox@1.27.2"}}},
+		{Vulnerability: "CVE-2021-2", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "pkg:apk/alpine/busybox@1.28.0"}}},
+	}
+
+	assert.Equal(t, 1, ApplyAssessments(&result, assessments))
+	assert.True(t, HasSevereVulnerabilities(result, ""))
+
+	findings := Findings(result, "")
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, "inTriage", string(findings[0].Status))
+		assert.Equal(t, "busybox", findings[0].ComponentName)
+		assert.Equal(t, "1.27.2", findings[0].ComponentVersion)
+		assert.Equal(t, "open", string(findings[1].Status))
+	}
+
+	assessments[1].Purls[0].Purl = "pkg:apk/alpine/busybox@1.27.2"
+	assert.Equal(t, 2, ApplyAssessments(&result, assessments))
+	assert.False(t, HasSevereVulnerabilities(result, ""))
+}
@@ -0,0 +1,292 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// Severity of a finding, a higher value is more severe
+type Severity int
+
+// Severities of findings
+const (
+	SeverityUnknown Severity = iota
+	SeverityInfo
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = [...]string{"unknown", "info", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < SeverityUnknown || s > SeverityCritical {
+		return severityNames[SeverityUnknown]
+	}
+	return severityNames[s]
+}
+
+// MarshalText writes the severity as name, e.g. high
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads the severity from its name
+func (s *Severity) UnmarshalText(text []byte) error {
+	*s = ParseSeverity(string(text))
+	if *s == SeverityUnknown && len(text) > 0 && !strings.EqualFold(string(text), severityNames[SeverityUnknown]) {
+		return fmt.Errorf("unknown severity '%v'", string(text))
+	}
+	return nil
+}
+
+// ParseSeverity converts the severity names used by the scanners, e.g. CRITICAL, Moderate or Information
+func ParseSeverity(name string) Severity {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "critical", "very high":
+		return SeverityCritical
+	case "high", "urgent", "major", "error":
+		return SeverityHigh
+	case "medium", "moderate", "warning":
+		return SeverityMedium
+	case "low", "minor", "note":
+		return SeverityLow
+	case "info", "information", "informational", "none":
+		return SeverityInfo
+	}
+	return SeverityUnknown
+}
+
+// SeverityFromCVSS returns the severity of the CVSS v3 score
+func SeverityFromCVSS(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityUnknown
+}
+
+// FindingType distinguishes vulnerable components from weaknesses in the code
+type FindingType string
+
+// Types of findings
+const (
+	FindingTypeVulnerability FindingType = "vulnerability"
+	FindingTypeLicense       FindingType = "license"
+	FindingTypeCode          FindingType = "code"
+)
+
+// FindingStatus is the assessment status of a finding
+type FindingStatus string
+
+// Assessment states of findings
+const (
+	// FindingStatusOpen has not been assessed yet
+	FindingStatusOpen FindingStatus = "open"
+	// FindingStatusInTriage is being assessed
+	FindingStatusInTriage FindingStatus = "inTriage"
+	// FindingStatusConfirmed has been assessed as relevant
+	FindingStatusConfirmed FindingStatus = "confirmed"
+	// FindingStatusNotAffected has been assessed as false positive, not exploitable or mitigated
+	FindingStatusNotAffected FindingStatus = "notAffected"
+)
+
+// FindingStatusFromAssessment converts the status of an assessment of hs-assessment.yaml
+func FindingStatusFromAssessment(assessment *format.Assessment) FindingStatus {
+	if assessment == nil {
+		return FindingStatusOpen
+	}
+	switch assessment.Status {
+	case format.NotRelevant:
+		return FindingStatusNotAffected
+	case format.InProcess:
+		return FindingStatusInTriage
+	case format.Relevant:
+		return FindingStatusConfirmed
+	}
+	return FindingStatusOpen
+}
+
+// Location of a finding in the source code
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Finding is the scanner independent representation of a vulnerable component or a weakness in the code
+type Finding struct {
+	Scanner string      `json:"scanner"`
+	Type    FindingType `json:"type"`
+	// ID identifies the finding within the scanner, e.g. the CVE or the name of the query
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// CVE and CWE are the identifiers of the vulnerability and the weakness, e.g. CVE-2021-44228 and CWE-502
+	CVE string `json:"cve,omitempty"`
+	CWE string `json:"cwe,omitempty"`
+
+	PackageURL       string    `json:"purl,omitempty"`
+	ComponentName    string    `json:"componentName,omitempty"`
+	ComponentVersion string    `json:"componentVersion,omitempty"`
+	Location         *Location `json:"location,omitempty"`
+
+	Severity   Severity `json:"severity"`
+	CVSSScore  float64  `json:"cvssScore,omitempty"`
+	CVSSVector string   `json:"cvssVector,omitempty"`
+	// FixVersion is the first version of the component without the vulnerability, Remediation describes further measures
+	FixVersion  string     `json:"fixVersion,omitempty"`
+	Remediation string     `json:"remediation,omitempty"`
+	Published   *time.Time `json:"published,omitempty"`
+	Link        string     `json:"link,omitempty"`
+
+	Status        FindingStatus `json:"status"`
+	StatusComment string        `json:"statusComment,omitempty"`
+
+	// ProjectName, ProjectVersion and ProjectLink identify the project in the scanner which contains the finding
+	ProjectName    string `json:"projectName,omitempty"`
+	ProjectVersion string `json:"projectVersion,omitempty"`
+	ProjectLink    string `json:"projectLink,omitempty"`
+
+	// IssueTitle overrides the title of the GitHub issue, e.g. to keep updating the issues which a scanner created before
+	IssueTitle string `json:"issueTitle,omitempty"`
+}
+
+// Key identifies the same finding across scanners, e.g. the CVE of a package or the weakness at a location in the code
+func (f Finding) Key() string {
+	id := f.ID
+	if len(f.CVE) > 0 {
+		id = f.CVE
+	}
+	switch {
+	case len(f.PackageURL) > 0:
+		return fmt.Sprintf("%v+%v", f.PackageURL, id)
+	case f.Location != nil:
+		if len(f.CWE) > 0 {
+			id = f.CWE
+		}
+		return fmt.Sprintf("%v:%v+%v", f.Location.File, f.Location.Line, id)
+	case len(f.ComponentVersion) > 0:
+		return fmt.Sprintf("%v@%v+%v", f.ComponentName, f.ComponentVersion, id)
+	}
+	return fmt.Sprintf("%v+%v", f.ComponentName, id)
+}
+
+// Open returns whether the finding still needs to be assessed or fixed
+func (f Finding) Open() bool {
+	return f.Status != FindingStatusNotAffected
+}
+
+// Title returns the issue title representation of the contents
+func (f Finding) Title() string {
+	if len(f.IssueTitle) > 0 {
+		return f.IssueTitle
+	}
+	switch f.Type {
+	case FindingTypeCode:
+		if f.Location != nil {
+			return fmt.Sprintf("%v %v %v", f.Scanner, f.ID, f.Location.File)
+		}
+		return fmt.Sprintf("%v %v", f.Scanner, f.ID)
+	case FindingTypeLicense:
+		return fmt.Sprintf("Policy Violation %v %v", f.ID, f.ComponentName)
+	}
+	return fmt.Sprintf("Security Vulnerability %v %v", f.ID, f.ComponentName)
+}
+
+// ToMarkdown returns the markdown representation of the contents which is used for GitHub issues
+func (f Finding) ToMarkdown() ([]byte, error) {
+	if f.Type == FindingTypeCode {
+		return f.codeFindingMarkdown()
+	}
+	report := NewVulnerabilityReport(f)
+	return report.ToMarkdown()
+}
+
+// ToTxt returns the textual representation of the contents
+func (f Finding) ToTxt() string {
+	lines := []string{
+		fmt.Sprintf("%v %v", f.Title(), f.Name),
+		fmt.Sprintf("Severity: %v", f.Severity),
+	}
+	if f.CVSSScore > 0 {
+		lines = append(lines, fmt.Sprintf("CVSS Score: %v %v", f.CVSSScore, f.CVSSVector))
+	}
+	if len(f.PackageURL) > 0 {
+		lines = append(lines, fmt.Sprintf("Package URL: %v", f.PackageURL))
+	}
+	if f.Location != nil {
+		lines = append(lines, fmt.Sprintf("Location: %v:%v", f.Location.File, f.Location.Line))
+	}
+	if len(f.FixVersion) > 0 {
+		lines = append(lines, fmt.Sprintf("Fix Version: %v", f.FixVersion))
+	}
+	lines = append(lines, fmt.Sprintf("Status: %v", f.Status))
+	if len(f.Link) > 0 {
+		lines = append(lines, fmt.Sprintf("Link: %v", f.Link))
+	}
+	lines = append(lines, fmt.Sprintf("Description: %v", f.Description))
+	return strings.Join(lines, "\n")
+}
+
+// NewVulnerabilityReport fills the vulnerability report of a GitHub issue with the details of the finding
+func NewVulnerabilityReport(f Finding) VulnerabilityReport {
+	report := VulnerabilityReport{
+		ArtifactID:           f.ComponentName,
+		Description:          f.Description,
+		PackageURL:           f.PackageURL,
+		Resolution:           f.Remediation,
+		Score:                f.CVSSScore,
+		Severity:             f.Severity.String(),
+		Version:              f.ComponentVersion,
+		VulnerabilityLink:    f.Link,
+		VulnerabilityName:    f.ID,
+		ProjectName:          f.ProjectName,
+		ProjectVersion:       f.ProjectVersion,
+		BlackDuckProjectLink: f.ProjectLink,
+	}
+	if len(report.Resolution) == 0 && len(f.FixVersion) > 0 {
+		report.Resolution = fmt.Sprintf("Upgrade to version %v", f.FixVersion)
+	}
+	if f.Published != nil {
+		report.PublishDate = f.Published.Format("2006-01-02")
+	}
+	return report
+}
+
+const codeFindingMdTemplate string = `# {{ .Severity }} {{ .ID }} in {{ with .Location }}{{ .File }}{{ end }}
+
+**Scanner:** {{ .Scanner }}
+{{if .CWE}}**Weakness:** {{ .CWE }}{{- end}}
+{{with .Location}}**Location:** {{ .File }}:{{ .Line }}{{- end}}
+**Status:** {{ .Status }}
+{{if .Link}}**Link:** [{{ .Link }}]({{ .Link }}){{- end}}
+
+## Description
+
+{{ .Description }}
+`
+
+func (f Finding) codeFindingMarkdown() ([]byte, error) {
+	tmpl, err := template.New("finding").Parse(codeFindingMdTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create markdown issue template: %w", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, f); err != nil {
+		return nil, fmt.Errorf("failed to execute markdown issue template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+//go:build unit
+// +build unit
+
+package reporting
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name     string
+		expected Severity
+	}{
+		{name: "CRITICAL", expected: SeverityCritical},
+		{name: "Very High", expected: SeverityCritical},
+		{name: "high", expected: SeverityHigh},
+		{name: "error", expected: SeverityHigh},
+		{name: "Moderate", expected: SeverityMedium},
+		{name: "LOW", expected: SeverityLow},
+		{name: "Information", expected: SeverityInfo},
+		{name: "something", expected: SeverityUnknown},
+	}
+	for _, test := range tt {
+		assert.Equal(t, test.expected, ParseSeverity(test.name), test.name)
+	}
+}
+
+func TestSeverityFromCVSS(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, SeverityCritical, SeverityFromCVSS(9.8))
+	assert.Equal(t, SeverityHigh, SeverityFromCVSS(7.0))
+	assert.Equal(t, SeverityMedium, SeverityFromCVSS(5.3))
+	assert.Equal(t, SeverityLow, SeverityFromCVSS(0.1))
+	assert.Equal(t, SeverityUnknown, SeverityFromCVSS(0))
+}
+
+func TestSeverityJSON(t *testing.T) {
+	t.Parallel()
+	content, err := json.Marshal(Finding{Severity: SeverityHigh})
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"severity":"high"`)
+
+	var finding Finding
+	assert.NoError(t, json.Unmarshal([]byte(`{"severity":"critical"}`), &finding))
+	assert.Equal(t, SeverityCritical, finding.Severity)
+}
+
+func TestFindingStatusFromAssessment(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, FindingStatusOpen, FindingStatusFromAssessment(nil))
+	assert.Equal(t, FindingStatusNotAffected, FindingStatusFromAssessment(&format.Assessment{Status: format.NotRelevant}))
+	assert.Equal(t, FindingStatusInTriage, FindingStatusFromAssessment(&format.Assessment{Status: format.InProcess}))
+	assert.Equal(t, FindingStatusConfirmed, FindingStatusFromAssessment(&format.Assessment{Status: format.Relevant}))
+}
+
+func TestFindingKey(t *testing.T) {
+	t.Parallel()
+	t.Run("component", func(t *testing.T) {
+		finding := Finding{ID: "BDSA-2021-1", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}
+		assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1+CVE-2021-44228", finding.Key())
+	})
+	t.Run("code", func(t *testing.T) {
+		finding := Finding{ID: "SQL_Injection", CWE: "CWE-89", Location: &Location{File: "src/db.go", Line: 12}}
+		assert.Equal(t, "src/db.go:12+CWE-89", finding.Key())
+	})
+	t.Run("fallback", func(t *testing.T) {
+		finding := Finding{ID: "CVE-2022-1", ComponentName: "lodash"}
+		assert.Equal(t, "lodash+CVE-2022-1", finding.Key())
+	})
+	t.Run("fallback with version", func(t *testing.T) {
+		finding := Finding{ID: "CVE-2022-1", ComponentName: "lodash", ComponentVersion: "4.17.20"}
+		assert.Equal(t, "lodash@4.17.20+CVE-2022-1", finding.Key())
+		assert.NotEqual(t, finding.Key(), Finding{ID: "CVE-2022-1", ComponentName: "lodash", ComponentVersion: "4.17.21"}.Key())
+	})
+}
+
+func TestFindingToMarkdown(t *testing.T) {
+	t.Parallel()
+	t.Run("vulnerability", func(t *testing.T) {
+		finding := Finding{
+			Scanner:          "whitesource",
+			Type:             FindingTypeVulnerability,
+			ID:               "CVE-2021-44228",
+			ComponentName:    "log4j-core",
+			ComponentVersion: "2.14.1",
+			Severity:         SeverityCritical,
+			CVSSScore:        10,
+			FixVersion:       "2.17.1",
+		}
+		assert.Equal(t, "Security Vulnerability CVE-2021-44228 log4j-core", finding.Title())
+		finding.IssueTitle = "CVE-2021-44228"
+		assert.Equal(t, "CVE-2021-44228", finding.Title())
+		finding.IssueTitle = ""
+		markdown, err := finding.ToMarkdown()
+		assert.NoError(t, err)
+		assert.Contains(t, string(markdown), "CVE-2021-44228")
+		assert.Contains(t, string(markdown), "Upgrade to version 2.17.1")
+	})
+	t.Run("code", func(t *testing.T) {
+		finding := Finding{
+			Scanner:     "checkmarx",
+			Type:        FindingTypeCode,
+			ID:          "SQL_Injection",
+			CWE:         "CWE-89",
+			Location:    &Location{File: "src/db.go", Line: 12},
+			Severity:    SeverityHigh,
+			Status:      FindingStatusConfirmed,
+			Description: "user input is used in a query",
+		}
+		assert.Equal(t, "checkmarx SQL_Injection src/db.go", finding.Title())
+		markdown, err := finding.ToMarkdown()
+		assert.NoError(t, err)
+		assert.Contains(t, string(markdown), "# high SQL_Injection in src/db.go")
+		assert.Contains(t, string(markdown), "**Location:** src/db.go:12")
+		assert.Contains(t, finding.ToTxt(), "Status: confirmed")
+	})
+}
@@ -0,0 +1,312 @@
+package reporting
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/pkg/errors"
+)
+
+// FindingsDirectory contains the findings of all scan steps of the pipeline in a scanner independent format
+var FindingsDirectory = filepath.Join(StepReportDirectory, "findings")
+
+// FindingsReport contains the findings of a scan step
+type FindingsReport struct {
+	StepName   string    `json:"stepName"`
+	Scanner    string    `json:"scanner"`
+	Project    string    `json:"project,omitempty"`
+	ReportTime time.Time `json:"reportTime"`
+	Findings   []Finding `json:"findings"`
+}
+
+type findingsFileUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+type findingsFileReader interface {
+	FileRead(path string) ([]byte, error)
+	Glob(pattern string) (matches []string, err error)
+}
+
+// WriteFindingsReport writes the findings into the FindingsDirectory, one file per step and project
+func WriteFindingsReport(report FindingsReport, utils findingsFileUtils) (string, error) {
+	if report.Findings == nil {
+		report.Findings = []Finding{}
+	}
+	if report.ReportTime.IsZero() {
+		report.ReportTime = time.Now()
+	}
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal findings")
+	}
+	if err := utils.MkdirAll(FindingsDirectory, 0777); err != nil {
+		return "", errors.Wrap(err, "failed to create findings directory")
+	}
+	file := filepath.Join(FindingsDirectory, fmt.Sprintf("%v_%x.json", report.StepName, sha1.Sum([]byte(report.Project))))
+	if err := utils.FileWrite(file, content, 0666); err != nil {
+		return "", errors.Wrap(err, "failed to write findings")
+	}
+	return file, nil
+}
+
+// WriteFindingsSarif writes the SARIF report of the findings as findings.sarif into the directory.
+// It complements the SARIF report of the scanner with the scanner independent view on the findings.
+func WriteFindingsSarif(sarif format.SARIF, directory string, utils findingsFileUtils) (string, error) {
+	// HTML characters will most likely be present, thus they must not be escaped
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sarif); err != nil {
+		return "", errors.Wrap(err, "failed to marshal SARIF report of the findings")
+	}
+	if err := utils.MkdirAll(directory, 0777); err != nil {
+		return "", errors.Wrap(err, "failed to create report directory")
+	}
+	file := filepath.Join(directory, "findings.sarif")
+	if err := utils.FileWrite(file, buffer.Bytes(), 0666); err != nil {
+		return "", errors.Wrap(err, "failed to write SARIF report of the findings")
+	}
+	return file, nil
+}
+
+// ReadFindingsReports reads the findings of all scan steps from the FindingsDirectory
+func ReadFindingsReports(utils findingsFileReader) ([]FindingsReport, error) {
+	files, err := utils.Glob(filepath.Join(FindingsDirectory, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list findings")
+	}
+	sort.Strings(files)
+	reports := []FindingsReport{}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read findings %v", file)
+		}
+		var report FindingsReport
+		if err := json.Unmarshal(content, &report); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse findings %v", file)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// FindingIssues returns the findings as details of GitHub issues
+func FindingIssues(findings []Finding) []IssueDetail {
+	issues := []IssueDetail{}
+	for _, finding := range findings {
+		issues = append(issues, finding)
+	}
+	return issues
+}
+
+// OpenFindings returns the findings which are not assessed as not affected
+func OpenFindings(findings []Finding) []Finding {
+	open := []Finding{}
+	for _, finding := range findings {
+		if finding.Open() {
+			open = append(open, finding)
+		}
+	}
+	return open
+}
+
+// CreateFindingsScanReport creates a ScanReport which lists the findings ordered by severity.
+// The scan is considered successful if there is no open finding with at least the severity limit.
+func CreateFindingsScanReport(title string, subheaders []Subheader, findings []Finding, severityLimit Severity) ScanReport {
+	return CreateFindingsScanReportFunc(title, subheaders, findings, fmt.Sprintf("Open findings with severity %v or higher", severityLimit), func(finding Finding) bool {
+		return finding.Open() && finding.Severity >= severityLimit
+	})
+}
+
+// CreateFindingsScanReportFunc creates a ScanReport which lists the findings ordered by severity.
+// The scan is considered successful if severe does not apply to any of the findings, severeDescription describes them in the overview.
+func CreateFindingsScanReportFunc(title string, subheaders []Subheader, findings []Finding, severeDescription string, severe func(Finding) bool) ScanReport {
+	sorted := append([]Finding{}, findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Severity != sorted[j].Severity {
+			return sorted[i].Severity > sorted[j].Severity
+		}
+		return sorted[i].CVSSScore > sorted[j].CVSSScore
+	})
+
+	severeCount, notAffected := 0, 0
+	for _, finding := range sorted {
+		if !finding.Open() {
+			notAffected++
+		}
+		if severe(finding) {
+			severeCount++
+		}
+	}
+
+	scanReport := ScanReport{
+		ReportTitle: title,
+		Subheaders:  subheaders,
+		Overview: []OverviewRow{
+			{Description: "Total number of findings", Details: fmt.Sprint(len(sorted))},
+			{Description: severeDescription, Details: fmt.Sprint(severeCount)},
+			{Description: "Findings assessed as not affected", Details: fmt.Sprint(notAffected)},
+		},
+		SuccessfulScan: severeCount == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := ScanDetailTable{
+		NoRowsMessage: "No findings detected",
+		Headers:       []string{"Severity", "ID", "Component / Location", "CVSS Score", "Fix", "Status"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, finding := range sorted {
+		row := newFindingRow(finding, severe(finding))
+		row.AddColumn(finding.CVSSScore, 0)
+		row.AddColumn(finding.FixVersion, 0)
+		row.AddColumn(finding.Status, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
+
+// newFindingRow starts the row of the finding in a detail table with the severity, the ID and the component or location.
+// The CVE is used as ID if known, the component is shown as name@version if there is neither a package URL nor a location.
+func newFindingRow(finding Finding, severe bool) ScanRow {
+	var style ColumnStyle = Yellow
+	if severe {
+		style = Red
+	} else if !finding.Open() {
+		style = Grey
+	}
+	id := finding.ID
+	if len(finding.CVE) > 0 {
+		id = finding.CVE
+	}
+	if len(finding.Link) > 0 {
+		id = fmt.Sprintf(`<a href="%v">%v</a>`, finding.Link, id)
+	}
+	subject := finding.PackageURL
+	if finding.Location != nil {
+		subject = fmt.Sprintf("%v:%v", finding.Location.File, finding.Location.Line)
+	} else if len(subject) == 0 {
+		subject = finding.ComponentName
+		if len(finding.ComponentVersion) > 0 {
+			subject = fmt.Sprintf("%v@%v", finding.ComponentName, finding.ComponentVersion)
+		}
+	}
+	row := ScanRow{}
+	row.AddColumn(finding.Severity, style)
+	row.AddColumn(id, 0)
+	row.AddColumn(subject, 0)
+	return row
+}
+
+// CreateFindingsSarif creates a SARIF report of the findings with one rule per finding ID
+func CreateFindingsSarif(tool format.Driver, findings []Finding) format.SARIF {
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{Tool: format.Tool{Driver: tool}, Results: []format.Results{}}},
+	}
+	rules := map[string]int{}
+	for _, finding := range findings {
+		ruleIndex, known := rules[finding.ID]
+		if !known {
+			ruleIndex = len(sarif.Runs[0].Tool.Driver.Rules)
+			rules[finding.ID] = ruleIndex
+			markdown, _ := finding.ToMarkdown()
+			rule := format.SarifRule{
+				ID:                   finding.ID,
+				Name:                 finding.Name,
+				ShortDescription:     &format.Message{Text: finding.Title()},
+				FullDescription:      &format.Message{Text: finding.Description},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(finding.Severity)},
+				HelpURI:              finding.Link,
+				Help:                 &format.Help{Text: finding.ToTxt(), Markdown: string(markdown)},
+				Properties:           &format.SarifRuleProperties{Tags: sarifTags(finding), Precision: "very-high"},
+			}
+			if finding.CVSSScore > 0 {
+				rule.Properties.SecuritySeverity = fmt.Sprint(finding.CVSSScore)
+			}
+			sarif.Runs[0].Tool.Driver.Rules = append(sarif.Runs[0].Tool.Driver.Rules, rule)
+		}
+
+		result := format.Results{
+			RuleID:    finding.ID,
+			RuleIndex: ruleIndex,
+			Level:     sarifLevel(finding.Severity),
+			Message:   &format.Message{Text: finding.Description},
+			Properties: &format.SarifProperties{
+				Audited:               finding.Status == FindingStatusConfirmed || finding.Status == FindingStatusNotAffected,
+				ToolSeverity:          finding.Severity.String(),
+				ToolState:             string(finding.Status),
+				ToolAuditMessage:      finding.StatusComment,
+				UnifiedAuditState:     sarifAuditState(finding.Status),
+				UnifiedSeverity:       finding.Severity.String(),
+				UnifiedCriticality:    float32(finding.CVSSScore),
+				AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
+				AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
+			},
+		}
+		location := format.Location{}
+		if finding.Location != nil {
+			location.PhysicalLocation.ArtifactLocation.URI = finding.Location.File
+			location.PhysicalLocation.Region = format.Region{StartLine: finding.Location.Line, StartColumn: finding.Location.Column}
+		} else {
+			location.PhysicalLocation.ArtifactLocation.URI = finding.PackageURL
+			result.AnalysisTarget = &format.ArtifactLocation{URI: finding.PackageURL}
+		}
+		result.Locations = []format.Location{location}
+		if len(finding.PackageURL) > 0 {
+			result.PartialFingerprints.PackageURLPlusCVEHash = base64.URLEncoding.EncodeToString([]byte(finding.Key()))
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+	}
+	return sarif
+}
+
+// sarifAuditState maps the status to the unified audit states of the assessments, e.g. inProcess
+func sarifAuditState(status FindingStatus) string {
+	switch status {
+	case FindingStatusInTriage:
+		return string(format.InProcess)
+	case FindingStatusConfirmed:
+		return string(format.Relevant)
+	case FindingStatusNotAffected:
+		return string(format.NotRelevant)
+	}
+	return "new"
+}
+
+func sarifLevel(severity Severity) string {
+	switch {
+	case severity >= SeverityHigh:
+		return "error"
+	case severity == SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+func sarifTags(finding Finding) []string {
+	tags := []string{"security", string(finding.Type)}
+	if len(finding.CWE) > 0 {
+		tags = append(tags, "external/cwe/"+strings.ToLower(finding.CWE))
+	}
+	if len(finding.PackageURL) > 0 {
+		tags = append(tags, finding.PackageURL)
+	}
+	return tags
+}
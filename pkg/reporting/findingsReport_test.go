@@ -0,0 +1,128 @@
+//go:build unit
+// +build unit
+
+package reporting
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFindings = []Finding{
+	{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2022-2", PackageURL: "pkg:npm/lodash@4.17.20", Severity: SeverityMedium, CVSSScore: 5.3, Status: FindingStatusOpen},
+	{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2021-44228", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: SeverityCritical, CVSSScore: 10, FixVersion: "2.17.1", Status: FindingStatusOpen},
+	{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2020-1", PackageURL: "pkg:npm/minimist@1.2.0", Severity: SeverityHigh, CVSSScore: 7.5, Status: FindingStatusNotAffected},
+}
+
+func TestWriteFindingsReport(t *testing.T) {
+	utils := &mock.FilesMock{}
+	file, err := WriteFindingsReport(FindingsReport{StepName: "whitesourceExecuteScan", Scanner: "whitesource", Project: "product", Findings: testFindings}, utils)
+	require.NoError(t, err)
+	assert.Equal(t, FindingsDirectory, filepath.Dir(file))
+
+	_, err = WriteFindingsReport(FindingsReport{StepName: "checkmarxExecuteScan", Scanner: "checkmarx", Project: "product"}, utils)
+	require.NoError(t, err)
+
+	reports, err := ReadFindingsReports(utils)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "checkmarxExecuteScan", reports[0].StepName)
+	assert.Equal(t, []Finding{}, reports[0].Findings)
+	assert.Equal(t, testFindings, reports[1].Findings)
+	assert.False(t, reports[1].ReportTime.IsZero())
+
+	t.Run("invalid file", func(t *testing.T) {
+		utils.AddFile(filepath.Join(FindingsDirectory, "invalid.json"), []byte("{"))
+		_, err := ReadFindingsReports(utils)
+		assert.ErrorContains(t, err, "failed to parse findings")
+	})
+}
+
+func TestWriteFindingsSarif(t *testing.T) {
+	utils := &mock.FilesMock{}
+	file, err := WriteFindingsSarif(CreateFindingsSarif(format.Driver{Name: "whitesource"}, testFindings), "whitesource", utils)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("whitesource", "findings.sarif"), file)
+	content, err := utils.FileRead(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"ruleId": "CVE-2021-44228"`)
+}
+
+func TestOpenFindings(t *testing.T) {
+	t.Parallel()
+	open := OpenFindings(testFindings)
+	assert.Equal(t, testFindings[:2], open)
+	assert.Equal(t, []Finding{}, OpenFindings(nil))
+}
+
+func TestCreateFindingsScanReport(t *testing.T) {
+	t.Parallel()
+	report := CreateFindingsScanReport("Findings", []Subheader{{Description: "Project", Details: "product"}}, testFindings, SeverityHigh)
+	assert.False(t, report.SuccessfulScan)
+	assert.Equal(t, "3", report.Overview[0].Details)
+	assert.Equal(t, "1", report.Overview[1].Details)
+	assert.Equal(t, "1", report.Overview[2].Details)
+	require.Len(t, report.DetailTable.Rows, 3)
+	// ordered by severity
+	assert.Equal(t, "critical", report.DetailTable.Rows[0].Columns[0].Content)
+	assert.Equal(t, ColumnStyle(Red), report.DetailTable.Rows[0].Columns[0].Style)
+	assert.Equal(t, ColumnStyle(Grey), report.DetailTable.Rows[1].Columns[0].Style)
+	assert.Equal(t, ColumnStyle(Yellow), report.DetailTable.Rows[2].Columns[0].Style)
+	assert.Equal(t, "CVE-2021-44228", report.DetailTable.Rows[0].Columns[1].Content)
+	assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", report.DetailTable.Rows[0].Columns[2].Content)
+
+	t.Run("component without package URL", func(t *testing.T) {
+		findings := []Finding{{Scanner: "protecode", ID: "CVE-2022-3", CVE: "CVE-2022-3", ComponentName: "openssl", ComponentVersion: "1.1.1k", Link: "https://nvd.nist.gov/vuln/detail/CVE-2022-3", Severity: SeverityHigh, Status: FindingStatusOpen}}
+		report := CreateFindingsScanReport("Findings", nil, findings, SeverityHigh)
+		require.Len(t, report.DetailTable.Rows, 1)
+		assert.Equal(t, `<a href="https://nvd.nist.gov/vuln/detail/CVE-2022-3">CVE-2022-3</a>`, report.DetailTable.Rows[0].Columns[1].Content)
+		assert.Equal(t, "openssl@1.1.1k", report.DetailTable.Rows[0].Columns[2].Content)
+	})
+
+	report = CreateFindingsScanReport("Findings", nil, testFindings, SeverityCritical+1)
+	assert.True(t, report.SuccessfulScan)
+}
+
+func TestFindingsSarif(t *testing.T) {
+	t.Parallel()
+	t.Run("components", func(t *testing.T) {
+		sarif := CreateFindingsSarif(format.Driver{Name: "whitesource"}, testFindings)
+		require.Len(t, sarif.Runs, 1)
+		assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 3)
+		require.Len(t, sarif.Runs[0].Results, 3)
+		assert.Equal(t, "error", sarif.Runs[0].Results[1].Level)
+		assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.NotEmpty(t, sarif.Runs[0].Results[1].PartialFingerprints.PackageURLPlusCVEHash)
+	})
+
+	t.Run("code findings", func(t *testing.T) {
+		findings := []Finding{
+			{Scanner: "checkmarx", Type: FindingTypeCode, ID: "SQL_Injection", CWE: "CWE-89", Location: &Location{File: "src/db.go", Line: 12, Column: 3}, Severity: SeverityHigh, Status: FindingStatusConfirmed, Description: "query"},
+			{Scanner: "checkmarx", Type: FindingTypeCode, ID: "SQL_Injection", CWE: "CWE-89", Location: &Location{File: "src/user.go", Line: 7}, Severity: SeverityHigh, Status: FindingStatusInTriage, Description: "query"},
+		}
+		sarif := CreateFindingsSarif(format.Driver{Name: "checkmarx"}, findings)
+		require.Len(t, sarif.Runs[0].Tool.Driver.Rules, 1)
+		assert.Contains(t, sarif.Runs[0].Tool.Driver.Rules[0].Properties.Tags, "external/cwe/cwe-89")
+		require.Len(t, sarif.Runs[0].Results, 2)
+		assert.Equal(t, "src/db.go", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 12, sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Empty(t, sarif.Runs[0].Results[0].PartialFingerprints.PackageURLPlusCVEHash)
+	})
+
+	t.Run("audit states", func(t *testing.T) {
+		sarif := CreateFindingsSarif(format.Driver{Name: "whitesource"}, append(testFindings, Finding{Scanner: "whitesource", ID: "CVE-2020-2", Status: FindingStatusInTriage}))
+		properties := sarif.Runs[0].Results[0].Properties
+		assert.Equal(t, "new", properties.UnifiedAuditState)
+		assert.False(t, properties.Audited)
+		assert.Equal(t, format.AUDIT_REQUIREMENT_GROUP_1_DESC, properties.AuditRequirement)
+		assert.Equal(t, "notRelevant", sarif.Runs[0].Results[2].Properties.UnifiedAuditState)
+		assert.True(t, sarif.Runs[0].Results[2].Properties.Audited)
+		assert.Equal(t, "inProcess", sarif.Runs[0].Results[3].Properties.UnifiedAuditState)
+		assert.False(t, sarif.Runs[0].Results[3].Properties.Audited)
+	})
+}
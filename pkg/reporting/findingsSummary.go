@@ -0,0 +1,279 @@
+package reporting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// AggregatedFinding is a finding reported by one or more scanners
+type AggregatedFinding struct {
+	Finding
+	// Fingerprint identifies the finding across scanners and pipeline runs
+	Fingerprint string `json:"fingerprint"`
+	// Scanners lists all scanners which reported the finding
+	Scanners []string `json:"scanners"`
+	// New is set if the finding was not contained in the previous summary
+	New bool `json:"new,omitempty"`
+}
+
+// FindingsSummary consolidates the findings of all scan steps of a pipeline run
+type FindingsSummary struct {
+	ReportTime time.Time           `json:"reportTime"`
+	Findings   []AggregatedFinding `json:"findings"`
+	// Counts contains the number of open findings per severity
+	Counts map[string]int `json:"counts"`
+	// Trend contains the change of Counts compared to the previous summary, Resolved lists the open findings of the previous summary which are no longer reported
+	Trend    map[string]int      `json:"trend,omitempty"`
+	Resolved []AggregatedFinding `json:"resolved,omitempty"`
+}
+
+var statusRank = map[FindingStatus]int{
+	FindingStatusNotAffected: 0,
+	FindingStatusInTriage:    1,
+	FindingStatusOpen:        2,
+	FindingStatusConfirmed:   3,
+}
+
+// AggregateFindings merges the findings of all reports which affect the same package and vulnerability or the same location in the code.
+// Findings without package URL are merged with a finding of the same CVE in a component of the same name and version.
+func AggregateFindings(reports []FindingsReport) []AggregatedFinding {
+	findings := []Finding{}
+	for _, report := range reports {
+		findings = append(findings, report.Findings...)
+	}
+	// findings with package URL are processed first, so that findings without can be attributed to them
+	sort.SliceStable(findings, func(i, j int) bool {
+		return len(findings[i].PackageURL) > 0 && len(findings[j].PackageURL) == 0
+	})
+
+	aggregated := []AggregatedFinding{}
+	byFingerprint := map[string]int{}
+	byComponentCVE := map[string]int{}
+	for _, finding := range findings {
+		fingerprint := Fingerprint(finding)
+		index, known := byFingerprint[fingerprint]
+		componentCVE := ""
+		if len(finding.CVE) > 0 {
+			componentCVE = componentCVEKey(finding)
+		}
+		if !known && len(finding.PackageURL) == 0 && len(componentCVE) > 0 {
+			index, known = byComponentCVE[componentCVE]
+		}
+		if known {
+			aggregated[index].merge(finding)
+			continue
+		}
+		index = len(aggregated)
+		aggregated = append(aggregated, AggregatedFinding{Finding: finding, Fingerprint: fingerprint, Scanners: []string{finding.Scanner}})
+		byFingerprint[fingerprint] = index
+		if _, exists := byComponentCVE[componentCVE]; len(componentCVE) > 0 && !exists {
+			byComponentCVE[componentCVE] = index
+		}
+	}
+
+	sort.SliceStable(aggregated, func(i, j int) bool {
+		if aggregated[i].Severity != aggregated[j].Severity {
+			return aggregated[i].Severity > aggregated[j].Severity
+		}
+		return aggregated[i].CVSSScore > aggregated[j].CVSSScore
+	})
+	return aggregated
+}
+
+// Fingerprint identifies the same finding across scanners: the CVE and the package URL without qualifiers or the weakness at a location in the code
+func Fingerprint(finding Finding) string {
+	if finding.Type == FindingTypeCode || finding.Location != nil {
+		return finding.Key()
+	}
+	id := strings.ToUpper(finding.ID)
+	if len(finding.CVE) > 0 {
+		id = strings.ToUpper(finding.CVE)
+	}
+	switch {
+	case len(finding.PackageURL) > 0:
+		return normalizePackageURL(finding.PackageURL) + "+" + id
+	case len(finding.ComponentName) > 0:
+		return fmt.Sprintf("%v@%v+%v", strings.ToLower(finding.ComponentName), finding.ComponentVersion, id)
+	}
+	return id
+}
+
+// componentCVEKey identifies the CVE in a component by name and version, which are taken from the package URL if available
+func componentCVEKey(finding Finding) string {
+	name, version := finding.ComponentName, finding.ComponentVersion
+	if parsed, err := packageurl.FromString(finding.PackageURL); len(finding.PackageURL) > 0 && err == nil {
+		name, version = parsed.Name, parsed.Version
+	}
+	return fmt.Sprintf("%v@%v+%v", strings.ToLower(name), version, strings.ToUpper(finding.CVE))
+}
+
+// normalizePackageURL removes qualifiers and subpath since the scanners provide different ones
+func normalizePackageURL(purl string) string {
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return strings.ToLower(purl)
+	}
+	normalized := packageurl.NewPackageURL(strings.ToLower(parsed.Type), strings.ToLower(parsed.Namespace), strings.ToLower(parsed.Name), parsed.Version, nil, "")
+	return normalized.ToString()
+}
+
+// merge adds the information of another scanner, the consolidated severity and score is the highest reported one
+func (a *AggregatedFinding) merge(finding Finding) {
+	found := false
+	for _, scanner := range a.Scanners {
+		if scanner == finding.Scanner {
+			found = true
+			break
+		}
+	}
+	if !found {
+		a.Scanners = append(a.Scanners, finding.Scanner)
+	}
+	if finding.Severity > a.Severity {
+		a.Severity = finding.Severity
+	}
+	if finding.CVSSScore > a.CVSSScore {
+		a.CVSSScore = finding.CVSSScore
+		a.CVSSVector = finding.CVSSVector
+	}
+	// the finding stays open unless all scanners agree that it is not affected
+	if statusRank[finding.Status] > statusRank[a.Status] {
+		a.Status = finding.Status
+		a.StatusComment = finding.StatusComment
+	}
+	fill := func(target *string, value string) {
+		if len(*target) == 0 {
+			*target = value
+		}
+	}
+	fill(&a.CVE, finding.CVE)
+	fill(&a.CWE, finding.CWE)
+	fill(&a.Name, finding.Name)
+	fill(&a.Description, finding.Description)
+	fill(&a.PackageURL, finding.PackageURL)
+	fill(&a.ComponentName, finding.ComponentName)
+	fill(&a.ComponentVersion, finding.ComponentVersion)
+	fill(&a.FixVersion, finding.FixVersion)
+	fill(&a.Remediation, finding.Remediation)
+	fill(&a.Link, finding.Link)
+	if a.Published == nil {
+		a.Published = finding.Published
+	}
+}
+
+// CreateFindingsSummary aggregates the findings of all reports and compares them with the previous summary if available
+func CreateFindingsSummary(reports []FindingsReport, previous *FindingsSummary) FindingsSummary {
+	summary := FindingsSummary{
+		ReportTime: time.Now(),
+		Findings:   AggregateFindings(reports),
+	}
+	summary.Counts = countOpenFindings(summary.Findings)
+	if previous == nil {
+		return summary
+	}
+
+	known := map[string]bool{}
+	for _, finding := range previous.Findings {
+		known[finding.Fingerprint] = true
+	}
+	current := map[string]bool{}
+	for i, finding := range summary.Findings {
+		current[finding.Fingerprint] = true
+		summary.Findings[i].New = !known[finding.Fingerprint]
+	}
+	for _, finding := range previous.Findings {
+		if finding.Open() && !current[finding.Fingerprint] {
+			finding.New = false
+			summary.Resolved = append(summary.Resolved, finding)
+		}
+	}
+	summary.Trend = map[string]int{}
+	for severity := SeverityCritical; severity >= SeverityUnknown; severity-- {
+		name := severity.String()
+		summary.Trend[name] = summary.Counts[name] - previous.Counts[name]
+	}
+	return summary
+}
+
+func countOpenFindings(findings []AggregatedFinding) map[string]int {
+	counts := map[string]int{}
+	for severity := SeverityCritical; severity >= SeverityUnknown; severity-- {
+		counts[severity.String()] = 0
+	}
+	for _, finding := range findings {
+		if finding.Open() {
+			counts[finding.Severity.String()]++
+		}
+	}
+	return counts
+}
+
+// CreateFindingsSummaryReport renders the summary as ScanReport, the summary is successful if there is no open finding with at least the severity limit
+func CreateFindingsSummaryReport(summary FindingsSummary, severityLimit Severity) ScanReport {
+	multipleScanners, newFindings, severe := 0, 0, 0
+	for _, finding := range summary.Findings {
+		if len(finding.Scanners) > 1 {
+			multipleScanners++
+		}
+		if finding.New {
+			newFindings++
+		}
+		if finding.Open() && finding.Severity >= severityLimit {
+			severe++
+		}
+	}
+
+	scanReport := ScanReport{
+		StepName:    "pipelineCreateScanSummary",
+		ReportTitle: "Consolidated Security Findings",
+		Overview: []OverviewRow{
+			{Description: "Distinct findings", Details: fmt.Sprint(len(summary.Findings))},
+			{Description: "Findings reported by several scanners", Details: fmt.Sprint(multipleScanners)},
+		},
+		SuccessfulScan: severe == 0,
+		ReportTime:     summary.ReportTime,
+	}
+	for severity := SeverityCritical; severity >= SeverityInfo; severity-- {
+		name := severity.String()
+		row := OverviewRow{Description: fmt.Sprintf("Open findings with severity %v", name), Details: fmt.Sprint(summary.Counts[name])}
+		if summary.Trend != nil {
+			row.Details += fmt.Sprintf(" (%+d)", summary.Trend[name])
+		}
+		if severity >= severityLimit && summary.Counts[name] > 0 {
+			row.Style = Red
+		}
+		scanReport.Overview = append(scanReport.Overview, row)
+	}
+	if summary.Trend != nil {
+		scanReport.Overview = append(scanReport.Overview,
+			OverviewRow{Description: "New findings since the previous run", Details: fmt.Sprint(newFindings)},
+			OverviewRow{Description: "Resolved findings since the previous run", Details: fmt.Sprint(len(summary.Resolved))},
+		)
+	}
+
+	detailTable := ScanDetailTable{
+		NoRowsMessage: "No findings detected",
+		Headers:       []string{"Severity", "ID", "Component / Location", "Scanners", "CVSS Score", "Fix", "Status", "Trend"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, finding := range summary.Findings {
+		trend := ""
+		if finding.New {
+			trend = "new"
+		}
+		row := newFindingRow(finding.Finding, finding.Open() && finding.Severity >= severityLimit)
+		row.AddColumn(strings.Join(finding.Scanners, ", "), 0)
+		row.AddColumn(finding.CVSSScore, 0)
+		row.AddColumn(finding.FixVersion, 0)
+		row.AddColumn(finding.Status, 0)
+		row.AddColumn(trend, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
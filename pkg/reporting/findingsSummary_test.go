@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+package reporting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var summaryTestReports = []FindingsReport{
+	{StepName: "protecodeExecuteScan", Scanner: "protecode", Findings: []Finding{
+		{Scanner: "protecode", Type: FindingTypeVulnerability, ID: "CVE-2021-44228", CVE: "CVE-2021-44228", ComponentName: "log4j-core", ComponentVersion: "2.14.1", Severity: SeverityCritical, CVSSScore: 10, Status: FindingStatusOpen},
+	}},
+	{StepName: "whitesourceExecuteScan", Scanner: "whitesource", Findings: []Finding{
+		{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2021-44228", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: SeverityHigh, CVSSScore: 9.3, FixVersion: "2.17.1", Status: FindingStatusNotAffected},
+		{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2021-23337", CVE: "CVE-2021-23337", PackageURL: "pkg:npm/lodash@4.17.20", Severity: SeverityHigh, CVSSScore: 7.2, Status: FindingStatusOpen},
+	}},
+	{StepName: "detectExecuteScan", Scanner: "blackduck", Findings: []Finding{
+		{Scanner: "blackduck", Type: FindingTypeVulnerability, ID: "BDSA-2021-3665", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?type=jar", Severity: SeverityCritical, CVSSScore: 10, Status: FindingStatusConfirmed, Link: "https://blackduck/BDSA-2021-3665"},
+		{Scanner: "blackduck", Type: FindingTypeVulnerability, ID: "CVE-2022-1", PackageURL: "pkg:npm/minimist@1.2.5", Severity: SeverityMedium, Status: FindingStatusOpen},
+	}},
+	{StepName: "checkmarxExecuteScan", Scanner: "checkmarx", Findings: []Finding{
+		{Scanner: "checkmarx", Type: FindingTypeCode, ID: "SQL_Injection", CWE: "CWE-89", Location: &Location{File: "src/db.go", Line: 12}, Severity: SeverityHigh, Status: FindingStatusOpen},
+	}},
+	{StepName: "fortifyExecuteScan", Scanner: "fortify", Findings: []Finding{
+		{Scanner: "fortify", Type: FindingTypeCode, ID: "SQL Injection", CWE: "CWE-89", Location: &Location{File: "src/db.go", Line: 12}, Severity: SeverityCritical, Status: FindingStatusOpen},
+	}},
+}
+
+func TestAggregateFindings(t *testing.T) {
+	t.Parallel()
+	findings := AggregateFindings(summaryTestReports)
+	require.Len(t, findings, 4)
+
+	log4j := findings[0]
+	assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1+CVE-2021-44228", log4j.Fingerprint)
+	assert.Equal(t, []string{"whitesource", "blackduck", "protecode"}, log4j.Scanners)
+	assert.Equal(t, SeverityCritical, log4j.Severity)
+	assert.Equal(t, 10.0, log4j.CVSSScore)
+	assert.Equal(t, FindingStatusConfirmed, log4j.Status)
+	assert.Equal(t, "2.17.1", log4j.FixVersion)
+	assert.Equal(t, "https://blackduck/BDSA-2021-3665", log4j.Link)
+
+	sqlInjection := findings[1]
+	assert.Equal(t, []string{"checkmarx", "fortify"}, sqlInjection.Scanners)
+	assert.Equal(t, SeverityCritical, sqlInjection.Severity)
+
+	assert.Equal(t, []string{"whitesource"}, findings[2].Scanners)
+	assert.Equal(t, "CVE-2022-1", findings[3].ID)
+}
+
+func TestAggregateFindingsSharedCVE(t *testing.T) {
+	t.Parallel()
+	// one vulnerability affects two components, the findings without package URL must only be merged with the finding of the same component
+	findings := AggregateFindings([]FindingsReport{
+		{Scanner: "whitesource", Findings: []Finding{
+			{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2022-42889", CVE: "CVE-2022-42889", PackageURL: "pkg:maven/org.apache.commons/commons-text@1.9", Severity: SeverityCritical, Status: FindingStatusOpen},
+			{Scanner: "whitesource", Type: FindingTypeVulnerability, ID: "CVE-2022-42889", CVE: "CVE-2022-42889", PackageURL: "pkg:maven/org.apache.commons/commons-configuration2@2.7", Severity: SeverityCritical, Status: FindingStatusOpen},
+		}},
+		{Scanner: "protecode", Findings: []Finding{
+			{Scanner: "protecode", Type: FindingTypeVulnerability, ID: "CVE-2022-42889", CVE: "CVE-2022-42889", ComponentName: "commons-configuration2", ComponentVersion: "2.7", Severity: SeverityCritical, Status: FindingStatusOpen},
+			{Scanner: "protecode", Type: FindingTypeVulnerability, ID: "CVE-2022-42889", CVE: "CVE-2022-42889", ComponentName: "commons-text", ComponentVersion: "1.10.0", Severity: SeverityCritical, Status: FindingStatusOpen},
+		}},
+	})
+
+	require.Len(t, findings, 3)
+	assert.Equal(t, []string{"whitesource"}, findings[0].Scanners)
+	assert.Equal(t, "pkg:maven/org.apache.commons/commons-text@1.9", findings[0].PackageURL)
+	assert.Equal(t, []string{"whitesource", "protecode"}, findings[1].Scanners)
+	assert.Equal(t, "pkg:maven/org.apache.commons/commons-configuration2@2.7", findings[1].PackageURL)
+	assert.Equal(t, []string{"protecode"}, findings[2].Scanners)
+	assert.Equal(t, "commons-text@1.10.0+CVE-2022-42889", findings[2].Fingerprint)
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "pkg:npm/lodash@4.17.20+CVE-2021-23337", Fingerprint(Finding{ID: "cve-2021-23337", PackageURL: "pkg:NPM/Lodash@4.17.20"}))
+	assert.Equal(t, "lodash@4.17.20+CVE-2021-23337", Fingerprint(Finding{CVE: "CVE-2021-23337", ComponentName: "Lodash", ComponentVersion: "4.17.20"}))
+	assert.Equal(t, "src/db.go:12+CWE-89", Fingerprint(Finding{Type: FindingTypeCode, ID: "SQL_Injection", CWE: "CWE-89", Location: &Location{File: "src/db.go", Line: 12}}))
+	assert.Equal(t, "CVE-2021-1", Fingerprint(Finding{CVE: "CVE-2021-1"}))
+}
+
+func TestCreateFindingsSummary(t *testing.T) {
+	t.Parallel()
+	t.Run("without previous summary", func(t *testing.T) {
+		summary := CreateFindingsSummary(summaryTestReports, nil)
+		assert.Len(t, summary.Findings, 4)
+		assert.Equal(t, 2, summary.Counts["critical"])
+		assert.Equal(t, 1, summary.Counts["high"])
+		assert.Equal(t, 1, summary.Counts["medium"])
+		assert.Nil(t, summary.Trend)
+		assert.False(t, summary.Findings[0].New)
+
+		report := CreateFindingsSummaryReport(summary, SeverityHigh)
+		assert.False(t, report.SuccessfulScan)
+		assert.Equal(t, "4", report.Overview[0].Details)
+		assert.Equal(t, "2", report.Overview[1].Details)
+		assert.Equal(t, "2", report.Overview[2].Details)
+		assert.Len(t, report.Overview, 7)
+		require.Len(t, report.DetailTable.Rows, 4)
+		assert.Equal(t, "whitesource, blackduck, protecode", report.DetailTable.Rows[0].Columns[3].Content)
+	})
+
+	t.Run("trend", func(t *testing.T) {
+		previous := CreateFindingsSummary(summaryTestReports[1:3], nil)
+		previous.Findings = append(previous.Findings, AggregatedFinding{Finding: Finding{ID: "CVE-2020-1", Severity: SeverityHigh, Status: FindingStatusOpen}, Fingerprint: "CVE-2020-1"})
+		previous.Counts["high"]++
+
+		summary := CreateFindingsSummary(summaryTestReports, &previous)
+
+		assert.Equal(t, 1, summary.Trend["critical"])
+		assert.Equal(t, -1, summary.Trend["high"])
+		assert.Equal(t, 0, summary.Trend["medium"])
+		require.Len(t, summary.Resolved, 1)
+		assert.Equal(t, "CVE-2020-1", summary.Resolved[0].ID)
+		assert.False(t, summary.Findings[0].New)
+		assert.True(t, summary.Findings[1].New)
+
+		report := CreateFindingsSummaryReport(summary, SeverityHigh)
+		assert.Equal(t, "2 (+1)", report.Overview[2].Details)
+		assert.Equal(t, "1 (-1)", report.Overview[3].Details)
+		assert.Equal(t, "1", report.Overview[7].Details)
+		assert.Equal(t, "1", report.Overview[8].Details)
+		assert.Equal(t, "new", report.DetailTable.Rows[1].Columns[7].Content)
+	})
+}
@@ -0,0 +1,128 @@
+package reporting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/package-url/packageurl-go"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// CreateVEX creates an OpenVEX document with a statement for each vulnerability of the summary and for each assessment which does not apply to any of them.
+// If productID is given, e.g. the package URL of the released product, the vulnerable packages are listed as its subcomponents.
+func CreateVEX(summary FindingsSummary, assessments []format.Assessment, author, productID string) format.OpenVEX {
+	document := format.OpenVEX{
+		Context:    format.OpenVEXContext,
+		ID:         fmt.Sprintf("urn:uuid:%v", uuid.New()),
+		Author:     author,
+		Timestamp:  summary.ReportTime,
+		Version:    1,
+		Tooling:    "Project Piper",
+		Statements: []format.OpenVEXStatement{},
+	}
+	applied := map[*format.Assessment]bool{}
+	for _, finding := range summary.Findings {
+		if finding.Type != FindingTypeVulnerability || finding.Location != nil {
+			continue
+		}
+		purl, id := vexPackageURL(finding.Finding)
+		statement := format.OpenVEXStatement{
+			Vulnerability: format.OpenVEXVulnerability{Name: finding.ID},
+			Products:      vexProducts(productID, id),
+		}
+		if len(finding.CVE) > 0 {
+			statement.Vulnerability.Name = finding.CVE
+		}
+		if assessment := format.FindAssessment(assessments, purl, finding.CVE, finding.ID); assessment != nil {
+			applied[assessment] = true
+			applyAssessment(&statement, *assessment)
+			if statement.Status == format.VEXAffected {
+				statement.ActionStatement = actionStatement(finding.Finding)
+			}
+			document.Statements = append(document.Statements, statement)
+			continue
+		}
+		switch finding.Status {
+		case FindingStatusNotAffected:
+			statement.Status = format.VEXNotAffected
+			statement.ImpactStatement = finding.StatusComment
+			if len(statement.ImpactStatement) == 0 {
+				statement.ImpactStatement = fmt.Sprintf("Assessed as not affected in %v", strings.Join(finding.Scanners, ", "))
+			}
+		case FindingStatusInTriage:
+			statement.Status = format.VEXUnderInvestigation
+		default:
+			statement.Status = format.VEXAffected
+			statement.ActionStatement = actionStatement(finding.Finding)
+		}
+		document.Statements = append(document.Statements, statement)
+	}
+
+	for i, assessment := range assessments {
+		if applied[&assessments[i]] {
+			continue
+		}
+		statement := format.OpenVEXStatement{Vulnerability: format.OpenVEXVulnerability{Name: assessment.Vulnerability}}
+		for _, purl := range assessment.Purls {
+			statement.Products = append(statement.Products, vexProducts(productID, purl.Purl)...)
+		}
+		applyAssessment(&statement, assessment)
+		if statement.Status == format.VEXAffected {
+			statement.ActionStatement = fmt.Sprintf("Assessed as %v", assessment.Analysis)
+		}
+		document.Statements = append(document.Statements, statement)
+	}
+	return document
+}
+
+func applyAssessment(statement *format.OpenVEXStatement, assessment format.Assessment) {
+	statement.Status = assessment.ToVEXStatus()
+	if len(assessment.Analysis) > 0 {
+		statement.StatusNotes = fmt.Sprintf("Assessment: %v", assessment.Analysis)
+	}
+	if statement.Status != format.VEXNotAffected {
+		return
+	}
+	// a not affected statement requires either a justification or an impact statement
+	statement.Justification = assessment.ToVEXJustification()
+	if len(statement.Justification) == 0 {
+		statement.ImpactStatement = fmt.Sprintf("Assessed as %v", assessment.Analysis)
+	}
+}
+
+// vexPackageURL returns the package URL of the vulnerable component to match assessments and to identify it in the statement.
+// If the scanner does not know the type of the package, assessments are matched by name and version and a generic package URL identifies it.
+func vexPackageURL(finding Finding) (packageurl.PackageURL, string) {
+	if purl, err := packageurl.FromString(finding.PackageURL); err == nil {
+		return purl, finding.PackageURL
+	}
+	if len(finding.ComponentName) == 0 {
+		return packageurl.PackageURL{}, ""
+	}
+	return packageurl.PackageURL{Name: finding.ComponentName, Version: finding.ComponentVersion},
+		packageurl.NewPackageURL("generic", "", finding.ComponentName, finding.ComponentVersion, nil, "").ToString()
+}
+
+func vexProducts(productID, purl string) []format.OpenVEXProduct {
+	switch {
+	case len(productID) == 0 && len(purl) == 0:
+		return nil
+	case len(productID) == 0:
+		return []format.OpenVEXProduct{{ID: purl}}
+	case len(purl) == 0:
+		return []format.OpenVEXProduct{{ID: productID}}
+	}
+	return []format.OpenVEXProduct{{ID: productID, Subcomponents: []format.OpenVEXProduct{{ID: purl}}}}
+}
+
+func actionStatement(finding Finding) string {
+	switch {
+	case len(finding.FixVersion) > 0:
+		return fmt.Sprintf("Update to version %v", finding.FixVersion)
+	case len(finding.Remediation) > 0:
+		return finding.Remediation
+	}
+	return "No fix is available yet"
+}
@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+func TestCreateVEX(t *testing.T) {
+	reportTime := time.Date(2023, 10, 18, 10, 0, 0, 0, time.UTC)
+	summary := FindingsSummary{
+		ReportTime: reportTime,
+		Findings: []AggregatedFinding{
+			{Finding: Finding{Type: FindingTypeVulnerability, ID: "BDSA-1", CVE: "CVE-2023-1", PackageURL: "pkg:npm/lodash@4.17.20", Status: FindingStatusOpen, FixVersion: "4.17.21"}, Scanners: []string{"blackduck"}},
+			{Finding: Finding{Type: FindingTypeVulnerability, ID: "CVE-2023-2", CVE: "CVE-2023-2", ComponentName: "busybox", ComponentVersion: "1.27.2", Status: FindingStatusOpen}, Scanners: []string{"protecode"}},
+			{Finding: Finding{Type: FindingTypeVulnerability, ID: "CVE-2023-3", PackageURL: "pkg:npm/express@4.0.0", Status: FindingStatusNotAffected}, Scanners: []string{"whitesource"}},
+			{Finding: Finding{Type: FindingTypeVulnerability, ID: "CVE-2023-4", PackageURL: "pkg:npm/express@4.0.0", Status: FindingStatusInTriage}, Scanners: []string{"whitesource"}},
+			{Finding: Finding{Type: FindingTypeCode, ID: "SQL Injection", Location: &Location{File: "main.go", Line: 42}, Status: FindingStatusOpen}, Scanners: []string{"checkmarx"}},
+		},
+	}
+	assessments := []format.Assessment{
+		{Vulnerability: "CVE-2023-2", Status: format.NotRelevant, Analysis: format.NotPresent, Purls: []format.Purl{{Purl: "pkg:apk/alpine/busybox@1.27.2"}}},
+		{Vulnerability: "CVE-2023-5", Status: format.NotRelevant, Analysis: format.RiskAccepted, Purls: []format.Purl{{Purl: "pkg:npm/express@4.0.0"}}},
+	}
+
+	t.Run("without product", func(t *testing.T) {
+		document := CreateVEX(summary, assessments, "ACME", "")
+
+		assert.Equal(t, format.OpenVEXContext, document.Context)
+		assert.Contains(t, document.ID, "urn:uuid:")
+		assert.Equal(t, "ACME", document.Author)
+		assert.Equal(t, reportTime, document.Timestamp)
+		assert.Equal(t, []format.OpenVEXStatement{
+			{
+				Vulnerability:   format.OpenVEXVulnerability{Name: "CVE-2023-1"},
+				Products:        []format.OpenVEXProduct{{ID: "pkg:npm/lodash@4.17.20"}},
+				Status:          format.VEXAffected,
+				ActionStatement: "Update to version 4.17.21",
+			},
+			{
+				Vulnerability: format.OpenVEXVulnerability{Name: "CVE-2023-2"},
+				Products:      []format.OpenVEXProduct{{ID: "pkg:generic/busybox@1.27.2"}},
+				Status:        format.VEXNotAffected,
+				StatusNotes:   "Assessment: notPresent",
+				Justification: format.VEXVulnerableCodeNotPresent,
+			},
+			{
+				Vulnerability:   format.OpenVEXVulnerability{Name: "CVE-2023-3"},
+				Products:        []format.OpenVEXProduct{{ID: "pkg:npm/express@4.0.0"}},
+				Status:          format.VEXNotAffected,
+				ImpactStatement: "Assessed as not affected in whitesource",
+			},
+			{
+				Vulnerability: format.OpenVEXVulnerability{Name: "CVE-2023-4"},
+				Products:      []format.OpenVEXProduct{{ID: "pkg:npm/express@4.0.0"}},
+				Status:        format.VEXUnderInvestigation,
+			},
+			{
+				Vulnerability:   format.OpenVEXVulnerability{Name: "CVE-2023-5"},
+				Products:        []format.OpenVEXProduct{{ID: "pkg:npm/express@4.0.0"}},
+				Status:          format.VEXNotAffected,
+				StatusNotes:     "Assessment: riskAccepted",
+				ImpactStatement: "Assessed as riskAccepted",
+			},
+		}, document.Statements)
+	})
+
+	t.Run("with product", func(t *testing.T) {
+		document := CreateVEX(summary, nil, "ACME", "pkg:oci/app@sha256:abc")
+
+		if assert.Len(t, document.Statements, 4) {
+			assert.Equal(t, []format.OpenVEXProduct{{ID: "pkg:oci/app@sha256:abc", Subcomponents: []format.OpenVEXProduct{{ID: "pkg:npm/lodash@4.17.20"}}}}, document.Statements[0].Products)
+		}
+	})
+}
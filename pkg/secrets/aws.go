@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AWSSecretsManager reads secrets from AWS Secrets Manager, the path is used as name of the secret
+type AWSSecretsManager struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	client      httpClient
+}
+
+// NewAWSSecretsManager creates a client for the given region, the region of the AWS configuration is used if it is empty.
+// It authenticates via the AWS default credential chain, e.g. the environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or a web identity.
+// The endpoint allows to use e.g. a VPC endpoint, by default the public endpoint of the region is used.
+func NewAWSSecretsManager(region, endpoint string) (*AWSSecretsManager, error) {
+	options := []func(*awsconfig.LoadOptions) error{}
+	if len(region) > 0 {
+		options = append(options, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if len(cfg.Region) == 0 {
+		return nil, fmt.Errorf("no AWS region configured")
+	}
+	if len(endpoint) == 0 {
+		endpoint = fmt.Sprintf("https://secretsmanager.%v.amazonaws.com", cfg.Region)
+	}
+	return &AWSSecretsManager{endpoint: endpoint, region: cfg.Region, credentials: cfg.Credentials, client: newHTTPClient()}, nil
+}
+
+// GetKvSecret returns the fields of the secret, nil is returned if the secret does not exist
+func (a *AWSSecretsManager) GetKvSecret(path string) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	request, err := http.NewRequest(http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	ctx := context.Background()
+	credentials, err := a.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, request, hex.EncodeToString(payloadHash[:]), "secretsmanager", a.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	response, err := a.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP %v request to %v failed: %w", request.Method, request.URL.Host, err)
+	}
+	defer response.Body.Close()
+	var result struct {
+		SecretString string `json:"SecretString"`
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response of AWS Secrets Manager: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		// Secrets Manager reports missing secrets with status 400, the type may be prefixed with a namespace
+		// like com.amazonaws.secretsmanager#ResourceNotFoundException
+		if strings.HasSuffix(result.Type, "ResourceNotFoundException") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read secret '%v' from AWS Secrets Manager: %v %v", path, result.Type, result.Message)
+	}
+	return parseFields(path, result.SecretString)
+}
@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSSecretsManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-central-1/secretsmanager/aws4_request")
+		var request map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		switch request["SecretId"] {
+		case "piper/my-pipeline/sonar":
+			fmt.Fprint(w, `{"Name": "piper/my-pipeline/sonar", "SecretString": "{\"token\": \"secret\"}"}`)
+		case "piper/namespaced":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "com.amazonaws.secretsmanager#ResourceNotFoundException", "message": "Secrets Manager can't find the specified secret."}`)
+		case "piper/denied":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "AccessDeniedException", "message": "not allowed"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "ResourceNotFoundException", "message": "Secrets Manager can't find the specified secret."}`)
+		}
+	}))
+	defer server.Close()
+
+	secretsManager := &AWSSecretsManager{
+		endpoint:    server.URL,
+		region:      "eu-central-1",
+		credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		client:      server.Client(),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		secret, err := secretsManager.GetKvSecret("piper/my-pipeline/sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secret"}, secret)
+	})
+
+	t.Run("secret does not exist", func(t *testing.T) {
+		secret, err := secretsManager.GetKvSecret("piper/GROUP-SECRETS/sonar")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("secret does not exist, type with namespace", func(t *testing.T) {
+		secret, err := secretsManager.GetKvSecret("piper/namespaced")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := secretsManager.GetKvSecret("piper/denied")
+		assert.EqualError(t, err, "failed to read secret 'piper/denied' from AWS Secrets Manager: AccessDeniedException not allowed")
+	})
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultScope      = "https://vault.azure.net/.default"
+)
+
+// AzureKeyVault reads secrets from an Azure Key Vault.
+// Since the names of Key Vault secrets must not contain slashes, the path piper/my-pipeline/sonar refers to the secret piper-my-pipeline-sonar.
+type AzureKeyVault struct {
+	vaultURL string
+	client   httpClient
+	token    func() (string, error)
+}
+
+// NewAzureKeyVault creates a client for the Key Vault with the given URL, e.g. https://my-vault.vault.azure.net.
+// It authenticates via the Azure default credential chain, e.g. the environment variables AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_CLIENT_SECRET or a managed identity.
+func NewAzureKeyVault(vaultURL string) (*AzureKeyVault, error) {
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credentials: %w", err)
+	}
+	token := func() (string, error) {
+		accessToken, err := credential.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{azureKeyVaultScope}})
+		if err != nil {
+			return "", fmt.Errorf("failed to retrieve Azure access token: %w", err)
+		}
+		return accessToken.Token, nil
+	}
+	return newAzureKeyVault(vaultURL, newHTTPClient(), token), nil
+}
+
+func newAzureKeyVault(vaultURL string, client httpClient, token func() (string, error)) *AzureKeyVault {
+	return &AzureKeyVault{vaultURL: strings.TrimSuffix(vaultURL, "/"), client: client, token: token}
+}
+
+// GetKvSecret returns the fields of the secret, nil is returned if the secret does not exist
+func (a *AzureKeyVault) GetKvSecret(path string) (map[string]string, error) {
+	token, err := a.token()
+	if err != nil {
+		return nil, err
+	}
+	log.RegisterSecret(token)
+
+	secretURL := fmt.Sprintf("%v/secrets/%v?api-version=%v", a.vaultURL, url.PathEscape(secretName(path)), azureKeyVaultAPIVersion)
+	request, err := http.NewRequest(http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	content, found, err := send(a.client, request)
+	if err != nil || !found {
+		return nil, err
+	}
+	var secret struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(content, &secret); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return parseFields(path, secret.Value)
+}
@@ -0,0 +1,63 @@
+//go:build unit
+// +build unit
+
+package secrets
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureKeyVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/secrets/piper-my-pipeline-sonar":
+			assert.Equal(t, "7.4", r.URL.Query().Get("api-version"))
+			fmt.Fprint(w, `{"value": "{\"token\": \"secret\", \"port\": 443}", "id": "https://my-vault.vault.azure.net/secrets/piper-my-pipeline-sonar/1"}`)
+		case "/secrets/piper-plain":
+			fmt.Fprint(w, `{"value": "secret"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": "SecretNotFound"}}`)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		keyVault := newAzureKeyVault(server.URL+"/", server.Client(), func() (string, error) { return "token", nil })
+		secret, err := keyVault.GetKvSecret("piper/my-pipeline/sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secret", "port": "443"}, secret)
+	})
+
+	t.Run("secret does not exist", func(t *testing.T) {
+		keyVault := newAzureKeyVault(server.URL, server.Client(), func() (string, error) { return "token", nil })
+		secret, err := keyVault.GetKvSecret("piper/GROUP-SECRETS/sonar")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("secret is no JSON object", func(t *testing.T) {
+		keyVault := newAzureKeyVault(server.URL, server.Client(), func() (string, error) { return "token", nil })
+		_, err := keyVault.GetKvSecret("piper/plain")
+		assert.ErrorContains(t, err, "secret 'piper/plain' does not contain a JSON object")
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		keyVault := newAzureKeyVault(server.URL, server.Client(), func() (string, error) { return "invalid", nil })
+		_, err := keyVault.GetKvSecret("piper/my-pipeline/sonar")
+		assert.ErrorContains(t, err, "401 Unauthorized")
+	})
+}
+
+func TestSecretName(t *testing.T) {
+	assert.Equal(t, "piper-my-pipeline-sonar", secretName("/piper/my_pipeline/sonar"))
+}
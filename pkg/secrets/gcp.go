@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/secretmanager/v1"
+)
+
+// GCPSecretManager reads the latest version of secrets from Google Cloud Secret Manager.
+// Since the names of secrets must not contain slashes, the path piper/my-pipeline/sonar refers to the secret piper-my-pipeline-sonar.
+type GCPSecretManager struct {
+	project string
+	service *secretmanager.Service
+}
+
+// NewGCPSecretManager creates a client for the secrets of the project.
+// It authenticates via the application default credentials, e.g. the environment variable GOOGLE_APPLICATION_CREDENTIALS.
+func NewGCPSecretManager(project string, opts ...option.ClientOption) (*GCPSecretManager, error) {
+	if len(project) == 0 {
+		return nil, fmt.Errorf("no Google Cloud project configured")
+	}
+	service, err := secretmanager.NewService(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secret Manager client: %w", err)
+	}
+	return &GCPSecretManager{project: project, service: service}, nil
+}
+
+// GetKvSecret returns the fields of the secret, nil is returned if the secret does not exist
+func (g *GCPSecretManager) GetKvSecret(path string) (map[string]string, error) {
+	name := fmt.Sprintf("projects/%v/secrets/%v/versions/latest", g.project, secretName(path))
+	response, err := g.service.Projects.Secrets.Versions.Access(name).Do()
+	if err != nil {
+		var apiError *googleapi.Error
+		if errors.As(err, &apiError) && apiError.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read secret '%v' from Secret Manager: %w", secretName(path), err)
+	}
+	if response.Payload == nil {
+		return nil, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret '%v': %w", secretName(path), err)
+	}
+	return parseFields(path, string(content))
+}
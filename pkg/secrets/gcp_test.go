@@ -0,0 +1,56 @@
+//go:build unit
+// +build unit
+
+package secrets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+func TestGCPSecretManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/piper-my-pipeline-sonar/versions/latest:access":
+			fmt.Fprintf(w, `{"name": "projects/1/secrets/piper-my-pipeline-sonar/versions/2", "payload": {"data": "%v"}}`, base64.StdEncoding.EncodeToString([]byte(`{"token": "secret"}`)))
+		case "/v1/projects/my-project/secrets/piper-denied/versions/latest:access":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": {"code": 403, "message": "Permission denied"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404, "message": "Secret not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	secretManager, err := NewGCPSecretManager("my-project", option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		secret, err := secretManager.GetKvSecret("piper/my-pipeline/sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secret"}, secret)
+	})
+
+	t.Run("secret does not exist", func(t *testing.T) {
+		secret, err := secretManager.GetKvSecret("piper/GROUP-SECRETS/sonar")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := secretManager.GetKvSecret("piper/denied")
+		assert.ErrorContains(t, err, "failed to read secret 'piper-denied' from Secret Manager")
+	})
+
+	t.Run("project missing", func(t *testing.T) {
+		_, err := NewGCPSecretManager("")
+		assert.EqualError(t, err, "no Google Cloud project configured")
+	})
+}
@@ -0,0 +1,75 @@
+// Package secrets provides clients for secret stores besides Vault, which resolve the secrets referenced in the step configuration.
+// Like for Vault, a secret is addressed by a path and contains several fields. Stores which only support single values
+// need to contain the fields as JSON object, e.g. {"token": "...", "user": "..."}.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// requestTimeout limits the duration of a request to a secret store, otherwise an unreachable store blocks the step
+const requestTimeout = 30 * time.Second
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// httpClient sends the requests to the secret stores. The piper http client is not used since it logs an error
+// for every secret which does not exist, while probing several paths for a secret is expected to fail for some of them.
+type httpClient interface {
+	Do(request *http.Request) (*http.Response, error)
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// send returns the body of the response, found is false if the secret does not exist
+func send(client httpClient, request *http.Request) ([]byte, bool, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("HTTP %v request to %v failed: %w", request.Method, request.URL.Host, err)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("request to %v returned with response %v: %v", request.URL.Host, response.Status, strings.TrimSpace(string(content)))
+	}
+	return content, true, nil
+}
+
+// secretName converts a path into a name supported by stores which do not allow slashes, e.g. piper/my-pipeline/sonar becomes piper-my-pipeline-sonar
+func secretName(path string) string {
+	return strings.Trim(invalidNameCharacters.ReplaceAllString(path, "-"), "-")
+}
+
+// parseFields parses the JSON object stored in a secret, values which are not strings are returned JSON encoded
+func parseFields(path, content string) (map[string]string, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("secret '%v' does not contain a JSON object: %w", path, err)
+	}
+	fields := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if str, ok := value.(string); ok {
+			fields[key] = str
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field '%v' of secret '%v': %w", key, path, err)
+		}
+		fields[key] = string(encoded)
+	}
+	return fields, nil
+}
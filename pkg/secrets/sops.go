@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SopsFile reads secrets from a file encrypted with SOPS, e.g. using age keys provided via SOPS_AGE_KEY_FILE.
+// The path refers to nested keys of the file, i.e. piper/my-pipeline/sonar refers to
+//
+//	piper:
+//	  my-pipeline:
+//	    sonar:
+//	      token: ...
+type SopsFile struct {
+	file    string
+	decrypt func(file string) ([]byte, error)
+	content map[string]interface{}
+}
+
+// NewSopsFile creates a client for the encrypted file, the file is decrypted with the sops executable on first access
+func NewSopsFile(file string) *SopsFile {
+	return newSopsFile(file, runSops)
+}
+
+func newSopsFile(file string, decrypt func(file string) ([]byte, error)) *SopsFile {
+	return &SopsFile{file: file, decrypt: decrypt}
+}
+
+// GetKvSecret returns the fields of the secret, nil is returned if the secret does not exist
+func (s *SopsFile) GetKvSecret(path string) (map[string]string, error) {
+	if s.content == nil {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	var current interface{} = s.content
+	for _, key := range strings.Split(strings.Trim(path, "/"), "/") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		if current, ok = node[key]; !ok {
+			return nil, nil
+		}
+	}
+	node, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("secret '%v' in %v is not a map of fields", path, s.file)
+	}
+	encoded, err := json.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode secret '%v': %w", path, err)
+	}
+	return parseFields(path, string(encoded))
+}
+
+func (s *SopsFile) load() error {
+	decrypted, err := s.decrypt(s.file)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %v: %w", s.file, err)
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(decrypted, &content); err != nil {
+		return fmt.Errorf("failed to parse decrypted content of %v: %w", s.file, err)
+	}
+	s.content = content
+	return nil
+}
+
+// runSops decrypts the file with the sops executable.
+// It is not called via command.Command since the decrypted content contains all secrets of the file,
+// which must neither be written to the command transcript nor be affected by dry-run mode or retries.
+func runSops(file string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sops", "--decrypt", "--output-type", "json", file)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,53 @@
+//go:build unit
+// +build unit
+
+package secrets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSopsFile(t *testing.T) {
+	decrypted := `{"piper": {"my-pipeline": {"sonar": {"token": "secret", "port": 443}}, "plain": "value"}}`
+
+	t.Run("success", func(t *testing.T) {
+		calls := 0
+		sopsFile := newSopsFile("secrets.enc.yaml", func(file string) ([]byte, error) {
+			calls++
+			assert.Equal(t, "secrets.enc.yaml", file)
+			return []byte(decrypted), nil
+		})
+
+		secret, err := sopsFile.GetKvSecret("piper/my-pipeline/sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secret", "port": "443"}, secret)
+
+		secret, err = sopsFile.GetKvSecret("piper/GROUP-SECRETS/sonar")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+
+		_, err = sopsFile.GetKvSecret("piper/plain")
+		assert.EqualError(t, err, "secret 'piper/plain' in secrets.enc.yaml is not a map of fields")
+
+		// the file is only decrypted once
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("decryption fails", func(t *testing.T) {
+		sopsFile := newSopsFile("secrets.enc.yaml", func(string) ([]byte, error) {
+			return nil, errors.New("exit status 128: no key")
+		})
+
+		_, err := sopsFile.GetKvSecret("piper/my-pipeline/sonar")
+		assert.EqualError(t, err, "failed to decrypt secrets.enc.yaml: exit status 128: no key")
+	})
+
+	t.Run("sops not available", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		_, err := runSops("secrets.enc.yaml")
+		assert.ErrorContains(t, err, "executable file not found")
+	})
+}
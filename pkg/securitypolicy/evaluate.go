@@ -0,0 +1,242 @@
+package securitypolicy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// Violation of a rule by a finding
+type Violation struct {
+	Rule    Rule                        `json:"rule"`
+	Finding reporting.AggregatedFinding `json:"finding"`
+	// Waiver is set if the violation is accepted
+	Waiver *Waiver `json:"waiver,omitempty"`
+}
+
+// Failing returns whether the violation fails the security gate
+func (v Violation) Failing() bool {
+	return v.Waiver == nil && v.Rule.Action == ActionFail
+}
+
+// Explanation describes why the finding violates the rule
+func (v Violation) Explanation() string {
+	explanation := fmt.Sprintf("%v %v %v violates rule %v", v.Finding.Severity, scanners(v.Finding), subject(v.Finding.Finding), v.Rule.Name)
+	if len(v.Rule.Description) > 0 {
+		explanation += ": " + v.Rule.Description
+	}
+	if v.Waiver != nil {
+		explanation += fmt.Sprintf(" (waived until %v: %v)", v.Waiver.Expires, v.Waiver.Reason)
+	}
+	return explanation
+}
+
+// Result of the evaluation of a policy
+type Result struct {
+	Rules      []Rule      `json:"rules"`
+	Findings   int         `json:"findings"`
+	Violations []Violation `json:"violations"`
+	// ExpiredWaivers would have waived violations if they were still valid
+	ExpiredWaivers []Waiver `json:"expiredWaivers,omitempty"`
+}
+
+// Passed returns whether no violation fails the security gate
+func (r Result) Passed() bool {
+	for _, violation := range r.Violations {
+		if violation.Failing() {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of failing, warning and waived violations
+func (r Result) Count() (failing, warning, waived int) {
+	for _, violation := range r.Violations {
+		switch {
+		case violation.Waiver != nil:
+			waived++
+		case violation.Failing():
+			failing++
+		default:
+			warning++
+		}
+	}
+	return
+}
+
+// Evaluate checks the aggregated findings of all scanners against the rules of the policy, thus a finding reported by several scanners violates a rule only once
+func Evaluate(policy Policy, findings []reporting.AggregatedFinding, now time.Time) Result {
+	result := Result{Rules: policy.Rules, Findings: len(findings), Violations: []Violation{}}
+	expired := map[int]bool{}
+	for _, rule := range policy.Rules {
+		for _, finding := range findings {
+			if !rule.When.Matches(finding, now) {
+				continue
+			}
+			violation := Violation{Rule: rule, Finding: finding}
+			for i, waiver := range policy.Waivers {
+				if waiver.Waives(rule, finding, now) {
+					waiver := waiver
+					violation.Waiver = &waiver
+					break
+				}
+				if waiver.appliesTo(rule, finding) {
+					expired[i] = true
+				}
+			}
+			result.Violations = append(result.Violations, violation)
+		}
+	}
+	for i, waiver := range policy.Waivers {
+		if expired[i] {
+			result.ExpiredWaivers = append(result.ExpiredWaivers, waiver)
+		}
+	}
+	return result
+}
+
+// CreateScanReport lists the violations, which can be rendered as markdown or HTML and is included in the scan summary
+func CreateScanReport(result Result, policyFile string) reporting.ScanReport {
+	failing, warning, waived := result.Count()
+	scanReport := reporting.ScanReport{
+		StepName:    "securityGate",
+		ReportTitle: "Security Gate",
+		Subheaders: []reporting.Subheader{
+			{Description: "Policy", Details: policyFile},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Evaluated findings", Details: fmt.Sprint(result.Findings)},
+			{Description: "Rules", Details: fmt.Sprint(len(result.Rules))},
+			{Description: "Failing violations", Details: fmt.Sprint(failing)},
+			{Description: "Warnings", Details: fmt.Sprint(warning)},
+			{Description: "Waived violations", Details: fmt.Sprint(waived)},
+		},
+		SuccessfulScan: result.Passed(),
+		ReportTime:     time.Now(),
+	}
+	for _, waiver := range result.ExpiredWaivers {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{
+			Description: fmt.Sprintf("Expired waiver for %v", waiver.ID),
+			Details:     fmt.Sprintf("expired %v: %v", waiver.Expires, waiver.Reason),
+			Style:       reporting.Yellow,
+		})
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No violations of the security policy",
+		Headers:       []string{"Rule", "Action", "Severity", "Scanner", "ID", "Component / Location", "Waiver"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, violation := range result.Violations {
+		var style reporting.ColumnStyle = reporting.Yellow
+		if violation.Waiver != nil {
+			style = reporting.Grey
+		} else if violation.Failing() {
+			style = reporting.Red
+		}
+		waiver := ""
+		if violation.Waiver != nil {
+			waiver = fmt.Sprintf("until %v: %v", violation.Waiver.Expires, violation.Waiver.Reason)
+		}
+		id := violation.Finding.ID
+		if len(violation.Finding.Link) > 0 {
+			id = fmt.Sprintf(`<a href="%v">%v</a>`, violation.Finding.Link, violation.Finding.ID)
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(violation.Rule.Name, style)
+		row.AddColumn(violation.Rule.Action, 0)
+		row.AddColumn(violation.Finding.Severity, 0)
+		row.AddColumn(scanners(violation.Finding), 0)
+		row.AddColumn(id, 0)
+		row.AddColumn(subject(violation.Finding.Finding), 0)
+		row.AddColumn(waiver, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
+
+// CreateSarif creates a SARIF report with the rules of the policy and one result per violation, waived violations are marked as audited
+func CreateSarif(result Result) format.SARIF {
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []format.Runs{{
+			Tool:    format.Tool{Driver: format.Driver{Name: "securityGate", InformationUri: "https://www.project-piper.io/steps/securityGate/"}},
+			Results: []format.Results{},
+		}},
+	}
+	ruleIndex := map[string]int{}
+	for i, rule := range result.Rules {
+		ruleIndex[rule.Name] = i
+		sarif.Runs[0].Tool.Driver.Rules = append(sarif.Runs[0].Tool.Driver.Rules, format.SarifRule{
+			ID:                   rule.Name,
+			Name:                 rule.Name,
+			ShortDescription:     &format.Message{Text: rule.Name},
+			FullDescription:      &format.Message{Text: rule.Description},
+			DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(rule.Action)},
+			Properties:           &format.SarifRuleProperties{Tags: []string{"security"}},
+		})
+	}
+	for _, violation := range result.Violations {
+		level := sarifLevel(violation.Rule.Action)
+		properties := &format.SarifProperties{
+			ToolSeverity:    violation.Finding.Severity.String(),
+			UnifiedSeverity: violation.Finding.Severity.String(),
+			ToolState:       string(violation.Finding.Status),
+		}
+		if violation.Waiver != nil {
+			level = "note"
+			properties.Audited = true
+			properties.ToolAuditMessage = violation.Waiver.Reason
+			properties.UnifiedAuditState = "waived"
+		}
+		location := format.Location{}
+		if violation.Finding.Location != nil {
+			location.PhysicalLocation.ArtifactLocation.URI = violation.Finding.Location.File
+			location.PhysicalLocation.Region = format.Region{StartLine: violation.Finding.Location.Line, StartColumn: violation.Finding.Location.Column}
+		} else {
+			location.PhysicalLocation.ArtifactLocation.URI = violation.Finding.PackageURL
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, format.Results{
+			RuleID:     violation.Rule.Name,
+			RuleIndex:  ruleIndex[violation.Rule.Name],
+			Level:      level,
+			Message:    &format.Message{Text: violation.Explanation()},
+			Locations:  []format.Location{location},
+			Properties: properties,
+		})
+	}
+	return sarif
+}
+
+func sarifLevel(action string) string {
+	if action == ActionWarn {
+		return "warning"
+	}
+	return "error"
+}
+
+func scanners(finding reporting.AggregatedFinding) string {
+	if len(finding.Scanners) == 0 {
+		return finding.Scanner
+	}
+	return strings.Join(finding.Scanners, ", ")
+}
+
+func subject(finding reporting.Finding) string {
+	switch {
+	case len(finding.PackageURL) > 0:
+		return fmt.Sprintf("%v in %v", finding.ID, finding.PackageURL)
+	case finding.Location != nil:
+		return fmt.Sprintf("%v in %v:%v", finding.ID, finding.Location.File, finding.Location.Line)
+	case len(finding.ComponentName) > 0:
+		return fmt.Sprintf("%v in %v", finding.ID, finding.ComponentName)
+	}
+	return finding.ID
+}
@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+package securitypolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	policy, err := ParsePolicy([]byte(`rules:
+  - name: no-critical
+    description: critical vulnerabilities need to be fixed
+    when:
+      minSeverity: critical
+  - name: sast
+    action: warn
+    when:
+      types: [code]
+waivers:
+  - id: CVE-2021-44228
+    package: pkg:maven/org.apache.logging.log4j/log4j-core
+    reason: not used at runtime
+    expires: 2026-12-31
+  - id: CVE-2021-44906
+    reason: only used in tests
+    expires: 2026-01-31
+`))
+	require.NoError(t, err)
+	findings := reporting.AggregateFindings([]reporting.FindingsReport{{Findings: []reporting.Finding{
+		{Scanner: "whitesource", Type: reporting.FindingTypeVulnerability, ID: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen},
+		{Scanner: "blackduck", Type: reporting.FindingTypeVulnerability, ID: "CVE-2021-44228", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?type=jar", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen},
+		{Scanner: "whitesource", Type: reporting.FindingTypeVulnerability, ID: "CVE-2021-44906", PackageURL: "pkg:npm/minimist@1.2.5", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusOpen},
+		{Scanner: "checkmarx", Type: reporting.FindingTypeCode, ID: "SQL_Injection", Location: &reporting.Location{File: "src/db.go", Line: 12}, Severity: reporting.SeverityHigh, Status: reporting.FindingStatusOpen},
+		{Scanner: "whitesource", Type: reporting.FindingTypeVulnerability, ID: "CVE-2020-1", Severity: reporting.SeverityCritical, Status: reporting.FindingStatusNotAffected},
+	}}})
+
+	result := Evaluate(*policy, findings, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.False(t, result.Passed())
+	assert.Equal(t, 4, result.Findings)
+	require.Len(t, result.Violations, 3)
+	failing, warning, waived := result.Count()
+	assert.Equal(t, 1, failing)
+	assert.Equal(t, 1, warning)
+	assert.Equal(t, 1, waived)
+	assert.Equal(t, "critical whitesource, blackduck CVE-2021-44228 in pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1 violates rule no-critical: critical vulnerabilities need to be fixed (waived until 2026-12-31: not used at runtime)", result.Violations[0].Explanation())
+	assert.True(t, result.Violations[1].Failing())
+	assert.Equal(t, "high checkmarx SQL_Injection in src/db.go:12 violates rule sast", result.Violations[2].Explanation())
+	require.Len(t, result.ExpiredWaivers, 1)
+	assert.Equal(t, "CVE-2021-44906", result.ExpiredWaivers[0].ID)
+
+	t.Run("scan report", func(t *testing.T) {
+		scanReport := CreateScanReport(result, ".pipeline/security-policy.yml")
+		assert.False(t, scanReport.SuccessfulScan)
+		assert.Equal(t, "1", scanReport.Overview[2].Details)
+		assert.Equal(t, "Expired waiver for CVE-2021-44906", scanReport.Overview[5].Description)
+		require.Len(t, scanReport.DetailTable.Rows, 3)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Grey), scanReport.DetailTable.Rows[0].Columns[0].Style)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[1].Columns[0].Style)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Yellow), scanReport.DetailTable.Rows[2].Columns[0].Style)
+		markdown, err := scanReport.ToMarkdown()
+		assert.NoError(t, err)
+		assert.Contains(t, string(markdown), "Security Gate")
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		sarif := CreateSarif(result)
+		require.Len(t, sarif.Runs, 1)
+		assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 2)
+		require.Len(t, sarif.Runs[0].Results, 3)
+		assert.Equal(t, "note", sarif.Runs[0].Results[0].Level)
+		assert.True(t, sarif.Runs[0].Results[0].Properties.Audited)
+		assert.Equal(t, "error", sarif.Runs[0].Results[1].Level)
+		assert.Equal(t, "pkg:npm/minimist@1.2.5", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, "warning", sarif.Runs[0].Results[2].Level)
+		assert.Equal(t, 1, sarif.Runs[0].Results[2].RuleIndex)
+		assert.Equal(t, 12, sarif.Runs[0].Results[2].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		result := Evaluate(*policy, findings[:1], time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.True(t, result.Passed())
+		assert.Empty(t, result.ExpiredWaivers)
+	})
+}
@@ -0,0 +1,205 @@
+package securitypolicy
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// Actions of a rule
+const (
+	ActionFail = "fail"
+	ActionWarn = "warn"
+)
+
+const dateFormat = "2006-01-02"
+
+// Policy defines which findings of the security scans are not acceptable
+type Policy struct {
+	Rules   []Rule   `json:"rules"`
+	Waivers []Waiver `json:"waivers,omitempty"`
+}
+
+// Rule is violated by each finding which matches its condition
+type Rule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Action is either fail (default) or warn
+	Action string    `json:"action,omitempty"`
+	When   Condition `json:"when"`
+}
+
+// Condition matches a finding if all of the specified criteria apply
+type Condition struct {
+	Scanners []string                  `json:"scanners,omitempty"`
+	Types    []reporting.FindingType   `json:"types,omitempty"`
+	Statuses []reporting.FindingStatus `json:"statuses,omitempty"`
+	// MinSeverity and MinCVSS are the lowest severity respectively CVSS score which is matched
+	MinSeverity reporting.Severity `json:"minSeverity,omitempty"`
+	MinCVSS     float64            `json:"minCVSS,omitempty"`
+	// MinAgeDays matches findings which have been published at least the given number of days ago
+	MinAgeDays int `json:"minAgeDays,omitempty"`
+	// FixAvailable matches findings depending on whether a fixed version or a remediation is known
+	FixAvailable *bool `json:"fixAvailable,omitempty"`
+}
+
+// Waiver accepts findings until it expires
+type Waiver struct {
+	// ID is the ID or the CVE of the finding
+	ID string `json:"id"`
+	// Package is the package URL of the affected component, a package URL without version waives all versions
+	Package string `json:"package,omitempty"`
+	Scanner string `json:"scanner,omitempty"`
+	// Rule restricts the waiver to a single rule
+	Rule    string `json:"rule,omitempty"`
+	Reason  string `json:"reason"`
+	Expires string `json:"expires"`
+	expires time.Time
+}
+
+type policyFileUtils interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// ReadPolicy reads and validates the policy file
+func ReadPolicy(file string, utils policyFileUtils) (*Policy, error) {
+	if strings.EqualFold(filepath.Ext(file), ".rego") {
+		return nil, fmt.Errorf("policy %v: Rego policies are not supported, please use a YAML policy", file)
+	}
+	content, err := utils.FileRead(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read policy %v", file)
+	}
+	return ParsePolicy(content)
+}
+
+// ParsePolicy parses and validates a policy in YAML or JSON format
+// Unknown keys are rejected, otherwise a misspelled criterion would be ignored and the rule would match more findings than intended.
+func ParsePolicy(content []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(content, &policy, yaml.DisallowUnknownFields); err != nil {
+		return nil, errors.Wrap(err, "failed to parse policy")
+	}
+	if len(policy.Rules) == 0 {
+		return nil, errors.New("policy does not define any rule")
+	}
+	names := map[string]bool{}
+	for i, rule := range policy.Rules {
+		if len(rule.Name) == 0 {
+			return nil, fmt.Errorf("rule %v has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %v is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+		switch rule.Action {
+		case "":
+			policy.Rules[i].Action = ActionFail
+		case ActionFail, ActionWarn:
+		default:
+			return nil, fmt.Errorf("rule %v has invalid action '%v', supported are %v and %v", rule.Name, rule.Action, ActionFail, ActionWarn)
+		}
+	}
+	for i, waiver := range policy.Waivers {
+		if len(waiver.ID) == 0 {
+			return nil, fmt.Errorf("waiver %v has no id", i+1)
+		}
+		if len(waiver.Reason) == 0 {
+			return nil, fmt.Errorf("waiver for %v has no reason", waiver.ID)
+		}
+		expires, err := time.Parse(dateFormat, waiver.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("waiver for %v has invalid expiry date '%v', expected format is YYYY-MM-DD", waiver.ID, waiver.Expires)
+		}
+		// a waiver is valid until the end of the day it expires
+		policy.Waivers[i].expires = expires.AddDate(0, 0, 1)
+		if len(waiver.Rule) > 0 && !names[waiver.Rule] {
+			return nil, fmt.Errorf("waiver for %v refers to unknown rule %v", waiver.ID, waiver.Rule)
+		}
+	}
+	return &policy, nil
+}
+
+// Matches returns whether the finding meets all criteria of the condition, the scanners match if one of them reported the finding
+func (c Condition) Matches(finding reporting.AggregatedFinding, now time.Time) bool {
+	if len(c.Scanners) > 0 && !containsFold(c.Scanners, finding.Scanner) && !containsAnyFold(c.Scanners, finding.Scanners) {
+		return false
+	}
+	if len(c.Types) > 0 && !slices.Contains(c.Types, finding.Type) {
+		return false
+	}
+	if len(c.Statuses) > 0 {
+		if !slices.Contains(c.Statuses, finding.Status) {
+			return false
+		}
+	} else if !finding.Open() {
+		return false
+	}
+	if finding.Severity < c.MinSeverity {
+		return false
+	}
+	if c.MinCVSS > 0 && finding.CVSSScore < c.MinCVSS {
+		return false
+	}
+	if c.MinAgeDays > 0 && (finding.Published == nil || now.Sub(*finding.Published) < time.Duration(c.MinAgeDays)*24*time.Hour) {
+		return false
+	}
+	if c.FixAvailable != nil && *c.FixAvailable != (len(finding.FixVersion) > 0 || len(finding.Remediation) > 0) {
+		return false
+	}
+	return true
+}
+
+// Waives returns whether the waiver accepts the finding for the rule, expired waivers are ignored
+func (w Waiver) Waives(rule Rule, finding reporting.AggregatedFinding, now time.Time) bool {
+	if !w.appliesTo(rule, finding) {
+		return false
+	}
+	return !w.Expired(now)
+}
+
+// Expired returns whether the waiver is no longer valid
+func (w Waiver) Expired(now time.Time) bool {
+	return !now.Before(w.expires)
+}
+
+func (w Waiver) appliesTo(rule Rule, finding reporting.AggregatedFinding) bool {
+	if !strings.EqualFold(w.ID, finding.ID) && !strings.EqualFold(w.ID, finding.CVE) {
+		return false
+	}
+	if len(w.Rule) > 0 && w.Rule != rule.Name {
+		return false
+	}
+	if len(w.Scanner) > 0 && !strings.EqualFold(w.Scanner, finding.Scanner) && !containsFold(finding.Scanners, w.Scanner) {
+		return false
+	}
+	if len(w.Package) > 0 && finding.PackageURL != w.Package && !strings.HasPrefix(finding.PackageURL, w.Package+"@") {
+		return false
+	}
+	return true
+}
+
+func containsAnyFold(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if containsFold(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+//go:build unit
+// +build unit
+
+package securitypolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		policy, err := ParsePolicy([]byte(`rules:
+  - name: critical
+    when:
+      minSeverity: critical
+      types: [vulnerability]
+      statuses: [open, confirmed]
+  - name: code
+    action: warn
+    when:
+      types: [code]
+waivers:
+  - id: CVE-2021-44228
+    rule: critical
+    reason: not used at runtime
+    expires: 2026-12-31
+`))
+		require.NoError(t, err)
+		require.Len(t, policy.Rules, 2)
+		assert.Equal(t, ActionFail, policy.Rules[0].Action)
+		assert.Equal(t, reporting.SeverityCritical, policy.Rules[0].When.MinSeverity)
+		assert.Equal(t, []reporting.FindingStatus{reporting.FindingStatusOpen, reporting.FindingStatusConfirmed}, policy.Rules[0].When.Statuses)
+		assert.Equal(t, ActionWarn, policy.Rules[1].Action)
+		require.Len(t, policy.Waivers, 1)
+		assert.False(t, policy.Waivers[0].Expired(time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC)))
+		assert.True(t, policy.Waivers[0].Expired(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	tt := []struct {
+		name     string
+		policy   string
+		expected string
+	}{
+		{name: "no rules", policy: `waivers: []`, expected: "policy does not define any rule"},
+		{name: "unnamed rule", policy: `rules: [{action: warn}]`, expected: "rule 1 has no name"},
+		{name: "duplicate rule", policy: `rules: [{name: a}, {name: a}]`, expected: "rule a is defined twice"},
+		{name: "invalid action", policy: `rules: [{name: a, action: block}]`, expected: "rule a has invalid action 'block', supported are fail and warn"},
+		{name: "invalid severity", policy: `rules: [{name: a, when: {minSeverity: severe}}]`, expected: "unknown severity 'severe'"},
+		{name: "unknown criterion", policy: `rules: [{name: a, when: {licenses: ["GPL-*"]}}]`, expected: `unknown field "licenses"`},
+		{name: "waiver without reason", policy: "rules: [{name: a}]\nwaivers: [{id: CVE-1, expires: 2026-01-01}]", expected: "waiver for CVE-1 has no reason"},
+		{name: "waiver without expiry", policy: "rules: [{name: a}]\nwaivers: [{id: CVE-1, reason: x}]", expected: "waiver for CVE-1 has invalid expiry date '', expected format is YYYY-MM-DD"},
+		{name: "waiver for unknown rule", policy: "rules: [{name: a}]\nwaivers: [{id: CVE-1, reason: x, expires: 2026-01-01, rule: b}]", expected: "waiver for CVE-1 refers to unknown rule b"},
+	}
+	for _, test := range tt {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(test.policy))
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestReadPolicy(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	utils.AddFile("security-policy.yml", []byte("rules: [{name: a}]"))
+	policy, err := ReadPolicy("security-policy.yml", utils)
+	assert.NoError(t, err)
+	assert.Len(t, policy.Rules, 1)
+
+	_, err = ReadPolicy("missing.yml", utils)
+	assert.ErrorContains(t, err, "failed to read policy missing.yml")
+
+	_, err = ReadPolicy("policy.rego", utils)
+	assert.ErrorContains(t, err, "Rego policies are not supported")
+}
+
+func TestConditionMatches(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	published := now.AddDate(0, 0, -40)
+	yes, no := true, false
+	finding := reporting.AggregatedFinding{Scanners: []string{"whitesource", "blackduck"}, Finding: reporting.Finding{
+		Scanner:    "whitesource",
+		Type:       reporting.FindingTypeVulnerability,
+		ID:         "CVE-2021-23337",
+		Severity:   reporting.SeverityHigh,
+		CVSSScore:  7.2,
+		FixVersion: "4.17.21",
+		Published:  &published,
+		Status:     reporting.FindingStatusOpen,
+	}}
+
+	tt := []struct {
+		name      string
+		condition Condition
+		expected  bool
+	}{
+		{name: "empty", condition: Condition{}, expected: true},
+		{name: "scanner", condition: Condition{Scanners: []string{"WhiteSource"}}, expected: true},
+		{name: "any scanner", condition: Condition{Scanners: []string{"blackduck"}}, expected: true},
+		{name: "other scanner", condition: Condition{Scanners: []string{"protecode"}}, expected: false},
+		{name: "type", condition: Condition{Types: []reporting.FindingType{reporting.FindingTypeCode}}, expected: false},
+		{name: "status", condition: Condition{Statuses: []reporting.FindingStatus{reporting.FindingStatusConfirmed}}, expected: false},
+		{name: "severity", condition: Condition{MinSeverity: reporting.SeverityHigh}, expected: true},
+		{name: "higher severity", condition: Condition{MinSeverity: reporting.SeverityCritical}, expected: false},
+		{name: "cvss", condition: Condition{MinCVSS: 7.5}, expected: false},
+		{name: "age", condition: Condition{MinAgeDays: 30}, expected: true},
+		{name: "younger", condition: Condition{MinAgeDays: 60}, expected: false},
+		{name: "fix available", condition: Condition{FixAvailable: &yes}, expected: true},
+		{name: "no fix available", condition: Condition{FixAvailable: &no}, expected: false},
+	}
+	for _, test := range tt {
+		assert.Equal(t, test.expected, test.condition.Matches(finding, now), test.name)
+	}
+
+	t.Run("assessed finding", func(t *testing.T) {
+		assessed := finding
+		assessed.Status = reporting.FindingStatusNotAffected
+		assert.False(t, Condition{}.Matches(assessed, now))
+		assert.True(t, Condition{Statuses: []reporting.FindingStatus{reporting.FindingStatusNotAffected}}.Matches(assessed, now))
+	})
+}
@@ -0,0 +1,67 @@
+package securitypolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory contains the reports of the security gate
+const ReportsDirectory = "securityGate"
+
+// WriteReports writes the result as HTML, markdown and SARIF report as well as the JSON report for the scan summary
+func WriteReports(result Result, policyFile string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	scanReport := CreateScanReport(result, policyFile)
+
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_security_gate_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Security Gate Report", Target: htmlReportPath})
+
+	mdReport, _ := scanReport.ToMarkdown()
+	mdReportPath := filepath.Join(ReportsDirectory, "piper_security_gate_report.md")
+	if err := utils.FileWrite(mdReportPath, mdReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write markdown report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Target: mdReportPath})
+
+	// HTML characters are part of the explanations, thus they must not be escaped
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(CreateSarif(result)); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to marshal SARIF report")
+	}
+	sarifReportPath := filepath.Join(ReportsDirectory, "result.sarif")
+	if err := utils.FileWrite(sarifReportPath, buffer.Bytes(), 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write SARIF report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Security Gate SARIF Report", Target: sarifReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	jsonReport, _ := scanReport.ToJSON()
+	if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "securityGate.json"), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+	return reportPaths, nil
+}
@@ -0,0 +1,55 @@
+package splunk
+
+import (
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+// Sink sends the telemetry data and in case of a failure the collected log messages to Splunk
+type Sink struct {
+	splunk       *Splunk
+	logCollector *log.CollectorHook
+}
+
+type sinkOptions struct {
+	Dsn           string `json:"dsn"`
+	Token         string `json:"token"`
+	Index         string `json:"index"`
+	SendLogs      bool   `json:"sendLogs"`
+	CorrelationID string `json:"correlationId"`
+}
+
+// NewSink creates a telemetry sink for the Splunk HEC endpoint.
+// The log messages are taken from the log collector, no messages are sent if it is nil.
+func NewSink(correlationID, dsn, token, index string, sendLogs bool, logCollector *log.CollectorHook) *Sink {
+	s := &Splunk{}
+	s.Initialize(correlationID, dsn, token, index, sendLogs)
+	if logCollector == nil {
+		logCollector = &log.CollectorHook{CorrelationID: correlationID}
+	}
+	return &Sink{splunk: s, logCollector: logCollector}
+}
+
+// NewSinkFactory returns the factory which is registered as telemetry sink type splunk.
+// The correlation ID is used if the sink options do not contain one.
+func NewSinkFactory(correlationID string, logCollector *log.CollectorHook) telemetry.SinkFactory {
+	return func(options map[string]interface{}) (telemetry.Sink, error) {
+		var opts sinkOptions
+		if err := telemetry.DecodeSinkOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if len(opts.CorrelationID) == 0 {
+			opts.CorrelationID = correlationID
+		}
+		if len(opts.Dsn) == 0 {
+			return nil, errors.New("option 'dsn' is required")
+		}
+		return NewSink(opts.CorrelationID, opts.Dsn, opts.Token, opts.Index, opts.SendLogs, logCollector), nil
+	}
+}
+
+// Send sends the telemetry data to Splunk
+func (s *Sink) Send(data telemetry.Data) error {
+	return s.splunk.Send(data, s.logCollector)
+}
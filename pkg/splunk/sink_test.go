@@ -0,0 +1,32 @@
+//go:build unit
+// +build unit
+
+package splunk
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinkFactory(t *testing.T) {
+	collector := &log.CollectorHook{CorrelationID: "https://build.url"}
+	factory := NewSinkFactory("https://build.url", collector)
+
+	t.Run("success", func(t *testing.T) {
+		sink, err := factory(map[string]interface{}{"dsn": "https://splunk.example.com", "token": "token", "index": "piper", "sendLogs": true})
+		assert.NoError(t, err)
+		splunkSink := sink.(*Sink)
+		assert.Equal(t, "https://build.url", splunkSink.splunk.correlationID)
+		assert.Equal(t, "https://splunk.example.com", splunkSink.splunk.splunkDsn)
+		assert.Equal(t, "piper", splunkSink.splunk.splunkIndex)
+		assert.True(t, splunkSink.splunk.sendLogs)
+		assert.Equal(t, collector, splunkSink.logCollector)
+	})
+
+	t.Run("missing dsn", func(t *testing.T) {
+		_, err := factory(map[string]interface{}{"token": "token"})
+		assert.EqualError(t, err, "option 'dsn' is required")
+	})
+}
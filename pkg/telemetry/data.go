@@ -1,5 +1,7 @@
 package telemetry
 
+import piperhttp "github.com/SAP/jenkins-library/pkg/http"
+
 // BaseData object definition containing the base data
 type BaseData struct {
 	ActionName      string `json:"actionName"`
@@ -62,4 +64,6 @@ type StepTelemetryData struct {
 type Data struct {
 	BaseData
 	CustomData
+	// HTTPPolicy contains the rate limit and circuit breaker metrics of the HTTP requests, if the requests were delayed or rejected
+	HTTPPolicy *piperhttp.PolicyMetrics `json:"httpPolicy,omitempty"`
 }
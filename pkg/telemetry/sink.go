@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sink is a destination for the telemetry data of a step run
+type Sink interface {
+	Send(data Data) error
+}
+
+// SinkConfig selects a registered sink type and contains its options
+type SinkConfig struct {
+	Type    string                 `json:"type"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// SinkFactory creates a sink based on the options of the sink configuration
+type SinkFactory func(options map[string]interface{}) (Sink, error)
+
+var (
+	sinkFactoriesMutex sync.Mutex
+	sinkFactories      = map[string]SinkFactory{
+		"file":    newFileSinkFromOptions,
+		"influx":  newInfluxSinkFromOptions,
+		"otlp":    newOTLPSinkFromOptions,
+		"pendo":   newPendoSinkFromOptions,
+		"webhook": newWebhookSinkFromOptions,
+	}
+)
+
+// RegisterSink registers a sink type, an existing registration with the same type is replaced.
+// Sinks living in packages which depend on the telemetry package, e.g. Splunk, are registered this way.
+func RegisterSink(sinkType string, factory SinkFactory) {
+	sinkFactoriesMutex.Lock()
+	defer sinkFactoriesMutex.Unlock()
+	sinkFactories[sinkType] = factory
+}
+
+// NewSink creates a sink of the registered type
+func NewSink(config SinkConfig) (Sink, error) {
+	sinkFactoriesMutex.Lock()
+	factory, ok := sinkFactories[config.Type]
+	types := make([]string, 0, len(sinkFactories))
+	for sinkType := range sinkFactories {
+		types = append(types, sinkType)
+	}
+	sinkFactoriesMutex.Unlock()
+
+	if !ok {
+		sort.Strings(types)
+		return nil, fmt.Errorf("telemetry sink '%v' not supported, use one of %v", config.Type, strings.Join(types, ", "))
+	}
+	sink, err := factory(config.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry sink '%v': %w", config.Type, err)
+	}
+	return sink, nil
+}
+
+// DecodeSinkOptions converts the options of a sink configuration into the options struct of the sink
+func DecodeSinkOptions(options map[string]interface{}, target interface{}) error {
+	if options == nil {
+		return nil
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sink options: %w", err)
+	}
+	if err := json.Unmarshal(b, target); err != nil {
+		return fmt.Errorf("invalid sink options: %w", err)
+	}
+	return nil
+}
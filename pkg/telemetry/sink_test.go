@@ -0,0 +1,158 @@
+//go:build unit
+// +build unit
+
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkMock struct {
+	data []Data
+	err  error
+}
+
+func (s *sinkMock) Send(data Data) error {
+	s.data = append(s.data, data)
+	return s.err
+}
+
+func newMockedSinkClient() *piperhttp.Client {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{UseDefaultTransport: true, MaxRetries: -1})
+	return client
+}
+
+func TestNewSink(t *testing.T) {
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := NewSink(SinkConfig{Type: "unknown"})
+		assert.EqualError(t, err, "telemetry sink 'unknown' not supported, use one of file, influx, otlp, pendo, webhook")
+	})
+
+	t.Run("registered type", func(t *testing.T) {
+		mock := &sinkMock{}
+		RegisterSink("mock", func(options map[string]interface{}) (Sink, error) {
+			assert.Equal(t, "value", options["key"])
+			return mock, nil
+		})
+		defer func() {
+			sinkFactoriesMutex.Lock()
+			delete(sinkFactories, "mock")
+			sinkFactoriesMutex.Unlock()
+		}()
+
+		sink, err := NewSink(SinkConfig{Type: "mock", Options: map[string]interface{}{"key": "value"}})
+		assert.NoError(t, err)
+		assert.Equal(t, mock, sink)
+	})
+
+	t.Run("missing options", func(t *testing.T) {
+		_, err := NewSink(SinkConfig{Type: "webhook"})
+		assert.EqualError(t, err, "failed to create telemetry sink 'webhook': option 'url' is required")
+
+		_, err = NewSink(SinkConfig{Type: "influx", Options: map[string]interface{}{"url": "https://influx.example.com"}})
+		assert.EqualError(t, err, "failed to create telemetry sink 'influx': options 'url' and 'bucket' are required")
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewSink(SinkConfig{Type: "file", Options: map[string]interface{}{"path": 42}})
+		assert.ErrorContains(t, err, "invalid sink options")
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	sink, err := NewSink(SinkConfig{Type: "file", Options: map[string]interface{}{"path": path}})
+	require.NoError(t, err)
+
+	assert.NoError(t, sink.Send(Data{BaseData: BaseData{StepName: "mavenBuild"}, CustomData: CustomData{Duration: "100"}}))
+	assert.NoError(t, sink.Send(Data{BaseData: BaseData{StepName: "npmExecuteScripts"}, CustomData: CustomData{Duration: "200"}}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "npmExecuteScripts", entry["stepName"])
+	assert.Equal(t, "200", entry["duration"])
+	assert.NotEmpty(t, entry["timestamp"])
+}
+
+func TestWebhookSink(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var body map[string]interface{}
+	httpmock.RegisterResponder(http.MethodPut, "https://metrics.example.com/piper", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		content, _ := io.ReadAll(req.Body)
+		json.Unmarshal(content, &body)
+		return httpmock.NewStringResponse(http.StatusAccepted, ""), nil
+	})
+
+	sink, err := newWebhookSinkFromOptions(map[string]interface{}{
+		"url":     "https://metrics.example.com/piper",
+		"method":  http.MethodPut,
+		"headers": map[string]interface{}{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+	sink.(*WebhookSink).client = newMockedSinkClient()
+
+	assert.NoError(t, sink.Send(Data{BaseData: BaseData{StepName: "mavenBuild"}, CustomData: CustomData{ErrorCode: "1"}}))
+	assert.Equal(t, "mavenBuild", body["stepName"])
+	assert.Equal(t, "1", body["errorCode"])
+}
+
+func TestOTLPSink(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var payload string
+	httpmock.RegisterResponder(http.MethodPost, "https://otel.example.com/v1/metrics", func(req *http.Request) (*http.Response, error) {
+		content, _ := io.ReadAll(req.Body)
+		payload = string(content)
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://otel.example.com/")
+	sink, err := newOTLPSinkFromOptions(nil)
+	require.NoError(t, err)
+	sink.(*OTLPSink).client = newMockedSinkClient()
+
+	assert.NoError(t, sink.Send(Data{BaseData: BaseData{StepName: "mavenBuild"}, CustomData: CustomData{Duration: "1500", ErrorCode: "0"}}))
+	assert.Contains(t, payload, `"name":"piper.step.duration","unit":"ms"`)
+	assert.Contains(t, payload, `"asInt":"1500"`)
+	assert.Contains(t, payload, `{"key":"piper.step.name","value":{"stringValue":"mavenBuild"}}`)
+	assert.Contains(t, payload, `"name":"piper.step.runs"`)
+}
+
+func TestTelemetry_SendToSinks(t *testing.T) {
+	successful := &sinkMock{}
+	failing := &sinkMock{err: errors.New("send failed")}
+
+	telemetryClient := &Telemetry{}
+	telemetryClient.Initialize(true, "mavenBuild", "")
+	telemetryClient.AddSink(failing)
+	telemetryClient.AddSink(successful)
+	telemetryClient.SetData(&CustomData{Duration: "100", ErrorCode: "0"})
+	telemetryClient.Send()
+
+	require.Len(t, failing.data, 1)
+	require.Len(t, successful.data, 1)
+	assert.Equal(t, "mavenBuild", successful.data[0].BaseData.StepName)
+	assert.Equal(t, "100", successful.data[0].Duration)
+}
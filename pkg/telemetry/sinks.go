@@ -0,0 +1,285 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/influx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+)
+
+// record is the representation of the telemetry data written by the file and webhook sinks
+type record struct {
+	Timestamp string `json:"timestamp"`
+	Data
+}
+
+func newRecord(data Data) record {
+	return record{Timestamp: time.Now().UTC().Format(time.RFC3339), Data: data}
+}
+
+func newSinkClient() *piperhttp.Client {
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRequestDuration: 10 * time.Second, MaxRetries: 1})
+	return client
+}
+
+func postJSON(client *piperhttp.Client, method, url string, payload interface{}, header http.Header) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry data: %w", err)
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	response, err := client.SendRequest(method, url, bytes.NewReader(body), header, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry data to %v: %w", url, err)
+	}
+	response.Body.Close()
+	return nil
+}
+
+func toHeader(headers map[string]string) http.Header {
+	header := http.Header{}
+	for key, value := range headers {
+		// headers usually contain credentials
+		log.RegisterSecret(value)
+		header.Set(key, value)
+	}
+	return header
+}
+
+// FileSink appends the telemetry data as one JSON object per line to a file
+type FileSink struct {
+	Path string `json:"path"`
+}
+
+func newFileSinkFromOptions(options map[string]interface{}) (Sink, error) {
+	sink := &FileSink{Path: "piper-telemetry.jsonl"}
+	if err := DecodeSinkOptions(options, sink); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Send appends the telemetry data to the file
+func (s *FileSink) Send(data Data) error {
+	line, err := json.Marshal(newRecord(data))
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry data: %w", err)
+	}
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open telemetry file %v: %w", s.Path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write telemetry file %v: %w", s.Path, err)
+	}
+	return nil
+}
+
+// WebhookSink sends the telemetry data as JSON to an arbitrary endpoint
+type WebhookSink struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	client  *piperhttp.Client
+}
+
+func newWebhookSinkFromOptions(options map[string]interface{}) (Sink, error) {
+	sink := &WebhookSink{Method: http.MethodPost, client: newSinkClient()}
+	if err := DecodeSinkOptions(options, sink); err != nil {
+		return nil, err
+	}
+	if len(sink.URL) == 0 {
+		return nil, fmt.Errorf("option 'url' is required")
+	}
+	return sink, nil
+}
+
+// Send posts the telemetry data to the webhook
+func (s *WebhookSink) Send(data Data) error {
+	return postJSON(s.client, s.Method, s.URL, newRecord(data), toHeader(s.Headers))
+}
+
+// PendoSink sends the telemetry data as track event to Pendo
+type PendoSink struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"baseURL"`
+	client  *piperhttp.Client
+}
+
+func newPendoSinkFromOptions(options map[string]interface{}) (Sink, error) {
+	sink := &PendoSink{BaseURL: "https://app.pendo.io", client: newSinkClient()}
+	if err := DecodeSinkOptions(options, sink); err != nil {
+		return nil, err
+	}
+	if len(sink.Token) == 0 {
+		return nil, fmt.Errorf("option 'token' is required")
+	}
+	log.RegisterSecret(sink.Token)
+	return sink, nil
+}
+
+// Send posts the telemetry data to Pendo
+func (s *PendoSink) Send(data Data) error {
+	return sendPendo(s.client, s.BaseURL+"/data/track", s.Token, newPendoEvent(&data))
+}
+
+// InfluxSink writes the duration and the result of the step run to InfluxDB
+type InfluxSink struct {
+	URL          string `json:"url"`
+	Token        string `json:"token"`
+	Organization string `json:"organization"`
+	Bucket       string `json:"bucket"`
+	Measurement  string `json:"measurement"`
+}
+
+func newInfluxSinkFromOptions(options map[string]interface{}) (Sink, error) {
+	sink := &InfluxSink{Measurement: "piper_step"}
+	if err := DecodeSinkOptions(options, sink); err != nil {
+		return nil, err
+	}
+	if len(sink.URL) == 0 || len(sink.Bucket) == 0 {
+		return nil, fmt.Errorf("options 'url' and 'bucket' are required")
+	}
+	log.RegisterSecret(sink.Token)
+	return sink, nil
+}
+
+// Send writes one point per step run to InfluxDB
+func (s *InfluxSink) Send(data Data) error {
+	influxClient := influxdb2.NewClient(s.URL, s.Token)
+	defer influxClient.Close()
+
+	duration, _ := strconv.ParseInt(data.Duration, 10, 64)
+	fields := map[string]map[string]interface{}{s.Measurement: {
+		"duration": duration,
+		"success":  data.ErrorCode == "0",
+	}}
+	if data.HTTPPolicy != nil {
+		fields[s.Measurement]["httpRateLimitWaitMs"] = data.HTTPPolicy.RateLimitWaitMs
+		fields[s.Measurement]["httpRetryAfterWaitMs"] = data.HTTPPolicy.RetryAfterWaitMs
+		fields[s.Measurement]["httpCircuitBreakerRejections"] = data.HTTPPolicy.CircuitBreakerRejections
+	}
+	tags := map[string]map[string]string{s.Measurement: {
+		"stepName":      data.BaseData.StepName,
+		"stageName":     data.StageName,
+		"orchestrator":  data.Orchestrator,
+		"errorCode":     data.ErrorCode,
+		"errorCategory": data.ErrorCategory,
+	}}
+	if err := influx.NewClient(influxClient, s.Organization, s.Bucket).WriteMetrics(fields, tags); err != nil {
+		return fmt.Errorf("failed to write telemetry data to InfluxDB: %w", err)
+	}
+	return nil
+}
+
+// OTLPSink sends the duration and the number of step runs as OTLP metrics via HTTP using the JSON encoding
+type OTLPSink struct {
+	Endpoint string            `json:"endpoint"`
+	Headers  map[string]string `json:"headers"`
+	client   *piperhttp.Client
+}
+
+func newOTLPSinkFromOptions(options map[string]interface{}) (Sink, error) {
+	sink := &OTLPSink{client: newSinkClient()}
+	if err := DecodeSinkOptions(options, sink); err != nil {
+		return nil, err
+	}
+	if len(sink.Endpoint) == 0 {
+		sink.Endpoint = os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")
+	}
+	if len(sink.Endpoint) == 0 {
+		if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); len(endpoint) > 0 {
+			sink.Endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/metrics"
+		}
+	}
+	if len(sink.Endpoint) == 0 {
+		return nil, fmt.Errorf("option 'endpoint' is required")
+	}
+	return sink, nil
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsInt        string          `json:"asInt"`
+}
+
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := []otlpAttribute{}
+	for _, key := range keys {
+		attribute := otlpAttribute{Key: key}
+		attribute.Value.StringValue = attributes[key]
+		result = append(result, attribute)
+	}
+	return result
+}
+
+// Send exports the metrics piper.step.duration and piper.step.runs
+func (s *OTLPSink) Send(data Data) error {
+	duration, _ := strconv.ParseInt(data.Duration, 10, 64)
+	attributes := otlpAttributes(map[string]string{
+		"piper.step.name":      data.BaseData.StepName,
+		"piper.stage.name":     data.StageName,
+		"piper.orchestrator":   data.Orchestrator,
+		"piper.error.code":     data.ErrorCode,
+		"piper.error.category": data.ErrorCategory,
+	})
+	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)
+	dataPoint := func(value int64) []otlpDataPoint {
+		return []otlpDataPoint{{Attributes: attributes, TimeUnixNano: timestamp, AsInt: strconv.FormatInt(value, 10)}}
+	}
+
+	payload := map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": otlpAttributes(map[string]string{"service.name": "piper"})},
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "github.com/SAP/jenkins-library/pkg/telemetry"},
+				"metrics": []interface{}{
+					map[string]interface{}{
+						"name":  "piper.step.duration",
+						"unit":  "ms",
+						"gauge": map[string]interface{}{"dataPoints": dataPoint(duration)},
+					},
+					map[string]interface{}{
+						"name": "piper.step.runs",
+						"unit": "1",
+						"sum": map[string]interface{}{
+							// delta temporality, each step run reports a single execution
+							"aggregationTemporality": 1,
+							"isMonotonic":            true,
+							"dataPoints":             dataPoint(1),
+						},
+					},
+				},
+			}},
+		}},
+	}
+	return postJSON(s.client, http.MethodPost, s.Endpoint, payload, toHeader(s.Headers))
+}
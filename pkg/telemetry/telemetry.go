@@ -39,6 +39,7 @@ type Telemetry struct {
 	SiteID               string
 	PendoToken           string
 	Pendo                Pendo
+	sinks                []Sink
 }
 
 type Pendo struct {
@@ -123,17 +124,30 @@ func (t *Telemetry) SetData(customData *CustomData) {
 		BaseData:   t.baseData,
 		CustomData: *customData,
 	}
+	if metrics := piperhttp.GetPolicyMetrics(); metrics != (piperhttp.PolicyMetrics{}) {
+		t.data.HTTPPolicy = &metrics
+	}
+	t.Pendo = newPendoEvent(&t.data)
+}
+
+func newPendoEvent(data *Data) Pendo {
 	pipelineID := readPipelineID(pipelineIDPath)
-	t.Pendo = Pendo{
+	return Pendo{
 		Type:       "track",
-		Event:      t.baseData.StepName,
+		Event:      data.BaseData.StepName,
 		AccountID:  pipelineID,
 		VisitorID:  pipelineID,
 		Timestamp:  time.Now().UnixMilli(),
-		Properties: &t.data,
+		Properties: data,
 	}
 }
 
+// AddSink adds a sink which receives the telemetry data in addition to Pendo.
+// Sinks are independent of the telemetry opt-out since they are configured explicitly.
+func (t *Telemetry) AddSink(sink Sink) {
+	t.sinks = append(t.sinks, sink)
+}
+
 // GetData returns telemetryData
 func (t *Telemetry) GetData() Data {
 	return t.data
@@ -144,22 +158,37 @@ func (t *Telemetry) Send() {
 	// always log step telemetry data to logfile used for internal use-case
 	t.logStepTelemetryData()
 
+	for _, sink := range t.sinks {
+		if err := sink.Send(t.data); err != nil {
+			log.Entry().WithError(err).Warnf("failed to send telemetry data to %T", sink)
+		}
+	}
+
 	// skip if telemetry is disabled
 	if t.disabled {
 		return
 	}
 
-	b, err := json.Marshal(t.Pendo)
-	if err != nil {
-		log.Entry().WithError(err).Println("Failed to marshal data")
-		return
+	log.Entry().Debug("Sending telemetry data")
+	if err := sendPendo(t.client, t.BaseURL+t.Endpoint, t.PendoToken, t.Pendo); err != nil {
+		log.Entry().WithError(err).Debug("Failed to send telemetry data")
 	}
+}
 
-	log.Entry().Debug("Sending telemetry data")
+func sendPendo(client *piperhttp.Client, url, token string, event Pendo) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
 	h := http.Header{}
 	h.Add("Content-Type", "application/json")
-	h.Add("X-Pendo-Integration-Key", t.PendoToken)
-	t.client.SendRequest(http.MethodPost, t.BaseURL+t.Endpoint, bytes.NewReader(b), h, nil)
+	h.Add("X-Pendo-Integration-Key", token)
+	response, err := client.SendRequest(http.MethodPost, url, bytes.NewReader(b), h, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
 }
 
 func (t *Telemetry) logStepTelemetryData() {
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"regexp"
 	"testing"
@@ -263,6 +264,36 @@ func TestSetData(t *testing.T) {
 	}
 }
 
+func TestSetDataHTTPPolicy(t *testing.T) {
+	piperhttp.SetDefaultRequestPolicy(piperhttp.RequestPolicy{})
+	defer piperhttp.SetDefaultRequestPolicy(piperhttp.RequestPolicy{})
+
+	telemetryClient := Telemetry{}
+	telemetryClient.SetData(&CustomData{})
+	assert.Nil(t, telemetryClient.data.HTTPPolicy)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+	client := piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRetries: 1})
+	_, err := client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+	assert.NoError(t, err)
+
+	telemetryClient.SetData(&CustomData{})
+	if assert.NotNil(t, telemetryClient.data.HTTPPolicy) {
+		assert.Equal(t, int64(1), telemetryClient.data.HTTPPolicy.RetryAfterWaits)
+	}
+	content, _ := json.Marshal(telemetryClient.data)
+	assert.Contains(t, string(content), `"httpPolicy":{"rateLimitWaits":0,"rateLimitWaitMs":0,"retryAfterWaits":1`)
+}
+
 func TestTelemetry_logStepTelemetryData(t *testing.T) {
 	provider := &orchestrator.UnknownOrchestratorConfigProvider{}
 
@@ -0,0 +1,202 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported exporters
+const (
+	ExporterOTLPHTTP = "otlphttp"
+	ExporterOTLPGRPC = "otlpgrpc"
+	ExporterFile     = "file"
+)
+
+// DefaultFile is used by the file exporter if no file is configured
+const DefaultFile = "piper-traces.jsonl"
+
+const tracerName = "github.com/SAP/jenkins-library"
+
+// Configuration defines where the spans are exported to.
+// If the endpoint is not set, the OTLP exporters fall back to the OTEL_EXPORTER_OTLP_* env variables.
+type Configuration struct {
+	Exporter string
+	Endpoint string
+	Insecure bool
+	File     string
+}
+
+var (
+	mutex          sync.Mutex
+	tracerProvider *sdktrace.TracerProvider
+	traceFile      *os.File
+	stepContext    = context.Background()
+	stepSpan       trace.Span
+)
+
+// Initialize sets up the exporter and registers the global tracer provider.
+// Tracing stays disabled if no exporter is configured.
+func Initialize(serviceVersion string, config Configuration) error {
+	if len(config.Exporter) == 0 {
+		return nil
+	}
+	exporter, err := newExporter(config)
+	if err != nil {
+		return fmt.Errorf("failed to create %v trace exporter: %w", config.Exporter, err)
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("piper"),
+		semconv.ServiceVersion(serviceVersion),
+	)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log.Entry().Debugf("Tracing enabled using exporter %v", config.Exporter)
+	return nil
+}
+
+func newExporter(config Configuration) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case ExporterOTLPHTTP:
+		options := []otlptracehttp.Option{}
+		if len(config.Endpoint) > 0 {
+			options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+		}
+		if config.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), options...)
+	case ExporterOTLPGRPC:
+		options := []otlptracegrpc.Option{}
+		if len(config.Endpoint) > 0 {
+			options = append(options, otlptracegrpc.WithEndpointURL(config.Endpoint))
+		}
+		if config.Insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(context.Background(), options...)
+	case ExporterFile:
+		fileName := config.File
+		if len(fileName) == 0 {
+			fileName = DefaultFile
+		}
+		file, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		traceFile = file
+		return stdouttrace.New(stdouttrace.WithWriter(file))
+	}
+	return nil, fmt.Errorf("exporter not supported, use one of %v, %v, %v", ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterFile)
+}
+
+// Enabled returns true if an exporter has been initialized
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return tracerProvider != nil
+}
+
+// StartStep starts the span of the step run. The trace context of a calling piper process is taken from the environment.
+func StartStep(stepName string, startTime time.Time) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), environmentCarrier())
+	ctx, span := otel.Tracer(tracerName).Start(ctx, stepName,
+		trace.WithTimestamp(startTime),
+		trace.WithAttributes(attribute.String("piper.step.name", stepName)),
+	)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	stepContext = ctx
+	stepSpan = span
+}
+
+// EndStep ends the span of the step run and flushes all spans to the exporter
+func EndStep(errorCode, errorCategory string) {
+	mutex.Lock()
+	span := stepSpan
+	stepSpan = nil
+	stepContext = context.Background()
+	mutex.Unlock()
+
+	if span != nil {
+		span.SetAttributes(attribute.String("piper.error.code", errorCode))
+		if errorCode != "0" {
+			span.SetAttributes(attribute.String("piper.error.category", errorCategory))
+			span.SetStatus(codes.Error, fmt.Sprintf("step failed with error category %v", errorCategory))
+		}
+		span.End()
+	}
+	Shutdown()
+}
+
+// Shutdown flushes the remaining spans and stops the exporter
+func Shutdown() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		log.Entry().WithError(err).Warn("failed to export traces")
+	}
+	tracerProvider = nil
+	if traceFile != nil {
+		traceFile.Close()
+		traceFile = nil
+	}
+}
+
+// StartSpan starts a child span. If the context does not contain a span, the span of the step run is used as parent.
+func StartSpan(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		mutex.Lock()
+		ctx = stepContext
+		mutex.Unlock()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, options...)
+}
+
+// Environment returns the env variables which propagate the trace context of ctx into a nested process, e.g. TRACEPARENT=00-...
+func Environment(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	env := []string{}
+	for _, key := range carrier.Keys() {
+		env = append(env, strings.ToUpper(key)+"="+carrier.Get(key))
+	}
+	return env
+}
+
+// environmentCarrier reads the trace context from the env variables TRACEPARENT and TRACESTATE
+func environmentCarrier() propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	for _, key := range (propagation.TraceContext{}).Fields() {
+		if value, found := os.LookupEnv(strings.ToUpper(key)); found {
+			carrier.Set(key, value)
+		}
+	}
+	return carrier
+}
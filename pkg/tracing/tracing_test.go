@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Status struct {
+		Code string
+	}
+}
+
+func readSpans(t *testing.T, file string) []exportedSpan {
+	content, err := os.Open(file)
+	require.NoError(t, err)
+	defer content.Close()
+
+	spans := []exportedSpan{}
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var span exportedSpan
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func TestTracing(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, Initialize("", Configuration{}))
+		assert.False(t, Enabled())
+
+		StartStep("mavenBuild", time.Now())
+		_, span := StartSpan(context.Background(), "command mvn")
+		span.End()
+		EndStep("0", "undefined")
+	})
+
+	t.Run("unsupported exporter", func(t *testing.T) {
+		err := Initialize("", Configuration{Exporter: "jaeger"})
+		assert.EqualError(t, err, "failed to create jaeger trace exporter: exporter not supported, use one of otlphttp, otlpgrpc, file")
+		assert.False(t, Enabled())
+	})
+
+	t.Run("file exporter", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "traces.jsonl")
+		require.NoError(t, Initialize("abc", Configuration{Exporter: ExporterFile, File: file}))
+		assert.True(t, Enabled())
+
+		StartStep("mavenBuild", time.Now())
+		ctx, commandSpan := StartSpan(context.Background(), "command mvn")
+		env := Environment(ctx)
+		commandSpan.End()
+		EndStep("1", "build")
+		assert.False(t, Enabled())
+
+		spans := readSpans(t, file)
+		require.Len(t, spans, 2)
+		assert.Equal(t, "command mvn", spans[0].Name)
+		assert.Equal(t, "mavenBuild", spans[1].Name)
+		assert.Equal(t, "Error", spans[1].Status.Code)
+		assert.Equal(t, spans[1].SpanContext.SpanID, spans[0].Parent.SpanID)
+		assert.Equal(t, spans[1].SpanContext.TraceID, spans[0].SpanContext.TraceID)
+
+		require.Len(t, env, 1)
+		assert.True(t, strings.HasPrefix(env[0], "TRACEPARENT=00-"+spans[0].SpanContext.TraceID+"-"+spans[0].SpanContext.SpanID))
+	})
+
+	t.Run("continue trace of calling process", func(t *testing.T) {
+		traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+		t.Setenv("TRACEPARENT", "00-"+traceID+"-00f067aa0ba902b7-01")
+		file := filepath.Join(t.TempDir(), "traces.jsonl")
+		require.NoError(t, Initialize("abc", Configuration{Exporter: ExporterFile, File: file}))
+
+		StartStep("mavenBuild", time.Now())
+		EndStep("0", "undefined")
+
+		spans := readSpans(t, file)
+		require.Len(t, spans, 1)
+		assert.Equal(t, traceID, spans[0].SpanContext.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID)
+		assert.Equal(t, "Unset", spans[0].Status.Code)
+	})
+}
@@ -25,6 +25,7 @@ type Client struct {
 type Config struct {
 	*api.Config
 	AppRoleMountPoint string
+	JWTMountPoint     string
 	Namespace         string
 }
 
@@ -69,6 +70,64 @@ func NewClientWithAppRole(config *Config, roleID, secretID string) (Client, erro
 		return Client{}, err
 	}
 
+	setRetryPolicy(client)
+
+	if config.Namespace != "" {
+		client.SetNamespace(config.Namespace)
+	}
+
+	result, err := client.Logical().Write(path.Join(config.AppRoleMountPoint, "/login"), map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return Client{}, err
+	}
+
+	authInfo := result.Auth
+	if authInfo == nil || authInfo.ClientToken == "" {
+		return Client{}, fmt.Errorf("Could not obtain token from approle with role_id %s", roleID)
+	}
+
+	return NewClient(config, authInfo.ClientToken)
+}
+
+// NewClientWithJWT instantiates a new client and obtains a token via the JWT auth method,
+// e.g. using the identity token issued by the orchestrator for the current pipeline run
+func NewClientWithJWT(config *Config, role, jwt string) (Client, error) {
+	if config == nil {
+		config = &Config{Config: api.DefaultConfig()}
+	}
+	if config.JWTMountPoint == "" {
+		config.JWTMountPoint = "auth/jwt"
+	}
+	client, err := api.NewClient(config.Config)
+	if err != nil {
+		return Client{}, err
+	}
+	setRetryPolicy(client)
+
+	if config.Namespace != "" {
+		client.SetNamespace(config.Namespace)
+	}
+
+	result, err := client.Logical().Write(path.Join(config.JWTMountPoint, "/login"), map[string]interface{}{
+		"role": role,
+		"jwt":  jwt,
+	})
+	if err != nil {
+		return Client{}, err
+	}
+
+	authInfo := result.Auth
+	if authInfo == nil || authInfo.ClientToken == "" {
+		return Client{}, fmt.Errorf("Could not obtain token from JWT auth with role %s", role)
+	}
+
+	return NewClient(config, authInfo.ClientToken)
+}
+
+func setRetryPolicy(client *api.Client) {
 	client.SetMinRetryWait(time.Second * 5)
 	client.SetMaxRetryWait(time.Second * 90)
 	client.SetMaxRetries(3)
@@ -97,25 +156,6 @@ func NewClientWithAppRole(config *Config, roleID, secretID string) (Client, erro
 		}
 		return false, nil
 	})
-
-	if config.Namespace != "" {
-		client.SetNamespace(config.Namespace)
-	}
-
-	result, err := client.Logical().Write(path.Join(config.AppRoleMountPoint, "/login"), map[string]interface{}{
-		"role_id":   roleID,
-		"secret_id": secretID,
-	})
-	if err != nil {
-		return Client{}, err
-	}
-
-	authInfo := result.Auth
-	if authInfo == nil || authInfo.ClientToken == "" {
-		return Client{}, fmt.Errorf("Could not obtain token from approle with role_id %s", roleID)
-	}
-
-	return NewClient(config, authInfo.ClientToken)
 }
 
 // GetSecret uses the given path to fetch a secret from vault
@@ -177,6 +217,60 @@ func (v Client) GetKvSecret(path string) (map[string]string, error) {
 	return secretData, nil
 }
 
+// DynamicSecret is a short-lived credential generated by a secrets engine, e.g. database, aws, kubernetes or pki
+type DynamicSecret struct {
+	// LeaseID is empty if the secrets engine did not create a lease, e.g. for certificates of the pki engine
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+	// Data contains the fields of the credential, values which are not strings are JSON encoded
+	Data map[string]string
+}
+
+// GetDynamicSecret generates a credential for the role at the given path, e.g. database/creds/my-role.
+// Engines which require parameters like pki/issue/my-role are called with a write request if parameters are provided.
+func (v Client) GetDynamicSecret(path string, parameters map[string]interface{}) (*DynamicSecret, error) {
+	path = sanitizePath(path)
+	var secret *api.Secret
+	var err error
+	if len(parameters) > 0 {
+		secret, err = v.lClient.Write(path, parameters)
+	} else {
+		secret, err = v.lClient.Read(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("No credential generated for path %s", path)
+	}
+
+	data := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		if valueStr, ok := v.(string); ok {
+			data[k] = valueStr
+			continue
+		}
+		valueJSON, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("Could not encode field %s of credential for path %s: %w", k, path, err)
+		}
+		data[k] = string(valueJSON)
+	}
+	return &DynamicSecret{
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable:     secret.Renewable,
+		Data:          data,
+	}, nil
+}
+
+// RevokeLease revokes the lease of a dynamic secret, i.e. the credential is invalidated immediately
+func (v Client) RevokeLease(leaseID string) error {
+	_, err := v.lClient.Write("sys/leases/revoke", map[string]interface{}{"lease_id": leaseID})
+	return err
+}
+
 // WriteKvSecret writes secret to kv engine
 func (v Client) WriteKvSecret(path string, newSecret map[string]string) error {
 	oldSecret, err := v.GetKvSecret(path)
@@ -6,6 +6,8 @@ package vault
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"path"
 	"strings"
 	"testing"
@@ -355,6 +357,103 @@ func TestUnknownKvVersion(t *testing.T) {
 
 }
 
+func TestGetDynamicSecret(t *testing.T) {
+	t.Run("read credential", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}}
+		vaultMock.On("Read", "database/creds/deployer").Return(&api.Secret{
+			LeaseID:       "database/creds/deployer/abc",
+			LeaseDuration: 3600,
+			Renewable:     true,
+			Data:          SecretData{"username": "v-deployer-x1", "password": "secret"},
+		}, nil)
+
+		secret, err := client.GetDynamicSecret("/database/creds/deployer", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, &DynamicSecret{
+			LeaseID:       "database/creds/deployer/abc",
+			LeaseDuration: time.Hour,
+			Renewable:     true,
+			Data:          map[string]string{"username": "v-deployer-x1", "password": "secret"},
+		}, secret)
+	})
+
+	t.Run("write parameters", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}}
+		parameters := SecretData{"common_name": "piper.example.com"}
+		vaultMock.On("Write", "pki/issue/piper", parameters).Return(&api.Secret{
+			Data: SecretData{"certificate": "-----BEGIN CERTIFICATE-----", "ca_chain": []interface{}{"ca1", "ca2"}},
+		}, nil)
+
+		secret, err := client.GetDynamicSecret("pki/issue/piper", parameters)
+		assert.NoError(t, err)
+		assert.Empty(t, secret.LeaseID)
+		assert.Equal(t, map[string]string{"certificate": "-----BEGIN CERTIFICATE-----", "ca_chain": `["ca1","ca2"]`}, secret.Data)
+	})
+
+	t.Run("no credential generated", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{vaultMock, &Config{}}
+		vaultMock.On("Read", "aws/creds/unknown").Return(nil, nil)
+
+		_, err := client.GetDynamicSecret("aws/creds/unknown", nil)
+		assert.EqualError(t, err, "No credential generated for path aws/creds/unknown")
+	})
+}
+
+func TestRevokeLease(t *testing.T) {
+	vaultMock := &mocks.VaultMock{}
+	client := Client{vaultMock, &Config{}}
+	vaultMock.On("Write", "sys/leases/revoke", SecretData{"lease_id": "database/creds/deployer/abc"}).Return(nil, nil)
+
+	assert.NoError(t, client.RevokeLease("database/creds/deployer/abc"))
+	vaultMock.AssertExpectations(t)
+}
+
+func TestNewClientWithJWT(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var request map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/auth/github-actions/login", r.URL.Path)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			fmt.Fprint(w, `{"auth": {"client_token": "s.token"}}`)
+		}))
+		defer server.Close()
+
+		config := &Config{Config: &api.Config{Address: server.URL}, JWTMountPoint: "auth/github-actions"}
+		client, err := NewClientWithJWT(config, "piper", "eyJhbGciOi")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, client.lClient)
+		assert.Equal(t, map[string]interface{}{"role": "piper", "jwt": "eyJhbGciOi"}, request)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/auth/jwt/login", r.URL.Path)
+			fmt.Fprint(w, `{"auth": null}`)
+		}))
+		defer server.Close()
+
+		_, err := NewClientWithJWT(&Config{Config: &api.Config{Address: server.URL}}, "piper", "eyJhbGciOi")
+
+		assert.EqualError(t, err, "Could not obtain token from JWT auth with role piper")
+	})
+
+	t.Run("login rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors": ["error validating token: invalid audience (aud) claim"]}`)
+		}))
+		defer server.Close()
+
+		_, err := NewClientWithJWT(&Config{Config: &api.Config{Address: server.URL}}, "piper", "eyJhbGciOi")
+
+		assert.ErrorContains(t, err, "invalid audience (aud) claim")
+	})
+}
+
 func TestSetAppRoleMountPont(t *testing.T) {
 	client := Client{nil, &Config{}}
 	const newMountpoint = "auth/test"
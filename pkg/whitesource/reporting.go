@@ -3,14 +3,12 @@ package whitesource
 import (
 	"bytes"
 	"crypto/sha1"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
-	"time"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/package-url/packageurl-go"
@@ -22,91 +20,26 @@ import (
 	"github.com/pkg/errors"
 )
 
-// CreateCustomVulnerabilityReport creates a vulnerability ScanReport to be used for uploading into various sinks
-func CreateCustomVulnerabilityReport(productName string, scan *Scan, alerts *[]Alert, cvssSeverityLimit float64) reporting.ScanReport {
-	severe, _ := CountSecurityVulnerabilities(alerts, cvssSeverityLimit)
-
-	// sort according to vulnerability severity
-	sort.Slice(*alerts, func(i, j int) bool {
-		return vulnerabilityScore((*alerts)[i]) > vulnerabilityScore((*alerts)[j])
-	})
-
-	projectNames := scan.ScannedProjectNames()
-
-	scanReport := reporting.ScanReport{
-		ReportTitle: "WhiteSource Security Vulnerability Report",
-		Subheaders: []reporting.Subheader{
-			{Description: "WhiteSource product name", Details: productName},
-			{Description: "Filtered project names", Details: strings.Join(projectNames, ", ")},
-		},
-		Overview: []reporting.OverviewRow{
-			{Description: "Total number of vulnerabilities", Details: fmt.Sprint(len(*alerts))},
-			{Description: "Total number of high/critical vulnerabilities with CVSS score >= 7.0", Details: fmt.Sprint(severe)},
-		},
-		SuccessfulScan: severe == 0,
-		ReportTime:     time.Now(),
+// CreateCustomVulnerabilityReport creates a vulnerability ScanReport of the findings to be used for uploading into various sinks
+func CreateCustomVulnerabilityReport(productName string, scan *Scan, findings []reporting.Finding, cvssSeverityLimit float64) reporting.ScanReport {
+	subheaders := []reporting.Subheader{
+		{Description: "WhiteSource product name", Details: productName},
+		{Description: "Filtered project names", Details: strings.Join(scan.ScannedProjectNames(), ", ")},
 	}
-
-	detailTable := reporting.ScanDetailTable{
-		NoRowsMessage: "No publicly known vulnerabilities detected",
-		Headers: []string{
-			"Date",
-			"CVE",
-			"CVSS Score",
-			"CVSS Version",
-			"Project",
-			"Library file name",
-			"Library group ID",
-			"Library artifact ID",
-			"Library version",
-			"Description",
-			"Top fix",
-		},
-		WithCounter:   true,
-		CounterHeader: "Entry #",
-	}
-
-	for _, alert := range *alerts {
-		var score float64
-		var scoreStyle reporting.ColumnStyle = reporting.Yellow
-		if isSevereVulnerability(alert, cvssSeverityLimit) {
-			scoreStyle = reporting.Red
-		}
-		var cveVersion string
-		if alert.Vulnerability.CVSS3Score > 0 {
-			score = alert.Vulnerability.CVSS3Score
-			cveVersion = "v3"
-		} else {
-			score = alert.Vulnerability.Score
-			cveVersion = "v2"
-		}
-
-		var topFix string
-		emptyFix := Fix{}
-		if alert.Vulnerability.TopFix != emptyFix {
-			topFix = fmt.Sprintf(`%v<br>%v<br><a href="%v">%v</a>}"`, alert.Vulnerability.TopFix.Message, alert.Vulnerability.TopFix.FixResolution, alert.Vulnerability.TopFix.URL, alert.Vulnerability.TopFix.URL)
-		}
-
-		row := reporting.ScanRow{}
-		row.AddColumn(alert.Vulnerability.PublishDate, 0)
-		row.AddColumn(fmt.Sprintf(`<a href="%v">%v</a>`, alert.Vulnerability.URL, alert.Vulnerability.Name), 0)
-		row.AddColumn(score, scoreStyle)
-		row.AddColumn(cveVersion, 0)
-		row.AddColumn(alert.Project, 0)
-		row.AddColumn(alert.Library.Filename, 0)
-		row.AddColumn(alert.Library.GroupID, 0)
-		row.AddColumn(alert.Library.ArtifactID, 0)
-		row.AddColumn(alert.Library.Version, 0)
-		row.AddColumn(alert.Vulnerability.Description, 0)
-		row.AddColumn(topFix, 0)
-
-		detailTable.Rows = append(detailTable.Rows, row)
-	}
-	scanReport.DetailTable = detailTable
-
+	severeDescription := fmt.Sprintf("Open findings with CVSS score %.1f or higher", cvssSeverityLimit)
+	scanReport := reporting.CreateFindingsScanReportFunc("WhiteSource Security Vulnerability Report", subheaders, findings, severeDescription, func(finding reporting.Finding) bool {
+		return isSevereFinding(finding, cvssSeverityLimit)
+	})
+	scanReport.DetailTable.NoRowsMessage = "No publicly known vulnerabilities detected"
 	return scanReport
 }
 
+// isSevereFinding applies the same rules as isSevereVulnerability to the finding of an alert.
+// Alerts ignored in WhiteSource or assessed in the assessment file are not considered, like in the checks of the step.
+func isSevereFinding(finding reporting.Finding, cvssSeverityLimit float64) bool {
+	return finding.Status == reporting.FindingStatusOpen && finding.CVSSScore >= cvssSeverityLimit && cvssSeverityLimit >= 0
+}
+
 // CountSecurityVulnerabilities counts the security vulnerabilities above severityLimit
 func CountSecurityVulnerabilities(alerts *[]Alert, cvssSeverityLimit float64) (int, int) {
 	severeVulnerabilities := 0
@@ -176,83 +109,10 @@ func WriteCustomVulnerabilityReports(productName string, scan *Scan, scanReport
 	return reportPaths, nil
 }
 
-// Creates a SARIF result from the Alerts that were brought up by the scan
-func CreateSarifResultFile(scan *Scan, alerts *[]Alert) *format.SARIF {
-	//Now, we handle the sarif
+// CreateSarifResultFile creates a SARIF result from the findings of the alerts that were brought up by the scan
+func CreateSarifResultFile(scan *Scan, findings []reporting.Finding) *format.SARIF {
 	log.Entry().Debug("Creating SARIF file for data transfer")
-	var sarif format.SARIF
-	sarif.Schema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json"
-	sarif.Version = "2.1.0"
-	var wsRun format.Runs
-	sarif.Runs = append(sarif.Runs, wsRun)
-
-	//handle the tool object
-	tool := *new(format.Tool)
-	tool.Driver = *new(format.Driver)
-	tool.Driver.Name = scan.AgentName
-	tool.Driver.Version = scan.AgentVersion
-	tool.Driver.InformationUri = "https://mend.io"
-
-	// Handle results/vulnerabilities
-	collectedRules := []string{}
-	for _, alert := range *alerts {
-		result := *new(format.Results)
-		ruleId := alert.Vulnerability.Name
-		log.Entry().Debugf("Transforming alert %v into SARIF format", ruleId)
-		result.RuleID = ruleId
-		result.Message = new(format.Message)
-		result.Message.Text = alert.Vulnerability.Description
-		artLoc := new(format.ArtifactLocation)
-		artLoc.Index = 0
-		artLoc.URI = alert.Library.Filename
-		result.AnalysisTarget = artLoc
-		location := format.Location{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: alert.Library.Filename}}}
-		result.Locations = append(result.Locations, location)
-		partialFingerprints := new(format.PartialFingerprints)
-		partialFingerprints.PackageURLPlusCVEHash = base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%v+%v", alert.Library.ToPackageUrl().ToString(), alert.Vulnerability.Name)))
-		result.PartialFingerprints = *partialFingerprints
-		result.Properties = getAuditInformation(alert)
-
-		//append the result
-		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
-
-		// only create rule on new CVE
-		if !piperutils.ContainsString(collectedRules, ruleId) {
-			collectedRules = append(collectedRules, ruleId)
-
-			sarifRule := *new(format.SarifRule)
-			sarifRule.ID = ruleId
-			sarifRule.Name = alert.Vulnerability.Name
-			sd := new(format.Message)
-			sd.Text = fmt.Sprintf("%v Package %v", alert.Vulnerability.Name, alert.Library.ArtifactID)
-			sarifRule.ShortDescription = sd
-			fd := new(format.Message)
-			fd.Text = alert.Vulnerability.Description
-			sarifRule.FullDescription = fd
-			defaultConfig := new(format.DefaultConfiguration)
-			defaultConfig.Level = transformToLevel(alert.Vulnerability.Severity, alert.Vulnerability.CVSS3Severity)
-			sarifRule.DefaultConfiguration = defaultConfig
-			sarifRule.HelpURI = alert.Vulnerability.URL
-			markdown, _ := alert.ToMarkdown()
-			sarifRule.Help = new(format.Help)
-			sarifRule.Help.Text = alert.ToTxt()
-			sarifRule.Help.Markdown = string(markdown)
-
-			ruleProp := *new(format.SarifRuleProperties)
-			ruleProp.Tags = append(ruleProp.Tags, alert.Type)
-			ruleProp.Tags = append(ruleProp.Tags, alert.Library.ToPackageUrl().ToString())
-			ruleProp.Tags = append(ruleProp.Tags, alert.Vulnerability.URL)
-			ruleProp.SecuritySeverity = fmt.Sprint(consolidateScores(alert.Vulnerability.Score, alert.Vulnerability.CVSS3Score))
-			ruleProp.Precision = "very-high"
-
-			sarifRule.Properties = &ruleProp
-
-			// append the rule
-			tool.Driver.Rules = append(tool.Driver.Rules, sarifRule)
-		}
-	}
-	//Finalize: tool
-	sarif.Runs[0].Tool = tool
+	sarif := reporting.CreateFindingsSarif(format.Driver{Name: scan.AgentName, Version: scan.AgentVersion, InformationUri: "https://mend.io"}, findings)
 
 	// Threadflowlocations is no loger useful: voiding it will make for smaller reports
 	sarif.Runs[0].ThreadFlowLocations = []format.Locations{}
@@ -270,56 +130,6 @@ func CreateSarifResultFile(scan *Scan, alerts *[]Alert) *format.SARIF {
 	return &sarif
 }
 
-func getAuditInformation(alert Alert) *format.SarifProperties {
-	unifiedAuditState := "new"
-	auditMessage := ""
-	isAudited := false
-
-	// unified audit state
-	switch alert.Status {
-	case "OPEN":
-		unifiedAuditState = "new"
-	case "IGNORE":
-		unifiedAuditState = "notRelevant"
-		auditMessage = alert.Comments
-	}
-
-	if alert.Assessment != nil {
-		unifiedAuditState = string(alert.Assessment.Status)
-		auditMessage = string(alert.Assessment.Analysis)
-	}
-
-	if unifiedAuditState == string(format.Relevant) ||
-		unifiedAuditState == string(format.NotRelevant) {
-		isAudited = true
-	}
-
-	return &format.SarifProperties{
-		Audited:               isAudited,
-		ToolAuditMessage:      auditMessage,
-		UnifiedAuditState:     unifiedAuditState,
-		AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
-		AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
-		UnifiedSeverity:       alert.Vulnerability.CVSS3Severity,
-		UnifiedCriticality:    float32(alert.Vulnerability.CVSS3Score),
-	}
-}
-
-func transformToLevel(cvss2severity, cvss3severity string) string {
-	cvssseverity := consolidateSeverities(cvss2severity, cvss3severity)
-	switch cvssseverity {
-	case "low":
-		return "warning"
-	case "medium":
-		return "warning"
-	case "high":
-		return "error"
-	case "critical":
-		return "error"
-	}
-	return "none"
-}
-
 func consolidateSeverities(cvss2severity, cvss3severity string) string {
 	if len(cvss3severity) > 0 {
 		return cvss3severity
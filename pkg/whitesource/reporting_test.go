@@ -36,31 +36,58 @@ func TestCreateCustomVulnerabilityReport(t *testing.T) {
 			{Library: Library{Filename: "vul3"}, Vulnerability: Vulnerability{Score: 6}},
 		}
 
-		scanReport := CreateCustomVulnerabilityReport(config.ProductName, scan, &alerts, 7.0)
+		findings := []reporting.Finding{}
+		for _, alert := range alerts {
+			findings = append(findings, alert.ToFinding())
+		}
+
+		scanReport := CreateCustomVulnerabilityReport(config.ProductName, scan, findings, 7.0)
 
 		assert.Equal(t, "WhiteSource Security Vulnerability Report", scanReport.Title())
+		assert.Equal(t, "testProject - ", scanReport.Subheaders[1].Details)
 		assert.Equal(t, 3, len(scanReport.DetailTable.Rows))
+		assert.False(t, scanReport.SuccessfulScan)
 
-		// assert that library info is filled and sorting has been executed
-		assert.Equal(t, "vul2", scanReport.DetailTable.Rows[0].Columns[5].Content)
-		assert.Equal(t, "vul1", scanReport.DetailTable.Rows[1].Columns[5].Content)
-		assert.Equal(t, "vul3", scanReport.DetailTable.Rows[2].Columns[5].Content)
-
-		// assert that CVSS version identification has been done
-		assert.Equal(t, "v3", scanReport.DetailTable.Rows[0].Columns[3].Content)
-		assert.Equal(t, "v3", scanReport.DetailTable.Rows[1].Columns[3].Content)
-		assert.Equal(t, "v2", scanReport.DetailTable.Rows[2].Columns[3].Content)
+		// assert that sorting has been executed
+		assert.Equal(t, "8", scanReport.DetailTable.Rows[0].Columns[3].Content)
+		assert.Equal(t, "7", scanReport.DetailTable.Rows[1].Columns[3].Content)
+		assert.Equal(t, "6", scanReport.DetailTable.Rows[2].Columns[3].Content)
 
 		// assert proper rating and styling of high prio issues
-		assert.Equal(t, "8", scanReport.DetailTable.Rows[0].Columns[2].Content)
-		assert.Equal(t, "7", scanReport.DetailTable.Rows[1].Columns[2].Content)
-		assert.Equal(t, "6", scanReport.DetailTable.Rows[2].Columns[2].Content)
-		assert.Equal(t, "red-cell", scanReport.DetailTable.Rows[0].Columns[2].Style.String())
-		assert.Equal(t, "red-cell", scanReport.DetailTable.Rows[1].Columns[2].Style.String())
-		assert.Equal(t, "yellow-cell", scanReport.DetailTable.Rows[2].Columns[2].Style.String())
+		assert.Equal(t, "red-cell", scanReport.DetailTable.Rows[0].Columns[0].Style.String())
+		assert.Equal(t, "red-cell", scanReport.DetailTable.Rows[1].Columns[0].Style.String())
+		assert.Equal(t, "yellow-cell", scanReport.DetailTable.Rows[2].Columns[0].Style.String())
+	})
+
+	t.Run("no severity limit", func(t *testing.T) {
+		scan := &Scan{}
+		findings := []reporting.Finding{(Alert{Vulnerability: Vulnerability{CVSS3Score: 9.8}}).ToFinding()}
+
+		scanReport := CreateCustomVulnerabilityReport("product", scan, findings, -1)
 
-		assert.Contains(t, scanReport.DetailTable.Rows[0].Columns[10].Content, "this is the top fix")
+		assert.True(t, scanReport.SuccessfulScan)
+		assert.Equal(t, "yellow-cell", scanReport.DetailTable.Rows[0].Columns[0].Style.String())
+	})
+
+	t.Run("score compared with limit", func(t *testing.T) {
+		scan := &Scan{}
+		findings := []reporting.Finding{(Alert{Vulnerability: Vulnerability{CVSS3Score: 6.5}}).ToFinding()}
 
+		assert.False(t, CreateCustomVulnerabilityReport("product", scan, findings, 6.5).SuccessfulScan)
+		assert.True(t, CreateCustomVulnerabilityReport("product", scan, findings, 6.6).SuccessfulScan)
+	})
+
+	t.Run("assessed alerts", func(t *testing.T) {
+		scan := &Scan{}
+		findings := []reporting.Finding{
+			(Alert{Vulnerability: Vulnerability{CVSS3Score: 9.8}, Assessment: &format.Assessment{Status: format.InProcess}}).ToFinding(),
+			(Alert{Vulnerability: Vulnerability{CVSS3Score: 9.8}, Status: "IGNORE"}).ToFinding(),
+		}
+
+		scanReport := CreateCustomVulnerabilityReport("product", scan, findings, 7.0)
+
+		assert.True(t, scanReport.SuccessfulScan)
+		assert.Equal(t, "0", scanReport.Overview[1].Details)
 	})
 }
 
@@ -186,7 +213,12 @@ func TestCreateSarifResultFile(t *testing.T) {
 		{Library: Library{Filename: "vul3", ArtifactID: "org.some.lib2"}, Vulnerability: Vulnerability{Name: "CVE-2022-003", Score: 6}},
 	}
 
-	sarif := CreateSarifResultFile(scan, &alerts)
+	findings := []reporting.Finding{}
+	for _, alert := range alerts {
+		findings = append(findings, alert.ToFinding())
+	}
+
+	sarif := CreateSarifResultFile(scan, findings)
 
 	assert.Equal(t, "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json", sarif.Schema)
 	assert.Equal(t, "2.1.0", sarif.Version)
@@ -195,7 +227,8 @@ func TestCreateSarifResultFile(t *testing.T) {
 	assert.Equal(t, "1.2.6", sarif.Runs[0].Tool.Driver.Version)
 	assert.Equal(t, 3, len(sarif.Runs[0].Tool.Driver.Rules))
 	assert.Equal(t, 3, len(sarif.Runs[0].Results))
-	// TODO add more extensive verification once we agree on the format details
+	assert.Equal(t, "pkg:generic/org.some.lib", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.NotNil(t, sarif.Runs[0].Conversion)
 }
 
 func TestWriteCustomVulnerabilityReports(t *testing.T) {
@@ -336,83 +369,3 @@ func TestVulnerabilityScore(t *testing.T) {
 		assert.Equalf(t, test.expected, vulnerabilityScore(test.alert), "run %v failed", i)
 	}
 }
-
-func TestGetAuditInformation(t *testing.T) {
-	tt := []struct {
-		name     string
-		alert    Alert
-		expected *format.SarifProperties
-	}{
-		{
-			name: "New not audited alert",
-			alert: Alert{
-				Status: "OPEN",
-			},
-			expected: &format.SarifProperties{
-				Audited:               false,
-				ToolAuditMessage:      "",
-				UnifiedAuditState:     "new",
-				AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
-				AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
-			},
-		},
-		{
-			name: "Audited alert",
-			alert: Alert{
-				Status:   "IGNORE",
-				Comments: "Not relevant alert",
-				Vulnerability: Vulnerability{
-					CVSS3Score:    9.3,
-					CVSS3Severity: "critical",
-				},
-			},
-			expected: &format.SarifProperties{
-				Audited:               true,
-				ToolAuditMessage:      "Not relevant alert",
-				UnifiedAuditState:     "notRelevant",
-				UnifiedSeverity:       "critical",
-				UnifiedCriticality:    9.3,
-				AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
-				AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
-			},
-		},
-		{
-			name: "Alert with incorrect status",
-			alert: Alert{
-				Status:   "Not correct",
-				Comments: "Some comment",
-			},
-			expected: &format.SarifProperties{
-				Audited:               false,
-				ToolAuditMessage:      "",
-				UnifiedAuditState:     "new",
-				AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
-				AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
-			},
-		},
-		{
-			name: "Not audited alert",
-			alert: Alert{
-				Assessment: &format.Assessment{
-					Status:   format.NotRelevant,
-					Analysis: format.FixedByDevTeam,
-				},
-				Status:   "OPEN",
-				Comments: "New alert",
-			},
-			expected: &format.SarifProperties{
-				Audited:               true,
-				ToolAuditMessage:      string(format.FixedByDevTeam),
-				UnifiedAuditState:     "notRelevant",
-				AuditRequirement:      format.AUDIT_REQUIREMENT_GROUP_1_DESC,
-				AuditRequirementIndex: format.AUDIT_REQUIREMENT_GROUP_1_INDEX,
-			},
-		},
-	}
-
-	for _, test := range tt {
-		t.Run(test.name, func(t *testing.T) {
-			assert.Equal(t, test.expected, getAuditInformation(test.alert))
-		})
-	}
-}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
@@ -79,27 +80,16 @@ func (a Alert) Title() string {
 	return fmt.Sprintf("%v %v %v ", a.Type, a.Vulnerability.Name, a.Library.ArtifactID)
 }
 
-func (a *Alert) ContainedIn(assessments *[]format.Assessment) (bool, error) {
-	localPurl := a.Library.ToPackageUrl().ToString()
-	for _, assessment := range *assessments {
-		if assessment.Vulnerability == a.Vulnerability.Name {
-			for _, purl := range assessment.Purls {
-				assessmentPurl, err := purl.ToPackageUrl()
-				assessmentPurlStr := assessmentPurl.ToString()
-				if err != nil {
-					log.SetErrorCategory(log.ErrorConfiguration)
-					log.Entry().WithError(err).Errorf("assessment from file ignored due to invalid packageUrl '%s'", purl)
-					return false, err
-				}
-				if assessmentPurlStr == localPurl {
-					log.Entry().Debugf("matching assessment %v on package %v detected for alert %v", assessment.Vulnerability, assessmentPurlStr, a.Vulnerability.Name)
-					a.Assessment = &assessment
-					return true, nil
-				}
-			}
-		}
+// ContainedIn checks whether one of the assessments applies to the vulnerability of the alert and attaches it to the alert.
+// Assessments with an invalid package URL are skipped, thus the alert stays active.
+func (a *Alert) ContainedIn(assessments *[]format.Assessment) bool {
+	assessment := format.FindAssessment(*assessments, *a.Library.ToPackageUrl(), a.Vulnerability.Name)
+	if assessment == nil {
+		return false
 	}
-	return false, nil
+	log.Entry().Debugf("matching assessment %v on package %v detected for alert %v", assessment.Vulnerability, a.Library.ToPackageUrl().ToString(), a.Vulnerability.Name)
+	a.Assessment = assessment
+	return true
 }
 
 func transformLibToPurlType(libType string) string {
@@ -157,30 +147,12 @@ func consolidate(cvss2severity, cvss3severity string, cvss2score, cvss3score flo
 func (a Alert) ToMarkdown() ([]byte, error) {
 
 	if a.Type == "SECURITY_VULNERABILITY" {
-		score := consolidateScores(a.Vulnerability.Score, a.Vulnerability.CVSS3Score)
-
-		vul := reporting.VulnerabilityReport{
-			ArtifactID: a.Library.ArtifactID,
-			// no information available about branch and commit, yet
-			Branch:         "",
-			CommitID:       "",
-			Description:    a.Vulnerability.Description,
-			DependencyType: a.DependencyType(),
-			// no information available about footer, yet
-			Footer: "",
-			Group:  a.Library.GroupID,
-			// no information available about pipeline name and link, yet
-			PipelineName:      "",
-			PipelineLink:      "",
-			PublishDate:       a.Vulnerability.PublishDate,
-			Resolution:        a.Vulnerability.TopFix.FixResolution,
-			Score:             score,
-			Severity:          consolidate(a.Vulnerability.Severity, a.Vulnerability.CVSS3Severity, a.Vulnerability.Score, a.Vulnerability.CVSS3Score),
-			Version:           a.Library.Version,
-			PackageURL:        a.Library.ToPackageUrl().ToString(),
-			VulnerabilityLink: a.Vulnerability.URL,
-			VulnerabilityName: a.Vulnerability.Name,
-		}
+		vul := reporting.NewVulnerabilityReport(a.ToFinding())
+		// no information available about branch, commit, footer, pipeline name and link, yet
+		vul.DependencyType = a.DependencyType()
+		vul.Group = a.Library.GroupID
+		vul.PublishDate = a.Vulnerability.PublishDate
+		vul.Resolution = a.Vulnerability.TopFix.FixResolution
 		return vul.ToMarkdown()
 	} else if a.Type == "REJECTED_BY_POLICY_RESOURCE" {
 		policyReport := reporting.PolicyViolationReport{
@@ -230,6 +202,51 @@ Link: [%v](%v)`,
 	)
 }
 
+var fixVersionPattern = regexp.MustCompile(`(?i)upgrade to version\s+v?([0-9][^\s,;]*)`)
+
+// ToFinding converts the alert into the scanner independent finding
+func (a Alert) ToFinding() reporting.Finding {
+	finding := reporting.Finding{
+		Scanner:          "whitesource",
+		Type:             reporting.FindingTypeVulnerability,
+		ID:               a.Vulnerability.Name,
+		Name:             a.Vulnerability.Name,
+		Description:      a.Vulnerability.Description,
+		PackageURL:       a.Library.ToPackageUrl().ToString(),
+		ComponentName:    a.Library.ArtifactID,
+		ComponentVersion: a.Library.Version,
+		Severity:         reporting.ParseSeverity(consolidate(a.Vulnerability.Severity, a.Vulnerability.CVSS3Severity, a.Vulnerability.Score, a.Vulnerability.CVSS3Score)),
+		CVSSScore:        consolidateScores(a.Vulnerability.Score, a.Vulnerability.CVSS3Score),
+		Remediation:      a.Vulnerability.TopFix.FixResolution,
+		Link:             a.Vulnerability.URL,
+		Status:           reporting.FindingStatusFromAssessment(a.Assessment),
+	}
+	if finding.Severity <= reporting.SeverityInfo {
+		// the severity is not always provided together with the score
+		finding.Severity = reporting.SeverityFromCVSS(finding.CVSSScore)
+	}
+	if a.Assessment != nil {
+		finding.StatusComment = string(a.Assessment.Analysis)
+	} else if a.Status == "IGNORE" {
+		// alerts ignored in WhiteSource have been assessed there
+		finding.Status = reporting.FindingStatusNotAffected
+		finding.StatusComment = a.Comments
+	}
+	if a.Type == "REJECTED_BY_POLICY_RESOURCE" {
+		finding.Type = reporting.FindingTypeLicense
+	}
+	if strings.HasPrefix(a.Vulnerability.Name, "CVE-") {
+		finding.CVE = a.Vulnerability.Name
+	}
+	if match := fixVersionPattern.FindStringSubmatch(a.Vulnerability.TopFix.FixResolution); match != nil {
+		finding.FixVersion = match[1]
+	}
+	if published, err := time.Parse("2006-01-02", a.Vulnerability.PublishDate); err == nil {
+		finding.Published = &published
+	}
+	return finding
+}
+
 func consolidateScores(cvss2score, cvss3score float64) float64 {
 	score := cvss3score
 	if score == 0 {
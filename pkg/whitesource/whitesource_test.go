@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/package-url/packageurl-go"
 	"github.com/stretchr/testify/assert"
@@ -298,6 +299,40 @@ func TestGetProductName(t *testing.T) {
 	assert.Equal(t, "Test Product", productName)
 }
 
+func TestAlertContainedIn(t *testing.T) {
+	alert := Alert{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1"},
+		Library:       Library{LibType: "MAVEN_ARTIFACT", GroupID: "org.example", ArtifactID: "lib", Version: "1.0.0"},
+	}
+
+	t.Run("assessed", func(t *testing.T) {
+		assessments := []format.Assessment{
+			{Vulnerability: "CVE-2023-2", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "pkg:maven/org.example/lib@1.0.0"}}},
+			{Vulnerability: "cve-2023-1", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "pkg:maven/org.example/lib@1.0.0?type=jar"}}},
+		}
+		a := alert
+
+		assert.True(t, a.ContainedIn(&assessments))
+		assert.Equal(t, &assessments[1], a.Assessment)
+	})
+
+	t.Run("other version", func(t *testing.T) {
+		assessments := []format.Assessment{{Vulnerability: "CVE-2023-1", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "pkg:maven/org.example/lib@1.0.1"}}}}
+		a := alert
+
+		assert.False(t, a.ContainedIn(&assessments))
+		assert.Nil(t, a.Assessment)
+	})
+
+	t.Run("invalid package URL", func(t *testing.T) {
+		assessments := []format.Assessment{{Vulnerability: "CVE-2023-1", Status: format.NotRelevant, Purls: []format.Purl{{Purl: "maven/org.example/lib@1.0.0"}}}}
+		a := alert
+
+		assert.False(t, a.ContainedIn(&assessments))
+		assert.Nil(t, a.Assessment)
+	})
+}
+
 func TestTransformLibToPurlType(t *testing.T) {
 	tt := []struct {
 		libType  string
@@ -531,3 +566,41 @@ func TestGetProjectAlertsByType(t *testing.T) {
 
 	})
 }
+
+func TestAlertToFinding(t *testing.T) {
+	t.Parallel()
+	alert := Alert{
+		Type:    "SECURITY_VULNERABILITY",
+		Library: Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "Java"},
+		Vulnerability: Vulnerability{
+			Name:          "CVE-2021-44228",
+			Severity:      "high",
+			Score:         9.3,
+			CVSS3Severity: "high",
+			CVSS3Score:    10,
+			PublishDate:   "2021-12-10",
+			TopFix:        Fix{FixResolution: "Upgrade to version org.apache.logging.log4j:log4j-core:2.15.0"},
+		},
+	}
+	finding := alert.ToFinding()
+	assert.Equal(t, "whitesource", finding.Scanner)
+	assert.Equal(t, "CVE-2021-44228", finding.CVE)
+	assert.Equal(t, "log4j-core", finding.ComponentName)
+	assert.Contains(t, finding.PackageURL, "log4j-core@2.14.1")
+	assert.Equal(t, 10.0, finding.CVSSScore)
+	assert.Equal(t, "critical", finding.Severity.String())
+	assert.Equal(t, "2021-12-10", finding.Published.Format("2006-01-02"))
+	assert.Equal(t, "open", string(finding.Status))
+
+	alert.Vulnerability.TopFix.FixResolution = "Upgrade to version 2.17.1"
+	alert.Assessment = &format.Assessment{Status: format.NotRelevant}
+	finding = alert.ToFinding()
+	assert.Equal(t, "2.17.1", finding.FixVersion)
+	assert.Equal(t, "notAffected", string(finding.Status))
+
+	ignored := Alert{Status: "IGNORE", Comments: "not used at runtime", Vulnerability: Vulnerability{Name: "CVE-2022-1", CVSS3Score: 7.5}}
+	finding = ignored.ToFinding()
+	assert.Equal(t, "notAffected", string(finding.Status))
+	assert.Equal(t, "not used at runtime", finding.StatusComment)
+	assert.Equal(t, "high", finding.Severity.String())
+}
@@ -33,6 +33,15 @@ class CommonPipelineEnvironmentTest extends BasePiperTest {
         nullScript.metaClass.findFiles = null
     }
 
+    @Test
+    void testCPEEncryptionKey() {
+        String key = nullScript.commonPipelineEnvironment.getCPEEncryptionKey(nullScript)
+        assertThat(key.length(), is(36))
+        // the key stays the same during the pipeline run
+        nullScript.commonPipelineEnvironment.reset()
+        assertThat(nullScript.commonPipelineEnvironment.getCPEEncryptionKey(nullScript), is(key))
+    }
+
     @Test
     void testCustomValueList() {
         nullScript.commonPipelineEnvironment.setValue('myList', [])
@@ -144,6 +144,7 @@ class PiperExecuteBinTest extends BasePiperTest {
         // asserts
         assertThat(writeFileRule.files['.pipeline/tmp/metadata/test.yaml'], containsString('name: testStep'))
         assertThat(withEnvArgs[0], allOf(startsWith('PIPER_parametersJSON'), containsString('"testParam":"This is test content"')))
+        assertThat(withEnvArgs[2], is("PIPER_cpeEncryptionKey=${nullScript.commonPipelineEnvironment.getCPEEncryptionKey(nullScript)}".toString()))
         assertThat(shellCallRule.shell[2], is('./piper testStep'))
         assertThat(credentials.size(), is(3))
         assertThat(credentials[0], allOf(hasEntry('credentialsId', 'credFile'), hasEntry('variable', 'PIPER_credFile')))
@@ -291,6 +292,7 @@ class PiperExecuteBinTest extends BasePiperTest {
 
         assertThat(writeFileRule.files['.pipeline/tmp/metadata/test.yaml'], containsString('name: testStep'))
         assertThat(withEnvArgs[0], allOf(startsWith('PIPER_parametersJSON'), containsString('"testParam":"This is test content"')))
+        assertThat(withEnvArgs[2], is("PIPER_cpeEncryptionKey=${nullScript.commonPipelineEnvironment.getCPEEncryptionKey(nullScript)}".toString()))
         assertThat(shellCallRule.shell[2], is('./piper testStep'))
         assertThat(credentials.size(), is(0))
 
@@ -76,6 +76,20 @@ class commonPipelineEnvironment implements Serializable {
 
     String changeDocumentId
 
+    // secret for the sensitive values of the commonPipelineEnvironment of the Go steps, generated once per pipeline run
+    // and intentionally not cleared by reset() since values encrypted before would no longer be readable
+    private String cpeEncryptionKey
+
+    String getCPEEncryptionKey(script) {
+        if (script?.env?.PIPER_cpeEncryptionKey) {
+            return script.env.PIPER_cpeEncryptionKey
+        }
+        if (!cpeEncryptionKey) {
+            cpeEncryptionKey = UUID.randomUUID().toString()
+        }
+        return cpeEncryptionKey
+    }
+
     def reset() {
 
         projectName = null
@@ -6,7 +6,8 @@ import groovy.transform.Field
 void call(Map parameters = [:]) {
     List credentials = [
     [type: 'usernamePassword', id: 'userCredentialsId', env: ['PIPER_username', 'PIPER_serviceKey']],
-    [type: 'token', id: 'apiKeyCredentialsId', env: ['PIPER_userApiKey']]
+    [type: 'token', id: 'apiKeyCredentialsId', env: ['PIPER_userApiKey']],
+    [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']]
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
@@ -7,7 +7,16 @@ import groovy.transform.Field
 void call(Map parameters = [:]) {
     final script = checkScript(this, parameters) ?: this
     String piperGoPath = parameters?.piperGoPath ?: './piper'
-    def output = script.sh(returnStdout: true, script: "${piperGoPath} readPipelineEnv")
+    def output
+    if (parameters?.decryptSensitive) {
+        // writePipelineEnv encrypts the decrypted sensitive values again with the key of the pipeline run
+        withEnv(["PIPER_cpeEncryptionKey=${script.commonPipelineEnvironment.getCPEEncryptionKey(script)}"]) {
+            output = script.sh(returnStdout: true, script: "${piperGoPath} readPipelineEnv --decryptSensitive")
+        }
+    } else {
+        // sensitive values stay encrypted, writePipelineEnv stores them again as they are
+        output = script.sh(returnStdout: true, script: "${piperGoPath} readPipelineEnv")
+    }
     Map cpeMap = script.readJSON(text: output)
     script?.commonPipelineEnvironment?.setCPEMap(script, cpeMap)
 }
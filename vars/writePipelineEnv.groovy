@@ -13,7 +13,7 @@ void call(Map parameters = [:]) {
 
     def jsonMap = groovy.json.JsonOutput.toJson(cpe)
     if (piperGoPath && jsonMap) {
-        withEnv(["PIPER_pipelineEnv=${jsonMap}"]) {
+        withEnv(["PIPER_pipelineEnv=${jsonMap}", "PIPER_cpeEncryptionKey=${script.commonPipelineEnvironment.getCPEEncryptionKey(script)}"]) {
             def output = script.sh(returnStdout: true, script: "${piperGoPath} writePipelineEnv")
             if (parameters?.verbose) {
                 script.echo("wrote commonPipelineEnvironment: ${output}")